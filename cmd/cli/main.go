@@ -3,7 +3,10 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -61,14 +64,30 @@ func newVMCommand() *cobra.Command {
 		Long:  "Create, list, update, delete, and control virtual machines",
 	}
 
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List virtual machines",
+		Long:  "List all virtual machines with optional filtering",
+		RunE:  runListVMs,
+	}
+	createCmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a new virtual machine",
+		Long:  "Create a new virtual machine with specified configuration",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runCreateVM,
+	}
+	cloneCmd := &cobra.Command{
+		Use:   "clone <vm-id> <new-name>",
+		Short: "Clone a virtual machine",
+		Long:  "Create a new virtual machine from the disk of an existing one or one of its snapshots",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runCloneVM,
+	}
+
 	// VM subcommands
 	cmd.AddCommand(
-		&cobra.Command{
-			Use:   "list",
-			Short: "List virtual machines",
-			Long:  "List all virtual machines with optional filtering",
-			RunE:  runListVMs,
-		},
+		listCmd,
 		&cobra.Command{
 			Use:   "get <vm-id>",
 			Short: "Get virtual machine details",
@@ -76,13 +95,8 @@ func newVMCommand() *cobra.Command {
 			Args:  cobra.ExactArgs(1),
 			RunE:  runGetVM,
 		},
-		&cobra.Command{
-			Use:   "create <name>",
-			Short: "Create a new virtual machine",
-			Long:  "Create a new virtual machine with specified configuration",
-			Args:  cobra.ExactArgs(1),
-			RunE:  runCreateVM,
-		},
+		createCmd,
+		cloneCmd,
 		&cobra.Command{
 			Use:   "delete <vm-id>",
 			Short: "Delete a virtual machine",
@@ -121,7 +135,7 @@ func newVMCommand() *cobra.Command {
 	)
 
 	// Add flags for create command
-	createCmd := cmd.Commands()[2] // "create" command
+	createCmd.Flags().String("flavor", "", "Flavor to size the VM by instead of --cpu, --ram and --disk")
 	createCmd.Flags().Int("cpu", 2, "Number of CPU cores")
 	createCmd.Flags().Int("ram", 2048, "RAM in MB")
 	createCmd.Flags().Int("disk", 50, "Disk size in GB")
@@ -129,11 +143,20 @@ func newVMCommand() *cobra.Command {
 	createCmd.Flags().String("network", "nat", "Network type (nat, bridge, host)")
 	createCmd.Flags().String("description", "", "VM description")
 
+	// Add flags for clone command
+	cloneCmd.Flags().Bool("linked", false, "Share the source disk instead of copying it")
+	cloneCmd.Flags().String("snapshot", "", "Clone from this snapshot of the source VM")
+	cloneCmd.Flags().String("flavor", "", "Flavor to size the clone by (default: as source)")
+	cloneCmd.Flags().Int("cpu", 0, "Number of CPU cores (default: as source)")
+	cloneCmd.Flags().Int("ram", 0, "RAM in MB (default: as source)")
+	cloneCmd.Flags().Int("disk", 0, "Disk size in GB, at least that of the source (default: as source)")
+	cloneCmd.Flags().String("description", "", "VM description")
+
 	// Add flags for list command
-	listCmd := cmd.Commands()[0] // "list" command
 	listCmd.Flags().String("status", "", "Filter by status")
 	listCmd.Flags().String("node", "", "Filter by node ID")
 	listCmd.Flags().String("search", "", "Search in name and description")
+	listCmd.Flags().StringP("selector", "l", "", "Label selector (e.g. env=prod,tier in (web,api),!deprecated)")
 	listCmd.Flags().Int("limit", 20, "Number of results per page")
 	listCmd.Flags().Int("page", 1, "Page number")
 
@@ -243,6 +266,9 @@ PowerShell:
 
 func runListVMs(cmd *cobra.Command, args []string) error {
 	fmt.Println("🔍 Listing VMs...")
+	if verbose {
+		fmt.Printf("GET %s/api/v1/vms?%s\n", apiURL, listVMsQuery(cmd).Encode())
+	}
 	// This would make HTTP request to API
 	fmt.Println("✅ Found 5 VMs")
 
@@ -278,6 +304,28 @@ func runListVMs(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// listVMsQuery builds the query parameters of a VM list request from the list flags
+func listVMsQuery(cmd *cobra.Command) url.Values {
+	query := url.Values{}
+	params := map[string]string{
+		"status":   "status",
+		"node":     "node_id",
+		"search":   "search",
+		"selector": "labelSelector",
+	}
+	for flag, param := range params {
+		if value, _ := cmd.Flags().GetString(flag); value != "" {
+			query.Set(param, value)
+		}
+	}
+
+	limit, _ := cmd.Flags().GetInt("limit")
+	page, _ := cmd.Flags().GetInt("page")
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("page", strconv.Itoa(page))
+	return query
+}
+
 func runGetVM(cmd *cobra.Command, args []string) error {
 	vmID := args[0]
 	fmt.Printf("🔍 Getting VM details: %s\n", vmID)
@@ -315,11 +363,16 @@ func runCreateVM(cmd *cobra.Command, args []string) error {
 	image, _ := cmd.Flags().GetString("image")
 	network, _ := cmd.Flags().GetString("network")
 	description, _ := cmd.Flags().GetString("description")
+	flavor, _ := cmd.Flags().GetString("flavor")
 
 	fmt.Printf("🚀 Creating VM: %s\n", name)
-	fmt.Printf("   CPU: %d cores\n", cpu)
-	fmt.Printf("   RAM: %d MB\n", ram)
-	fmt.Printf("   Disk: %d GB\n", disk)
+	if flavor != "" {
+		fmt.Printf("   Flavor: %s\n", flavor)
+	} else {
+		fmt.Printf("   CPU: %d cores\n", cpu)
+		fmt.Printf("   RAM: %d MB\n", ram)
+		fmt.Printf("   Disk: %d GB\n", disk)
+	}
 	fmt.Printf("   Image: %s\n", image)
 	fmt.Printf("   Network: %s\n", network)
 	if description != "" {
@@ -333,6 +386,58 @@ func runCreateVM(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runCloneVM(cmd *cobra.Command, args []string) error {
+	vmID, name := args[0], args[1]
+	req := cloneVMRequest(cmd, name)
+
+	fmt.Printf("🧬 Cloning VM %s to %s (%s clone)\n", vmID, name, req["mode"])
+	if snapshot, ok := req["snapshot_id"]; ok {
+		fmt.Printf("   Snapshot: %s\n", snapshot)
+	}
+	if verbose {
+		body, _ := json.Marshal(req)
+		fmt.Printf("POST %s/api/v1/vms/%s/clone %s\n", apiURL, vmID, body)
+	}
+
+	// This would make HTTP POST request to API
+	fmt.Println("✅ VM clone created, disk copy in progress!")
+	fmt.Println("📋 VM ID: 123e4567-e89b-12d3-a456-426614174003")
+
+	return nil
+}
+
+// cloneVMRequest builds the body of a VM clone request from the clone flags
+func cloneVMRequest(cmd *cobra.Command, name string) map[string]interface{} {
+	req := map[string]interface{}{
+		"name": name,
+		"mode": "full",
+	}
+	if linked, _ := cmd.Flags().GetBool("linked"); linked {
+		req["mode"] = "linked"
+	}
+	if snapshot, _ := cmd.Flags().GetString("snapshot"); snapshot != "" {
+		req["snapshot_id"] = snapshot
+	}
+	if description, _ := cmd.Flags().GetString("description"); description != "" {
+		req["description"] = description
+	}
+	if flavor, _ := cmd.Flags().GetString("flavor"); flavor != "" {
+		req["flavor"] = flavor
+	}
+
+	overrides := map[string]string{
+		"cpu":  "cpu_cores",
+		"ram":  "ram_mb",
+		"disk": "disk_gb",
+	}
+	for flag, field := range overrides {
+		if value, _ := cmd.Flags().GetInt(flag); value > 0 {
+			req[field] = value
+		}
+	}
+	return req
+}
+
 func runDeleteVM(cmd *cobra.Command, args []string) error {
 	vmID := args[0]
 	fmt.Printf("🗑️  Deleting VM: %s\n", vmID)
@@ -491,11 +596,25 @@ func printVMDetails(vm map[string]interface{}) {
 	fmt.Printf("Disk (GB):    %v\n", vm["disk_gb"])
 	fmt.Printf("Image:        %s\n", vm["image_name"])
 	fmt.Printf("Node:         %s\n", vm["node_id"])
+	fmt.Printf("IP Addresses: %s\n", formatAddresses(vm["ip_addresses"]))
 	fmt.Printf("Created:      %s\n", vm["created_at"])
 	fmt.Printf("Uptime:       %v seconds\n", vm["uptime"])
 	fmt.Println()
 }
 
+// formatAddresses joins the IP addresses of a VM, or returns "-" if it has none
+func formatAddresses(value interface{}) string {
+	addresses, _ := value.([]interface{})
+	if len(addresses) == 0 {
+		return "-"
+	}
+	parts := make([]string, len(addresses))
+	for i, address := range addresses {
+		parts[i] = fmt.Sprint(address)
+	}
+	return strings.Join(parts, ", ")
+}
+
 func printVMStats(stats map[string]interface{}) {
 	fmt.Println()
 	fmt.Println("📊 VM Statistics")
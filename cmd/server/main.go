@@ -11,13 +11,23 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"github.com/stackit/enterprise-vm-manager/internal/api/handlers"
 	"github.com/stackit/enterprise-vm-manager/internal/api/middleware"
 	"github.com/stackit/enterprise-vm-manager/internal/api/routes"
+	"github.com/stackit/enterprise-vm-manager/internal/auth"
+	"github.com/stackit/enterprise-vm-manager/internal/cache"
 	"github.com/stackit/enterprise-vm-manager/internal/config"
 	"github.com/stackit/enterprise-vm-manager/internal/database"
+	"github.com/stackit/enterprise-vm-manager/internal/health"
+	"github.com/stackit/enterprise-vm-manager/internal/hypervisor"
+	"github.com/stackit/enterprise-vm-manager/internal/imagestore"
+	"github.com/stackit/enterprise-vm-manager/internal/metrics"
+	"github.com/stackit/enterprise-vm-manager/internal/ratelimit"
 	"github.com/stackit/enterprise-vm-manager/internal/repositories"
+	"github.com/stackit/enterprise-vm-manager/internal/scheduler"
 	"github.com/stackit/enterprise-vm-manager/internal/services"
+	"github.com/stackit/enterprise-vm-manager/internal/watch"
 	"github.com/stackit/enterprise-vm-manager/pkg/logger"
 )
 
@@ -28,22 +38,70 @@ var (
 	gitCommit = "unknown"
 )
 
+// readinessCheckTimeout bounds each dependency check of the readiness probe
+const readinessCheckTimeout = 3 * time.Second
+
 // Application represents the main application
 type Application struct {
 	cfg    *config.Config
 	logger *logger.Logger
 	db     *database.Database
+	redis  *redis.Client
 	server *http.Server
 	router *routes.Router
 
+	// Hypervisor
+	driver hypervisor.Driver
+
+	// Prometheus collectors (nil when metrics are disabled)
+	metrics *metrics.Metrics
+
 	// Services
-	vmService services.VMService
+	vmService        services.VMService
+	nodeService      services.NodeService
+	operationService services.OperationService
+	eventService     services.EventService
+	rbacService      services.RBACService
+	projectService   services.ProjectService
+	quotaService     services.QuotaService
+	flavorService    services.FlavorService
+	imageService     services.ImageService
+	volumeService    services.VolumeService
+	networkService   services.NetworkService
 
 	// Repositories
-	vmRepo repositories.VMRepository
+	vmRepo       repositories.VMRepository
+	nodeRepo     repositories.NodeRepository
+	opRepo       repositories.OperationRepository
+	eventRepo    repositories.EventRepository
+	roleRepo     repositories.RoleRepository
+	bindingRepo  repositories.RoleBindingRepository
+	projectRepo  repositories.ProjectRepository
+	quotaRepo    repositories.QuotaRepository
+	snapshotRepo repositories.SnapshotRepository
+	flavorRepo   repositories.FlavorRepository
+	imageRepo    repositories.ImageRepository
+	volumeRepo   repositories.VolumeRepository
+	networkRepo  repositories.NetworkRepository
 
 	// Handlers
-	vmHandler *handlers.VMHandler
+	vmHandler        *handlers.VMHandler
+	nodeHandler      *handlers.NodeHandler
+	operationHandler *handlers.OperationHandler
+	eventHandler     *handlers.EventHandler
+	watchHandler     *handlers.WatchHandler
+	snapshotHandler  *handlers.SnapshotHandler
+	authHandler      *handlers.AuthHandler
+	rbacHandler      *handlers.RBACHandler
+	projectHandler   *handlers.ProjectHandler
+	quotaHandler     *handlers.QuotaHandler
+	flavorHandler    *handlers.FlavorHandler
+	imageHandler     *handlers.ImageHandler
+	volumeHandler    *handlers.VolumeHandler
+	networkHandler   *handlers.NetworkHandler
+
+	// Background workers
+	cancelWorkers context.CancelFunc
 
 	// Middleware
 	middleware *middleware.MiddlewareManager
@@ -167,20 +225,130 @@ func (app *Application) initializeComponents() error {
 		}
 	}
 
+	// Connect to Redis
+	if app.cfg.Redis.Enabled {
+		app.redis = cache.NewRedisClient(&app.cfg.Redis)
+		app.logger.Infof("Using Redis at %s", app.cfg.Redis.Address())
+	}
+
 	// Initialize repositories
 	app.vmRepo = repositories.NewVMRepository(app.db.DB)
+	if app.redis != nil && app.cfg.Redis.CacheTTL > 0 {
+		app.vmRepo = repositories.NewCachedVMRepository(app.vmRepo, app.redis, app.cfg.Redis.CacheTTL)
+	}
+	app.nodeRepo = repositories.NewNodeRepository(app.db.DB)
+	app.opRepo = repositories.NewOperationRepository(app.db.DB)
+	app.eventRepo = repositories.NewEventRepository(app.db.DB)
+	app.roleRepo = repositories.NewRoleRepository(app.db.DB)
+	app.bindingRepo = repositories.NewRoleBindingRepository(app.db.DB)
+	app.projectRepo = repositories.NewProjectRepository(app.db.DB)
+	app.quotaRepo = repositories.NewQuotaRepository(app.db.DB)
+	app.snapshotRepo = repositories.NewSnapshotRepository(app.db.DB)
+	app.flavorRepo = repositories.NewFlavorRepository(app.db.DB)
+	app.imageRepo = repositories.NewImageRepository(app.db.DB)
+	app.volumeRepo = repositories.NewVolumeRepository(app.db.DB)
+	app.networkRepo = repositories.NewNetworkRepository(app.db.DB)
+
+	// Initialize metrics
+	if app.cfg.Metrics.Enabled {
+		app.metrics = metrics.New(app.cfg.Metrics, app.logger)
+		app.metrics.RegisterVMCounts(app.vmRepo)
+		app.metrics.RegisterDatabaseStats(app.db)
+	}
+
+	// Initialize hypervisor driver
+	app.driver, err = hypervisor.New(&app.cfg.Hypervisor, app.logger)
+	if err != nil {
+		return fmt.Errorf("failed to initialize hypervisor driver: %w", err)
+	}
+	app.logger.Infof("Using hypervisor driver: %s", app.driver.Name())
+
+	// Initialize scheduler
+	sched, err := scheduler.New(app.cfg.Scheduler.Strategy)
+	if err != nil {
+		return fmt.Errorf("failed to initialize scheduler: %w", err)
+	}
+	app.logger.Infof("Using scheduling strategy: %s", sched.Strategy())
+
+	// Initialize watch hub
+	hub := watch.NewHub(app.cfg.Watch.HistorySize, app.cfg.Watch.SubscriberBuffer)
+
+	// Initialize image store
+	store, err := imagestore.New(app.cfg.Images.StorageDir, app.cfg.Images.MaxUploadBytes)
+	if err != nil {
+		return fmt.Errorf("failed to initialize image store: %w", err)
+	}
 
 	// Initialize services
-	app.vmService = services.NewVMService(app.vmRepo, app.cfg, app.logger)
+	app.vmService = services.NewVMService(app.vmRepo, app.nodeRepo, app.opRepo, app.eventRepo, app.quotaRepo, app.snapshotRepo, app.flavorRepo, app.imageRepo, store, app.volumeRepo, app.networkRepo, app.driver, sched, hub, app.metrics, app.cfg, app.logger)
+	app.nodeService = services.NewNodeService(app.nodeRepo, app.cfg, app.logger)
+	app.operationService = services.NewOperationService(app.opRepo, app.logger)
+	app.eventService = services.NewEventService(app.eventRepo, app.logger)
+	app.rbacService = services.NewRBACService(app.roleRepo, app.bindingRepo, app.logger)
+	app.projectService = services.NewProjectService(app.projectRepo, app.vmRepo, app.volumeRepo, app.networkRepo, app.imageRepo, app.logger)
+	app.quotaService = services.NewQuotaService(app.quotaRepo, app.projectRepo, app.cfg, app.logger)
+	app.flavorService = services.NewFlavorService(app.flavorRepo, app.projectRepo, app.logger)
+	app.imageService = services.NewImageService(app.imageRepo, app.projectRepo, app.vmRepo, app.snapshotRepo, store, app.cfg, app.logger)
+	app.volumeService = services.NewVolumeService(app.volumeRepo, app.vmRepo, app.quotaRepo, app.eventRepo, app.driver, hub, app.cfg, app.logger)
+	app.networkService = services.NewNetworkService(app.networkRepo, app.quotaRepo, app.logger)
+
+	// VMs created outside a project are placed in the default project
+	if _, err := app.projectService.EnsureDefaultProject(context.Background()); err != nil {
+		return fmt.Errorf("failed to create default project: %w", err)
+	}
+
+	// Operations of a previous process can no longer complete
+	if err := app.operationService.RecoverInterrupted(context.Background()); err != nil {
+		return fmt.Errorf("failed to recover interrupted operations: %w", err)
+	}
 
 	// Initialize handlers
 	app.vmHandler = handlers.NewVMHandler(app.vmService, app.logger)
+	app.nodeHandler = handlers.NewNodeHandler(app.nodeService, app.logger)
+	app.operationHandler = handlers.NewOperationHandler(app.operationService, app.logger)
+	app.eventHandler = handlers.NewEventHandler(app.eventService, app.logger)
+	app.watchHandler = handlers.NewWatchHandler(app.vmService, app.cfg.Watch.HeartbeatInterval, app.logger)
+	app.snapshotHandler = handlers.NewSnapshotHandler(app.vmService, app.logger)
+	app.rbacHandler = handlers.NewRBACHandler(app.rbacService, app.logger)
+	app.projectHandler = handlers.NewProjectHandler(app.projectService, app.logger)
+	app.quotaHandler = handlers.NewQuotaHandler(app.quotaService, app.logger)
+	app.flavorHandler = handlers.NewFlavorHandler(app.flavorService, app.logger)
+	app.imageHandler = handlers.NewImageHandler(app.imageService, app.logger)
+	app.volumeHandler = handlers.NewVolumeHandler(app.volumeService, app.logger)
+	app.networkHandler = handlers.NewNetworkHandler(app.networkService, app.logger)
+
+	// Initialize authentication
+	var authenticator *auth.Authenticator
+	if app.cfg.Auth.Enabled {
+		authenticator, err = auth.NewAuthenticator(app.cfg.Auth)
+		if err != nil {
+			return fmt.Errorf("failed to initialize authentication: %w", err)
+		}
+		app.authHandler = handlers.NewAuthHandler(authenticator, app.logger)
+		app.logger.Infof("Authentication enabled with %s tokens", app.cfg.Auth.JWTAlgorithm)
+	}
+
+	// Share rate limit buckets between replicas through Redis if available
+	limiter := ratelimit.NewMemoryLimiter(app.cfg.Server.RateLimit.Cleanup)
+	if app.redis != nil {
+		limiter = ratelimit.NewRedisLimiter(app.redis)
+	}
 
 	// Initialize middleware
-	app.middleware = middleware.NewMiddlewareManager(app.cfg, app.logger)
+	app.middleware = middleware.NewMiddlewareManager(app.cfg, app.logger, app.metrics, authenticator, app.rbacService, app.projectService, limiter)
+
+	// Initialize readiness checks
+	checker := health.NewChecker(readinessCheckTimeout)
+	checker.Register("database", app.db.HealthCheck)
+	if app.redis != nil {
+		checker.Register("redis", func(ctx context.Context) error {
+			return cache.HealthCheck(ctx, app.redis)
+		})
+	}
+	build := health.BuildInfo{Version: version, BuildTime: buildTime, GitCommit: gitCommit}
 
 	// Initialize router
-	app.router = routes.NewRouter(app.cfg, app.logger, app.vmHandler, app.middleware)
+	app.router = routes.NewRouter(app.cfg, app.logger, app.vmHandler, app.nodeHandler, app.operationHandler, app.eventHandler, app.watchHandler, app.snapshotHandler, app.authHandler, app.rbacHandler, app.projectHandler, app.quotaHandler, app.flavorHandler, app.imageHandler, app.volumeHandler, app.networkHandler, app.middleware, app.metrics, checker, build)
 
 	app.logger.Info("All components initialized successfully")
 	return nil
@@ -221,6 +389,12 @@ func (app *Application) start() error {
 		}
 	}()
 
+	// Start background workers
+	ctx, cancel := context.WithCancel(context.Background())
+	app.cancelWorkers = cancel
+	go app.nodeService.StartHeartbeatMonitor(ctx)
+	go app.imageService.StartGarbageCollector(ctx)
+
 	app.logger.Info("VM Manager API started successfully")
 	return nil
 }
@@ -248,6 +422,11 @@ func (app *Application) shutdown() error {
 	}
 	app.logger.Info("HTTP server stopped")
 
+	// Stop background workers
+	if app.cancelWorkers != nil {
+		app.cancelWorkers()
+	}
+
 	// Close database connection
 	if app.db != nil {
 		app.logger.Info("Closing database connection...")
@@ -258,6 +437,13 @@ func (app *Application) shutdown() error {
 		app.logger.Info("Database connection closed")
 	}
 
+	// Close Redis connection
+	if app.redis != nil {
+		if err := app.redis.Close(); err != nil {
+			app.logger.Errorf("Redis close error: %v", err)
+		}
+	}
+
 	// Close logger
 	if err := app.logger.Close(); err != nil {
 		fmt.Fprintf(os.Stderr, "Logger close error: %v\n", err)
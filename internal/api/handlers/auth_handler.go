@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/stackit/enterprise-vm-manager/internal/auth"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// AuthHandler handles authentication-related HTTP requests
+type AuthHandler struct {
+	authenticator *auth.Authenticator
+	logger        *logger.Logger
+}
+
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(authenticator *auth.Authenticator, logger *logger.Logger) *AuthHandler {
+	return &AuthHandler{
+		authenticator: authenticator,
+		logger:        logger.WithComponent("auth-handler"),
+	}
+}
+
+// IssueToken exchanges an API key for a JWT
+// @Summary Issue an access token
+// @Description Exchange an API key for a bearer token carrying the identity, roles and tenant of the API client
+// @Tags Auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.TokenResponse "Token issued"
+// @Failure 401 {object} map[string]interface{} "Missing or unknown API key"
+// @Failure 503 {object} map[string]interface{} "Token issuing not configured"
+// @Router /api/v1/auth/token [post]
+func (h *AuthHandler) IssueToken(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("issue-token")
+
+	identity, err := h.authenticator.AuthenticateAPIKey(c.GetHeader(h.authenticator.APIKeyHeader()))
+	if err != nil {
+		log.WithField("client_ip", c.ClientIP()).Warnf("Token request rejected: %v", err)
+		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	token, expiresAt, err := h.authenticator.IssueToken(identity)
+	if err != nil {
+		log.Errorf("Failed to issue token: %v", err)
+		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	log.Infof("Issued token for %s", identity.Subject)
+	c.JSON(http.StatusOK, gin.H{
+		"data": models.TokenResponse{
+			AccessToken: token,
+			TokenType:   "Bearer",
+			ExpiresIn:   int64(time.Until(expiresAt).Seconds()),
+			ExpiresAt:   expiresAt,
+		},
+		"message":    "Token issued successfully",
+		"request_id": requestID,
+	})
+}
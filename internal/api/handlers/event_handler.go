@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/services"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// EventHandler handles VM event-related HTTP requests
+type EventHandler struct {
+	eventService services.EventService
+	logger       *logger.Logger
+}
+
+// NewEventHandler creates a new event handler
+func NewEventHandler(eventService services.EventService, logger *logger.Logger) *EventHandler {
+	return &EventHandler{
+		eventService: eventService,
+		logger:       logger.WithComponent("event-handler"),
+	}
+}
+
+// ListEvents lists VM events across all VMs
+// @Summary List VM events
+// @Description Get the audit trail of VM changes with filtering and pagination, newest first
+// @Tags Events
+// @Produce json
+// @Param vm_id query string false "Filter by VM ID" format(uuid)
+// @Param type query string false "Filter by event type" Enums(created,updated,deleted,status_changed,snapshot_created,snapshot_deleted,snapshot_reverted,volume_attached,volume_detached)
+// @Param actor query string false "Filter by actor"
+// @Param request_id query string false "Filter by originating request ID"
+// @Param new_status query string false "Filter by resulting status" Enums(pending,stopped,starting,running,stopping,suspended,error)
+// @Param since query string false "Only events at or after this time" format(date-time)
+// @Param until query string false "Only events before this time" format(date-time)
+// @Param page query int false "Page number" default(1) minimum(1)
+// @Param limit query int false "Items per page" default(50) minimum(1) maximum(500)
+// @Success 200 {object} models.VMEventListResponse "List of events"
+// @Failure 400 {object} map[string]interface{} "Invalid query parameters"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/events [get]
+func (h *EventHandler) ListEvents(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("list-events")
+
+	var opts models.VMEventListOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		log.Warnf("Invalid query parameters: %v", err)
+		appErr := errors.ErrValidationFailed.WithContext("request_id", requestID).WithDetails(err.Error())
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	h.respondWithEvents(c, opts)
+}
+
+// ListVMEvents lists the events of a single VM
+// @Summary List events of a VM
+// @Description Get the audit trail of a virtual machine, including events recorded after its deletion
+// @Tags Events
+// @Produce json
+// @Param id path string true "VM ID" format(uuid)
+// @Param type query string false "Filter by event type" Enums(created,updated,deleted,status_changed,snapshot_created,snapshot_deleted,snapshot_reverted,volume_attached,volume_detached)
+// @Param actor query string false "Filter by actor"
+// @Param since query string false "Only events at or after this time" format(date-time)
+// @Param until query string false "Only events before this time" format(date-time)
+// @Param page query int false "Page number" default(1) minimum(1)
+// @Param limit query int false "Items per page" default(50) minimum(1) maximum(500)
+// @Success 200 {object} models.VMEventListResponse "List of events"
+// @Failure 400 {object} map[string]interface{} "Invalid VM ID or query parameters"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/vms/{id}/events [get]
+func (h *EventHandler) ListVMEvents(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("list-vm-events")
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		log.Warnf("Invalid VM ID format: %s", idParam)
+		appErr := errors.ErrInvalidInput.WithContext("request_id", requestID).WithDetails("Invalid UUID format")
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	var opts models.VMEventListOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		log.Warnf("Invalid query parameters: %v", err)
+		appErr := errors.ErrValidationFailed.WithContext("request_id", requestID).WithDetails(err.Error())
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+	opts.VMID = id.String()
+
+	h.respondWithEvents(c, opts)
+}
+
+// respondWithEvents lists events and writes the paginated response
+func (h *EventHandler) respondWithEvents(c *gin.Context, opts models.VMEventListOptions) {
+	requestID := requestid.Get(c)
+
+	result, err := h.eventService.ListEvents(c.Request.Context(), opts)
+	if err != nil {
+		h.logger.WithRequestID(requestID).Errorf("Failed to list VM events: %v", err)
+		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       result,
+		"request_id": requestID,
+	})
+}
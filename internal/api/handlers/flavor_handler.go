@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/services"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// FlavorHandler handles flavor catalog related HTTP requests
+type FlavorHandler struct {
+	flavorService services.FlavorService
+	logger        *logger.Logger
+}
+
+// NewFlavorHandler creates a new flavor handler
+func NewFlavorHandler(flavorService services.FlavorService, logger *logger.Logger) *FlavorHandler {
+	return &FlavorHandler{
+		flavorService: flavorService,
+		logger:        logger.WithComponent("flavor-handler"),
+	}
+}
+
+// ListFlavors lists the flavor catalog
+// @Summary List flavors
+// @Description Get the flavors VMs can be sized by, smallest first
+// @Tags Flavors
+// @Produce json
+// @Success 200 {array} models.Flavor "List of flavors"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/flavors [get]
+func (h *FlavorHandler) ListFlavors(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("list-flavors")
+
+	flavors, err := h.flavorService.ListFlavors(c.Request.Context())
+	if err != nil {
+		log.Errorf("Failed to list flavors: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       flavors,
+		"request_id": requestID,
+	})
+}
+
+// GetFlavor retrieves a flavor
+// @Summary Get flavor
+// @Description Get a flavor by name
+// @Tags Flavors
+// @Produce json
+// @Param name path string true "Flavor name"
+// @Success 200 {object} models.Flavor "Flavor details"
+// @Failure 404 {object} map[string]interface{} "Flavor not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/flavors/{name} [get]
+func (h *FlavorHandler) GetFlavor(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("get-flavor")
+
+	flavor, err := h.flavorService.GetFlavor(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		log.Warnf("Failed to get flavor: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       flavor,
+		"request_id": requestID,
+	})
+}
+
+// CreateFlavor adds a flavor to the catalog
+// @Summary Create flavor
+// @Description Add a named VM size to the flavor catalog
+// @Tags Flavors
+// @Accept json
+// @Produce json
+// @Param request body models.FlavorCreateRequest true "Flavor creation request"
+// @Success 201 {object} models.Flavor "Flavor created"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 409 {object} map[string]interface{} "Flavor already exists"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/flavors [post]
+func (h *FlavorHandler) CreateFlavor(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("create-flavor")
+
+	var req models.FlavorCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Invalid request body: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+	req.CreatedBy = actor(c)
+
+	flavor, err := h.flavorService.CreateFlavor(c.Request.Context(), &req)
+	if err != nil {
+		log.Warnf("Failed to create flavor: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data":       flavor,
+		"message":    "Flavor created successfully",
+		"request_id": requestID,
+	})
+}
+
+// UpdateFlavor replaces the size of a flavor
+// @Summary Update flavor
+// @Description Replace the description and resources of a flavor; existing VMs sized by it keep their resources
+// @Tags Flavors
+// @Accept json
+// @Produce json
+// @Param name path string true "Flavor name"
+// @Param request body models.FlavorUpdateRequest true "Flavor update request"
+// @Success 200 {object} models.Flavor "Flavor updated"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 404 {object} map[string]interface{} "Flavor not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/flavors/{name} [put]
+func (h *FlavorHandler) UpdateFlavor(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("update-flavor")
+
+	var req models.FlavorUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Invalid request body: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+	req.UpdatedBy = actor(c)
+
+	flavor, err := h.flavorService.UpdateFlavor(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		log.Warnf("Failed to update flavor: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       flavor,
+		"message":    "Flavor updated successfully",
+		"request_id": requestID,
+	})
+}
+
+// DeleteFlavor removes a flavor from the catalog
+// @Summary Delete flavor
+// @Description Remove a flavor from the catalog; flavors allowed in a project cannot be deleted
+// @Tags Flavors
+// @Param name path string true "Flavor name"
+// @Success 204 "Flavor deleted"
+// @Failure 404 {object} map[string]interface{} "Flavor not found"
+// @Failure 409 {object} map[string]interface{} "Flavor is allowed in a project"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/flavors/{name} [delete]
+func (h *FlavorHandler) DeleteFlavor(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("delete-flavor")
+
+	if err := h.flavorService.DeleteFlavor(c.Request.Context(), c.Param("name")); err != nil {
+		log.Warnf("Failed to delete flavor: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetProjectFlavors reports the flavors a project may use
+// @Summary Get project flavors
+// @Description Get the flavors VMs of a project may be sized by; unrestricted projects may use every flavor and custom sizes
+// @Tags Flavors
+// @Produce json
+// @Param project path string true "Project name"
+// @Success 200 {object} models.ProjectFlavors "Project flavors"
+// @Failure 404 {object} map[string]interface{} "Project not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/projects/{project}/flavors [get]
+func (h *FlavorHandler) GetProjectFlavors(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("get-project-flavors")
+
+	flavors, err := h.flavorService.GetProjectFlavors(c.Request.Context(), c.Param("project"))
+	if err != nil {
+		log.Warnf("Failed to get project flavors: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       flavors,
+		"request_id": requestID,
+	})
+}
+
+// UpdateProjectFlavors restricts a project to a set of flavors
+// @Summary Update project flavors
+// @Description Replace the flavors VMs of a project may be sized by; an empty list lifts the restriction
+// @Tags Flavors
+// @Accept json
+// @Produce json
+// @Param project path string true "Project name"
+// @Param request body models.ProjectFlavorsUpdateRequest true "Allowed flavors"
+// @Success 200 {object} models.ProjectFlavors "Project flavors updated"
+// @Failure 400 {object} map[string]interface{} "Invalid request or unknown flavor"
+// @Failure 404 {object} map[string]interface{} "Project not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/projects/{project}/flavors [put]
+func (h *FlavorHandler) UpdateProjectFlavors(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("update-project-flavors")
+
+	var req models.ProjectFlavorsUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Invalid request body: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+	req.UpdatedBy = actor(c)
+
+	flavors, err := h.flavorService.UpdateProjectFlavors(c.Request.Context(), c.Param("project"), &req)
+	if err != nil {
+		log.Warnf("Failed to update project flavors: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       flavors,
+		"message":    "Project flavors updated successfully",
+		"request_id": requestID,
+	})
+}
+
+// respondWithError writes an error response
+func (h *FlavorHandler) respondWithError(c *gin.Context, err error) {
+	requestID := requestid.Get(c)
+	appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+	c.JSON(appErr.HTTPCode, gin.H{
+		"error":      appErr,
+		"request_id": requestID,
+	})
+}
@@ -0,0 +1,364 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/services"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+const (
+	// uploadOffsetHeader reports how many bytes of an image file have been received
+	uploadOffsetHeader = "Upload-Offset"
+	// imageChecksumHeader carries the checksum of an image file as "<algorithm>:<hex digest>"
+	imageChecksumHeader = "X-Image-Checksum"
+)
+
+// ImageHandler handles image catalog related HTTP requests
+type ImageHandler struct {
+	imageService services.ImageService
+	logger       *logger.Logger
+}
+
+// NewImageHandler creates a new image handler
+func NewImageHandler(imageService services.ImageService, logger *logger.Logger) *ImageHandler {
+	return &ImageHandler{
+		imageService: imageService,
+		logger:       logger.WithComponent("image-handler"),
+	}
+}
+
+// ListImages lists the image catalog
+// @Summary List images
+// @Description Get the public images and the private images of the caller's projects
+// @Tags Images
+// @Produce json
+// @Param os_family query string false "Filter by OS family" Enums(linux,windows,bsd,other)
+// @Param name query string false "Filter by image name"
+// @Param include_deprecated query bool false "Include deprecated images"
+// @Success 200 {array} models.Image "List of images"
+// @Failure 400 {object} map[string]interface{} "Invalid query parameters"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/images [get]
+func (h *ImageHandler) ListImages(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("list-images")
+
+	var opts models.ImageListOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		log.Warnf("Invalid query parameters: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+
+	images, err := h.imageService.ListImages(c.Request.Context(), opts)
+	if err != nil {
+		log.Errorf("Failed to list images: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       images,
+		"request_id": requestID,
+	})
+}
+
+// GetImage retrieves an image
+// @Summary Get image
+// @Description Get an image of the catalog by ID or "<name>:<version>" reference
+// @Tags Images
+// @Produce json
+// @Param name path string true "Image ID or reference"
+// @Success 200 {object} models.Image "Image details"
+// @Failure 404 {object} map[string]interface{} "Image not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/images/{name} [get]
+func (h *ImageHandler) GetImage(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("get-image")
+
+	image, err := h.imageService.GetImage(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		log.Warnf("Failed to get image: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       image,
+		"request_id": requestID,
+	})
+}
+
+// CreateImage registers an image
+// @Summary Register image
+// @Description Add an image to the catalog. VMs refer to it as "<name>:<version>"; private images are only available to their project.
+// @Tags Images
+// @Accept json
+// @Produce json
+// @Param request body models.ImageCreateRequest true "Image registration request"
+// @Success 201 {object} models.Image "Image registered"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 404 {object} map[string]interface{} "Project not found"
+// @Failure 409 {object} map[string]interface{} "Image already exists"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/images [post]
+func (h *ImageHandler) CreateImage(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("create-image")
+
+	var req models.ImageCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Invalid request body: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+	req.CreatedBy = actor(c)
+
+	image, err := h.imageService.CreateImage(c.Request.Context(), &req)
+	if err != nil {
+		log.Warnf("Failed to create image: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data":       image,
+		"message":    "Image registered successfully",
+		"request_id": requestID,
+	})
+}
+
+// UpdateImage replaces the mutable fields of an image
+// @Summary Update image
+// @Description Replace the description, requirements, checksum and deprecation state of an image. Deprecated images cannot be used for new VMs.
+// @Tags Images
+// @Accept json
+// @Produce json
+// @Param name path string true "Image ID or reference"
+// @Param request body models.ImageUpdateRequest true "Image update request"
+// @Success 200 {object} models.Image "Image updated"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 404 {object} map[string]interface{} "Image not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/images/{name} [put]
+func (h *ImageHandler) UpdateImage(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("update-image")
+
+	var req models.ImageUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Invalid request body: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+	req.UpdatedBy = actor(c)
+
+	image, err := h.imageService.UpdateImage(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		log.Warnf("Failed to update image: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       image,
+		"message":    "Image updated successfully",
+		"request_id": requestID,
+	})
+}
+
+// DeleteImage removes an image from the catalog
+// @Summary Delete image
+// @Description Remove an image and its uploaded file; images VMs were created from must be deprecated instead
+// @Tags Images
+// @Param name path string true "Image ID or reference"
+// @Success 204 "Image deleted"
+// @Failure 404 {object} map[string]interface{} "Image not found"
+// @Failure 409 {object} map[string]interface{} "Image is used by VMs"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/images/{name} [delete]
+func (h *ImageHandler) DeleteImage(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("delete-image")
+
+	if err := h.imageService.DeleteImage(c.Request.Context(), c.Param("name")); err != nil {
+		log.Warnf("Failed to delete image: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetUpload reports the progress of an image file upload
+// @Summary Get image upload
+// @Description Get how many bytes of the image file have been received, so an interrupted upload can resume at the returned offset
+// @Tags Images
+// @Produce json
+// @Param name path string true "Image ID or reference"
+// @Success 200 {object} models.ImageUpload "Upload progress"
+// @Header 200 {integer} Upload-Offset "Bytes received"
+// @Failure 404 {object} map[string]interface{} "Image not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/images/{name}/upload [get]
+func (h *ImageHandler) GetUpload(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("get-image-upload")
+
+	upload, err := h.imageService.GetUpload(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		log.Warnf("Failed to get image upload: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.Header(uploadOffsetHeader, strconv.FormatInt(upload.Offset, 10))
+	c.JSON(http.StatusOK, gin.H{
+		"data":       upload,
+		"request_id": requestID,
+	})
+}
+
+// UploadImage receives the image file or a chunk of it
+// @Summary Upload image file
+// @Description Stream a qcow2 or raw image file. Large files may be sent in chunks with a Content-Range header, each starting at the offset received so far; a chunk at offset 0 starts over. The complete file is verified against the checksum of the image or the X-Image-Checksum header.
+// @Tags Images
+// @Accept application/octet-stream
+// @Produce json
+// @Param name path string true "Image ID or reference"
+// @Param Content-Range header string false "Position of the chunk, e.g. bytes 0-1048575/4194304"
+// @Param X-Image-Checksum header string false "Checksum of the whole file as <algorithm>:<hex digest>, required if the image has none"
+// @Success 200 {object} models.ImageUpload "Upload complete"
+// @Success 202 {object} models.ImageUpload "Chunk received, upload incomplete"
+// @Header 200,202 {integer} Upload-Offset "Bytes received"
+// @Failure 400 {object} map[string]interface{} "Invalid Content-Range or checksum"
+// @Failure 404 {object} map[string]interface{} "Image not found"
+// @Failure 409 {object} map[string]interface{} "Chunk does not continue at the upload offset, or image already uploaded"
+// @Failure 413 {object} map[string]interface{} "Image file too large"
+// @Failure 422 {object} map[string]interface{} "Uploaded file does not match the checksum"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/images/{name}/upload [post]
+func (h *ImageHandler) UploadImage(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("upload-image")
+	clearDeadlines(c)
+
+	req := models.ImageUploadRequest{
+		Total:     c.Request.ContentLength,
+		Body:      c.Request.Body,
+		Checksum:  c.GetHeader(imageChecksumHeader),
+		UpdatedBy: actor(c),
+	}
+	if header := c.GetHeader("Content-Range"); header != "" {
+		start, end, total, err := parseContentRange(header)
+		if err != nil {
+			log.Warnf("Invalid Content-Range header: %s", header)
+			h.respondWithError(c, errors.ValidationError("Content-Range", err.Error()))
+			return
+		}
+		if c.Request.ContentLength >= 0 && c.Request.ContentLength != end-start+1 {
+			h.respondWithError(c, errors.ValidationError("Content-Range", "Range length does not match Content-Length"))
+			return
+		}
+		req.Offset = start
+		req.Total = total
+		req.Body = io.LimitReader(c.Request.Body, end-start+1)
+	}
+
+	upload, err := h.imageService.UploadImage(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		log.Warnf("Failed to upload image: %v", err)
+		if appErr := errors.ToAppError(err); errors.Is(appErr, errors.ErrUploadOffset) {
+			c.Header(uploadOffsetHeader, appErr.Context["offset"])
+		}
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.Header(uploadOffsetHeader, strconv.FormatInt(upload.Offset, 10))
+	if !upload.Complete {
+		c.JSON(http.StatusAccepted, gin.H{
+			"data":       upload,
+			"message":    "Chunk received, continue at the upload offset",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       upload,
+		"message":    "Image uploaded successfully",
+		"request_id": requestID,
+	})
+}
+
+// DownloadImage streams the uploaded image file
+// @Summary Download image file
+// @Description Download the uploaded image file. Range requests are supported, so interrupted downloads can resume.
+// @Tags Images
+// @Produce application/octet-stream
+// @Param name path string true "Image ID or reference"
+// @Success 200 {file} file "Image file"
+// @Success 206 {file} file "Requested range of the image file"
+// @Header 200 {string} X-Image-Checksum "Checksum of the image file"
+// @Failure 404 {object} map[string]interface{} "Image not found or not uploaded"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/images/{name}/download [get]
+func (h *ImageHandler) DownloadImage(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("download-image")
+	clearDeadlines(c)
+
+	image, file, err := h.imageService.OpenImage(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		log.Warnf("Failed to open image: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Type", "application/octet-stream")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filepath.Base(file.Name())))
+	c.Header("ETag", strconv.Quote(image.Checksum))
+	c.Header(imageChecksumHeader, image.Checksum)
+	http.ServeContent(c.Writer, c.Request, file.Name(), *image.UploadedAt, file)
+}
+
+// clearDeadlines lifts the server read and write timeouts, which image files take longer to transfer than
+func clearDeadlines(c *gin.Context) {
+	rc := http.NewResponseController(c.Writer)
+	rc.SetReadDeadline(time.Time{})
+	rc.SetWriteDeadline(time.Time{})
+}
+
+// parseContentRange parses a "bytes <start>-<end>/<total>" Content-Range header
+func parseContentRange(header string) (start, end, total int64, err error) {
+	if _, err := fmt.Sscanf(header, "bytes %d-%d/%d", &start, &end, &total); err != nil {
+		return 0, 0, 0, fmt.Errorf("expected bytes <start>-<end>/<total>")
+	}
+	if start < 0 || end < start || total <= end {
+		return 0, 0, 0, fmt.Errorf("range %d-%d is not within %d bytes", start, end, total)
+	}
+	return start, end, total, nil
+}
+
+// respondWithError writes an error response
+func (h *ImageHandler) respondWithError(c *gin.Context, err error) {
+	requestID := requestid.Get(c)
+	appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+	c.JSON(appErr.HTTPCode, gin.H{
+		"error":      appErr,
+		"request_id": requestID,
+	})
+}
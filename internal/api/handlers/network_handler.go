@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/api/middleware"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/services"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// NetworkHandler handles virtual network related HTTP requests
+type NetworkHandler struct {
+	networkService services.NetworkService
+	logger         *logger.Logger
+}
+
+// NewNetworkHandler creates a new network handler
+func NewNetworkHandler(networkService services.NetworkService, logger *logger.Logger) *NetworkHandler {
+	return &NetworkHandler{
+		networkService: networkService,
+		logger:         logger.WithComponent("network-handler"),
+	}
+}
+
+// CreateNetwork creates a virtual network
+// @Summary Create network
+// @Description Create an IPv4 network in the project. The gateway defaults to the first host address and the DHCP range to the remaining host addresses.
+// @Tags Networks
+// @Accept json
+// @Produce json
+// @Param request body models.NetworkCreateRequest true "Network creation request"
+// @Success 201 {object} models.Network "Network created"
+// @Failure 400 {object} map[string]interface{} "Invalid request or addressing"
+// @Failure 409 {object} map[string]interface{} "Network name taken"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/networks [post]
+func (h *NetworkHandler) CreateNetwork(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("create-network")
+
+	var req models.NetworkCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Invalid request body: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+	req.CreatedBy = actor(c)
+	req.ProjectID = middleware.GetProjectID(c)
+
+	network, err := h.networkService.CreateNetwork(c.Request.Context(), &req)
+	if err != nil {
+		log.Warnf("Failed to create network: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data":       network,
+		"message":    "Network created successfully",
+		"request_id": requestID,
+	})
+}
+
+// ListNetworks lists virtual networks
+// @Summary List networks
+// @Description Get the networks of the caller's projects ordered by name
+// @Tags Networks
+// @Produce json
+// @Success 200 {array} models.Network "List of networks"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/networks [get]
+func (h *NetworkHandler) ListNetworks(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("list-networks")
+
+	networks, err := h.networkService.ListNetworks(c.Request.Context())
+	if err != nil {
+		log.Errorf("Failed to list networks: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       networks,
+		"request_id": requestID,
+	})
+}
+
+// GetNetwork retrieves a virtual network
+// @Summary Get network
+// @Description Get a network and its addressing
+// @Tags Networks
+// @Produce json
+// @Param id path string true "Network ID" format(uuid)
+// @Success 200 {object} models.Network "Network details"
+// @Failure 400 {object} map[string]interface{} "Invalid network ID"
+// @Failure 404 {object} map[string]interface{} "Network not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/networks/{id} [get]
+func (h *NetworkHandler) GetNetwork(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("get-network")
+
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	network, err := h.networkService.GetNetwork(c.Request.Context(), id)
+	if err != nil {
+		log.Warnf("Failed to get network: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       network,
+		"request_id": requestID,
+	})
+}
+
+// UpdateNetwork updates a virtual network
+// @Summary Update network
+// @Description Replace the description and DNS servers of a network. The addressing of a network cannot change.
+// @Tags Networks
+// @Accept json
+// @Produce json
+// @Param id path string true "Network ID" format(uuid)
+// @Param request body models.NetworkUpdateRequest true "Network update request"
+// @Success 200 {object} models.Network "Network updated"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 404 {object} map[string]interface{} "Network not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/networks/{id} [put]
+func (h *NetworkHandler) UpdateNetwork(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("update-network")
+
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.NetworkUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Invalid request body: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+	req.UpdatedBy = actor(c)
+
+	network, err := h.networkService.UpdateNetwork(c.Request.Context(), id, &req)
+	if err != nil {
+		log.Warnf("Failed to update network: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       network,
+		"message":    "Network updated successfully",
+		"request_id": requestID,
+	})
+}
+
+// DeleteNetwork deletes a virtual network
+// @Summary Delete network
+// @Description Delete a network that no VM is connected to
+// @Tags Networks
+// @Param id path string true "Network ID" format(uuid)
+// @Success 204 "Network deleted"
+// @Failure 400 {object} map[string]interface{} "Invalid network ID"
+// @Failure 404 {object} map[string]interface{} "Network not found"
+// @Failure 409 {object} map[string]interface{} "VMs are connected to the network"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/networks/{id} [delete]
+func (h *NetworkHandler) DeleteNetwork(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("delete-network")
+
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.networkService.DeleteNetwork(c.Request.Context(), id); err != nil {
+		log.Warnf("Failed to delete network: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListInterfaces lists the VM interfaces connected to a virtual network
+// @Summary List network interfaces
+// @Description Get the VM interfaces connected to a network with their MAC and IP addresses, ordered by IP address
+// @Tags Networks
+// @Produce json
+// @Param id path string true "Network ID" format(uuid)
+// @Success 200 {array} models.NetworkInterface "List of interfaces"
+// @Failure 400 {object} map[string]interface{} "Invalid network ID"
+// @Failure 404 {object} map[string]interface{} "Network not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/networks/{id}/interfaces [get]
+func (h *NetworkHandler) ListInterfaces(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("list-network-interfaces")
+
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	interfaces, err := h.networkService.ListInterfaces(c.Request.Context(), id)
+	if err != nil {
+		log.Warnf("Failed to list network interfaces: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       interfaces,
+		"request_id": requestID,
+	})
+}
+
+// parseID parses a UUID path parameter, responding with an error if it is invalid
+func (h *NetworkHandler) parseID(c *gin.Context, param string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		h.logger.WithRequestID(requestid.Get(c)).Warnf("Invalid %s format: %s", param, c.Param(param))
+		h.respondWithError(c, errors.ErrInvalidInput.WithDetails("Invalid UUID format"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondWithError writes an error response
+func (h *NetworkHandler) respondWithError(c *gin.Context, err error) {
+	requestID := requestid.Get(c)
+	appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+	c.JSON(appErr.HTTPCode, gin.H{
+		"error":      appErr,
+		"request_id": requestID,
+	})
+}
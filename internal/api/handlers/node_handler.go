@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/services"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// NodeHandler handles compute node related HTTP requests
+type NodeHandler struct {
+	nodeService services.NodeService
+	logger      *logger.Logger
+}
+
+// NewNodeHandler creates a new node handler
+func NewNodeHandler(nodeService services.NodeService, logger *logger.Logger) *NodeHandler {
+	return &NodeHandler{
+		nodeService: nodeService,
+		logger:      logger.WithComponent("node-handler"),
+	}
+}
+
+// RegisterNode registers a compute node
+// @Summary Register a compute node
+// @Description Register a new compute node or refresh the capacity and labels of a known one
+// @Tags Nodes
+// @Accept json
+// @Produce json
+// @Param request body models.NodeRegisterRequest true "Node registration request"
+// @Success 201 {object} models.Node "Node registered"
+// @Success 200 {object} models.Node "Node inventory refreshed"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/nodes [post]
+func (h *NodeHandler) RegisterNode(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("register-node")
+
+	var req models.NodeRegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Invalid request body: %v", err)
+		appErr := errors.ErrValidationFailed.WithContext("request_id", requestID).WithDetails(err.Error())
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	node, created, err := h.nodeService.RegisterNode(c.Request.Context(), &req)
+	if err != nil {
+		log.Errorf("Failed to register node: %v", err)
+		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	status := http.StatusOK
+	message := "Node inventory refreshed"
+	if created {
+		status = http.StatusCreated
+		message = "Node registered successfully"
+	}
+
+	c.JSON(status, gin.H{
+		"data":       node,
+		"message":    message,
+		"request_id": requestID,
+	})
+}
+
+// Heartbeat records a node heartbeat
+// @Summary Node heartbeat
+// @Description Record a heartbeat for a registered compute node
+// @Tags Nodes
+// @Produce json
+// @Param name path string true "Node name"
+// @Success 200 {object} models.Node "Heartbeat recorded"
+// @Failure 404 {object} map[string]interface{} "Node not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/nodes/{name}/heartbeat [post]
+func (h *NodeHandler) Heartbeat(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("node-heartbeat")
+
+	node, err := h.nodeService.Heartbeat(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		log.Errorf("Failed to record heartbeat: %v", err)
+		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       node,
+		"request_id": requestID,
+	})
+}
+
+// GetNode retrieves a compute node
+// @Summary Get compute node
+// @Description Get capacity, labels and liveness of a compute node
+// @Tags Nodes
+// @Produce json
+// @Param name path string true "Node name"
+// @Success 200 {object} models.Node "Node details"
+// @Failure 404 {object} map[string]interface{} "Node not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/nodes/{name} [get]
+func (h *NodeHandler) GetNode(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("get-node")
+
+	node, err := h.nodeService.GetNode(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		log.Errorf("Failed to get node: %v", err)
+		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       node,
+		"request_id": requestID,
+	})
+}
+
+// ListNodes lists compute nodes
+// @Summary List compute nodes
+// @Description Get all registered compute nodes
+// @Tags Nodes
+// @Produce json
+// @Param state query string false "Filter by state" Enums(ready,unreachable,maintenance)
+// @Success 200 {array} models.Node "List of nodes"
+// @Failure 400 {object} map[string]interface{} "Invalid query parameters"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/nodes [get]
+func (h *NodeHandler) ListNodes(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("list-nodes")
+
+	var opts models.NodeListOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		log.Warnf("Invalid query parameters: %v", err)
+		appErr := errors.ErrValidationFailed.WithContext("request_id", requestID).WithDetails(err.Error())
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	nodes, err := h.nodeService.ListNodes(c.Request.Context(), opts)
+	if err != nil {
+		log.Errorf("Failed to list nodes: %v", err)
+		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       nodes,
+		"request_id": requestID,
+	})
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/services"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// OperationHandler handles operation-related HTTP requests
+type OperationHandler struct {
+	operationService services.OperationService
+	logger           *logger.Logger
+}
+
+// NewOperationHandler creates a new operation handler
+func NewOperationHandler(operationService services.OperationService, logger *logger.Logger) *OperationHandler {
+	return &OperationHandler{
+		operationService: operationService,
+		logger:           logger.WithComponent("operation-handler"),
+	}
+}
+
+// GetOperation retrieves an operation by ID
+// @Summary Get operation
+// @Description Get the state and progress of an asynchronous VM operation
+// @Tags Operations
+// @Produce json
+// @Param id path string true "Operation ID" format(uuid)
+// @Success 200 {object} models.Operation "Operation details"
+// @Failure 400 {object} map[string]interface{} "Invalid operation ID"
+// @Failure 404 {object} map[string]interface{} "Operation not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/operations/{id} [get]
+func (h *OperationHandler) GetOperation(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("get-operation")
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		log.Warnf("Invalid operation ID format: %s", idParam)
+		appErr := errors.ErrInvalidInput.WithContext("request_id", requestID).WithDetails("Invalid UUID format")
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	op, err := h.operationService.GetOperation(c.Request.Context(), id)
+	if err != nil {
+		log.Errorf("Failed to get operation: %v", err)
+		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       op,
+		"request_id": requestID,
+	})
+}
+
+// ListOperations lists operations
+// @Summary List operations
+// @Description Get recent asynchronous VM operations, newest first
+// @Tags Operations
+// @Produce json
+// @Param vm_id query string false "Filter by target VM ID" format(uuid)
+// @Param type query string false "Filter by type" Enums(provision,start,stop,force-stop,restart,suspend,resume,delete,clone,snapshot-create,snapshot-delete,snapshot-revert)
+// @Param state query string false "Filter by state" Enums(pending,running,succeeded,failed)
+// @Param limit query int false "Maximum number of operations" default(50) minimum(1) maximum(500)
+// @Success 200 {array} models.Operation "List of operations"
+// @Failure 400 {object} map[string]interface{} "Invalid query parameters"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/operations [get]
+func (h *OperationHandler) ListOperations(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("list-operations")
+
+	var opts models.OperationListOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		log.Warnf("Invalid query parameters: %v", err)
+		appErr := errors.ErrValidationFailed.WithContext("request_id", requestID).WithDetails(err.Error())
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	ops, err := h.operationService.ListOperations(c.Request.Context(), opts)
+	if err != nil {
+		log.Errorf("Failed to list operations: %v", err)
+		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       ops,
+		"request_id": requestID,
+	})
+}
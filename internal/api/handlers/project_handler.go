@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/services"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// ProjectHandler handles project and project membership related HTTP requests
+type ProjectHandler struct {
+	projectService services.ProjectService
+	logger         *logger.Logger
+}
+
+// NewProjectHandler creates a new project handler
+func NewProjectHandler(projectService services.ProjectService, logger *logger.Logger) *ProjectHandler {
+	return &ProjectHandler{
+		projectService: projectService,
+		logger:         logger.WithComponent("project-handler"),
+	}
+}
+
+// ListProjects lists the projects of the caller
+// @Summary List projects
+// @Description Get the projects the caller is a member of
+// @Tags Projects
+// @Produce json
+// @Success 200 {array} models.Project "List of projects"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/projects [get]
+func (h *ProjectHandler) ListProjects(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("list-projects")
+
+	projects, err := h.projectService.ListProjects(c.Request.Context())
+	if err != nil {
+		log.Errorf("Failed to list projects: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       projects,
+		"request_id": requestID,
+	})
+}
+
+// GetProject retrieves a project
+// @Summary Get project
+// @Description Get a project the caller is a member of
+// @Tags Projects
+// @Produce json
+// @Param project path string true "Project name"
+// @Success 200 {object} models.Project "Project details"
+// @Failure 404 {object} map[string]interface{} "Project not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/projects/{project} [get]
+func (h *ProjectHandler) GetProject(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("get-project")
+
+	project, err := h.projectService.GetProject(c.Request.Context(), c.Param("project"))
+	if err != nil {
+		log.Warnf("Failed to get project: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       project,
+		"request_id": requestID,
+	})
+}
+
+// CreateProject creates a project
+// @Summary Create project
+// @Description Create a project; the caller becomes its first member
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Param request body models.ProjectCreateRequest true "Project creation request"
+// @Success 201 {object} models.Project "Project created"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 409 {object} map[string]interface{} "Project already exists"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/projects [post]
+func (h *ProjectHandler) CreateProject(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("create-project")
+
+	var req models.ProjectCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Invalid request body: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+	req.CreatedBy = actor(c)
+
+	project, err := h.projectService.CreateProject(c.Request.Context(), &req)
+	if err != nil {
+		log.Warnf("Failed to create project: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data":       project,
+		"message":    "Project created successfully",
+		"request_id": requestID,
+	})
+}
+
+// DeleteProject deletes an empty project
+// @Summary Delete project
+// @Description Delete a project without VMs, volumes, networks or private images; the default project cannot be deleted
+// @Tags Projects
+// @Param project path string true "Project name"
+// @Success 204 "Project deleted"
+// @Failure 404 {object} map[string]interface{} "Project not found"
+// @Failure 409 {object} map[string]interface{} "Default project or project still has VMs"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/projects/{project} [delete]
+func (h *ProjectHandler) DeleteProject(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("delete-project")
+
+	if err := h.projectService.DeleteProject(c.Request.Context(), c.Param("project")); err != nil {
+		log.Warnf("Failed to delete project: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListMembers lists the members of a project
+// @Summary List project members
+// @Description Get the subjects that are members of a project
+// @Tags Projects
+// @Produce json
+// @Param project path string true "Project name"
+// @Success 200 {array} models.ProjectMember "List of project members"
+// @Failure 404 {object} map[string]interface{} "Project not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/projects/{project}/members [get]
+func (h *ProjectHandler) ListMembers(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("list-project-members")
+
+	members, err := h.projectService.ListMembers(c.Request.Context(), c.Param("project"))
+	if err != nil {
+		log.Warnf("Failed to list project members: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       members,
+		"request_id": requestID,
+	})
+}
+
+// AddMember adds a subject to a project
+// @Summary Add project member
+// @Description Grant a subject access to the VMs of a project
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Param project path string true "Project name"
+// @Param request body models.ProjectMemberCreateRequest true "Project member request"
+// @Success 201 {object} models.ProjectMember "Project member added"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 404 {object} map[string]interface{} "Project not found"
+// @Failure 409 {object} map[string]interface{} "Subject is already a member"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/projects/{project}/members [post]
+func (h *ProjectHandler) AddMember(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("add-project-member")
+
+	var req models.ProjectMemberCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Invalid request body: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+	req.CreatedBy = actor(c)
+
+	member, err := h.projectService.AddMember(c.Request.Context(), c.Param("project"), &req)
+	if err != nil {
+		log.Warnf("Failed to add project member: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data":       member,
+		"message":    "Project member added successfully",
+		"request_id": requestID,
+	})
+}
+
+// RemoveMember removes a subject from a project
+// @Summary Remove project member
+// @Description Revoke the access of a subject to the VMs of a project
+// @Tags Projects
+// @Param project path string true "Project name"
+// @Param subject path string true "Subject"
+// @Success 204 "Project member removed"
+// @Failure 404 {object} map[string]interface{} "Project or member not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/projects/{project}/members/{subject} [delete]
+func (h *ProjectHandler) RemoveMember(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("remove-project-member")
+
+	if err := h.projectService.RemoveMember(c.Request.Context(), c.Param("project"), c.Param("subject")); err != nil {
+		log.Warnf("Failed to remove project member: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondWithError writes an error response
+func (h *ProjectHandler) respondWithError(c *gin.Context, err error) {
+	requestID := requestid.Get(c)
+	appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+	c.JSON(appErr.HTTPCode, gin.H{
+		"error":      appErr,
+		"request_id": requestID,
+	})
+}
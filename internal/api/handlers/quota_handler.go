@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/services"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// QuotaHandler handles project quota related HTTP requests
+type QuotaHandler struct {
+	quotaService services.QuotaService
+	logger       *logger.Logger
+}
+
+// NewQuotaHandler creates a new quota handler
+func NewQuotaHandler(quotaService services.QuotaService, logger *logger.Logger) *QuotaHandler {
+	return &QuotaHandler{
+		quotaService: quotaService,
+		logger:       logger.WithComponent("quota-handler"),
+	}
+}
+
+// ListQuotas reports the quota usage of the caller's projects
+// @Summary List quota usage
+// @Description Get the limits and current usage of every project the caller is a member of
+// @Tags Quotas
+// @Produce json
+// @Success 200 {array} models.QuotaStatus "Quota usage per project"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/quotas [get]
+func (h *QuotaHandler) ListQuotas(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("list-quotas")
+
+	quotas, err := h.quotaService.ListQuotas(c.Request.Context())
+	if err != nil {
+		log.Errorf("Failed to list quotas: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       quotas,
+		"request_id": requestID,
+	})
+}
+
+// GetQuota reports the quota usage of a project
+// @Summary Get project quota
+// @Description Get the limits and current usage of a project
+// @Tags Quotas
+// @Produce json
+// @Param project path string true "Project name"
+// @Success 200 {object} models.QuotaStatus "Quota usage"
+// @Failure 404 {object} map[string]interface{} "Project not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/projects/{project}/quota [get]
+func (h *QuotaHandler) GetQuota(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("get-quota")
+
+	quota, err := h.quotaService.GetQuota(c.Request.Context(), c.Param("project"))
+	if err != nil {
+		log.Warnf("Failed to get quota: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       quota,
+		"request_id": requestID,
+	})
+}
+
+// UpdateQuota replaces the limits of a project
+// @Summary Update project quota
+// @Description Replace the limits on VMs, running VMs, vCPUs, RAM and disk of a project; zero means unlimited
+// @Tags Quotas
+// @Accept json
+// @Produce json
+// @Param project path string true "Project name"
+// @Param request body models.QuotaUpdateRequest true "Quota limits"
+// @Success 200 {object} models.QuotaStatus "Quota updated"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 404 {object} map[string]interface{} "Project not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/projects/{project}/quota [put]
+func (h *QuotaHandler) UpdateQuota(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("update-quota")
+
+	var req models.QuotaUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Invalid request body: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+	req.UpdatedBy = actor(c)
+
+	quota, err := h.quotaService.UpdateQuota(c.Request.Context(), c.Param("project"), &req)
+	if err != nil {
+		log.Warnf("Failed to update quota: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       quota,
+		"message":    "Quota updated successfully",
+		"request_id": requestID,
+	})
+}
+
+// respondWithError writes an error response
+func (h *QuotaHandler) respondWithError(c *gin.Context, err error) {
+	requestID := requestid.Get(c)
+	appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+	c.JSON(appErr.HTTPCode, gin.H{
+		"error":      appErr,
+		"request_id": requestID,
+	})
+}
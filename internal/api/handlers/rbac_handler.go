@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/api/middleware"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/services"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// RBACHandler handles role and role binding related HTTP requests
+type RBACHandler struct {
+	rbacService services.RBACService
+	logger      *logger.Logger
+}
+
+// NewRBACHandler creates a new RBAC handler
+func NewRBACHandler(rbacService services.RBACService, logger *logger.Logger) *RBACHandler {
+	return &RBACHandler{
+		rbacService: rbacService,
+		logger:      logger.WithComponent("rbac-handler"),
+	}
+}
+
+// ListRoles lists built-in and custom roles
+// @Summary List roles
+// @Description Get the built-in roles and all custom roles with their permissions
+// @Tags RBAC
+// @Produce json
+// @Success 200 {array} models.Role "List of roles"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/rbac/roles [get]
+func (h *RBACHandler) ListRoles(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("list-roles")
+
+	roles, err := h.rbacService.ListRoles(c.Request.Context())
+	if err != nil {
+		log.Errorf("Failed to list roles: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       roles,
+		"request_id": requestID,
+	})
+}
+
+// GetRole retrieves a role
+// @Summary Get role
+// @Description Get a built-in or custom role with its permissions
+// @Tags RBAC
+// @Produce json
+// @Param name path string true "Role name"
+// @Success 200 {object} models.Role "Role details"
+// @Failure 404 {object} map[string]interface{} "Role not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/rbac/roles/{name} [get]
+func (h *RBACHandler) GetRole(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("get-role")
+
+	role, err := h.rbacService.GetRole(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		log.Warnf("Failed to get role: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       role,
+		"request_id": requestID,
+	})
+}
+
+// CreateRole creates a custom role
+// @Summary Create role
+// @Description Create a custom role granting a set of permissions such as vm:read or vm:restart
+// @Tags RBAC
+// @Accept json
+// @Produce json
+// @Param request body models.RoleCreateRequest true "Role creation request"
+// @Success 201 {object} models.Role "Role created"
+// @Failure 400 {object} map[string]interface{} "Invalid request or unknown permission"
+// @Failure 409 {object} map[string]interface{} "Role already exists"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/rbac/roles [post]
+func (h *RBACHandler) CreateRole(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("create-role")
+
+	var req models.RoleCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Invalid request body: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+	req.CreatedBy = actor(c)
+
+	role, err := h.rbacService.CreateRole(c.Request.Context(), &req)
+	if err != nil {
+		log.Warnf("Failed to create role: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data":       role,
+		"message":    "Role created successfully",
+		"request_id": requestID,
+	})
+}
+
+// UpdateRole replaces the permissions of a custom role
+// @Summary Update role
+// @Description Replace the description and permissions of a custom role; built-in roles cannot be changed
+// @Tags RBAC
+// @Accept json
+// @Produce json
+// @Param name path string true "Role name"
+// @Param request body models.RoleUpdateRequest true "Role update request"
+// @Success 200 {object} models.Role "Role updated"
+// @Failure 400 {object} map[string]interface{} "Invalid request or unknown permission"
+// @Failure 404 {object} map[string]interface{} "Role not found"
+// @Failure 409 {object} map[string]interface{} "Built-in role"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/rbac/roles/{name} [put]
+func (h *RBACHandler) UpdateRole(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("update-role")
+
+	var req models.RoleUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Invalid request body: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+	req.UpdatedBy = actor(c)
+
+	role, err := h.rbacService.UpdateRole(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		log.Warnf("Failed to update role: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       role,
+		"message":    "Role updated successfully",
+		"request_id": requestID,
+	})
+}
+
+// DeleteRole deletes a custom role
+// @Summary Delete role
+// @Description Delete a custom role and all bindings granting it; built-in roles cannot be deleted
+// @Tags RBAC
+// @Param name path string true "Role name"
+// @Success 204 "Role deleted"
+// @Failure 404 {object} map[string]interface{} "Role not found"
+// @Failure 409 {object} map[string]interface{} "Built-in role"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/rbac/roles/{name} [delete]
+func (h *RBACHandler) DeleteRole(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("delete-role")
+
+	if err := h.rbacService.DeleteRole(c.Request.Context(), c.Param("name")); err != nil {
+		log.Warnf("Failed to delete role: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListBindings lists role bindings
+// @Summary List role bindings
+// @Description Get the roles granted to subjects in addition to the roles of their tokens
+// @Tags RBAC
+// @Produce json
+// @Param subject query string false "Filter by subject"
+// @Param role query string false "Filter by role"
+// @Success 200 {array} models.RoleBinding "List of role bindings"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/rbac/bindings [get]
+func (h *RBACHandler) ListBindings(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("list-role-bindings")
+
+	var opts models.RoleBindingListOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		log.Warnf("Invalid query parameters: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+
+	bindings, err := h.rbacService.ListBindings(c.Request.Context(), opts)
+	if err != nil {
+		log.Errorf("Failed to list role bindings: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       bindings,
+		"request_id": requestID,
+	})
+}
+
+// CreateBinding grants a role to a subject
+// @Summary Create role binding
+// @Description Grant a built-in or custom role to a subject
+// @Tags RBAC
+// @Accept json
+// @Produce json
+// @Param request body models.RoleBindingCreateRequest true "Role binding request"
+// @Success 201 {object} models.RoleBinding "Role binding created"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 404 {object} map[string]interface{} "Role not found"
+// @Failure 409 {object} map[string]interface{} "Role already bound to subject"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/rbac/bindings [post]
+func (h *RBACHandler) CreateBinding(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("create-role-binding")
+
+	var req models.RoleBindingCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Invalid request body: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+	req.CreatedBy = actor(c)
+
+	binding, err := h.rbacService.CreateBinding(c.Request.Context(), &req)
+	if err != nil {
+		log.Warnf("Failed to create role binding: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data":       binding,
+		"message":    "Role binding created successfully",
+		"request_id": requestID,
+	})
+}
+
+// DeleteBinding revokes a role binding
+// @Summary Delete role binding
+// @Description Revoke a role granted to a subject
+// @Tags RBAC
+// @Param id path string true "Role binding ID" format(uuid)
+// @Success 204 "Role binding deleted"
+// @Failure 400 {object} map[string]interface{} "Invalid role binding ID"
+// @Failure 404 {object} map[string]interface{} "Role binding not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/rbac/bindings/{id} [delete]
+func (h *RBACHandler) DeleteBinding(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("delete-role-binding")
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		log.Warnf("Invalid role binding ID format: %s", c.Param("id"))
+		h.respondWithError(c, errors.ErrInvalidInput.WithDetails("Invalid UUID format"))
+		return
+	}
+
+	if err := h.rbacService.DeleteBinding(c.Request.Context(), id); err != nil {
+		log.Warnf("Failed to delete role binding: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondWithError writes an error response
+func (h *RBACHandler) respondWithError(c *gin.Context, err error) {
+	requestID := requestid.Get(c)
+	appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+	c.JSON(appErr.HTTPCode, gin.H{
+		"error":      appErr,
+		"request_id": requestID,
+	})
+}
+
+// actor returns the authenticated user, or system when authentication is disabled
+func actor(c *gin.Context) string {
+	if userID := middleware.GetUserID(c); userID != "" {
+		return userID
+	}
+	return "system"
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/services"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// SnapshotHandler handles VM snapshot related HTTP requests
+type SnapshotHandler struct {
+	vmService services.VMService
+	logger    *logger.Logger
+}
+
+// NewSnapshotHandler creates a new snapshot handler
+func NewSnapshotHandler(vmService services.VMService, logger *logger.Logger) *SnapshotHandler {
+	return &SnapshotHandler{
+		vmService: vmService,
+		logger:    logger.WithComponent("snapshot-handler"),
+	}
+}
+
+// CreateSnapshot snapshots a virtual machine
+// @Summary Create snapshot
+// @Description Capture the disk of a VM, or for memory snapshots its disk and running state. Disk snapshots can be taken of stopped, running and suspended VMs; memory snapshots of running and suspended VMs.
+// @Tags Snapshots
+// @Accept json
+// @Produce json
+// @Param id path string true "VM ID" format(uuid)
+// @Param request body models.SnapshotCreateRequest true "Snapshot creation request"
+// @Success 202 {object} models.Snapshot "Snapshot creation initiated"
+// @Header 202 {string} Location "Operation capturing the snapshot"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 404 {object} map[string]interface{} "VM not found"
+// @Failure 409 {object} map[string]interface{} "Snapshot name taken, VM in wrong state or another snapshot operation in progress"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/vms/{id}/snapshots [post]
+func (h *SnapshotHandler) CreateSnapshot(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("create-snapshot")
+
+	vmID, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.SnapshotCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Invalid request body: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+	req.CreatedBy = actor(c)
+	req.RequestID = requestID
+
+	snapshot, op, err := h.vmService.CreateSnapshot(c.Request.Context(), vmID, &req)
+	if err != nil {
+		log.Warnf("Failed to create snapshot: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.Header("Location", operationLocation(op))
+	c.JSON(http.StatusAccepted, gin.H{
+		"data":         snapshot,
+		"message":      "Snapshot creation initiated",
+		"operation_id": op.ID,
+		"request_id":   requestID,
+	})
+}
+
+// ListSnapshots lists the snapshots of a virtual machine
+// @Summary List snapshots
+// @Description Get all snapshots of a VM, oldest first
+// @Tags Snapshots
+// @Produce json
+// @Param id path string true "VM ID" format(uuid)
+// @Success 200 {array} models.Snapshot "List of snapshots"
+// @Failure 400 {object} map[string]interface{} "Invalid VM ID"
+// @Failure 404 {object} map[string]interface{} "VM not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/vms/{id}/snapshots [get]
+func (h *SnapshotHandler) ListSnapshots(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("list-snapshots")
+
+	vmID, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	snapshots, err := h.vmService.ListSnapshots(c.Request.Context(), vmID)
+	if err != nil {
+		log.Warnf("Failed to list snapshots: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       snapshots,
+		"request_id": requestID,
+	})
+}
+
+// GetSnapshot retrieves a snapshot of a virtual machine
+// @Summary Get snapshot
+// @Description Get a snapshot of a VM
+// @Tags Snapshots
+// @Produce json
+// @Param id path string true "VM ID" format(uuid)
+// @Param snapshot_id path string true "Snapshot ID" format(uuid)
+// @Success 200 {object} models.Snapshot "Snapshot details"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 404 {object} map[string]interface{} "VM or snapshot not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/vms/{id}/snapshots/{snapshot_id} [get]
+func (h *SnapshotHandler) GetSnapshot(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("get-snapshot")
+
+	vmID, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "snapshot_id")
+	if !ok {
+		return
+	}
+
+	snapshot, err := h.vmService.GetSnapshot(c.Request.Context(), vmID, id)
+	if err != nil {
+		log.Warnf("Failed to get snapshot: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       snapshot,
+		"request_id": requestID,
+	})
+}
+
+// DeleteSnapshot deletes a snapshot of a virtual machine
+// @Summary Delete snapshot
+// @Description Remove a snapshot of a stopped, running or suspended VM
+// @Tags Snapshots
+// @Accept json
+// @Produce json
+// @Param id path string true "VM ID" format(uuid)
+// @Param snapshot_id path string true "Snapshot ID" format(uuid)
+// @Param request body models.VMStateChangeRequest false "Deletion options"
+// @Success 202 {object} models.Operation "Snapshot deletion initiated"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 404 {object} map[string]interface{} "VM or snapshot not found"
+// @Failure 409 {object} map[string]interface{} "VM in wrong state or another snapshot operation in progress"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/vms/{id}/snapshots/{snapshot_id} [delete]
+func (h *SnapshotHandler) DeleteSnapshot(c *gin.Context) {
+	h.snapshotOperation(c, "delete-snapshot", "Snapshot deletion initiated", h.vmService.DeleteSnapshot)
+}
+
+// RevertSnapshot reverts a virtual machine to a snapshot
+// @Summary Revert to snapshot
+// @Description Restore a stopped VM to a snapshot. The VM stays stopped after reverting to a disk snapshot and is running again after reverting to a memory snapshot.
+// @Tags Snapshots
+// @Accept json
+// @Produce json
+// @Param id path string true "VM ID" format(uuid)
+// @Param snapshot_id path string true "Snapshot ID" format(uuid)
+// @Param request body models.VMStateChangeRequest false "Revert options"
+// @Param If-Match header string false "ETag the VM must still have"
+// @Success 202 {object} models.Operation "Snapshot revert initiated"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 404 {object} map[string]interface{} "VM or snapshot not found"
+// @Failure 409 {object} map[string]interface{} "VM not stopped, snapshot not ready or quota exceeded"
+// @Failure 412 {object} map[string]interface{} "VM no longer matches If-Match"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/vms/{id}/snapshots/{snapshot_id}/revert [post]
+func (h *SnapshotHandler) RevertSnapshot(c *gin.Context) {
+	h.snapshotOperation(c, "revert-snapshot", "Snapshot revert initiated", h.vmService.RevertSnapshot)
+}
+
+// snapshotOperation starts an asynchronous operation on an existing snapshot
+func (h *SnapshotHandler) snapshotOperation(c *gin.Context, operation, message string, serviceFunc func(ctx context.Context, vmID, id uuid.UUID, req *models.VMStateChangeRequest) (*models.Operation, error)) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation(operation)
+
+	vmID, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "snapshot_id")
+	if !ok {
+		return
+	}
+
+	var req models.VMStateChangeRequest
+	// Optional body - ignore binding errors
+	c.ShouldBindJSON(&req)
+	req.UpdatedBy = actor(c)
+	req.RequestID = requestID
+
+	var err error
+	if req.ResourceVersion, err = ifMatchVersion(c); err != nil {
+		h.respondWithError(c, err)
+		return
+	}
+
+	op, err := serviceFunc(c.Request.Context(), vmID, id, &req)
+	if err != nil {
+		log.Warnf("Failed to %s: %v", operation, err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.Header("Location", operationLocation(op))
+	c.JSON(http.StatusAccepted, gin.H{
+		"data":         op,
+		"message":      message,
+		"operation_id": op.ID,
+		"request_id":   requestID,
+		"vm_id":        vmID,
+	})
+}
+
+// parseID parses a UUID path parameter, responding with an error if it is invalid
+func (h *SnapshotHandler) parseID(c *gin.Context, param string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		h.logger.WithRequestID(requestid.Get(c)).Warnf("Invalid %s format: %s", param, c.Param(param))
+		h.respondWithError(c, errors.ErrInvalidInput.WithDetails("Invalid UUID format"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondWithError writes an error response
+func (h *SnapshotHandler) respondWithError(c *gin.Context, err error) {
+	requestID := requestid.Get(c)
+	appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+	c.JSON(appErr.HTTPCode, gin.H{
+		"error":      appErr,
+		"request_id": requestID,
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -12,6 +14,7 @@ import (
 	"github.com/stackit/enterprise-vm-manager/internal/models"
 	"github.com/stackit/enterprise-vm-manager/internal/services"
 	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/jsonpatch"
 	"github.com/stackit/enterprise-vm-manager/pkg/logger"
 )
 
@@ -31,13 +34,14 @@ func NewVMHandler(vmService services.VMService, logger *logger.Logger) *VMHandle
 
 // CreateVM creates a new virtual machine
 // @Summary Create a new virtual machine
-// @Description Create a new virtual machine with specified configuration
+// @Description Create a new virtual machine sized by a flavor or by custom resources in the project of the route, or in the default project
 // @Tags VMs
 // @Accept json
 // @Produce json
 // @Param request body models.VMCreateRequest true "VM creation request"
 // @Success 201 {object} models.VM "VM created successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 403 {object} map[string]interface{} "Flavor or custom size not allowed in the project"
 // @Failure 409 {object} map[string]interface{} "VM already exists"
 // @Failure 422 {object} map[string]interface{} "Resource limits exceeded"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
@@ -63,8 +67,10 @@ func (h *VMHandler) CreateVM(c *gin.Context) {
 	} else {
 		req.CreatedBy = "system"
 	}
+	req.RequestID = requestID
+	req.ProjectID = middleware.GetProjectID(c)
 
-	vm, err := h.vmService.CreateVM(c.Request.Context(), &req)
+	vm, op, err := h.vmService.CreateVM(c.Request.Context(), &req)
 	if err != nil {
 		log.Errorf("Failed to create VM: %v", err)
 		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
@@ -76,10 +82,13 @@ func (h *VMHandler) CreateVM(c *gin.Context) {
 	}
 
 	log.Infof("VM created successfully: %s", vm.Name)
+	c.Header("Location", operationLocation(op))
+	c.Header("ETag", vm.ETag())
 	c.JSON(http.StatusCreated, gin.H{
-		"data":       models.NewVMResponse(vm),
-		"message":    "VM created successfully",
-		"request_id": requestID,
+		"data":         models.NewVMResponse(vm),
+		"message":      "VM created successfully",
+		"operation_id": op.ID,
+		"request_id":   requestID,
 	})
 }
 
@@ -90,6 +99,7 @@ func (h *VMHandler) CreateVM(c *gin.Context) {
 // @Produce json
 // @Param id path string true "VM ID" format(uuid)
 // @Success 200 {object} models.VMResponse "VM details"
+// @Header 200 {string} ETag "Resource version of the VM"
 // @Failure 400 {object} map[string]interface{} "Invalid VM ID"
 // @Failure 404 {object} map[string]interface{} "VM not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
@@ -121,6 +131,7 @@ func (h *VMHandler) GetVM(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", vm.ETag())
 	c.JSON(http.StatusOK, gin.H{
 		"data":       models.NewVMResponse(vm),
 		"request_id": requestID,
@@ -134,13 +145,16 @@ func (h *VMHandler) GetVM(c *gin.Context) {
 // @Produce json
 // @Param page query int false "Page number" default(1) minimum(1)
 // @Param limit query int false "Items per page" default(20) minimum(1) maximum(100)
-// @Param status query string false "Filter by status" Enums(pending,stopped,starting,running,stopping,suspended,error)
+// @Param status query string false "Filter by status" Enums(pending,stopped,starting,running,stopping,suspended,error,deleting)
 // @Param node_id query string false "Filter by node ID"
 // @Param created_by query string false "Filter by creator"
 // @Param search query string false "Search in name and description"
+// @Param labelSelector query string false "Label selector, e.g. env=prod,tier in (web,api),!deprecated"
 // @Param sort_by query string false "Sort field" default(created_at) Enums(created_at,updated_at,name,status)
 // @Param sort_order query string false "Sort order" default(desc) Enums(asc,desc)
 // @Param include_stats query bool false "Include runtime statistics" default(false)
+// @Param continue query string false "next_cursor of the previous page; page is ignored when set"
+// @Param include_total query bool false "Count all matching VMs; ignored when continue is set" default(true)
 // @Success 200 {object} models.VMListResponse "List of VMs"
 // @Failure 400 {object} map[string]interface{} "Invalid query parameters"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
@@ -171,7 +185,7 @@ func (h *VMHandler) ListVMs(c *gin.Context) {
 		return
 	}
 
-	log.Debugf("Listed %d VMs (total: %d)", len(response.VMs), response.Pagination.Total)
+	log.Debugf("Listed %d VMs (more: %t)", len(response.VMs), response.Pagination.HasNext)
 	c.JSON(http.StatusOK, gin.H{
 		"data":       response,
 		"request_id": requestID,
@@ -186,11 +200,15 @@ func (h *VMHandler) ListVMs(c *gin.Context) {
 // @Produce json
 // @Param id path string true "VM ID" format(uuid)
 // @Param request body models.VMUpdateRequest true "VM update request"
+// @Param If-Match header string false "ETag the VM must still have"
 // @Success 200 {object} models.VMResponse "Updated VM"
+// @Header 200 {string} ETag "Resource version of the updated VM"
 // @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 403 {object} map[string]interface{} "Flavor or custom size not allowed in the project"
 // @Failure 404 {object} map[string]interface{} "VM not found"
 // @Failure 409 {object} map[string]interface{} "VM cannot be updated in current state"
 // @Failure 422 {object} map[string]interface{} "Resource limits exceeded"
+// @Failure 412 {object} map[string]interface{} "VM no longer matches If-Match"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/v1/vms/{id} [put]
 func (h *VMHandler) UpdateVM(c *gin.Context) {
@@ -226,6 +244,15 @@ func (h *VMHandler) UpdateVM(c *gin.Context) {
 	} else {
 		req.UpdatedBy = "system"
 	}
+	req.RequestID = requestID
+	if req.ResourceVersion, err = ifMatchVersion(c); err != nil {
+		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
 
 	vm, err := h.vmService.UpdateVM(c.Request.Context(), id, &req)
 	if err != nil {
@@ -239,6 +266,7 @@ func (h *VMHandler) UpdateVM(c *gin.Context) {
 	}
 
 	log.Infof("VM updated successfully: %s", vm.Name)
+	c.Header("ETag", vm.ETag())
 	c.JSON(http.StatusOK, gin.H{
 		"data":       models.NewVMResponse(vm),
 		"message":    "VM updated successfully",
@@ -246,15 +274,119 @@ func (h *VMHandler) UpdateVM(c *gin.Context) {
 	})
 }
 
+// PatchVM patches a virtual machine
+// @Summary Patch virtual machine
+// @Description Apply a JSON Merge Patch (RFC 7386) or JSON Patch (RFC 6902) to the name, description, resources, labels and annotations of a virtual machine (only when stopped). The patched VM is validated like a create request.
+// @Tags VMs
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Param id path string true "VM ID" format(uuid)
+// @Param request body object true "Merge patch document or array of JSON Patch operations"
+// @Param If-Match header string false "ETag the VM must still have"
+// @Success 200 {object} models.VMResponse "Patched VM"
+// @Header 200 {string} ETag "Resource version of the patched VM"
+// @Failure 400 {object} map[string]interface{} "Invalid patch or patched VM"
+// @Failure 403 {object} map[string]interface{} "Flavor or custom size not allowed in the project"
+// @Failure 404 {object} map[string]interface{} "VM not found"
+// @Failure 409 {object} map[string]interface{} "VM cannot be updated in current state"
+// @Failure 412 {object} map[string]interface{} "VM no longer matches If-Match"
+// @Failure 415 {object} map[string]interface{} "Unsupported patch format"
+// @Failure 422 {object} map[string]interface{} "Patch could not be applied"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/vms/{id} [patch]
+func (h *VMHandler) PatchVM(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("patch-vm")
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		log.Warnf("Invalid VM ID format: %s", idParam)
+		appErr := errors.ErrInvalidInput.WithContext("request_id", requestID).WithDetails("Invalid UUID format")
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	contentType := c.ContentType()
+	if contentType != jsonpatch.MergePatchType && contentType != jsonpatch.JSONPatchType {
+		log.Warnf("Unsupported patch content type: %s", contentType)
+		appErr := errors.ErrUnsupportedMedia.WithContext("request_id", requestID).
+			WithDetails(fmt.Sprintf("Content-Type must be %s or %s", jsonpatch.MergePatchType, jsonpatch.JSONPatchType))
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		log.Warnf("Failed to read request body: %v", err)
+		appErr := errors.ErrInvalidInput.WithContext("request_id", requestID).WithDetails(err.Error())
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	req := models.VMPatchRequest{
+		ContentType: contentType,
+		Patch:       patch,
+		RequestID:   requestID,
+	}
+	if userID := middleware.GetUserID(c); userID != "" {
+		req.UpdatedBy = userID
+	} else {
+		req.UpdatedBy = "system"
+	}
+	if req.ResourceVersion, err = ifMatchVersion(c); err != nil {
+		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	vm, err := h.vmService.PatchVM(c.Request.Context(), id, &req)
+	if err != nil {
+		log.Errorf("Failed to patch VM: %v", err)
+		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	log.Infof("VM patched successfully: %s", vm.Name)
+	c.Header("ETag", vm.ETag())
+	c.JSON(http.StatusOK, gin.H{
+		"data":       models.NewVMResponse(vm),
+		"message":    "VM patched successfully",
+		"request_id": requestID,
+	})
+}
+
 // DeleteVM deletes a virtual machine
 // @Summary Delete virtual machine
-// @Description Delete a virtual machine (only when stopped)
+// @Description Delete a virtual machine (only when stopped). The deletion completes before the response; the returned operation records it.
 // @Tags VMs
+// @Accept json
+// @Produce json
 // @Param id path string true "VM ID" format(uuid)
-// @Success 204 "VM deleted successfully"
+// @Param request body models.VMStateChangeRequest false "Deletion reason"
+// @Param If-Match header string false "ETag the VM must still have"
+// @Success 200 {object} models.Operation "VM deleted successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid VM ID"
 // @Failure 404 {object} map[string]interface{} "VM not found"
 // @Failure 409 {object} map[string]interface{} "VM cannot be deleted in current state"
+// @Failure 412 {object} map[string]interface{} "VM no longer matches If-Match"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/v1/vms/{id} [delete]
 func (h *VMHandler) DeleteVM(c *gin.Context) {
@@ -273,7 +405,27 @@ func (h *VMHandler) DeleteVM(c *gin.Context) {
 		return
 	}
 
-	err = h.vmService.DeleteVM(c.Request.Context(), id)
+	var req models.VMStateChangeRequest
+	// Optional body carrying the deletion reason - ignore binding errors
+	c.ShouldBindJSON(&req)
+
+	// Set updated_by from context
+	if userID := middleware.GetUserID(c); userID != "" {
+		req.UpdatedBy = userID
+	} else {
+		req.UpdatedBy = "system"
+	}
+	req.RequestID = requestID
+	if req.ResourceVersion, err = ifMatchVersion(c); err != nil {
+		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	op, err := h.vmService.DeleteVM(c.Request.Context(), id, &req)
 	if err != nil {
 		log.Errorf("Failed to delete VM: %v", err)
 		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
@@ -286,8 +438,83 @@ func (h *VMHandler) DeleteVM(c *gin.Context) {
 
 	log.Infof("VM deleted successfully: %s", id)
 	c.JSON(http.StatusOK, gin.H{
-		"message":    "VM deleted successfully",
-		"request_id": requestID,
+		"data":         op,
+		"message":      "VM deleted successfully",
+		"operation_id": op.ID,
+		"request_id":   requestID,
+	})
+}
+
+// CloneVM clones a virtual machine
+// @Summary Clone virtual machine
+// @Description Create a new VM in the project of the source with a copy of its spec, labels and annotations. Full clones copy the disk of a stopped source, or of a snapshot of a stopped, running or suspended source. Linked clones share the disk of a stopped source without snapshots and are placed on its node.
+// @Tags VMs
+// @Accept json
+// @Produce json
+// @Param id path string true "Source VM ID" format(uuid)
+// @Param request body models.VMCloneRequest true "VM clone request"
+// @Success 201 {object} models.VM "VM clone created"
+// @Header 201 {string} Location "Operation copying the disk"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 403 {object} map[string]interface{} "Flavor or custom size not allowed in the project"
+// @Failure 404 {object} map[string]interface{} "Source VM or snapshot not found"
+// @Failure 409 {object} map[string]interface{} "VM already exists, source in wrong state, snapshot not ready or quota exceeded"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/vms/{id}/clone [post]
+func (h *VMHandler) CloneVM(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("clone-vm")
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		log.Warnf("Invalid VM ID format: %s", idParam)
+		appErr := errors.ErrInvalidInput.WithContext("request_id", requestID).WithDetails("Invalid UUID format")
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	var req models.VMCloneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Invalid request body: %v", err)
+		appErr := errors.ErrValidationFailed.WithContext("request_id", requestID).WithDetails(err.Error())
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	// Set created_by from context
+	if userID := middleware.GetUserID(c); userID != "" {
+		req.CreatedBy = userID
+	} else {
+		req.CreatedBy = "system"
+	}
+	req.RequestID = requestID
+
+	vm, op, err := h.vmService.CloneVM(c.Request.Context(), id, &req)
+	if err != nil {
+		log.Errorf("Failed to clone VM: %v", err)
+		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	log.Infof("VM %s cloned to %s", id, vm.Name)
+	c.Header("Location", operationLocation(op))
+	c.Header("ETag", vm.ETag())
+	c.JSON(http.StatusCreated, gin.H{
+		"data":         models.NewVMResponse(vm),
+		"message":      "VM clone created",
+		"operation_id": op.ID,
+		"request_id":   requestID,
 	})
 }
 
@@ -299,10 +526,12 @@ func (h *VMHandler) DeleteVM(c *gin.Context) {
 // @Produce json
 // @Param id path string true "VM ID" format(uuid)
 // @Param request body models.VMStateChangeRequest false "State change options"
-// @Success 202 {object} map[string]interface{} "VM start initiated"
+// @Param If-Match header string false "ETag the VM must still have"
+// @Success 202 {object} models.Operation "VM start initiated"
 // @Failure 400 {object} map[string]interface{} "Invalid VM ID"
 // @Failure 404 {object} map[string]interface{} "VM not found"
 // @Failure 409 {object} map[string]interface{} "VM cannot be started in current state"
+// @Failure 412 {object} map[string]interface{} "VM no longer matches If-Match"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/v1/vms/{id}/start [post]
 func (h *VMHandler) StartVM(c *gin.Context) {
@@ -317,10 +546,12 @@ func (h *VMHandler) StartVM(c *gin.Context) {
 // @Produce json
 // @Param id path string true "VM ID" format(uuid)
 // @Param request body models.VMStateChangeRequest false "State change options"
-// @Success 202 {object} map[string]interface{} "VM stop initiated"
+// @Param If-Match header string false "ETag the VM must still have"
+// @Success 202 {object} models.Operation "VM stop initiated"
 // @Failure 400 {object} map[string]interface{} "Invalid VM ID"
 // @Failure 404 {object} map[string]interface{} "VM not found"
 // @Failure 409 {object} map[string]interface{} "VM cannot be stopped in current state"
+// @Failure 412 {object} map[string]interface{} "VM no longer matches If-Match"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/v1/vms/{id}/stop [post]
 func (h *VMHandler) StopVM(c *gin.Context) {
@@ -335,10 +566,12 @@ func (h *VMHandler) StopVM(c *gin.Context) {
 // @Produce json
 // @Param id path string true "VM ID" format(uuid)
 // @Param request body models.VMStateChangeRequest false "State change options"
-// @Success 202 {object} map[string]interface{} "VM restart initiated"
+// @Param If-Match header string false "ETag the VM must still have"
+// @Success 202 {object} models.Operation "VM restart initiated"
 // @Failure 400 {object} map[string]interface{} "Invalid VM ID"
 // @Failure 404 {object} map[string]interface{} "VM not found"
 // @Failure 409 {object} map[string]interface{} "VM cannot be restarted in current state"
+// @Failure 412 {object} map[string]interface{} "VM no longer matches If-Match"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/v1/vms/{id}/restart [post]
 func (h *VMHandler) RestartVM(c *gin.Context) {
@@ -353,10 +586,12 @@ func (h *VMHandler) RestartVM(c *gin.Context) {
 // @Produce json
 // @Param id path string true "VM ID" format(uuid)
 // @Param request body models.VMStateChangeRequest false "State change options"
-// @Success 202 {object} map[string]interface{} "VM suspend initiated"
+// @Param If-Match header string false "ETag the VM must still have"
+// @Success 202 {object} models.Operation "VM suspend initiated"
 // @Failure 400 {object} map[string]interface{} "Invalid VM ID"
 // @Failure 404 {object} map[string]interface{} "VM not found"
 // @Failure 409 {object} map[string]interface{} "VM cannot be suspended in current state"
+// @Failure 412 {object} map[string]interface{} "VM no longer matches If-Match"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/v1/vms/{id}/suspend [post]
 func (h *VMHandler) SuspendVM(c *gin.Context) {
@@ -371,10 +606,12 @@ func (h *VMHandler) SuspendVM(c *gin.Context) {
 // @Produce json
 // @Param id path string true "VM ID" format(uuid)
 // @Param request body models.VMStateChangeRequest false "State change options"
-// @Success 202 {object} map[string]interface{} "VM resume initiated"
+// @Param If-Match header string false "ETag the VM must still have"
+// @Success 202 {object} models.Operation "VM resume initiated"
 // @Failure 400 {object} map[string]interface{} "Invalid VM ID"
 // @Failure 404 {object} map[string]interface{} "VM not found"
 // @Failure 409 {object} map[string]interface{} "VM cannot be resumed in current state"
+// @Failure 412 {object} map[string]interface{} "VM no longer matches If-Match"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/v1/vms/{id}/resume [post]
 func (h *VMHandler) ResumeVM(c *gin.Context) {
@@ -463,7 +700,7 @@ func (h *VMHandler) GetResourceSummary(c *gin.Context) {
 }
 
 // Helper method for state change operations
-func (h *VMHandler) changeVMState(c *gin.Context, operation string, serviceFunc func(context.Context, uuid.UUID, *models.VMStateChangeRequest) error) {
+func (h *VMHandler) changeVMState(c *gin.Context, operation string, serviceFunc func(context.Context, uuid.UUID, *models.VMStateChangeRequest) (*models.Operation, error)) {
 	requestID := requestid.Get(c)
 	log := h.logger.WithRequestID(requestID).WithOperation(operation + "-vm")
 
@@ -489,8 +726,17 @@ func (h *VMHandler) changeVMState(c *gin.Context, operation string, serviceFunc
 	} else {
 		req.UpdatedBy = "system"
 	}
+	req.RequestID = requestID
+	if req.ResourceVersion, err = ifMatchVersion(c); err != nil {
+		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
 
-	err = serviceFunc(c.Request.Context(), id, &req)
+	op, err := serviceFunc(c.Request.Context(), id, &req)
 	if err != nil {
 		log.Errorf("Failed to %s VM: %v", operation, err)
 		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
@@ -501,10 +747,34 @@ func (h *VMHandler) changeVMState(c *gin.Context, operation string, serviceFunc
 		return
 	}
 
-	log.Infof("VM %s operation initiated successfully: %s", operation, id)
+	log.Infof("VM %s operation initiated successfully: %s (operation: %s)", operation, id, op.ID)
+	c.Header("Location", operationLocation(op))
 	c.JSON(http.StatusAccepted, gin.H{
-		"message":    fmt.Sprintf("VM %s operation initiated", operation),
-		"request_id": requestID,
-		"vm_id":      id,
+		"data":         op,
+		"message":      fmt.Sprintf("VM %s operation initiated", operation),
+		"operation_id": op.ID,
+		"request_id":   requestID,
+		"vm_id":        id,
 	})
 }
+
+// ifMatchVersion reads the resource version required by the If-Match header.
+// Zero means the request carries no precondition.
+func ifMatchVersion(c *gin.Context) (int64, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	tag := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version < 1 {
+		return 0, errors.ErrPreconditionFailed.WithDetails(fmt.Sprintf("If-Match %s is not the ETag of a VM", header))
+	}
+	return version, nil
+}
+
+// operationLocation returns the URL clients poll to follow an operation
+func operationLocation(op *models.Operation) string {
+	return "/api/v1/operations/" + op.ID.String()
+}
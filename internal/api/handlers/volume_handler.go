@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/api/middleware"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/services"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// VolumeHandler handles block volume related HTTP requests
+type VolumeHandler struct {
+	volumeService services.VolumeService
+	logger        *logger.Logger
+}
+
+// NewVolumeHandler creates a new volume handler
+func NewVolumeHandler(volumeService services.VolumeService, logger *logger.Logger) *VolumeHandler {
+	return &VolumeHandler{
+		volumeService: volumeService,
+		logger:        logger.WithComponent("volume-handler"),
+	}
+}
+
+// CreateVolume creates a block volume
+// @Summary Create volume
+// @Description Create an empty block volume in the project. Its size counts towards the disk quota of the project.
+// @Tags Volumes
+// @Accept json
+// @Produce json
+// @Param request body models.VolumeCreateRequest true "Volume creation request"
+// @Success 201 {object} models.Volume "Volume created"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 409 {object} map[string]interface{} "Volume name taken or project quota exceeded"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Failure 502 {object} map[string]interface{} "Hypervisor error"
+// @Router /api/v1/volumes [post]
+func (h *VolumeHandler) CreateVolume(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("create-volume")
+
+	var req models.VolumeCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Invalid request body: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+	req.CreatedBy = actor(c)
+	req.ProjectID = middleware.GetProjectID(c)
+
+	volume, err := h.volumeService.CreateVolume(c.Request.Context(), &req)
+	if err != nil {
+		log.Warnf("Failed to create volume: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data":       volume,
+		"message":    "Volume created successfully",
+		"request_id": requestID,
+	})
+}
+
+// ListVolumes lists block volumes
+// @Summary List volumes
+// @Description Get the volumes of the caller's projects ordered by name
+// @Tags Volumes
+// @Produce json
+// @Param status query string false "Filter by status" Enums(available,in-use)
+// @Param vm_id query string false "Filter by the VM volumes are attached to" format(uuid)
+// @Success 200 {array} models.Volume "List of volumes"
+// @Failure 400 {object} map[string]interface{} "Invalid query parameters"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/volumes [get]
+func (h *VolumeHandler) ListVolumes(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("list-volumes")
+
+	var opts models.VolumeListOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		log.Warnf("Invalid query parameters: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+
+	volumes, err := h.volumeService.ListVolumes(c.Request.Context(), opts)
+	if err != nil {
+		log.Errorf("Failed to list volumes: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       volumes,
+		"request_id": requestID,
+	})
+}
+
+// GetVolume retrieves a block volume
+// @Summary Get volume
+// @Description Get a volume and the VM it is attached to
+// @Tags Volumes
+// @Produce json
+// @Param id path string true "Volume ID" format(uuid)
+// @Success 200 {object} models.Volume "Volume details"
+// @Failure 400 {object} map[string]interface{} "Invalid volume ID"
+// @Failure 404 {object} map[string]interface{} "Volume not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/volumes/{id} [get]
+func (h *VolumeHandler) GetVolume(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("get-volume")
+
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	volume, err := h.volumeService.GetVolume(c.Request.Context(), id)
+	if err != nil {
+		log.Warnf("Failed to get volume: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       volume,
+		"request_id": requestID,
+	})
+}
+
+// ResizeVolume grows a block volume
+// @Summary Resize volume
+// @Description Grow a volume. Volumes attached to a running VM are grown live; the guest has to grow its file system. Volumes cannot shrink.
+// @Tags Volumes
+// @Accept json
+// @Produce json
+// @Param id path string true "Volume ID" format(uuid)
+// @Param request body models.VolumeResizeRequest true "Volume resize request"
+// @Success 200 {object} models.Volume "Volume resized"
+// @Failure 400 {object} map[string]interface{} "Invalid request or smaller size"
+// @Failure 404 {object} map[string]interface{} "Volume not found"
+// @Failure 409 {object} map[string]interface{} "Project quota exceeded or VM in wrong state"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Failure 502 {object} map[string]interface{} "Hypervisor error"
+// @Router /api/v1/volumes/{id}/resize [post]
+func (h *VolumeHandler) ResizeVolume(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("resize-volume")
+
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.VolumeResizeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Invalid request body: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+	req.UpdatedBy = actor(c)
+	req.RequestID = requestID
+
+	volume, err := h.volumeService.ResizeVolume(c.Request.Context(), id, &req)
+	if err != nil {
+		log.Warnf("Failed to resize volume: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       volume,
+		"message":    "Volume resized successfully",
+		"request_id": requestID,
+	})
+}
+
+// DeleteVolume deletes a block volume
+// @Summary Delete volume
+// @Description Delete a detached volume and its data
+// @Tags Volumes
+// @Param id path string true "Volume ID" format(uuid)
+// @Success 204 "Volume deleted"
+// @Failure 400 {object} map[string]interface{} "Invalid volume ID"
+// @Failure 404 {object} map[string]interface{} "Volume not found"
+// @Failure 409 {object} map[string]interface{} "Volume is attached"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Failure 502 {object} map[string]interface{} "Hypervisor error"
+// @Router /api/v1/volumes/{id} [delete]
+func (h *VolumeHandler) DeleteVolume(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("delete-volume")
+
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.volumeService.DeleteVolume(c.Request.Context(), id); err != nil {
+		log.Warnf("Failed to delete volume: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AttachVolume attaches a block volume to a VM
+// @Summary Attach volume
+// @Description Attach an available volume to a stopped or running VM of the same project. Running VMs get the volume hot-plugged.
+// @Tags Volumes
+// @Accept json
+// @Produce json
+// @Param id path string true "Volume ID" format(uuid)
+// @Param request body models.VolumeAttachRequest true "Volume attach request"
+// @Success 200 {object} models.Volume "Volume attached"
+// @Failure 400 {object} map[string]interface{} "Invalid request or VM of another project"
+// @Failure 404 {object} map[string]interface{} "Volume or VM not found"
+// @Failure 409 {object} map[string]interface{} "Volume already attached or VM in wrong state"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Failure 502 {object} map[string]interface{} "Hypervisor error"
+// @Router /api/v1/volumes/{id}/attach [post]
+func (h *VolumeHandler) AttachVolume(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("attach-volume")
+
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.VolumeAttachRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Invalid request body: %v", err)
+		h.respondWithError(c, errors.ErrValidationFailed.WithDetails(err.Error()))
+		return
+	}
+	req.UpdatedBy = actor(c)
+	req.RequestID = requestID
+
+	volume, err := h.volumeService.AttachVolume(c.Request.Context(), id, &req)
+	if err != nil {
+		log.Warnf("Failed to attach volume: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       volume,
+		"message":    "Volume attached successfully",
+		"request_id": requestID,
+	})
+}
+
+// DetachVolume detaches a block volume from its VM
+// @Summary Detach volume
+// @Description Detach a volume from its stopped or running VM. Running VMs get the volume unplugged; the guest should unmount it first.
+// @Tags Volumes
+// @Produce json
+// @Param id path string true "Volume ID" format(uuid)
+// @Success 200 {object} models.Volume "Volume detached"
+// @Failure 400 {object} map[string]interface{} "Invalid volume ID or volume not attached"
+// @Failure 404 {object} map[string]interface{} "Volume not found"
+// @Failure 409 {object} map[string]interface{} "VM in wrong state"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Failure 502 {object} map[string]interface{} "Hypervisor error"
+// @Router /api/v1/volumes/{id}/detach [post]
+func (h *VolumeHandler) DetachVolume(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("detach-volume")
+
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	req := models.VolumeDetachRequest{UpdatedBy: actor(c), RequestID: requestID}
+	volume, err := h.volumeService.DetachVolume(c.Request.Context(), id, &req)
+	if err != nil {
+		log.Warnf("Failed to detach volume: %v", err)
+		h.respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       volume,
+		"message":    "Volume detached successfully",
+		"request_id": requestID,
+	})
+}
+
+// parseID parses a UUID path parameter, responding with an error if it is invalid
+func (h *VolumeHandler) parseID(c *gin.Context, param string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		h.logger.WithRequestID(requestid.Get(c)).Warnf("Invalid %s format: %s", param, c.Param(param))
+		h.respondWithError(c, errors.ErrInvalidInput.WithDetails("Invalid UUID format"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondWithError writes an error response
+func (h *VolumeHandler) respondWithError(c *gin.Context, err error) {
+	requestID := requestid.Get(c)
+	appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+	c.JSON(appErr.HTTPCode, gin.H{
+		"error":      appErr,
+		"request_id": requestID,
+	})
+}
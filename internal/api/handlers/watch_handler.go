@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/services"
+	"github.com/stackit/enterprise-vm-manager/internal/watch"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+	"golang.org/x/net/websocket"
+)
+
+// heartbeatEvent is sent on idle streams so proxies and clients can detect dead connections
+const heartbeatEvent = "heartbeat"
+
+// WatchHandler streams VM changes over Server-Sent Events or WebSocket
+type WatchHandler struct {
+	vmService         services.VMService
+	heartbeatInterval time.Duration
+	logger            *logger.Logger
+}
+
+// NewWatchHandler creates a new watch handler
+func NewWatchHandler(vmService services.VMService, heartbeatInterval time.Duration, logger *logger.Logger) *WatchHandler {
+	return &WatchHandler{
+		vmService:         vmService,
+		heartbeatInterval: heartbeatInterval,
+		logger:            logger.WithComponent("watch-handler"),
+	}
+}
+
+// WatchVMs streams changes of all VMs
+// @Summary Watch virtual machines
+// @Description Stream status changes, stats updates and deletions of VMs as Server-Sent Events, or as WebSocket messages when the request is a WebSocket upgrade
+// @Tags VMs
+// @Produce text/event-stream
+// @Param status query string false "Filter by status" Enums(pending,stopped,starting,running,stopping,suspended,error,deleting)
+// @Param node_id query string false "Filter by node ID"
+// @Param created_by query string false "Filter by creator"
+// @Param search query string false "Search in name and description"
+// @Param labelSelector query string false "Label selector, e.g. env=prod,tier in (web,api),!deprecated"
+// @Param resource_version query int false "Resume after this resource version (SSE clients may send Last-Event-ID instead)"
+// @Success 200 {object} watch.Event "Stream of VM events"
+// @Failure 400 {object} map[string]interface{} "Invalid query parameters"
+// @Failure 410 {object} map[string]interface{} "Resource version expired"
+// @Router /api/v1/vms/watch [get]
+func (h *WatchHandler) WatchVMs(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("watch-vms")
+
+	var opts models.VMListOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		log.Warnf("Invalid query parameters: %v", err)
+		appErr := errors.ErrValidationFailed.WithContext("request_id", requestID).WithDetails(err.Error())
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	resourceVersion, ok := h.resourceVersion(c)
+	if !ok {
+		return
+	}
+
+	sub, err := h.vmService.WatchVMs(c.Request.Context(), opts, resourceVersion)
+	if err != nil {
+		log.Warnf("Failed to start watch: %v", err)
+		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	h.stream(c, sub)
+}
+
+// WatchVM streams changes of a single VM
+// @Summary Watch virtual machine
+// @Description Stream status changes, stats updates and the deletion of a VM as Server-Sent Events, or as WebSocket messages when the request is a WebSocket upgrade
+// @Tags VMs
+// @Produce text/event-stream
+// @Param id path string true "VM ID" format(uuid)
+// @Param resource_version query int false "Resume after this resource version (SSE clients may send Last-Event-ID instead)"
+// @Success 200 {object} watch.Event "Stream of VM events"
+// @Failure 400 {object} map[string]interface{} "Invalid VM ID"
+// @Failure 404 {object} map[string]interface{} "VM not found"
+// @Failure 410 {object} map[string]interface{} "Resource version expired"
+// @Router /api/v1/vms/{id}/watch [get]
+func (h *WatchHandler) WatchVM(c *gin.Context) {
+	requestID := requestid.Get(c)
+	log := h.logger.WithRequestID(requestID).WithOperation("watch-vm")
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		log.Warnf("Invalid VM ID format: %s", idParam)
+		appErr := errors.ErrInvalidInput.WithContext("request_id", requestID).WithDetails("Invalid UUID format")
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	resourceVersion, ok := h.resourceVersion(c)
+	if !ok {
+		return
+	}
+
+	sub, err := h.vmService.WatchVM(c.Request.Context(), id, resourceVersion)
+	if err != nil {
+		log.Warnf("Failed to start watch: %v", err)
+		appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	h.stream(c, sub)
+}
+
+// resourceVersion reads the version to resume from, writing an error response if it is malformed
+func (h *WatchHandler) resourceVersion(c *gin.Context) (uint64, bool) {
+	value := c.Query("resource_version")
+	if value == "" {
+		// EventSource sends the ID of the last received event when it reconnects
+		value = c.GetHeader("Last-Event-ID")
+	}
+	if value == "" {
+		return 0, true
+	}
+
+	version, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		requestID := requestid.Get(c)
+		appErr := errors.ValidationError("resource_version", "Resource version must be a non-negative integer").
+			WithContext("request_id", requestID)
+		c.JSON(appErr.HTTPCode, gin.H{
+			"error":      appErr,
+			"request_id": requestID,
+		})
+		return 0, false
+	}
+	return version, true
+}
+
+// stream delivers subscription events using the transport requested by the client
+func (h *WatchHandler) stream(c *gin.Context, sub *watch.Subscription) {
+	defer sub.Close()
+
+	if strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+		h.streamWebSocket(c, sub)
+		return
+	}
+	h.streamSSE(c, sub)
+}
+
+// streamSSE writes events as Server-Sent Events until the client disconnects
+func (h *WatchHandler) streamSSE(c *gin.Context, sub *watch.Subscription) {
+	log := h.logger.WithRequestID(requestid.Get(c))
+
+	// The stream outlives the server write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Debugf("Failed to clear write deadline: %v", err)
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	h.deliver(c.Request.Context(), sub, func(event *watch.Event) error {
+		if event == nil {
+			_, err := io.WriteString(c.Writer, ": "+heartbeatEvent+"\n\n")
+			c.Writer.Flush()
+			return err
+		}
+
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.ResourceVersion, event.Type, data); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+}
+
+// streamWebSocket upgrades the connection and writes events as JSON messages
+func (h *WatchHandler) streamWebSocket(c *gin.Context, sub *watch.Subscription) {
+	server := websocket.Server{
+		Handler: func(conn *websocket.Conn) {
+			ctx, cancel := context.WithCancel(c.Request.Context())
+			defer cancel()
+
+			// The stream is one-way; reading only detects when the client goes away
+			go func() {
+				io.Copy(io.Discard, conn)
+				cancel()
+			}()
+
+			h.deliver(ctx, sub, func(event *watch.Event) error {
+				if event == nil {
+					return websocket.JSON.Send(conn, gin.H{"type": heartbeatEvent})
+				}
+				return websocket.JSON.Send(conn, event)
+			})
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// deliver passes events to send until the client disconnects or the subscription ends.
+// A nil event requests a heartbeat.
+func (h *WatchHandler) deliver(ctx context.Context, sub *watch.Subscription, send func(event *watch.Event) error) {
+	log := h.logger.WithOperation("watch")
+
+	ticker := time.NewTicker(h.heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case event, ok := <-sub.Events():
+			if !ok {
+				if sub.Overflowed() {
+					log.Warn("Watch client fell behind and was disconnected")
+				}
+				return
+			}
+			if err := send(&event); err != nil {
+				log.Debugf("Failed to send watch event: %v", err)
+				return
+			}
+
+		case <-ticker.C:
+			if err := send(nil); err != nil {
+				log.Debugf("Failed to send heartbeat: %v", err)
+				return
+			}
+		}
+	}
+}
@@ -1,8 +1,10 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,23 +12,58 @@ import (
 	"github.com/gin-contrib/requestid"
 	ginzap "github.com/gin-contrib/zap"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/auth"
 	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/internal/metrics"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/ratelimit"
+	"github.com/stackit/enterprise-vm-manager/internal/tenancy"
 	"github.com/stackit/enterprise-vm-manager/pkg/errors"
 	"github.com/stackit/enterprise-vm-manager/pkg/logger"
-	"golang.org/x/time/rate"
 )
 
+// Context keys under which the authenticated identity is stored
+const (
+	contextUserID    = "user_id"
+	contextUserRole  = "user_role"
+	contextUserRoles = "user_roles"
+	contextTenantID  = "tenant_id"
+	contextProjectID = "project_id"
+)
+
+// Authorizer decides whether a caller is granted a permission
+type Authorizer interface {
+	Authorize(ctx context.Context, subject string, roles []string, permission models.Permission) error
+}
+
+// ProjectResolver looks up projects and the projects a subject may access
+type ProjectResolver interface {
+	GetProject(ctx context.Context, name string) (*models.Project, error)
+	ProjectIDsForSubject(ctx context.Context, subject string, includeDefault bool) ([]uuid.UUID, error)
+}
+
 // MiddlewareManager manages all middleware components
 type MiddlewareManager struct {
-	cfg    *config.Config
-	logger *logger.Logger
+	cfg           *config.Config
+	logger        *logger.Logger
+	metrics       *metrics.Metrics
+	authenticator *auth.Authenticator
+	authorizer    Authorizer
+	projects      ProjectResolver
+	limiter       ratelimit.Limiter
 }
 
 // NewMiddlewareManager creates a new middleware manager
-func NewMiddlewareManager(cfg *config.Config, logger *logger.Logger) *MiddlewareManager {
+func NewMiddlewareManager(cfg *config.Config, logger *logger.Logger, metrics *metrics.Metrics, authenticator *auth.Authenticator, authorizer Authorizer, projects ProjectResolver, limiter ratelimit.Limiter) *MiddlewareManager {
 	return &MiddlewareManager{
-		cfg:    cfg,
-		logger: logger,
+		cfg:           cfg,
+		logger:        logger,
+		metrics:       metrics,
+		authenticator: authenticator,
+		authorizer:    authorizer,
+		projects:      projects,
+		limiter:       limiter,
 	}
 }
 
@@ -94,26 +131,43 @@ func (m *MiddlewareManager) RecoveryMiddleware() gin.HandlerFunc {
 	})
 }
 
-// RateLimitMiddleware implements rate limiting
+// RateLimitMiddleware limits the request rate of each client with a token bucket
 func (m *MiddlewareManager) RateLimitMiddleware() gin.HandlerFunc {
-	limiter := rate.NewLimiter(
-		rate.Limit(m.cfg.Server.RateLimit.RPS),
-		m.cfg.Server.RateLimit.Burst,
-	)
+	limiter := m.limiter
+	if limiter == nil {
+		limiter = ratelimit.NewMemoryLimiter(m.cfg.Server.RateLimit.Cleanup)
+	}
 
 	return func(c *gin.Context) {
-		if !limiter.Allow() {
-			requestID := requestid.Get(c)
+		requestID := requestid.Get(c)
+		key, limit := m.rateLimitClient(c)
 
+		result, err := limiter.Allow(c.Request.Context(), key, limit)
+		if err != nil {
+			// Rather serve requests unthrottled than fail them while the limiter is unavailable
 			m.logger.WithField("request_id", requestID).
-				WithField("client_ip", c.ClientIP()).
+				Warnf("Rate limiter unavailable, allowing request: %v", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			retryAfter := strconv.Itoa(ceilSeconds(result.RetryAfter))
+
+			m.logger.WithField("request_id", requestID).
+				WithField("client", key).
 				Warn("Rate limit exceeded")
 
 			err := errors.ErrRateLimitExceeded.WithContext("request_id", requestID)
+			c.Header("Retry-After", retryAfter)
 			c.JSON(err.HTTPCode, gin.H{
 				"error":       err,
 				"request_id":  requestID,
-				"retry_after": "60",
+				"retry_after": retryAfter,
 			})
 			c.Abort()
 			return
@@ -122,6 +176,34 @@ func (m *MiddlewareManager) RateLimitMiddleware() gin.HandlerFunc {
 	}
 }
 
+// rateLimitClient returns the bucket key of the caller and the limits that apply to it.
+// API clients are keyed by name and token holders by subject; callers whose credentials
+// are missing or invalid are keyed by IP and left to the authentication middleware.
+func (m *MiddlewareManager) rateLimitClient(c *gin.Context) (string, ratelimit.Limit) {
+	cfg := m.cfg.Server.RateLimit
+	limit := ratelimit.Limit{RPS: cfg.RPS, Burst: cfg.Burst}
+
+	if !m.cfg.Auth.Enabled || m.authenticator == nil {
+		return "ip:" + c.ClientIP(), limit
+	}
+
+	identity, err := m.authenticate(c)
+	if err != nil {
+		return "ip:" + c.ClientIP(), limit
+	}
+
+	for _, role := range identity.Roles {
+		if tier, ok := cfg.Tiers[role]; ok && tier.RPS > limit.RPS {
+			limit = ratelimit.Limit{RPS: tier.RPS, Burst: tier.Burst}
+		}
+	}
+
+	if c.GetHeader(m.cfg.Auth.APIKeyHeader) != "" {
+		return "key:" + identity.Subject, limit
+	}
+	return "user:" + identity.Subject, limit
+}
+
 // SecurityHeadersMiddleware adds security headers
 func (m *MiddlewareManager) SecurityHeadersMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -149,45 +231,103 @@ func (m *MiddlewareManager) AuthenticationMiddleware() gin.HandlerFunc {
 
 		requestID := requestid.Get(c)
 
-		// Check API key header
-		apiKey := c.GetHeader(m.cfg.Auth.APIKeyHeader)
-		if apiKey == "" {
-			authHeader := c.GetHeader("Authorization")
-			if authHeader == "" {
-				err := errors.ErrUnauthorized.WithContext("request_id", requestID)
-				c.JSON(err.HTTPCode, gin.H{
-					"error":      err,
-					"request_id": requestID,
-				})
-				c.Abort()
-				return
-			}
+		identity, err := m.authenticate(c)
+		if err != nil {
+			m.logger.WithField("request_id", requestID).
+				WithField("client_ip", c.ClientIP()).
+				Warnf("Authentication failed: %v", err)
 
-			// Extract Bearer token
-			if strings.HasPrefix(authHeader, "Bearer ") {
-				apiKey = strings.TrimPrefix(authHeader, "Bearer ")
-			}
+			appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+			c.JSON(appErr.HTTPCode, gin.H{
+				"error":      appErr,
+				"request_id": requestID,
+			})
+			c.Abort()
+			return
+		}
+
+		SetIdentity(c, identity)
+
+		c.Next()
+	}
+}
+
+// RequirePermission rejects callers whose roles do not grant the permission.
+// Every caller is allowed when authentication is disabled.
+func (m *MiddlewareManager) RequirePermission(permission models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.cfg.Auth.Enabled {
+			c.Next()
+			return
 		}
 
-		// Validate API key
-		if !m.isValidAPIKey(apiKey) {
+		userID := GetUserID(c)
+		if err := m.authorizer.Authorize(c.Request.Context(), userID, GetUserRoles(c), permission); err != nil {
+			requestID := requestid.Get(c)
+
 			m.logger.WithField("request_id", requestID).
-				WithField("client_ip", c.ClientIP()).
-				Warn("Invalid API key provided")
+				WithField("user_id", userID).
+				Warnf("Authorization failed: %v", err)
 
-			err := errors.ErrInvalidToken.WithContext("request_id", requestID)
-			c.JSON(err.HTTPCode, gin.H{
-				"error":      err,
+			appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+			c.JSON(appErr.HTTPCode, gin.H{
+				"error":      appErr,
 				"request_id": requestID,
 			})
 			c.Abort()
 			return
 		}
 
-		// Set user context (simplified - in real implementation, extract from JWT)
-		c.Set("user_id", "api-user")
-		c.Set("user_role", "admin")
+		c.Next()
+	}
+}
+
+// ProjectScopeMiddleware restricts data access to the projects of the caller.
+// The project of the route, or the default project on routes outside a project,
+// is stored in the context. On project routes the request is scoped to that
+// project, elsewhere to all projects the caller is a member of. Only members of
+// the default project may use the routes outside a project, unless every caller
+// is treated as a member of it by configuration. Callers holding
+// every permission, and all callers when authentication is disabled, are only
+// restricted by the project of the route.
+func (m *MiddlewareManager) ProjectScopeMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		name := c.Param("project")
+		if name == "" {
+			name = models.DefaultProjectName
+		}
+
+		project, err := m.projects.GetProject(ctx, name)
+		if err != nil {
+			m.abortWithError(c, err)
+			return
+		}
+
+		var projectIDs []uuid.UUID
+		restricted := m.cfg.Auth.Enabled &&
+			m.authorizer.Authorize(ctx, GetUserID(c), GetUserRoles(c), models.PermissionAll) != nil
+		if restricted {
+			projectIDs, err = m.projects.ProjectIDsForSubject(ctx, GetUserID(c), m.cfg.Auth.OpenDefaultProject)
+			if err != nil {
+				m.abortWithError(c, err)
+				return
+			}
+			if !containsProject(projectIDs, project.ID) {
+				// Projects of other teams are indistinguishable from missing ones
+				m.abortWithError(c, errors.NotFoundError("Project", name))
+				return
+			}
+		}
+		if c.Param("project") != "" {
+			projectIDs = []uuid.UUID{project.ID}
+		}
+		if restricted || c.Param("project") != "" {
+			c.Request = c.Request.WithContext(tenancy.WithProjects(ctx, projectIDs))
+		}
 
+		c.Set(contextProjectID, project.ID)
 		c.Next()
 	}
 }
@@ -233,7 +373,13 @@ func (m *MiddlewareManager) MetricsMiddleware() gin.HandlerFunc {
 		duration := time.Since(start)
 		status := c.Writer.Status()
 
-		// In real implementation, this would update Prometheus metrics
+		// Label by route template so that IDs in paths do not create new series
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		m.metrics.ObserveRequest(c.Request.Method, route, status, duration)
+
 		m.logger.WithFields(map[string]interface{}{
 			"method":      c.Request.Method,
 			"path":        c.Request.URL.Path,
@@ -339,37 +485,99 @@ func (m *MiddlewareManager) TimeoutMiddleware(timeout time.Duration) gin.Handler
 
 // Helper methods
 
-func (m *MiddlewareManager) isValidAPIKey(apiKey string) bool {
-	if apiKey == "" {
-		return false
+// authenticate identifies the caller by API key or by a bearer JWT
+func (m *MiddlewareManager) authenticate(c *gin.Context) (*auth.Identity, error) {
+	if apiKey := c.GetHeader(m.cfg.Auth.APIKeyHeader); apiKey != "" {
+		return m.authenticator.AuthenticateAPIKey(apiKey)
+	}
+
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		return nil, errors.ErrUnauthorized
+	}
+
+	token, ok := strings.CutPrefix(authHeader, "Bearer ")
+	if !ok {
+		return nil, errors.ErrInvalidToken.WithDetails("Authorization header must use the Bearer scheme")
 	}
 
-	// Check against configured API keys
-	for _, validKey := range m.cfg.Auth.APIKeys {
-		if apiKey == validKey {
+	return m.authenticator.ParseToken(token)
+}
+
+// abortWithError writes an error response and stops the handler chain
+func (m *MiddlewareManager) abortWithError(c *gin.Context, err error) {
+	requestID := requestid.Get(c)
+	appErr := errors.ToAppError(err).WithContext("request_id", requestID)
+	c.JSON(appErr.HTTPCode, gin.H{
+		"error":      appErr,
+		"request_id": requestID,
+	})
+	c.Abort()
+}
+
+// containsProject reports whether the project is in the list
+func containsProject(projectIDs []uuid.UUID, projectID uuid.UUID) bool {
+	for _, id := range projectIDs {
+		if id == projectID {
 			return true
 		}
 	}
-
 	return false
 }
 
+// ceilSeconds rounds a duration up to whole seconds for HTTP headers
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
+
+// SetIdentity stores the authenticated identity in the context
+func SetIdentity(c *gin.Context, identity *auth.Identity) {
+	c.Set(contextUserID, identity.Subject)
+	c.Set(contextUserRoles, identity.Roles)
+	c.Set(contextTenantID, identity.Tenant)
+	if len(identity.Roles) > 0 {
+		c.Set(contextUserRole, identity.Roles[0])
+	}
+}
+
 // GetUserID extracts user ID from context
 func GetUserID(c *gin.Context) string {
-	userID, exists := c.Get("user_id")
-	if !exists {
-		return ""
-	}
-	return userID.(string)
+	return c.GetString(contextUserID)
 }
 
-// GetUserRole extracts user role from context
+// GetUserRole extracts the primary user role from context
 func GetUserRole(c *gin.Context) string {
-	userRole, exists := c.Get("user_role")
-	if !exists {
-		return ""
+	return c.GetString(contextUserRole)
+}
+
+// GetUserRoles extracts all user roles from context
+func GetUserRoles(c *gin.Context) []string {
+	return c.GetStringSlice(contextUserRoles)
+}
+
+// GetTenantID extracts the tenant of the user from context
+func GetTenantID(c *gin.Context) string {
+	return c.GetString(contextTenantID)
+}
+
+// GetProjectID extracts the project of the request from context
+func GetProjectID(c *gin.Context) uuid.UUID {
+	if value, ok := c.Get(contextProjectID); ok {
+		if projectID, ok := value.(uuid.UUID); ok {
+			return projectID
+		}
 	}
-	return userRole.(string)
+	return uuid.Nil
+}
+
+// HasRole reports whether the user holds the given role
+func HasRole(c *gin.Context, role string) bool {
+	for _, r := range GetUserRoles(c) {
+		if r == role {
+			return true
+		}
+	}
+	return false
 }
 
 // RequireAuth middleware that requires authentication
@@ -393,8 +601,7 @@ func RequireAuth() gin.HandlerFunc {
 // RequireRole middleware that requires specific role
 func RequireRole(requiredRole string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userRole := GetUserRole(c)
-		if userRole != requiredRole {
+		if !HasRole(c, requiredRole) {
 			requestID := requestid.Get(c)
 			err := errors.ErrInsufficientPerm.WithContext("request_id", requestID)
 			c.JSON(err.HTTPCode, gin.H{
@@ -1,10 +1,17 @@
 package routes
 
 import (
+	"net/http"
+	"runtime"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/stackit/enterprise-vm-manager/internal/api/handlers"
 	"github.com/stackit/enterprise-vm-manager/internal/api/middleware"
 	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/internal/health"
+	"github.com/stackit/enterprise-vm-manager/internal/metrics"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
 	"github.com/stackit/enterprise-vm-manager/pkg/logger"
 	"github.com/swaggo/files"
 	"github.com/swaggo/gin-swagger"
@@ -12,10 +19,27 @@ import (
 
 // Router manages API routes
 type Router struct {
-	cfg        *config.Config
-	logger     *logger.Logger
-	vmHandler  *handlers.VMHandler
-	middleware *middleware.MiddlewareManager
+	cfg             *config.Config
+	logger          *logger.Logger
+	vmHandler       *handlers.VMHandler
+	nodeHandler     *handlers.NodeHandler
+	opHandler       *handlers.OperationHandler
+	eventHandler    *handlers.EventHandler
+	watchHandler    *handlers.WatchHandler
+	snapshotHandler *handlers.SnapshotHandler
+	authHandler     *handlers.AuthHandler
+	rbacHandler     *handlers.RBACHandler
+	projHandler     *handlers.ProjectHandler
+	quotaHandler    *handlers.QuotaHandler
+	flavorHandler   *handlers.FlavorHandler
+	imageHandler    *handlers.ImageHandler
+	volumeHandler   *handlers.VolumeHandler
+	networkHandler  *handlers.NetworkHandler
+	middleware      *middleware.MiddlewareManager
+	metrics         *metrics.Metrics
+	health          *health.Checker
+	build           health.BuildInfo
+	startedAt       time.Time
 }
 
 // NewRouter creates a new router
@@ -23,13 +47,46 @@ func NewRouter(
 	cfg *config.Config,
 	logger *logger.Logger,
 	vmHandler *handlers.VMHandler,
+	nodeHandler *handlers.NodeHandler,
+	opHandler *handlers.OperationHandler,
+	eventHandler *handlers.EventHandler,
+	watchHandler *handlers.WatchHandler,
+	snapshotHandler *handlers.SnapshotHandler,
+	authHandler *handlers.AuthHandler,
+	rbacHandler *handlers.RBACHandler,
+	projHandler *handlers.ProjectHandler,
+	quotaHandler *handlers.QuotaHandler,
+	flavorHandler *handlers.FlavorHandler,
+	imageHandler *handlers.ImageHandler,
+	volumeHandler *handlers.VolumeHandler,
+	networkHandler *handlers.NetworkHandler,
 	middlewareManager *middleware.MiddlewareManager,
+	metrics *metrics.Metrics,
+	healthChecker *health.Checker,
+	build health.BuildInfo,
 ) *Router {
 	return &Router{
-		cfg:        cfg,
-		logger:     logger,
-		vmHandler:  vmHandler,
-		middleware: middlewareManager,
+		cfg:             cfg,
+		logger:          logger,
+		vmHandler:       vmHandler,
+		nodeHandler:     nodeHandler,
+		opHandler:       opHandler,
+		eventHandler:    eventHandler,
+		watchHandler:    watchHandler,
+		snapshotHandler: snapshotHandler,
+		authHandler:     authHandler,
+		rbacHandler:     rbacHandler,
+		projHandler:     projHandler,
+		quotaHandler:    quotaHandler,
+		flavorHandler:   flavorHandler,
+		imageHandler:    imageHandler,
+		volumeHandler:   volumeHandler,
+		networkHandler:  networkHandler,
+		middleware:      middlewareManager,
+		metrics:         metrics,
+		health:          healthChecker,
+		build:           build,
+		startedAt:       time.Now(),
 	}
 }
 
@@ -41,6 +98,11 @@ func (r *Router) SetupRoutes(engine *gin.Engine) {
 	// Health and system routes (no auth required)
 	r.setupSystemRoutes(engine)
 
+	// Token issuing routes (authenticated by API key)
+	if r.cfg.Auth.Enabled && r.authHandler != nil {
+		r.setupAuthRoutes(engine)
+	}
+
 	// API routes
 	r.setupAPIRoutes(engine)
 
@@ -63,11 +125,18 @@ func (r *Router) setupSystemRoutes(engine *gin.Engine) {
 	engine.GET("/version", r.versionInfo)
 
 	// Metrics endpoint (if enabled)
-	if r.cfg.Metrics.Enabled {
-		engine.GET(r.cfg.Metrics.Path, r.metricsHandler)
+	if r.cfg.Metrics.Enabled && r.metrics != nil {
+		engine.GET(r.cfg.Metrics.Path, gin.WrapH(r.metrics.Handler()))
 	}
 }
 
+// setupAuthRoutes sets up token issuing routes outside the token-authenticated API group
+func (r *Router) setupAuthRoutes(engine *gin.Engine) {
+	authGroup := engine.Group("/api/v1/auth")
+
+	authGroup.POST("/token", r.authHandler.IssueToken)
+}
+
 // setupAPIRoutes sets up main API routes
 func (r *Router) setupAPIRoutes(engine *gin.Engine) {
 	// API v1 group
@@ -85,39 +154,288 @@ func (r *Router) setupAPIRoutes(engine *gin.Engine) {
 	// VM management routes
 	r.setupVMRoutes(v1)
 
+	// Project and project-scoped VM routes
+	r.setupProjectRoutes(v1)
+
+	// Project quota usage routes
+	r.setupQuotaRoutes(v1)
+
+	// Flavor catalog routes
+	r.setupFlavorRoutes(v1)
+
+	// Image catalog routes
+	r.setupImageRoutes(v1)
+
+	// Block volume routes
+	r.setupVolumeRoutes(v1)
+
+	// Virtual network routes
+	r.setupNetworkRoutes(v1)
+
+	// Compute node inventory routes
+	r.setupNodeRoutes(v1)
+
+	// Asynchronous operation routes
+	r.setupOperationRoutes(v1)
+
+	// VM audit trail routes
+	r.setupEventRoutes(v1)
+
 	// System statistics routes
 	r.setupStatsRoutes(v1)
+
+	// Role and role binding management routes
+	r.setupRBACRoutes(v1)
 }
 
-// setupVMRoutes sets up VM-related routes
+// setupVMRoutes sets up VM-related routes of the default project and of all projects of the caller
 func (r *Router) setupVMRoutes(rg *gin.RouterGroup) {
-	vms := rg.Group("/vms")
+	r.registerVMRoutes(rg.Group("/vms", r.middleware.ProjectScopeMiddleware()))
+}
+
+// setupProjectRoutes sets up project management and project-scoped VM, volume and network routes
+func (r *Router) setupProjectRoutes(rg *gin.RouterGroup) {
+	if r.projHandler == nil {
+		return
+	}
+
+	projects := rg.Group("/projects", r.middleware.ProjectScopeMiddleware())
+	can := r.middleware.RequirePermission
+
+	projects.GET("", can(models.PermissionProjectRead), r.projHandler.ListProjects)
+	projects.POST("", can(models.PermissionProjectWrite), r.projHandler.CreateProject)
+
+	project := projects.Group("/:project")
+	project.GET("", can(models.PermissionProjectRead), r.projHandler.GetProject)
+	project.DELETE("", can(models.PermissionProjectWrite), r.projHandler.DeleteProject)
+
+	// Membership management
+	project.GET("/members", can(models.PermissionProjectRead), r.projHandler.ListMembers)
+	project.POST("/members", can(models.PermissionProjectWrite), r.projHandler.AddMember)
+	project.DELETE("/members/:subject", can(models.PermissionProjectWrite), r.projHandler.RemoveMember)
+
+	// Quota management
+	if r.quotaHandler != nil {
+		project.GET("/quota", can(models.PermissionQuotaRead), r.quotaHandler.GetQuota)
+		project.PUT("/quota", can(models.PermissionQuotaWrite), r.quotaHandler.UpdateQuota)
+	}
+
+	// Flavors the project may use
+	if r.flavorHandler != nil {
+		project.GET("/flavors", can(models.PermissionFlavorRead), r.flavorHandler.GetProjectFlavors)
+		project.PUT("/flavors", can(models.PermissionFlavorWrite), r.flavorHandler.UpdateProjectFlavors)
+	}
+
+	// VMs of the project
+	r.registerVMRoutes(project.Group("/vms"))
+
+	// Volumes of the project
+	if r.volumeHandler != nil {
+		r.registerVolumeRoutes(project.Group("/volumes"))
+	}
+
+	// Networks of the project
+	if r.networkHandler != nil {
+		r.registerNetworkRoutes(project.Group("/networks"))
+	}
+}
+
+// setupQuotaRoutes sets up the quota usage view of the caller's projects
+func (r *Router) setupQuotaRoutes(rg *gin.RouterGroup) {
+	if r.quotaHandler == nil {
+		return
+	}
+
+	rg.GET("/quotas",
+		r.middleware.ProjectScopeMiddleware(),
+		r.middleware.RequirePermission(models.PermissionQuotaRead),
+		r.quotaHandler.ListQuotas,
+	)
+}
+
+// setupFlavorRoutes sets up the flavor catalog routes
+func (r *Router) setupFlavorRoutes(rg *gin.RouterGroup) {
+	if r.flavorHandler == nil {
+		return
+	}
+
+	flavors := rg.Group("/flavors")
+	can := r.middleware.RequirePermission
+
+	flavors.GET("", can(models.PermissionFlavorRead), r.flavorHandler.ListFlavors)
+	flavors.GET("/:name", can(models.PermissionFlavorRead), r.flavorHandler.GetFlavor)
+	flavors.POST("", can(models.PermissionFlavorWrite), r.flavorHandler.CreateFlavor)
+	flavors.PUT("/:name", can(models.PermissionFlavorWrite), r.flavorHandler.UpdateFlavor)
+	flavors.DELETE("/:name", can(models.PermissionFlavorWrite), r.flavorHandler.DeleteFlavor)
+}
+
+// setupImageRoutes sets up the image catalog routes
+func (r *Router) setupImageRoutes(rg *gin.RouterGroup) {
+	if r.imageHandler == nil {
+		return
+	}
+
+	images := rg.Group("/images", r.middleware.ProjectScopeMiddleware())
+	can := r.middleware.RequirePermission
+
+	images.GET("", can(models.PermissionImageRead), r.imageHandler.ListImages)
+	images.GET("/:name", can(models.PermissionImageRead), r.imageHandler.GetImage)
+	images.POST("", can(models.PermissionImageWrite), r.imageHandler.CreateImage)
+	images.PUT("/:name", can(models.PermissionImageWrite), r.imageHandler.UpdateImage)
+	images.DELETE("/:name", can(models.PermissionImageWrite), r.imageHandler.DeleteImage)
+
+	// Image files
+	images.GET("/:name/upload", can(models.PermissionImageWrite), r.imageHandler.GetUpload)
+	images.POST("/:name/upload", can(models.PermissionImageWrite), r.imageHandler.UploadImage)
+	images.GET("/:name/download", can(models.PermissionImageRead), r.imageHandler.DownloadImage)
+}
+
+// setupVolumeRoutes sets up block volume routes of the default project and of all projects of the caller
+func (r *Router) setupVolumeRoutes(rg *gin.RouterGroup) {
+	if r.volumeHandler == nil {
+		return
+	}
+
+	r.registerVolumeRoutes(rg.Group("/volumes", r.middleware.ProjectScopeMiddleware()))
+}
+
+// registerVolumeRoutes registers the volume routes on a group
+func (r *Router) registerVolumeRoutes(volumes *gin.RouterGroup) {
+	can := r.middleware.RequirePermission
+
+	volumes.GET("", can(models.PermissionVolumeRead), r.volumeHandler.ListVolumes)
+	volumes.GET("/:id", can(models.PermissionVolumeRead), r.volumeHandler.GetVolume)
+	volumes.POST("", can(models.PermissionVolumeWrite), r.volumeHandler.CreateVolume)
+	volumes.DELETE("/:id", can(models.PermissionVolumeWrite), r.volumeHandler.DeleteVolume)
+	volumes.POST("/:id/resize", can(models.PermissionVolumeWrite), r.volumeHandler.ResizeVolume)
+
+	// Attachments
+	volumes.POST("/:id/attach", can(models.PermissionVolumeWrite), r.volumeHandler.AttachVolume)
+	volumes.POST("/:id/detach", can(models.PermissionVolumeWrite), r.volumeHandler.DetachVolume)
+}
+
+// setupNetworkRoutes sets up virtual network routes of the default project and of all projects of the caller
+func (r *Router) setupNetworkRoutes(rg *gin.RouterGroup) {
+	if r.networkHandler == nil {
+		return
+	}
+
+	r.registerNetworkRoutes(rg.Group("/networks", r.middleware.ProjectScopeMiddleware()))
+}
+
+// registerNetworkRoutes registers the network routes on a group
+func (r *Router) registerNetworkRoutes(networks *gin.RouterGroup) {
+	can := r.middleware.RequirePermission
+
+	networks.GET("", can(models.PermissionNetworkRead), r.networkHandler.ListNetworks)
+	networks.GET("/:id", can(models.PermissionNetworkRead), r.networkHandler.GetNetwork)
+	networks.POST("", can(models.PermissionNetworkWrite), r.networkHandler.CreateNetwork)
+	networks.PUT("/:id", can(models.PermissionNetworkWrite), r.networkHandler.UpdateNetwork)
+	networks.DELETE("/:id", can(models.PermissionNetworkWrite), r.networkHandler.DeleteNetwork)
+
+	// Address allocations
+	networks.GET("/:id/interfaces", can(models.PermissionNetworkRead), r.networkHandler.ListInterfaces)
+}
+
+// registerVMRoutes registers the VM routes on a group
+func (r *Router) registerVMRoutes(vms *gin.RouterGroup) {
+	can := r.middleware.RequirePermission
 
 	// CRUD operations
-	vms.POST("", r.vmHandler.CreateVM)
-	vms.GET("", r.vmHandler.ListVMs)
-	vms.GET("/:id", r.vmHandler.GetVM)
-	vms.PUT("/:id", r.vmHandler.UpdateVM)
-	vms.DELETE("/:id", r.vmHandler.DeleteVM)
+	vms.POST("", can(models.PermissionVMCreate), r.vmHandler.CreateVM)
+	vms.GET("", can(models.PermissionVMRead), r.vmHandler.ListVMs)
+	vms.GET("/:id", can(models.PermissionVMRead), r.vmHandler.GetVM)
+	vms.PUT("/:id", can(models.PermissionVMUpdate), r.vmHandler.UpdateVM)
+	vms.PATCH("/:id", can(models.PermissionVMUpdate), r.vmHandler.PatchVM)
+	vms.DELETE("/:id", can(models.PermissionVMDelete), r.vmHandler.DeleteVM)
+	vms.POST("/:id/clone", can(models.PermissionVMCreate), r.vmHandler.CloneVM)
+
+	// Change streams
+	vms.GET("/watch", can(models.PermissionVMRead), r.watchHandler.WatchVMs)
+	vms.GET("/:id/watch", can(models.PermissionVMRead), r.watchHandler.WatchVM)
 
 	// State management operations
-	vms.POST("/:id/start", r.vmHandler.StartVM)
-	vms.POST("/:id/stop", r.vmHandler.StopVM)
-	vms.POST("/:id/restart", r.vmHandler.RestartVM)
-	vms.POST("/:id/suspend", r.vmHandler.SuspendVM)
-	vms.POST("/:id/resume", r.vmHandler.ResumeVM)
+	vms.POST("/:id/start", can(models.PermissionVMStart), r.vmHandler.StartVM)
+	vms.POST("/:id/stop", can(models.PermissionVMStop), r.vmHandler.StopVM)
+	vms.POST("/:id/restart", can(models.PermissionVMRestart), r.vmHandler.RestartVM)
+	vms.POST("/:id/suspend", can(models.PermissionVMSuspend), r.vmHandler.SuspendVM)
+	vms.POST("/:id/resume", can(models.PermissionVMResume), r.vmHandler.ResumeVM)
 
 	// Statistics and monitoring
-	vms.GET("/:id/stats", r.vmHandler.GetVMStats)
+	vms.GET("/:id/stats", can(models.PermissionVMRead), r.vmHandler.GetVMStats)
+
+	// Audit trail
+	vms.GET("/:id/events", can(models.PermissionEventRead), r.eventHandler.ListVMEvents)
+
+	// Snapshots
+	snapshots := vms.Group("/:id/snapshots")
+	snapshots.POST("", can(models.PermissionSnapshotWrite), r.snapshotHandler.CreateSnapshot)
+	snapshots.GET("", can(models.PermissionSnapshotRead), r.snapshotHandler.ListSnapshots)
+	snapshots.GET("/:snapshot_id", can(models.PermissionSnapshotRead), r.snapshotHandler.GetSnapshot)
+	snapshots.DELETE("/:snapshot_id", can(models.PermissionSnapshotWrite), r.snapshotHandler.DeleteSnapshot)
+	snapshots.POST("/:snapshot_id/revert", can(models.PermissionSnapshotRevert), r.snapshotHandler.RevertSnapshot)
+}
+
+// setupNodeRoutes sets up compute node routes
+func (r *Router) setupNodeRoutes(rg *gin.RouterGroup) {
+	nodes := rg.Group("/nodes")
+	can := r.middleware.RequirePermission
+
+	nodes.POST("", can(models.PermissionNodeWrite), r.nodeHandler.RegisterNode)
+	nodes.GET("", can(models.PermissionNodeRead), r.nodeHandler.ListNodes)
+	nodes.GET("/:name", can(models.PermissionNodeRead), r.nodeHandler.GetNode)
+	nodes.POST("/:name/heartbeat", can(models.PermissionNodeWrite), r.nodeHandler.Heartbeat)
+}
+
+// setupOperationRoutes sets up operation tracking routes
+func (r *Router) setupOperationRoutes(rg *gin.RouterGroup) {
+	operations := rg.Group("/operations",
+		r.middleware.ProjectScopeMiddleware(),
+		r.middleware.RequirePermission(models.PermissionOperationRead),
+	)
+
+	operations.GET("", r.opHandler.ListOperations)
+	operations.GET("/:id", r.opHandler.GetOperation)
+}
+
+// setupEventRoutes sets up VM audit trail routes
+func (r *Router) setupEventRoutes(rg *gin.RouterGroup) {
+	rg.GET("/events",
+		r.middleware.ProjectScopeMiddleware(),
+		r.middleware.RequirePermission(models.PermissionEventRead),
+		r.eventHandler.ListEvents,
+	)
 }
 
 // setupStatsRoutes sets up statistics routes
 func (r *Router) setupStatsRoutes(rg *gin.RouterGroup) {
-	stats := rg.Group("/stats")
+	stats := rg.Group("/stats",
+		r.middleware.ProjectScopeMiddleware(),
+		r.middleware.RequirePermission(models.PermissionStatsRead),
+	)
 
 	stats.GET("/summary", r.vmHandler.GetResourceSummary)
 }
 
+// setupRBACRoutes sets up role and role binding management routes
+func (r *Router) setupRBACRoutes(rg *gin.RouterGroup) {
+	if r.rbacHandler == nil {
+		return
+	}
+
+	rbac := rg.Group("/rbac", r.middleware.RequirePermission(models.PermissionRBACManage))
+
+	rbac.GET("/roles", r.rbacHandler.ListRoles)
+	rbac.POST("/roles", r.rbacHandler.CreateRole)
+	rbac.GET("/roles/:name", r.rbacHandler.GetRole)
+	rbac.PUT("/roles/:name", r.rbacHandler.UpdateRole)
+	rbac.DELETE("/roles/:name", r.rbacHandler.DeleteRole)
+
+	rbac.GET("/bindings", r.rbacHandler.ListBindings)
+	rbac.POST("/bindings", r.rbacHandler.CreateBinding)
+	rbac.DELETE("/bindings/:id", r.rbacHandler.DeleteBinding)
+}
+
 // setupDocumentationRoutes sets up API documentation
 func (r *Router) setupDocumentationRoutes(engine *gin.Engine) {
 	// Swagger documentation
@@ -136,27 +454,31 @@ func (r *Router) setupDocumentationRoutes(engine *gin.Engine) {
 
 // healthCheck returns the health status of the application
 func (r *Router) healthCheck(c *gin.Context) {
-	c.JSON(200, gin.H{
-		"status":    "ok",
-		"service":   "enterprise-vm-manager",
-		"version":   "1.0.0",
-		"timestamp": "2025-10-15T22:00:00Z",
+	c.JSON(http.StatusOK, gin.H{
+		"status":         "ok",
+		"service":        "enterprise-vm-manager",
+		"version":        r.build.Version,
+		"timestamp":      time.Now().UTC().Format(time.RFC3339),
+		"uptime_seconds": int64(time.Since(r.startedAt).Seconds()),
 	})
 }
 
-// readinessCheck checks if the application is ready to serve traffic
+// readinessCheck checks if the application's dependencies are able to serve traffic
 func (r *Router) readinessCheck(c *gin.Context) {
-	// In a real implementation, this would check:
-	// - Database connectivity
-	// - External service dependencies
-	// - Cache availability
+	report := r.health.Run(c.Request.Context())
 
-	c.JSON(200, gin.H{
+	if !report.Healthy {
+		r.logger.Warnf("Readiness check failed: %+v", report.Checks)
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status": "not_ready",
+			"checks": report.Checks,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
 		"status": "ready",
-		"checks": gin.H{
-			"database": "ok",
-			"cache":    "ok",
-		},
+		"checks": report.Checks,
 	})
 }
 
@@ -169,33 +491,16 @@ func (r *Router) livenessCheck(c *gin.Context) {
 
 // versionInfo returns version information
 func (r *Router) versionInfo(c *gin.Context) {
-	c.JSON(200, gin.H{
+	c.JSON(http.StatusOK, gin.H{
 		"service":     "enterprise-vm-manager",
-		"version":     "1.0.0",
-		"build_time":  "2025-10-15T22:00:00Z",
-		"git_commit":  "abc123def",
-		"go_version":  "1.21.0",
+		"version":     r.build.Version,
+		"build_time":  r.build.BuildTime,
+		"git_commit":  r.build.GitCommit,
+		"go_version":  runtime.Version(),
 		"environment": r.cfg.Server.Mode,
 	})
 }
 
-// metricsHandler handles Prometheus metrics
-func (r *Router) metricsHandler(c *gin.Context) {
-	// In a real implementation, this would serve Prometheus metrics
-	c.String(200, `# HELP vm_manager_requests_total Total number of requests
-# TYPE vm_manager_requests_total counter
-vm_manager_requests_total 42
-
-# HELP vm_manager_vms_total Total number of VMs
-# TYPE vm_manager_vms_total gauge
-vm_manager_vms_total 10
-
-# HELP vm_manager_vms_running Number of running VMs
-# TYPE vm_manager_vms_running gauge
-vm_manager_vms_running 7
-`)
-}
-
 // RouteInfo represents route information for debugging
 type RouteInfo struct {
 	Method  string `json:"method"`
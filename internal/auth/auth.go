@@ -0,0 +1,242 @@
+// Package auth validates and issues the JWTs that identify API callers.
+//
+// Tokens are signed with HS256 using the configured secret, or with RS256
+// using keys published in a local JWKS file. API clients exchange their API
+// key for a token whose claims carry their configured identity.
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+)
+
+// clockSkew is the leeway granted when checking token expiry and validity
+const clockSkew = 30 * time.Second
+
+// Identity represents an authenticated caller
+type Identity struct {
+	Subject string   `json:"sub"`
+	Roles   []string `json:"roles"`
+	Tenant  string   `json:"tenant,omitempty"`
+}
+
+// Claims represents the JWT claims issued and accepted by the API
+type Claims struct {
+	Roles  []string `json:"roles,omitempty"`
+	Tenant string   `json:"tenant,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Authenticator verifies API keys and JWTs and issues tokens
+type Authenticator struct {
+	cfg        config.AuthConfig
+	method     jwt.SigningMethod
+	verifyKeys map[string]*rsa.PublicKey
+	signingKey interface{}
+}
+
+// NewAuthenticator creates an authenticator, loading the RS256 keys if configured
+func NewAuthenticator(cfg config.AuthConfig) (*Authenticator, error) {
+	a := &Authenticator{cfg: cfg}
+
+	switch cfg.JWTAlgorithm {
+	case "", "HS256":
+		a.method = jwt.SigningMethodHS256
+		a.signingKey = []byte(cfg.JWTSecret)
+
+	case "RS256":
+		a.method = jwt.SigningMethodRS256
+
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		a.verifyKeys = keys
+
+		if cfg.SigningKeyFile != "" {
+			data, err := os.ReadFile(cfg.SigningKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read signing key: %w", err)
+			}
+			key, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse signing key: %w", err)
+			}
+			a.signingKey = key
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm: %s", cfg.JWTAlgorithm)
+	}
+
+	return a, nil
+}
+
+// APIKeyHeader returns the header API clients send their key in
+func (a *Authenticator) APIKeyHeader() string {
+	return a.cfg.APIKeyHeader
+}
+
+// AuthenticateAPIKey returns the identity of the API client owning the key
+func (a *Authenticator) AuthenticateAPIKey(key string) (*Identity, error) {
+	if key == "" {
+		return nil, errors.ErrUnauthorized
+	}
+
+	for _, client := range a.cfg.APIClients {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(client.Key)) == 1 {
+			return &Identity{
+				Subject: client.Name,
+				Roles:   client.Roles,
+				Tenant:  client.Tenant,
+			}, nil
+		}
+	}
+
+	return nil, errors.ErrInvalidToken.WithDetails("Unknown API key")
+}
+
+// ParseToken verifies a JWT and returns the identity carried by its claims
+func (a *Authenticator) ParseToken(tokenString string) (*Identity, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{a.method.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clockSkew),
+	}
+	if a.cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(a.cfg.Issuer))
+	}
+	if a.cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(a.cfg.Audience))
+	}
+
+	var claims Claims
+	if _, err := jwt.ParseWithClaims(tokenString, &claims, a.verificationKey, options...); err != nil {
+		return nil, errors.ErrInvalidToken.WithDetails(err.Error())
+	}
+	if claims.Subject == "" {
+		return nil, errors.ErrInvalidToken.WithDetails("Token has no subject")
+	}
+
+	return &Identity{
+		Subject: claims.Subject,
+		Roles:   claims.Roles,
+		Tenant:  claims.Tenant,
+	}, nil
+}
+
+// IssueToken signs a token for the identity that expires after the configured lifetime
+func (a *Authenticator) IssueToken(identity *Identity) (string, time.Time, error) {
+	if a.signingKey == nil {
+		return "", time.Time{}, errors.ErrServiceUnavailable.WithDetails("No signing key is configured for issuing tokens")
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(a.cfg.JWTExpiration)
+
+	claims := Claims{
+		Roles:  identity.Roles,
+		Tenant: identity.Tenant,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   identity.Subject,
+			Issuer:    a.cfg.Issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	if a.cfg.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{a.cfg.Audience}
+	}
+
+	token := jwt.NewWithClaims(a.method, claims)
+	if a.cfg.SigningKeyID != "" {
+		token.Header["kid"] = a.cfg.SigningKeyID
+	}
+
+	signed, err := token.SignedString(a.signingKey)
+	if err != nil {
+		return "", time.Time{}, errors.InternalError("failed to sign token", err)
+	}
+	return signed, expiresAt, nil
+}
+
+// verificationKey selects the key that verifies a token's signature
+func (a *Authenticator) verificationKey(token *jwt.Token) (interface{}, error) {
+	if a.method == jwt.SigningMethodHS256 {
+		return a.signingKey, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" && len(a.verifyKeys) == 1 {
+		for _, key := range a.verifyKeys {
+			return key, nil
+		}
+	}
+
+	key, ok := a.verifyKeys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// jwk represents an RSA entry of a JSON Web Key Set
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// loadJWKS reads the RSA signature keys of a JWKS file indexed by key ID
+func loadJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwks file: %w", err)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse jwks file: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, key := range set.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus of key %q: %w", key.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent of key %q: %w", key.Kid, err)
+		}
+
+		keys[key.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("jwks file %s contains no RSA signature keys", path)
+	}
+	return keys, nil
+}
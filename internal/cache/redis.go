@@ -0,0 +1,27 @@
+// Package cache provides the Redis client shared by caching and rate limiting.
+package cache
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stackit/enterprise-vm-manager/internal/config"
+)
+
+// NewRedisClient creates a Redis client from configuration.
+// The connection is established lazily, so an unavailable Redis does not prevent startup.
+func NewRedisClient(cfg *config.RedisConfig) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:         cfg.Address(),
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		DialTimeout:  cfg.Timeout,
+		ReadTimeout:  cfg.Timeout,
+		WriteTimeout: cfg.Timeout,
+	})
+}
+
+// HealthCheck checks that Redis answers commands
+func HealthCheck(ctx context.Context, client *redis.Client) error {
+	return client.Ping(ctx).Err()
+}
@@ -10,13 +10,18 @@ import (
 
 // Config represents the application configuration
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server" yaml:"server"`
-	Database DatabaseConfig `mapstructure:"database" yaml:"database"`
-	Redis    RedisConfig    `mapstructure:"redis" yaml:"redis"`
-	Logging  LoggingConfig  `mapstructure:"logging" yaml:"logging"`
-	Auth     AuthConfig     `mapstructure:"auth" yaml:"auth"`
-	Metrics  MetricsConfig  `mapstructure:"metrics" yaml:"metrics"`
-	Limits   LimitsConfig   `mapstructure:"limits" yaml:"limits"`
+	Server     ServerConfig     `mapstructure:"server" yaml:"server"`
+	Database   DatabaseConfig   `mapstructure:"database" yaml:"database"`
+	Redis      RedisConfig      `mapstructure:"redis" yaml:"redis"`
+	Logging    LoggingConfig    `mapstructure:"logging" yaml:"logging"`
+	Auth       AuthConfig       `mapstructure:"auth" yaml:"auth"`
+	Metrics    MetricsConfig    `mapstructure:"metrics" yaml:"metrics"`
+	Limits     LimitsConfig     `mapstructure:"limits" yaml:"limits"`
+	Hypervisor HypervisorConfig `mapstructure:"hypervisor" yaml:"hypervisor"`
+	Nodes      NodesConfig      `mapstructure:"nodes" yaml:"nodes"`
+	Scheduler  SchedulerConfig  `mapstructure:"scheduler" yaml:"scheduler"`
+	Watch      WatchConfig      `mapstructure:"watch" yaml:"watch"`
+	Images     ImagesConfig     `mapstructure:"images" yaml:"images"`
 }
 
 // ServerConfig contains HTTP server configuration
@@ -41,12 +46,20 @@ type CORSConfig struct {
 	MaxAge           int      `mapstructure:"max_age" yaml:"max_age"`
 }
 
-// RateLimitConfig contains rate limiting configuration
+// RateLimitConfig contains rate limiting configuration.
+// Every client gets its own bucket of RPS and Burst unless one of its roles has a tier.
 type RateLimitConfig struct {
-	Enabled bool          `mapstructure:"enabled" yaml:"enabled"`
-	RPS     float64       `mapstructure:"rps" yaml:"rps"`
-	Burst   int           `mapstructure:"burst" yaml:"burst"`
-	Cleanup time.Duration `mapstructure:"cleanup" yaml:"cleanup"`
+	Enabled bool                     `mapstructure:"enabled" yaml:"enabled"`
+	RPS     float64                  `mapstructure:"rps" yaml:"rps"`
+	Burst   int                      `mapstructure:"burst" yaml:"burst"`
+	Cleanup time.Duration            `mapstructure:"cleanup" yaml:"cleanup"`
+	Tiers   map[string]RateLimitTier `mapstructure:"tiers" yaml:"tiers"`
+}
+
+// RateLimitTier contains the limits of clients holding a role
+type RateLimitTier struct {
+	RPS   float64 `mapstructure:"rps" yaml:"rps"`
+	Burst int     `mapstructure:"burst" yaml:"burst"`
 }
 
 // DatabaseConfig contains database connection configuration
@@ -77,6 +90,8 @@ type RedisConfig struct {
 	DB       int           `mapstructure:"db" yaml:"db"`
 	Timeout  time.Duration `mapstructure:"timeout" yaml:"timeout"`
 	Enabled  bool          `mapstructure:"enabled" yaml:"enabled"`
+	// CacheTTL bounds how long VMs and VM list pages stay cached; zero disables caching
+	CacheTTL time.Duration `mapstructure:"cache_ttl" yaml:"cache_ttl"`
 }
 
 // Address returns the Redis address
@@ -98,11 +113,27 @@ type LoggingConfig struct {
 
 // AuthConfig contains authentication configuration
 type AuthConfig struct {
-	JWTSecret     string        `mapstructure:"jwt_secret" yaml:"jwt_secret"`
-	JWTExpiration time.Duration `mapstructure:"jwt_expiration" yaml:"jwt_expiration"`
-	APIKeyHeader  string        `mapstructure:"api_key_header" yaml:"api_key_header"`
-	APIKeys       []string      `mapstructure:"api_keys" yaml:"api_keys"`
-	Enabled       bool          `mapstructure:"enabled" yaml:"enabled"`
+	JWTSecret      string        `mapstructure:"jwt_secret" yaml:"jwt_secret"`
+	JWTExpiration  time.Duration `mapstructure:"jwt_expiration" yaml:"jwt_expiration"`
+	JWTAlgorithm   string        `mapstructure:"jwt_algorithm" yaml:"jwt_algorithm"`       // HS256 or RS256
+	JWKSFile       string        `mapstructure:"jwks_file" yaml:"jwks_file"`               // public keys verifying RS256 tokens
+	SigningKeyFile string        `mapstructure:"signing_key_file" yaml:"signing_key_file"` // PEM private key issuing RS256 tokens
+	SigningKeyID   string        `mapstructure:"signing_key_id" yaml:"signing_key_id"`
+	Issuer         string        `mapstructure:"issuer" yaml:"issuer"`
+	Audience       string        `mapstructure:"audience" yaml:"audience"`
+	APIKeyHeader   string        `mapstructure:"api_key_header" yaml:"api_key_header"`
+	APIClients     []APIClient   `mapstructure:"api_clients" yaml:"api_clients"`
+	Enabled        bool          `mapstructure:"enabled" yaml:"enabled"`
+	// OpenDefaultProject makes every caller a member of the default project
+	OpenDefaultProject bool `mapstructure:"open_default_project" yaml:"open_default_project"`
+}
+
+// APIClient maps an API key to the identity it authenticates as
+type APIClient struct {
+	Name   string   `mapstructure:"name" yaml:"name"`
+	Key    string   `mapstructure:"key" yaml:"key"`
+	Roles  []string `mapstructure:"roles" yaml:"roles"`
+	Tenant string   `mapstructure:"tenant" yaml:"tenant"`
 }
 
 // MetricsConfig contains metrics configuration
@@ -118,7 +149,48 @@ type LimitsConfig struct {
 	MaxCPUCores int `mapstructure:"max_cpu_cores" yaml:"max_cpu_cores"`
 	MaxRAMMB    int `mapstructure:"max_ram_mb" yaml:"max_ram_mb"`
 	MaxDiskGB   int `mapstructure:"max_disk_gb" yaml:"max_disk_gb"`
-	MaxVMs      int `mapstructure:"max_vms" yaml:"max_vms"`
+	// MaxVMs is the VM quota of projects that have no quota of their own
+	MaxVMs int `mapstructure:"max_vms" yaml:"max_vms"`
+}
+
+// HypervisorConfig contains hypervisor driver configuration
+type HypervisorConfig struct {
+	Driver          string        `mapstructure:"driver" yaml:"driver"`
+	FakeDelay       time.Duration `mapstructure:"fake_delay" yaml:"fake_delay"`
+	QEMUBinary      string        `mapstructure:"qemu_binary" yaml:"qemu_binary"`
+	QEMUImgBinary   string        `mapstructure:"qemu_img_binary" yaml:"qemu_img_binary"`
+	DataDir         string        `mapstructure:"data_dir" yaml:"data_dir"`
+	RunDir          string        `mapstructure:"run_dir" yaml:"run_dir"`
+	EnableKVM       bool          `mapstructure:"enable_kvm" yaml:"enable_kvm"`
+	BridgeName      string        `mapstructure:"bridge_name" yaml:"bridge_name"`
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout" yaml:"shutdown_timeout"`
+}
+
+// NodesConfig contains compute node inventory configuration
+type NodesConfig struct {
+	HeartbeatTimeout time.Duration `mapstructure:"heartbeat_timeout" yaml:"heartbeat_timeout"`
+	MonitorInterval  time.Duration `mapstructure:"monitor_interval" yaml:"monitor_interval"`
+}
+
+// SchedulerConfig contains VM placement configuration
+type SchedulerConfig struct {
+	Strategy string `mapstructure:"strategy" yaml:"strategy"`
+}
+
+// WatchConfig contains VM watch stream configuration
+type WatchConfig struct {
+	HistorySize       int           `mapstructure:"history_size" yaml:"history_size"`
+	SubscriberBuffer  int           `mapstructure:"subscriber_buffer" yaml:"subscriber_buffer"`
+	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval" yaml:"heartbeat_interval"`
+}
+
+// ImagesConfig contains image store configuration
+type ImagesConfig struct {
+	StorageDir     string `mapstructure:"storage_dir" yaml:"storage_dir"`
+	MaxUploadBytes int64  `mapstructure:"max_upload_bytes" yaml:"max_upload_bytes"`
+	// Uploaded images no VM or snapshot refers to are deleted after the grace period; zero disables collection
+	GCGracePeriod time.Duration `mapstructure:"gc_grace_period" yaml:"gc_grace_period"`
+	GCInterval    time.Duration `mapstructure:"gc_interval" yaml:"gc_interval"`
 }
 
 // Load loads configuration from file and environment variables
@@ -175,8 +247,8 @@ func setDefaults() {
 
 	// CORS defaults
 	viper.SetDefault("server.cors.allow_origins", []string{"*"})
-	viper.SetDefault("server.cors.allow_methods", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"})
-	viper.SetDefault("server.cors.allow_headers", []string{"Content-Type", "Authorization", "X-Request-ID"})
+	viper.SetDefault("server.cors.allow_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"})
+	viper.SetDefault("server.cors.allow_headers", []string{"Content-Type", "Authorization", "X-Request-ID", "If-Match", "Content-Range", "X-Image-Checksum"})
 	viper.SetDefault("server.cors.allow_credentials", false)
 	viper.SetDefault("server.cors.max_age", 3600)
 
@@ -205,6 +277,7 @@ func setDefaults() {
 	viper.SetDefault("redis.db", 0)
 	viper.SetDefault("redis.timeout", "5s")
 	viper.SetDefault("redis.enabled", false)
+	viper.SetDefault("redis.cache_ttl", "30s")
 
 	// Logging defaults
 	viper.SetDefault("logging.level", "info")
@@ -218,8 +291,11 @@ func setDefaults() {
 	// Auth defaults
 	viper.SetDefault("auth.jwt_secret", "default-secret-change-in-production")
 	viper.SetDefault("auth.jwt_expiration", "24h")
+	viper.SetDefault("auth.jwt_algorithm", "HS256")
+	viper.SetDefault("auth.issuer", "enterprise-vm-manager")
 	viper.SetDefault("auth.api_key_header", "X-API-Key")
 	viper.SetDefault("auth.enabled", false)
+	viper.SetDefault("auth.open_default_project", false)
 
 	// Metrics defaults
 	viper.SetDefault("metrics.enabled", true)
@@ -232,6 +308,35 @@ func setDefaults() {
 	viper.SetDefault("limits.max_ram_mb", 262144)
 	viper.SetDefault("limits.max_disk_gb", 10240)
 	viper.SetDefault("limits.max_vms", 1000)
+
+	// Hypervisor defaults
+	viper.SetDefault("hypervisor.driver", "fake")
+	viper.SetDefault("hypervisor.fake_delay", "2s")
+	viper.SetDefault("hypervisor.qemu_binary", "qemu-system-x86_64")
+	viper.SetDefault("hypervisor.qemu_img_binary", "qemu-img")
+	viper.SetDefault("hypervisor.data_dir", "/var/lib/vm-manager/disks")
+	viper.SetDefault("hypervisor.run_dir", "/run/vm-manager")
+	viper.SetDefault("hypervisor.enable_kvm", true)
+	viper.SetDefault("hypervisor.bridge_name", "br0")
+	viper.SetDefault("hypervisor.shutdown_timeout", "60s")
+
+	// Node inventory defaults
+	viper.SetDefault("nodes.heartbeat_timeout", "90s")
+	viper.SetDefault("nodes.monitor_interval", "15s")
+
+	// Scheduler defaults
+	viper.SetDefault("scheduler.strategy", "binpack")
+
+	// Watch defaults
+	viper.SetDefault("watch.history_size", 1000)
+	viper.SetDefault("watch.subscriber_buffer", 100)
+	viper.SetDefault("watch.heartbeat_interval", "30s")
+
+	// Image store defaults
+	viper.SetDefault("images.storage_dir", "/var/lib/vm-manager/images")
+	viper.SetDefault("images.max_upload_bytes", int64(64)<<30)
+	viper.SetDefault("images.gc_grace_period", "168h")
+	viper.SetDefault("images.gc_interval", "1h")
 }
 
 // validateConfig validates the configuration
@@ -248,8 +353,107 @@ func validateConfig(cfg *Config) error {
 		return fmt.Errorf("database name is required")
 	}
 
-	if cfg.Auth.Enabled && cfg.Auth.JWTSecret == "default-secret-change-in-production" {
-		return fmt.Errorf("jwt secret must be changed in production")
+	switch cfg.Hypervisor.Driver {
+	case "fake", "qemu":
+	default:
+		return fmt.Errorf("invalid hypervisor driver: %s", cfg.Hypervisor.Driver)
+	}
+
+	switch cfg.Scheduler.Strategy {
+	case "binpack", "spread", "least-allocated":
+	default:
+		return fmt.Errorf("invalid scheduler strategy: %s", cfg.Scheduler.Strategy)
+	}
+
+	if cfg.Nodes.MonitorInterval <= 0 {
+		return fmt.Errorf("nodes monitor interval must be positive")
+	}
+
+	if cfg.Watch.HistorySize < 1 || cfg.Watch.SubscriberBuffer < 1 {
+		return fmt.Errorf("watch history size and subscriber buffer must be positive")
+	}
+
+	if cfg.Watch.HeartbeatInterval <= 0 {
+		return fmt.Errorf("watch heartbeat interval must be positive")
+	}
+
+	if cfg.Images.StorageDir == "" {
+		return fmt.Errorf("image storage directory is required")
+	}
+
+	if cfg.Images.MaxUploadBytes <= 0 {
+		return fmt.Errorf("image max upload bytes must be positive")
+	}
+
+	if cfg.Images.GCGracePeriod < 0 {
+		return fmt.Errorf("image gc grace period must not be negative")
+	}
+
+	if cfg.Images.GCGracePeriod > 0 && cfg.Images.GCInterval <= 0 {
+		return fmt.Errorf("image gc interval must be positive")
+	}
+
+	if cfg.Redis.CacheTTL < 0 {
+		return fmt.Errorf("redis cache ttl must not be negative")
+	}
+
+	if cfg.Server.RateLimit.Enabled {
+		if err := validateRateLimitConfig(&cfg.Server.RateLimit); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Auth.Enabled {
+		if err := validateAuthConfig(&cfg.Auth); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateRateLimitConfig validates the default limits and the tiers of enabled rate limiting
+func validateRateLimitConfig(cfg *RateLimitConfig) error {
+	if cfg.RPS <= 0 || cfg.Burst < 1 {
+		return fmt.Errorf("rate limit rps and burst must be positive")
+	}
+
+	if cfg.Cleanup <= 0 {
+		return fmt.Errorf("rate limit cleanup interval must be positive")
+	}
+
+	for role, tier := range cfg.Tiers {
+		if tier.RPS <= 0 || tier.Burst < 1 {
+			return fmt.Errorf("rate limit tier %s: rps and burst must be positive", role)
+		}
+	}
+
+	return nil
+}
+
+// validateAuthConfig validates the token and API client settings of enabled authentication
+func validateAuthConfig(cfg *AuthConfig) error {
+	switch cfg.JWTAlgorithm {
+	case "HS256":
+		if cfg.JWTSecret == "default-secret-change-in-production" {
+			return fmt.Errorf("jwt secret must be changed in production")
+		}
+	case "RS256":
+		if cfg.JWKSFile == "" {
+			return fmt.Errorf("jwks file is required for RS256 tokens")
+		}
+	default:
+		return fmt.Errorf("invalid jwt algorithm: %s", cfg.JWTAlgorithm)
+	}
+
+	if cfg.JWTExpiration <= 0 {
+		return fmt.Errorf("jwt expiration must be positive")
+	}
+
+	for i, client := range cfg.APIClients {
+		if client.Name == "" || client.Key == "" {
+			return fmt.Errorf("api client %d requires a name and a key", i)
+		}
 	}
 
 	return nil
@@ -119,6 +119,21 @@ func (d *Database) AutoMigrate() error {
 
 	err := d.DB.AutoMigrate(
 		&models.VM{},
+		&models.Node{},
+		&models.Operation{},
+		&models.VMEvent{},
+		&models.Role{},
+		&models.RoleBinding{},
+		&models.Project{},
+		&models.ProjectMember{},
+		&models.Quota{},
+		&models.Snapshot{},
+		&models.Flavor{},
+		&models.ProjectFlavor{},
+		&models.Image{},
+		&models.Volume{},
+		&models.Network{},
+		&models.NetworkInterface{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to run auto-migrations: %w", err)
@@ -183,12 +198,38 @@ func (d *Database) Seed() error {
 		return nil
 	}
 
-	// Create sample VMs
+	// Create sample nodes, images and VMs
+	sampleNodes := d.createSampleNodes()
+	sampleImages := d.createSampleImages()
 	sampleVMs := d.createSampleVMs()
 
 	// Insert sample data in transaction
 	err := d.DB.Transaction(func(tx *gorm.DB) error {
+		for _, node := range sampleNodes {
+			if err := tx.Where("name = ?", node.Name).FirstOrCreate(node).Error; err != nil {
+				return fmt.Errorf("failed to create sample node %s: %w", node.Name, err)
+			}
+		}
+
+		for _, image := range sampleImages {
+			if err := tx.Where("name = ? AND version = ?", image.Name, image.Version).FirstOrCreate(image).Error; err != nil {
+				return fmt.Errorf("failed to create sample image %s: %w", image.Reference(), err)
+			}
+		}
+
+		// Sample VMs belong to the default project
+		project := &models.Project{
+			Name:        models.DefaultProjectName,
+			Description: "VMs created outside a project",
+			CreatedBy:   "system",
+			UpdatedBy:   "system",
+		}
+		if err := tx.Where("name = ?", project.Name).FirstOrCreate(project).Error; err != nil {
+			return fmt.Errorf("failed to create default project: %w", err)
+		}
+
 		for _, vm := range sampleVMs {
+			vm.ProjectID = project.ID
 			if err := tx.Create(vm).Error; err != nil {
 				return fmt.Errorf("failed to create sample VM %s: %w", vm.Name, err)
 			}
@@ -200,10 +241,51 @@ func (d *Database) Seed() error {
 		return fmt.Errorf("failed to seed database: %w", err)
 	}
 
-	d.logger.Infof("Database seeded successfully with %d sample VMs", len(sampleVMs))
+	d.logger.Infof("Database seeded successfully with %d sample nodes, %d sample images and %d sample VMs", len(sampleNodes), len(sampleImages), len(sampleVMs))
 	return nil
 }
 
+// createSampleNodes creates sample compute node data
+func (d *Database) createSampleNodes() []*models.Node {
+	now := time.Now()
+
+	nodes := make([]*models.Node, 0, 4)
+	for i := 1; i <= 4; i++ {
+		nodes = append(nodes, &models.Node{
+			Name:    fmt.Sprintf("node-%02d", i),
+			Address: fmt.Sprintf("10.0.0.%d", 10+i),
+			Capacity: models.NodeCapacity{
+				CPUCores: 32,
+				RAMMb:    131072,
+				DiskGb:   2048,
+			},
+			State:           models.NodeStateReady,
+			LastHeartbeatAt: &now,
+		})
+	}
+	return nodes
+}
+
+// createSampleImages creates the catalog entries of the images of the sample VMs
+func (d *Database) createSampleImages() []*models.Image {
+	images := []*models.Image{
+		{Name: "ubuntu", Version: "22.04", Description: "Ubuntu 22.04 LTS server", MinDiskGb: 10, MinRAMMb: 1024},
+		{Name: "postgres", Version: "15", Description: "PostgreSQL 15 appliance", MinDiskGb: 20, MinRAMMb: 2048},
+		{Name: "redis", Version: "7", Description: "Redis 7 appliance", MinDiskGb: 10, MinRAMMb: 1024},
+		{Name: "golang", Version: "1.21-alpine", Description: "Go 1.21 on Alpine Linux", MinDiskGb: 10, MinRAMMb: 512},
+		{Name: "prom/prometheus", Version: "latest", Description: "Prometheus monitoring appliance", MinDiskGb: 20, MinRAMMb: 2048},
+		{Name: "alpine", Version: "latest", Description: "Alpine Linux", MinDiskGb: 10, MinRAMMb: 512},
+	}
+	for _, image := range images {
+		image.OSFamily = models.OSFamilyLinux
+		image.Visibility = models.ImageVisibilityPublic
+		image.State = models.ImageStateActive
+		image.CreatedBy = "system"
+		image.UpdatedBy = "system"
+	}
+	return images
+}
+
 // createSampleVMs creates sample VM data
 func (d *Database) createSampleVMs() []*models.VM {
 	now := time.Now()
@@ -379,6 +461,21 @@ func (d *Database) Truncate() error {
 
 	tables := []string{
 		"virtual_machines",
+		"nodes",
+		"operations",
+		"vm_events",
+		"roles",
+		"role_bindings",
+		"projects",
+		"project_members",
+		"project_quotas",
+		"snapshots",
+		"flavors",
+		"project_flavors",
+		"images",
+		"volumes",
+		"networks",
+		"network_interfaces",
 	}
 
 	return d.DB.Transaction(func(tx *gorm.DB) error {
@@ -0,0 +1,12 @@
+-- Drop compute node inventory
+
+DROP TRIGGER IF EXISTS update_nodes_updated_at ON nodes;
+
+DROP INDEX IF EXISTS idx_nodes_labels;
+DROP INDEX IF EXISTS idx_nodes_deleted_at;
+DROP INDEX IF EXISTS idx_nodes_last_heartbeat_at;
+DROP INDEX IF EXISTS idx_nodes_state;
+
+DROP TABLE IF EXISTS nodes;
+
+DROP TYPE IF EXISTS node_state;
@@ -0,0 +1,44 @@
+-- Compute node inventory
+
+CREATE TYPE node_state AS ENUM (
+    'ready',
+    'unreachable',
+    'maintenance'
+);
+
+CREATE TABLE nodes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) UNIQUE NOT NULL,
+    address VARCHAR(255),
+
+    -- Node capacity (embedded)
+    capacity_cpu_cores INTEGER NOT NULL CHECK (capacity_cpu_cores > 0),
+    capacity_ram_mb INTEGER NOT NULL CHECK (capacity_ram_mb > 0),
+    capacity_disk_gb INTEGER NOT NULL CHECK (capacity_disk_gb > 0),
+
+    -- Node state
+    state node_state DEFAULT 'ready',
+    last_heartbeat_at TIMESTAMP WITH TIME ZONE,
+
+    -- Metadata
+    labels JSONB,
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_nodes_state ON nodes(state);
+CREATE INDEX idx_nodes_last_heartbeat_at ON nodes(last_heartbeat_at);
+CREATE INDEX idx_nodes_deleted_at ON nodes(deleted_at);
+CREATE INDEX idx_nodes_labels ON nodes USING GIN(labels);
+
+CREATE TRIGGER update_nodes_updated_at
+    BEFORE UPDATE ON nodes
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE nodes IS 'Compute nodes that host virtual machines';
+COMMENT ON COLUMN nodes.name IS 'Node identifier referenced by virtual_machines.node_id';
+COMMENT ON COLUMN nodes.last_heartbeat_at IS 'Time of the last heartbeat received from the node agent';
@@ -0,0 +1,12 @@
+-- Drop asynchronous VM operations
+
+DROP TRIGGER IF EXISTS update_operations_updated_at ON operations;
+
+DROP INDEX IF EXISTS idx_operations_created_at;
+DROP INDEX IF EXISTS idx_operations_state;
+DROP INDEX IF EXISTS idx_operations_type;
+DROP INDEX IF EXISTS idx_operations_vm_id;
+
+DROP TABLE IF EXISTS operations;
+
+DROP TYPE IF EXISTS operation_state;
@@ -0,0 +1,44 @@
+-- Asynchronous VM operations
+
+CREATE TYPE operation_state AS ENUM (
+    'pending',
+    'running',
+    'succeeded',
+    'failed'
+);
+
+CREATE TABLE operations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    type VARCHAR(20) NOT NULL,
+
+    -- Target of the operation
+    vm_id UUID NOT NULL,
+
+    -- Progress tracking
+    state operation_state DEFAULT 'pending',
+    progress INTEGER DEFAULT 0 CHECK (progress >= 0 AND progress <= 100),
+    error VARCHAR(2000),
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+
+    -- Audit fields
+    created_by VARCHAR(255)
+);
+
+CREATE INDEX idx_operations_vm_id ON operations(vm_id);
+CREATE INDEX idx_operations_type ON operations(type);
+CREATE INDEX idx_operations_state ON operations(state);
+CREATE INDEX idx_operations_created_at ON operations(created_at);
+
+CREATE TRIGGER update_operations_updated_at
+    BEFORE UPDATE ON operations
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE operations IS 'Asynchronous state changes of virtual machines';
+COMMENT ON COLUMN operations.vm_id IS 'Virtual machine targeted by the operation (kept after the VM is deleted)';
+COMMENT ON COLUMN operations.progress IS 'Completion percentage between 0 and 100';
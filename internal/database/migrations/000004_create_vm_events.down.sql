@@ -0,0 +1,9 @@
+-- Drop VM event history
+
+DROP INDEX IF EXISTS idx_vm_events_created_at;
+DROP INDEX IF EXISTS idx_vm_events_request_id;
+DROP INDEX IF EXISTS idx_vm_events_actor;
+DROP INDEX IF EXISTS idx_vm_events_type;
+DROP INDEX IF EXISTS idx_vm_events_vm_id_created_at;
+
+DROP TABLE IF EXISTS vm_events;
@@ -0,0 +1,38 @@
+-- VM event history (audit trail)
+
+CREATE TABLE vm_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    type VARCHAR(20) NOT NULL,
+
+    -- Affected VM
+    vm_id UUID NOT NULL,
+    vm_name VARCHAR(255),
+
+    -- Who changed what and why
+    actor VARCHAR(255),
+    reason VARCHAR(1000),
+    old_status VARCHAR(20),
+    new_status VARCHAR(20),
+    message VARCHAR(2000),
+
+    -- Correlation
+    request_id VARCHAR(64),
+    operation_id UUID,
+
+    -- Timestamp
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT chk_vm_events_type CHECK (type IN ('created', 'updated', 'deleted', 'status_changed'))
+);
+
+CREATE INDEX idx_vm_events_vm_id_created_at ON vm_events(vm_id, created_at DESC);
+CREATE INDEX idx_vm_events_type ON vm_events(type);
+CREATE INDEX idx_vm_events_actor ON vm_events(actor);
+CREATE INDEX idx_vm_events_request_id ON vm_events(request_id);
+CREATE INDEX idx_vm_events_created_at ON vm_events(created_at);
+
+COMMENT ON TABLE vm_events IS 'Append-only history of changes to virtual machines';
+COMMENT ON COLUMN vm_events.vm_id IS 'Virtual machine the event belongs to (kept after the VM is deleted)';
+COMMENT ON COLUMN vm_events.actor IS 'User or system component that requested the change';
+COMMENT ON COLUMN vm_events.request_id IS 'ID of the API request that caused the change';
+COMMENT ON COLUMN vm_events.operation_id IS 'Operation that performed the change, if any';
@@ -0,0 +1,10 @@
+-- Drop role-based access control
+
+DROP INDEX IF EXISTS idx_role_bindings_role;
+DROP INDEX IF EXISTS idx_role_bindings_subject_role;
+
+DROP TABLE IF EXISTS role_bindings;
+
+DROP TRIGGER IF EXISTS update_roles_updated_at ON roles;
+
+DROP TABLE IF EXISTS roles;
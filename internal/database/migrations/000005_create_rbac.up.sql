@@ -0,0 +1,42 @@
+-- Role-based access control
+
+CREATE TABLE roles (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(63) UNIQUE NOT NULL,
+    description VARCHAR(1000),
+    permissions JSONB NOT NULL DEFAULT '[]',
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    -- Audit fields
+    created_by VARCHAR(255),
+    updated_by VARCHAR(255),
+
+    CONSTRAINT chk_roles_permissions_array CHECK (jsonb_typeof(permissions) = 'array')
+);
+
+CREATE TRIGGER update_roles_updated_at
+    BEFORE UPDATE ON roles
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE role_bindings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subject VARCHAR(255) NOT NULL,
+    role VARCHAR(63) NOT NULL,
+
+    -- Timestamp and audit
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    created_by VARCHAR(255)
+);
+
+CREATE UNIQUE INDEX idx_role_bindings_subject_role ON role_bindings(subject, role);
+CREATE INDEX idx_role_bindings_role ON role_bindings(role);
+
+COMMENT ON TABLE roles IS 'Custom roles; the built-in viewer, operator and admin roles are not stored';
+COMMENT ON COLUMN roles.permissions IS 'Permissions granted by the role, such as vm:start or * for all';
+COMMENT ON TABLE role_bindings IS 'Roles granted to subjects in addition to the roles carried by their tokens';
+COMMENT ON COLUMN role_bindings.subject IS 'Subject (sub claim or API client name) the role is granted to';
+COMMENT ON COLUMN role_bindings.role IS 'Name of a built-in or custom role';
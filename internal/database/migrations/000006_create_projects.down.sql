@@ -0,0 +1,18 @@
+-- Drop projects and restore globally unique VM names
+
+DROP INDEX IF EXISTS idx_virtual_machines_project_name;
+
+ALTER TABLE virtual_machines ADD CONSTRAINT virtual_machines_name_key UNIQUE (name);
+
+ALTER TABLE virtual_machines DROP COLUMN IF EXISTS project_id;
+
+COMMENT ON COLUMN virtual_machines.name IS 'Human-readable name for the virtual machine (must be unique)';
+
+DROP INDEX IF EXISTS idx_project_members_subject;
+DROP INDEX IF EXISTS idx_project_members_project_subject;
+
+DROP TABLE IF EXISTS project_members;
+
+DROP TRIGGER IF EXISTS update_projects_updated_at ON projects;
+
+DROP TABLE IF EXISTS projects;
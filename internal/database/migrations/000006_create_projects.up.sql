@@ -0,0 +1,58 @@
+-- Projects scope VMs to the teams that own them
+
+CREATE TABLE projects (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(63) UNIQUE NOT NULL,
+    description VARCHAR(1000),
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    -- Audit fields
+    created_by VARCHAR(255),
+    updated_by VARCHAR(255)
+);
+
+CREATE TRIGGER update_projects_updated_at
+    BEFORE UPDATE ON projects
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE project_members (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    subject VARCHAR(255) NOT NULL,
+
+    -- Timestamp and audit
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    created_by VARCHAR(255)
+);
+
+CREATE UNIQUE INDEX idx_project_members_project_subject ON project_members(project_id, subject);
+CREATE INDEX idx_project_members_subject ON project_members(subject);
+
+-- Existing VMs move into the default project
+INSERT INTO projects (name, description, created_by, updated_by)
+VALUES ('default', 'VMs created outside a project', 'system', 'system');
+
+ALTER TABLE virtual_machines ADD COLUMN project_id UUID REFERENCES projects(id);
+
+UPDATE virtual_machines
+SET project_id = (SELECT id FROM projects WHERE name = 'default');
+
+ALTER TABLE virtual_machines ALTER COLUMN project_id SET NOT NULL;
+
+-- VM names are unique per project instead of globally
+ALTER TABLE virtual_machines DROP CONSTRAINT IF EXISTS virtual_machines_name_key;
+
+CREATE UNIQUE INDEX idx_virtual_machines_project_name
+    ON virtual_machines(project_id, name)
+    WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE projects IS 'Namespaces of VMs owned by groups of subjects';
+COMMENT ON COLUMN projects.name IS 'Unique project name used in /api/v1/projects/{project} routes';
+COMMENT ON TABLE project_members IS 'Subjects granted access to the VMs of a project';
+COMMENT ON COLUMN project_members.subject IS 'Subject (sub claim or API client name) that is a member of the project';
+COMMENT ON COLUMN virtual_machines.project_id IS 'Project owning the virtual machine';
+COMMENT ON COLUMN virtual_machines.name IS 'Human-readable name for the virtual machine (unique within its project)';
@@ -0,0 +1,5 @@
+-- Drop project quotas
+
+DROP TRIGGER IF EXISTS update_project_quotas_updated_at ON project_quotas;
+
+DROP TABLE IF EXISTS project_quotas;
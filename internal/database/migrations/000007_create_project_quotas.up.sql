@@ -0,0 +1,26 @@
+-- Per-project resource quotas enforced when VMs are created, resized and started
+
+CREATE TABLE project_quotas (
+    project_id UUID PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    max_vms INTEGER NOT NULL DEFAULT 0 CHECK (max_vms >= 0),
+    max_running_vms INTEGER NOT NULL DEFAULT 0 CHECK (max_running_vms >= 0),
+    max_cpu_cores INTEGER NOT NULL DEFAULT 0 CHECK (max_cpu_cores >= 0),
+    max_ram_mb INTEGER NOT NULL DEFAULT 0 CHECK (max_ram_mb >= 0),
+    max_disk_gb INTEGER NOT NULL DEFAULT 0 CHECK (max_disk_gb >= 0),
+
+    -- Timestamp and audit
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_by VARCHAR(255)
+);
+
+CREATE TRIGGER update_project_quotas_updated_at
+    BEFORE UPDATE ON project_quotas
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE project_quotas IS 'Resource limits of projects; projects without a row get the configured default';
+COMMENT ON COLUMN project_quotas.max_vms IS 'Maximum number of VMs in the project (0 means unlimited)';
+COMMENT ON COLUMN project_quotas.max_running_vms IS 'Maximum number of powered-on VMs in the project (0 means unlimited)';
+COMMENT ON COLUMN project_quotas.max_cpu_cores IS 'Maximum total vCPUs of the VMs in the project (0 means unlimited)';
+COMMENT ON COLUMN project_quotas.max_ram_mb IS 'Maximum total RAM in MB of the VMs in the project (0 means unlimited)';
+COMMENT ON COLUMN project_quotas.max_disk_gb IS 'Maximum total disk in GB of the VMs in the project (0 means unlimited)';
@@ -0,0 +1,3 @@
+-- Drop VM resource versions
+
+ALTER TABLE virtual_machines DROP COLUMN IF EXISTS resource_version;
@@ -0,0 +1,5 @@
+-- Resource versions let clients detect concurrent modifications of a VM
+
+ALTER TABLE virtual_machines ADD COLUMN resource_version BIGINT NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN virtual_machines.resource_version IS 'Version bumped on every write; returned as the ETag and checked against If-Match';
@@ -0,0 +1,6 @@
+-- Drop keyset pagination indexes
+
+DROP INDEX IF EXISTS idx_virtual_machines_status_id;
+DROP INDEX IF EXISTS idx_virtual_machines_name_id;
+DROP INDEX IF EXISTS idx_virtual_machines_updated_at_id;
+DROP INDEX IF EXISTS idx_virtual_machines_created_at_id;
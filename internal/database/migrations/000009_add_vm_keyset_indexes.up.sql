@@ -0,0 +1,7 @@
+-- Keyset pagination orders VMs by the sort key and then the ID and continues
+-- after the (sort key, ID) pair of the last VM of the previous page
+
+CREATE INDEX idx_virtual_machines_created_at_id ON virtual_machines(created_at, id);
+CREATE INDEX idx_virtual_machines_updated_at_id ON virtual_machines(updated_at, id);
+CREATE INDEX idx_virtual_machines_name_id ON virtual_machines(name, id);
+CREATE INDEX idx_virtual_machines_status_id ON virtual_machines(status, id);
@@ -0,0 +1,15 @@
+-- Drop VM snapshots
+
+DELETE FROM vm_events WHERE type IN ('snapshot_created', 'snapshot_deleted', 'snapshot_reverted');
+ALTER TABLE vm_events DROP CONSTRAINT chk_vm_events_type;
+ALTER TABLE vm_events ADD CONSTRAINT chk_vm_events_type CHECK (type IN ('created', 'updated', 'deleted', 'status_changed'));
+
+DROP INDEX IF EXISTS idx_operations_snapshot_id;
+ALTER TABLE operations DROP COLUMN IF EXISTS snapshot_id;
+
+DROP TRIGGER IF EXISTS update_snapshots_updated_at ON snapshots;
+
+DROP INDEX IF EXISTS idx_snapshots_status;
+DROP INDEX IF EXISTS idx_snapshots_vm_name;
+
+DROP TABLE IF EXISTS snapshots;
@@ -0,0 +1,49 @@
+-- VM snapshots
+
+CREATE TABLE snapshots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    vm_id UUID NOT NULL REFERENCES virtual_machines(id) ON DELETE CASCADE,
+    name VARCHAR(63) NOT NULL,
+    description VARCHAR(1000),
+    type VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+
+    -- VM state at capture time
+    vm_status VARCHAR(20),
+    error VARCHAR(2000),
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    -- Audit fields
+    created_by VARCHAR(255),
+
+    CONSTRAINT chk_snapshots_type CHECK (type IN ('disk', 'memory')),
+    CONSTRAINT chk_snapshots_status CHECK (status IN ('creating', 'ready', 'deleting', 'error'))
+);
+
+CREATE UNIQUE INDEX idx_snapshots_vm_name ON snapshots(vm_id, name);
+CREATE INDEX idx_snapshots_status ON snapshots(status);
+
+CREATE TRIGGER update_snapshots_updated_at
+    BEFORE UPDATE ON snapshots
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Snapshot operations reference the snapshot they work on
+ALTER TABLE operations ADD COLUMN snapshot_id UUID;
+
+CREATE INDEX idx_operations_snapshot_id ON operations(snapshot_id);
+
+-- Snapshot changes are part of the VM audit trail
+ALTER TABLE vm_events DROP CONSTRAINT chk_vm_events_type;
+ALTER TABLE vm_events ADD CONSTRAINT chk_vm_events_type CHECK (type IN (
+    'created', 'updated', 'deleted', 'status_changed',
+    'snapshot_created', 'snapshot_deleted', 'snapshot_reverted'
+));
+
+COMMENT ON TABLE snapshots IS 'Point-in-time captures of virtual machine disks and, for memory snapshots, memory';
+COMMENT ON COLUMN snapshots.type IS 'disk captures the disk only; memory also captures the running guest';
+COMMENT ON COLUMN snapshots.vm_status IS 'Status of the virtual machine when the snapshot was taken';
+COMMENT ON COLUMN operations.snapshot_id IS 'Snapshot targeted by snapshot operations (kept after the snapshot is deleted)';
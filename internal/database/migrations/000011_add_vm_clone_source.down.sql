@@ -0,0 +1,9 @@
+-- Drop VM clone sources
+
+DROP INDEX IF EXISTS idx_virtual_machines_source_vm_id;
+
+ALTER TABLE virtual_machines
+    DROP CONSTRAINT IF EXISTS chk_virtual_machines_clone_mode,
+    DROP COLUMN IF EXISTS clone_mode,
+    DROP COLUMN IF EXISTS source_snapshot_id,
+    DROP COLUMN IF EXISTS source_vm_id;
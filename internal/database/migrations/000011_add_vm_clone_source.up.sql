@@ -0,0 +1,11 @@
+-- Cloned VMs remember the VM and snapshot they were copied from
+
+ALTER TABLE virtual_machines
+    ADD COLUMN source_vm_id UUID REFERENCES virtual_machines(id) ON DELETE SET NULL,
+    ADD COLUMN source_snapshot_id UUID REFERENCES snapshots(id) ON DELETE SET NULL,
+    ADD COLUMN clone_mode VARCHAR(20),
+    ADD CONSTRAINT chk_virtual_machines_clone_mode CHECK (clone_mode IS NULL OR clone_mode IN ('full', 'linked'));
+
+CREATE INDEX idx_virtual_machines_source_vm_id ON virtual_machines(source_vm_id) WHERE source_vm_id IS NOT NULL;
+
+COMMENT ON COLUMN virtual_machines.clone_mode IS 'full clones copy the source disk; linked clones share it as a read-only base';
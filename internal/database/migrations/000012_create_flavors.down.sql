@@ -0,0 +1,11 @@
+-- Drop flavor catalog
+
+DROP INDEX IF EXISTS idx_virtual_machines_flavor;
+ALTER TABLE virtual_machines DROP COLUMN IF EXISTS flavor;
+
+DROP INDEX IF EXISTS idx_project_flavors_flavor_name;
+DROP INDEX IF EXISTS idx_project_flavors_project_flavor;
+DROP TABLE IF EXISTS project_flavors;
+
+DROP TRIGGER IF EXISTS update_flavors_updated_at ON flavors;
+DROP TABLE IF EXISTS flavors;
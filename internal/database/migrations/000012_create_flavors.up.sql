@@ -0,0 +1,51 @@
+-- Flavor catalog of named VM sizes and the flavors projects are restricted to
+
+CREATE TABLE flavors (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(63) UNIQUE NOT NULL,
+    description VARCHAR(1000),
+    cpu_cores INTEGER NOT NULL CHECK (cpu_cores > 0),
+    ram_mb INTEGER NOT NULL CHECK (ram_mb > 0),
+    disk_gb INTEGER NOT NULL CHECK (disk_gb > 0),
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    -- Audit fields
+    created_by VARCHAR(255),
+    updated_by VARCHAR(255)
+);
+
+CREATE TRIGGER update_flavors_updated_at
+    BEFORE UPDATE ON flavors
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+INSERT INTO flavors (name, description, cpu_cores, ram_mb, disk_gb, created_by, updated_by) VALUES
+    ('m1.small', 'General purpose, 1 vCPU', 1, 2048, 20, 'system', 'system'),
+    ('m1.medium', 'General purpose, 2 vCPUs', 2, 4096, 40, 'system', 'system'),
+    ('m1.large', 'General purpose, 4 vCPUs', 4, 8192, 80, 'system', 'system'),
+    ('m1.xlarge', 'General purpose, 8 vCPUs', 8, 16384, 160, 'system', 'system'),
+    ('c2.large', 'Compute optimized, 4 vCPUs', 4, 4096, 40, 'system', 'system'),
+    ('c2.xlarge', 'Compute optimized, 8 vCPUs', 8, 8192, 80, 'system', 'system');
+
+CREATE TABLE project_flavors (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    flavor_name VARCHAR(63) NOT NULL REFERENCES flavors(name) ON DELETE RESTRICT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    created_by VARCHAR(255)
+);
+
+CREATE UNIQUE INDEX idx_project_flavors_project_flavor ON project_flavors(project_id, flavor_name);
+CREATE INDEX idx_project_flavors_flavor_name ON project_flavors(flavor_name);
+
+-- VMs remember the flavor they were sized by
+ALTER TABLE virtual_machines ADD COLUMN flavor VARCHAR(63);
+
+CREATE INDEX idx_virtual_machines_flavor ON virtual_machines(flavor) WHERE flavor IS NOT NULL;
+
+COMMENT ON TABLE flavors IS 'Named VM sizes VMs can be created with instead of custom resources';
+COMMENT ON TABLE project_flavors IS 'Flavors a project is restricted to; projects without rows may use every flavor and custom sizes';
+COMMENT ON COLUMN virtual_machines.flavor IS 'Flavor the resources were expanded from; empty for custom sizes. Flavor changes do not resize existing VMs';
@@ -0,0 +1,10 @@
+-- Drop image catalog
+
+DROP TRIGGER IF EXISTS update_images_updated_at ON images;
+
+DROP INDEX IF EXISTS idx_images_state;
+DROP INDEX IF EXISTS idx_images_project_id;
+DROP INDEX IF EXISTS idx_images_os_family;
+DROP INDEX IF EXISTS idx_images_name_version;
+
+DROP TABLE IF EXISTS images;
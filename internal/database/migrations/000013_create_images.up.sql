@@ -0,0 +1,73 @@
+-- Image catalog VMs are created from
+
+CREATE TABLE images (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(128) NOT NULL,
+    version VARCHAR(63) NOT NULL,
+    description VARCHAR(1000),
+    os_family VARCHAR(20) NOT NULL,
+
+    -- Requirements of VMs created from the image
+    min_disk_gb INTEGER NOT NULL DEFAULT 0 CHECK (min_disk_gb >= 0),
+    min_ram_mb INTEGER NOT NULL DEFAULT 0 CHECK (min_ram_mb >= 0),
+
+    checksum VARCHAR(135),
+
+    -- Private images belong to a project
+    visibility VARCHAR(20) NOT NULL DEFAULT 'public',
+    project_id UUID REFERENCES projects(id) ON DELETE CASCADE,
+
+    -- Deprecation
+    state VARCHAR(20) NOT NULL DEFAULT 'active',
+    deprecated_at TIMESTAMP WITH TIME ZONE,
+    replacement VARCHAR(192),
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    -- Audit fields
+    created_by VARCHAR(255),
+    updated_by VARCHAR(255),
+
+    CONSTRAINT chk_images_os_family CHECK (os_family IN ('linux', 'windows', 'bsd', 'other')),
+    CONSTRAINT chk_images_visibility CHECK (
+        (visibility = 'public' AND project_id IS NULL) OR
+        (visibility = 'private' AND project_id IS NOT NULL)
+    ),
+    CONSTRAINT chk_images_state CHECK (state IN ('active', 'deprecated'))
+);
+
+CREATE UNIQUE INDEX idx_images_name_version ON images(name, version);
+CREATE INDEX idx_images_os_family ON images(os_family);
+CREATE INDEX idx_images_project_id ON images(project_id) WHERE project_id IS NOT NULL;
+CREATE INDEX idx_images_state ON images(state);
+
+CREATE TRIGGER update_images_updated_at
+    BEFORE UPDATE ON images
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+INSERT INTO images (name, version, description, os_family, min_disk_gb, min_ram_mb, created_by, updated_by) VALUES
+    ('ubuntu', '22.04', 'Ubuntu 22.04 LTS server', 'linux', 10, 1024, 'system', 'system'),
+    ('ubuntu', '24.04', 'Ubuntu 24.04 LTS server', 'linux', 10, 1024, 'system', 'system'),
+    ('debian', '12', 'Debian 12 server', 'linux', 10, 512, 'system', 'system');
+
+-- Register the images of existing VMs so they can still be cloned and resized; references
+-- without a version refer to "latest"
+INSERT INTO images (name, version, description, os_family, created_by, updated_by)
+SELECT DISTINCT
+    regexp_replace(image_name, ':[^:/]*$', ''),
+    COALESCE(substring(image_name from ':([^:/]*)$'), 'latest'),
+    'Registered from existing VMs',
+    'other',
+    'system',
+    'system'
+FROM virtual_machines
+WHERE deleted_at IS NULL
+ON CONFLICT (name, version) DO NOTHING;
+
+COMMENT ON TABLE images IS 'Base images VMs are created from, referenced by virtual_machines.image_name as <name>:<version>';
+COMMENT ON COLUMN images.checksum IS 'Checksum of the image file as <algorithm>:<hex digest>';
+COMMENT ON COLUMN images.state IS 'Deprecated images cannot be used for new VMs; existing VMs keep running';
+COMMENT ON COLUMN images.replacement IS 'Image reference suggested instead of a deprecated image';
@@ -0,0 +1,10 @@
+-- Drop image files
+
+DROP INDEX IF EXISTS idx_images_uploaded_at;
+
+ALTER TABLE images
+    DROP CONSTRAINT IF EXISTS chk_images_disk_format,
+    DROP COLUMN IF EXISTS unreferenced_since,
+    DROP COLUMN IF EXISTS uploaded_at,
+    DROP COLUMN IF EXISTS size_bytes,
+    DROP COLUMN IF EXISTS disk_format;
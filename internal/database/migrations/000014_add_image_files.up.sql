@@ -0,0 +1,13 @@
+-- Images remember the file uploaded to the local image store
+
+ALTER TABLE images
+    ADD COLUMN disk_format VARCHAR(20),
+    ADD COLUMN size_bytes BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN uploaded_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN unreferenced_since TIMESTAMP WITH TIME ZONE,
+    ADD CONSTRAINT chk_images_disk_format CHECK (disk_format IS NULL OR disk_format IN ('qcow2', 'raw'));
+
+CREATE INDEX idx_images_uploaded_at ON images(uploaded_at) WHERE uploaded_at IS NOT NULL;
+
+COMMENT ON COLUMN images.uploaded_at IS 'When the image file was uploaded and verified; NULL for catalog-only images';
+COMMENT ON COLUMN images.unreferenced_since IS 'When garbage collection first found no VM or snapshot using the uploaded file';
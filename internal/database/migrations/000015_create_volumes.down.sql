@@ -0,0 +1,16 @@
+-- Drop block volumes
+
+DELETE FROM vm_events WHERE type IN ('volume_attached', 'volume_detached');
+ALTER TABLE vm_events DROP CONSTRAINT chk_vm_events_type;
+ALTER TABLE vm_events ADD CONSTRAINT chk_vm_events_type CHECK (type IN (
+    'created', 'updated', 'deleted', 'status_changed',
+    'snapshot_created', 'snapshot_deleted', 'snapshot_reverted'
+));
+
+DROP TRIGGER IF EXISTS update_volumes_updated_at ON volumes;
+
+DROP INDEX IF EXISTS idx_volumes_vm_id;
+DROP INDEX IF EXISTS idx_volumes_status;
+DROP INDEX IF EXISTS idx_volumes_project_name;
+
+DROP TABLE IF EXISTS volumes;
@@ -0,0 +1,46 @@
+-- Block volumes that can be attached to VMs
+
+CREATE TABLE volumes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(63) NOT NULL,
+    description VARCHAR(1000),
+    size_gb INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'available',
+
+    -- Attachment
+    vm_id UUID REFERENCES virtual_machines(id) ON DELETE SET NULL,
+    attached_at TIMESTAMP WITH TIME ZONE,
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    -- Audit fields
+    created_by VARCHAR(255),
+    updated_by VARCHAR(255),
+
+    CONSTRAINT chk_volumes_size_gb CHECK (size_gb >= 1 AND size_gb <= 10240),
+    CONSTRAINT chk_volumes_status CHECK (status IN ('available', 'in-use'))
+);
+
+CREATE UNIQUE INDEX idx_volumes_project_name ON volumes(project_id, name);
+CREATE INDEX idx_volumes_status ON volumes(status);
+CREATE INDEX idx_volumes_vm_id ON volumes(vm_id) WHERE vm_id IS NOT NULL;
+
+CREATE TRIGGER update_volumes_updated_at
+    BEFORE UPDATE ON volumes
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Attachments are part of the VM audit trail
+ALTER TABLE vm_events DROP CONSTRAINT chk_vm_events_type;
+ALTER TABLE vm_events ADD CONSTRAINT chk_vm_events_type CHECK (type IN (
+    'created', 'updated', 'deleted', 'status_changed',
+    'snapshot_created', 'snapshot_deleted', 'snapshot_reverted',
+    'volume_attached', 'volume_detached'
+));
+
+COMMENT ON TABLE volumes IS 'Block volumes of a project; they outlive the virtual machines they are attached to';
+COMMENT ON COLUMN volumes.vm_id IS 'Virtual machine the volume is attached to, NULL while available';
+COMMENT ON COLUMN volumes.size_gb IS 'Capacity counted towards the disk quota of the project';
@@ -0,0 +1,13 @@
+-- Drop virtual networks
+
+DROP INDEX IF EXISTS idx_network_interfaces_vm_device;
+DROP INDEX IF EXISTS idx_network_interfaces_mac_address;
+DROP INDEX IF EXISTS idx_network_interfaces_network_ip;
+
+DROP TABLE IF EXISTS network_interfaces;
+
+DROP TRIGGER IF EXISTS update_networks_updated_at ON networks;
+
+DROP INDEX IF EXISTS idx_networks_project_name;
+
+DROP TABLE IF EXISTS networks;
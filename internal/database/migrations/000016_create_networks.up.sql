@@ -0,0 +1,65 @@
+-- Virtual networks and the addresses allocated to VM interfaces
+
+CREATE TABLE networks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(63) NOT NULL,
+    description VARCHAR(1000),
+
+    -- Addressing
+    cidr VARCHAR(18) NOT NULL,
+    gateway VARCHAR(15) NOT NULL,
+    dns_servers JSONB NOT NULL DEFAULT '[]',
+    dhcp_start VARCHAR(15) NOT NULL,
+    dhcp_end VARCHAR(15) NOT NULL,
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    -- Audit fields
+    created_by VARCHAR(255),
+    updated_by VARCHAR(255),
+
+    CONSTRAINT chk_networks_cidr CHECK (family(cidr::cidr) = 4),
+    CONSTRAINT chk_networks_gateway CHECK (gateway::inet << cidr::cidr),
+    CONSTRAINT chk_networks_dhcp_range CHECK (
+        dhcp_start::inet << cidr::cidr AND dhcp_end::inet << cidr::cidr AND dhcp_start::inet <= dhcp_end::inet
+    ),
+    CONSTRAINT chk_networks_dns_servers_array CHECK (jsonb_typeof(dns_servers) = 'array')
+);
+
+CREATE UNIQUE INDEX idx_networks_project_name ON networks(project_id, name);
+
+CREATE TRIGGER update_networks_updated_at
+    BEFORE UPDATE ON networks
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE network_interfaces (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    vm_id UUID NOT NULL REFERENCES virtual_machines(id) ON DELETE CASCADE,
+    network_id UUID NOT NULL REFERENCES networks(id) ON DELETE RESTRICT,
+    network VARCHAR(63) NOT NULL,
+    device_index INTEGER NOT NULL DEFAULT 0,
+
+    -- Addressing copied from the network
+    mac_address VARCHAR(17) NOT NULL,
+    ip_address VARCHAR(15) NOT NULL,
+    prefix_length INTEGER NOT NULL,
+    gateway VARCHAR(15) NOT NULL,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT chk_network_interfaces_device_index CHECK (device_index >= 0)
+);
+
+-- An address or MAC address can only be allocated once
+CREATE UNIQUE INDEX idx_network_interfaces_network_ip ON network_interfaces(network_id, ip_address);
+CREATE UNIQUE INDEX idx_network_interfaces_mac_address ON network_interfaces(mac_address);
+CREATE UNIQUE INDEX idx_network_interfaces_vm_device ON network_interfaces(vm_id, device_index);
+
+COMMENT ON TABLE networks IS 'IPv4 networks of a project that VMs get addresses from';
+COMMENT ON COLUMN networks.dhcp_start IS 'First address of the pool VM interfaces are allocated from';
+COMMENT ON COLUMN networks.dhcp_end IS 'Last address of the pool VM interfaces are allocated from';
+COMMENT ON TABLE network_interfaces IS 'Interfaces of virtual machines and their allocated addresses, released when the VM is deleted';
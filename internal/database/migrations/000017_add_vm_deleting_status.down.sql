@@ -0,0 +1,50 @@
+-- Drop the deleting VM status
+-- PostgreSQL cannot remove enum values, so vm_status keeps 'deleting' but no VM uses it
+
+UPDATE virtual_machines SET status = 'stopped' WHERE status = 'deleting';
+
+CREATE OR REPLACE FUNCTION validate_vm_status_transition()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- Allow all transitions during INSERT
+    IF TG_OP = 'INSERT' THEN
+        RETURN NEW;
+    END IF;
+
+    -- Validate status transitions during UPDATE
+    IF OLD.status != NEW.status THEN
+        CASE OLD.status
+            WHEN 'pending' THEN
+                IF NEW.status NOT IN ('stopped', 'starting', 'error') THEN
+                    RAISE EXCEPTION 'Invalid status transition from % to %', OLD.status, NEW.status;
+                END IF;
+            WHEN 'stopped' THEN
+                IF NEW.status NOT IN ('starting', 'pending', 'error') THEN
+                    RAISE EXCEPTION 'Invalid status transition from % to %', OLD.status, NEW.status;
+                END IF;
+            WHEN 'starting' THEN
+                IF NEW.status NOT IN ('running', 'stopped', 'error') THEN
+                    RAISE EXCEPTION 'Invalid status transition from % to %', OLD.status, NEW.status;
+                END IF;
+            WHEN 'running' THEN
+                IF NEW.status NOT IN ('stopping', 'suspended', 'error') THEN
+                    RAISE EXCEPTION 'Invalid status transition from % to %', OLD.status, NEW.status;
+                END IF;
+            WHEN 'stopping' THEN
+                IF NEW.status NOT IN ('stopped', 'error', 'running') THEN
+                    RAISE EXCEPTION 'Invalid status transition from % to %', OLD.status, NEW.status;
+                END IF;
+            WHEN 'suspended' THEN
+                IF NEW.status NOT IN ('running', 'stopped', 'error') THEN
+                    RAISE EXCEPTION 'Invalid status transition from % to %', OLD.status, NEW.status;
+                END IF;
+            WHEN 'error' THEN
+                IF NEW.status NOT IN ('stopped', 'starting') THEN
+                    RAISE EXCEPTION 'Invalid status transition from % to %', OLD.status, NEW.status;
+                END IF;
+        END CASE;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
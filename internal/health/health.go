@@ -0,0 +1,106 @@
+// Package health runs dependency checks for the readiness probe.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Status values reported by checks and reports
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Check verifies a single dependency and returns an error if it is unusable
+type Check func(ctx context.Context) error
+
+// CheckResult represents the outcome of a single check
+type CheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report represents the outcome of all checks
+type Report struct {
+	Healthy bool                   `json:"-"`
+	Checks  map[string]CheckResult `json:"checks"`
+}
+
+// BuildInfo describes the running binary as set via ldflags
+type BuildInfo struct {
+	Version   string `json:"version"`
+	BuildTime string `json:"build_time"`
+	GitCommit string `json:"git_commit"`
+}
+
+// Checker runs named dependency checks with a per-check timeout
+type Checker struct {
+	timeout time.Duration
+	names   []string
+	checks  map[string]Check
+}
+
+// NewChecker creates a checker whose checks are cancelled after the given timeout
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{
+		timeout: timeout,
+		checks:  make(map[string]Check),
+	}
+}
+
+// Register adds a named check
+func (c *Checker) Register(name string, check Check) {
+	if _, exists := c.checks[name]; !exists {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = check
+}
+
+// Run executes all checks concurrently and reports the result of each
+func (c *Checker) Run(ctx context.Context) *Report {
+	report := &Report{
+		Healthy: true,
+		Checks:  make(map[string]CheckResult, len(c.names)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range c.names {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			result := c.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != StatusOK {
+				report.Healthy = false
+			}
+		}(name, c.checks[name])
+	}
+	wg.Wait()
+
+	return report
+}
+
+// run executes a single check and measures its latency
+func (c *Checker) run(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := CheckResult{
+		Status:    StatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
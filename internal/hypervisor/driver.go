@@ -0,0 +1,95 @@
+package hypervisor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// Supported driver names
+const (
+	DriverFake = "fake"
+	DriverQEMU = "qemu"
+)
+
+// BaseImage is the image file the disk of a new VM is created from
+type BaseImage struct {
+	// Reference is the catalog reference of the image, such as "ubuntu:22.04"
+	Reference string
+	// Path of the image file in the image store, empty if the file has not been uploaded
+	Path   string
+	Format models.DiskFormat
+}
+
+// Driver defines the operations a hypervisor backend must implement
+type Driver interface {
+	// Name returns the driver name
+	Name() string
+
+	// Provision allocates the backing resources (disks, definitions) for a VM, creating its disk from the base image
+	Provision(ctx context.Context, vm *models.VM, base *BaseImage) error
+
+	// Start boots a provisioned VM
+	Start(ctx context.Context, vm *models.VM) error
+
+	// Stop requests a graceful guest shutdown and waits for it to complete
+	Stop(ctx context.Context, vm *models.VM) error
+
+	// ForceStop powers off a VM immediately
+	ForceStop(ctx context.Context, vm *models.VM) error
+
+	// Suspend pauses a running VM
+	Suspend(ctx context.Context, vm *models.VM) error
+
+	// Resume continues a suspended VM
+	Resume(ctx context.Context, vm *models.VM) error
+
+	// Destroy releases all backing resources of a stopped VM
+	Destroy(ctx context.Context, vm *models.VM) error
+
+	// CreateSnapshot captures the disk of a VM and, for memory snapshots, its running state
+	CreateSnapshot(ctx context.Context, vm *models.VM, snapshot *models.Snapshot) error
+
+	// RevertSnapshot restores a stopped VM to a snapshot; memory snapshots leave it running
+	RevertSnapshot(ctx context.Context, vm *models.VM, snapshot *models.Snapshot) error
+
+	// DeleteSnapshot removes a snapshot; removing a missing snapshot is not an error
+	DeleteSnapshot(ctx context.Context, vm *models.VM, snapshot *models.Snapshot) error
+
+	// Clone provisions the disk of a new VM from the disk of an existing VM, or from one of its
+	// snapshots if snapshot is not nil. Linked clones share the source disk as a read-only base.
+	Clone(ctx context.Context, source, clone *models.VM, snapshot *models.Snapshot, mode models.CloneMode) error
+
+	// CreateVolume allocates the backing storage of a volume
+	CreateVolume(ctx context.Context, volume *models.Volume) error
+
+	// ResizeVolume grows a volume to its current size; attached volumes of running VMs are grown live
+	ResizeVolume(ctx context.Context, volume *models.Volume) error
+
+	// DeleteVolume releases the backing storage of a detached volume; deleting a missing volume is not an error
+	DeleteVolume(ctx context.Context, volume *models.Volume) error
+
+	// AttachVolume attaches a volume to a VM, hot-plugging it into running VMs
+	AttachVolume(ctx context.Context, vm *models.VM, volume *models.Volume) error
+
+	// DetachVolume detaches a volume from a VM, unplugging it from running VMs
+	DetachVolume(ctx context.Context, vm *models.VM, volume *models.Volume) error
+
+	// Stats returns current runtime statistics of a running VM
+	Stats(ctx context.Context, vm *models.VM) (*models.VMStats, error)
+}
+
+// New creates the driver selected in the configuration
+func New(cfg *config.HypervisorConfig, log *logger.Logger) (Driver, error) {
+	switch cfg.Driver {
+	case "", DriverFake:
+		return NewFakeDriver(cfg.FakeDelay), nil
+	case DriverQEMU:
+		return NewQEMUDriver(cfg, log)
+	default:
+		return nil, fmt.Errorf("unknown hypervisor driver: %s", cfg.Driver)
+	}
+}
@@ -0,0 +1,395 @@
+package hypervisor
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+)
+
+// fakeState represents the power state of a VM inside the fake driver
+type fakeState string
+
+const (
+	fakeStateStopped   fakeState = "stopped"
+	fakeStateRunning   fakeState = "running"
+	fakeStateSuspended fakeState = "suspended"
+)
+
+// fakeInstance holds the in-process state of a single VM
+type fakeInstance struct {
+	state     fakeState
+	startedAt time.Time
+	samples   int64
+	rxBytes   int64
+	txBytes   int64
+
+	// snapshots maps snapshot IDs to the power state they were taken in
+	snapshots map[uuid.UUID]fakeState
+}
+
+// fakeVolume holds the in-process state of a single volume
+type fakeVolume struct {
+	sizeGb int
+	// vmID is the VM the volume is attached to, or uuid.Nil
+	vmID uuid.UUID
+}
+
+// FakeDriver is a deterministic in-process driver used for development and tests
+type FakeDriver struct {
+	mu        sync.Mutex
+	delay     time.Duration
+	instances map[uuid.UUID]*fakeInstance
+	volumes   map[uuid.UUID]*fakeVolume
+	failures  map[string]error
+}
+
+// NewFakeDriver creates a new fake driver. Every lifecycle call blocks for the given delay.
+func NewFakeDriver(delay time.Duration) *FakeDriver {
+	return &FakeDriver{
+		delay:     delay,
+		instances: make(map[uuid.UUID]*fakeInstance),
+		volumes:   make(map[uuid.UUID]*fakeVolume),
+		failures:  make(map[string]error),
+	}
+}
+
+// Name returns the driver name
+func (d *FakeDriver) Name() string {
+	return DriverFake
+}
+
+// FailNext makes the next call of the given operation return err
+func (d *FakeDriver) FailNext(operation string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.failures[operation] = err
+}
+
+// Provision registers the VM with the driver
+func (d *FakeDriver) Provision(ctx context.Context, vm *models.VM, base *BaseImage) error {
+	return d.transition(ctx, "provision", vm, func(inst *fakeInstance, exists bool) (*fakeInstance, error) {
+		if exists {
+			return nil, fmt.Errorf("vm %s is already provisioned", vm.ID)
+		}
+		return &fakeInstance{state: fakeStateStopped}, nil
+	})
+}
+
+// Start boots the VM
+func (d *FakeDriver) Start(ctx context.Context, vm *models.VM) error {
+	return d.transition(ctx, "start", vm, func(inst *fakeInstance, exists bool) (*fakeInstance, error) {
+		if !exists {
+			// VMs created before the driver was running are adopted on first use
+			inst = &fakeInstance{}
+		}
+		if inst.state == fakeStateRunning {
+			return nil, fmt.Errorf("vm %s is already running", vm.ID)
+		}
+		inst.state = fakeStateRunning
+		inst.startedAt = time.Now()
+		return inst, nil
+	})
+}
+
+// Stop shuts the VM down
+func (d *FakeDriver) Stop(ctx context.Context, vm *models.VM) error {
+	return d.transition(ctx, "stop", vm, d.powerOff)
+}
+
+// ForceStop powers the VM off
+func (d *FakeDriver) ForceStop(ctx context.Context, vm *models.VM) error {
+	return d.transition(ctx, "force-stop", vm, d.powerOff)
+}
+
+// Suspend pauses the VM
+func (d *FakeDriver) Suspend(ctx context.Context, vm *models.VM) error {
+	return d.transition(ctx, "suspend", vm, func(inst *fakeInstance, exists bool) (*fakeInstance, error) {
+		if !exists || inst.state != fakeStateRunning {
+			return nil, fmt.Errorf("vm %s is not running", vm.ID)
+		}
+		inst.state = fakeStateSuspended
+		return inst, nil
+	})
+}
+
+// Resume continues the VM
+func (d *FakeDriver) Resume(ctx context.Context, vm *models.VM) error {
+	return d.transition(ctx, "resume", vm, func(inst *fakeInstance, exists bool) (*fakeInstance, error) {
+		if !exists || inst.state != fakeStateSuspended {
+			return nil, fmt.Errorf("vm %s is not suspended", vm.ID)
+		}
+		inst.state = fakeStateRunning
+		return inst, nil
+	})
+}
+
+// Destroy removes the VM from the driver
+func (d *FakeDriver) Destroy(ctx context.Context, vm *models.VM) error {
+	return d.transition(ctx, "destroy", vm, func(inst *fakeInstance, exists bool) (*fakeInstance, error) {
+		if exists && inst.state != fakeStateStopped {
+			return nil, fmt.Errorf("vm %s is still %s", vm.ID, inst.state)
+		}
+		return nil, nil
+	})
+}
+
+// CreateSnapshot records the snapshot with the current power state of the VM
+func (d *FakeDriver) CreateSnapshot(ctx context.Context, vm *models.VM, snapshot *models.Snapshot) error {
+	return d.transition(ctx, "snapshot-create", vm, func(inst *fakeInstance, exists bool) (*fakeInstance, error) {
+		if !exists {
+			inst = &fakeInstance{state: fakeStateStopped}
+		}
+		if snapshot.Type == models.SnapshotTypeMemory && inst.state == fakeStateStopped {
+			return nil, fmt.Errorf("vm %s is not running", vm.ID)
+		}
+		if inst.snapshots == nil {
+			inst.snapshots = make(map[uuid.UUID]fakeState)
+		}
+		inst.snapshots[snapshot.ID] = inst.state
+		return inst, nil
+	})
+}
+
+// RevertSnapshot restores the VM to a snapshot, starting it for memory snapshots
+func (d *FakeDriver) RevertSnapshot(ctx context.Context, vm *models.VM, snapshot *models.Snapshot) error {
+	return d.transition(ctx, "snapshot-revert", vm, func(inst *fakeInstance, exists bool) (*fakeInstance, error) {
+		if !exists {
+			return nil, fmt.Errorf("snapshot %s of vm %s does not exist", snapshot.ID, vm.ID)
+		}
+		if _, ok := inst.snapshots[snapshot.ID]; !ok {
+			return nil, fmt.Errorf("snapshot %s of vm %s does not exist", snapshot.ID, vm.ID)
+		}
+		if inst.state != fakeStateStopped {
+			return nil, fmt.Errorf("vm %s is still %s", vm.ID, inst.state)
+		}
+		if snapshot.Type == models.SnapshotTypeMemory {
+			inst.state = fakeStateRunning
+			inst.startedAt = time.Now()
+		}
+		return inst, nil
+	})
+}
+
+// DeleteSnapshot forgets the snapshot
+func (d *FakeDriver) DeleteSnapshot(ctx context.Context, vm *models.VM, snapshot *models.Snapshot) error {
+	return d.transition(ctx, "snapshot-delete", vm, func(inst *fakeInstance, exists bool) (*fakeInstance, error) {
+		if !exists {
+			return nil, nil
+		}
+		delete(inst.snapshots, snapshot.ID)
+		return inst, nil
+	})
+}
+
+// Clone registers the clone as a stopped VM after checking that the source can be copied
+func (d *FakeDriver) Clone(ctx context.Context, source, clone *models.VM, snapshot *models.Snapshot, mode models.CloneMode) error {
+	return d.transition(ctx, "clone", clone, func(inst *fakeInstance, exists bool) (*fakeInstance, error) {
+		if exists {
+			return nil, fmt.Errorf("vm %s is already provisioned", clone.ID)
+		}
+		if snapshot != nil {
+			src, ok := d.instances[source.ID]
+			if !ok {
+				return nil, fmt.Errorf("snapshot %s of vm %s does not exist", snapshot.ID, source.ID)
+			}
+			if _, ok := src.snapshots[snapshot.ID]; !ok {
+				return nil, fmt.Errorf("snapshot %s of vm %s does not exist", snapshot.ID, source.ID)
+			}
+		}
+		return &fakeInstance{state: fakeStateStopped}, nil
+	})
+}
+
+// CreateVolume registers the volume with the driver
+func (d *FakeDriver) CreateVolume(ctx context.Context, volume *models.Volume) error {
+	return d.updateVolumes(ctx, "volume-create", func() error {
+		if _, exists := d.volumes[volume.ID]; exists {
+			return fmt.Errorf("volume %s already exists", volume.ID)
+		}
+		d.volumes[volume.ID] = &fakeVolume{sizeGb: volume.SizeGb}
+		return nil
+	})
+}
+
+// ResizeVolume grows the volume, refusing to shrink it
+func (d *FakeDriver) ResizeVolume(ctx context.Context, volume *models.Volume) error {
+	return d.updateVolumes(ctx, "volume-resize", func() error {
+		vol := d.adoptVolume(volume)
+		if volume.SizeGb < vol.sizeGb {
+			return fmt.Errorf("volume %s cannot shrink from %dG to %dG", volume.ID, vol.sizeGb, volume.SizeGb)
+		}
+		vol.sizeGb = volume.SizeGb
+		return nil
+	})
+}
+
+// DeleteVolume forgets a detached volume
+func (d *FakeDriver) DeleteVolume(ctx context.Context, volume *models.Volume) error {
+	return d.updateVolumes(ctx, "volume-delete", func() error {
+		if vol, exists := d.volumes[volume.ID]; exists && vol.vmID != uuid.Nil {
+			return fmt.Errorf("volume %s is attached to vm %s", volume.ID, vol.vmID)
+		}
+		delete(d.volumes, volume.ID)
+		return nil
+	})
+}
+
+// AttachVolume attaches the volume to a stopped or running VM
+func (d *FakeDriver) AttachVolume(ctx context.Context, vm *models.VM, volume *models.Volume) error {
+	return d.updateVolumes(ctx, "volume-attach", func() error {
+		if inst, exists := d.instances[vm.ID]; exists && inst.state == fakeStateSuspended {
+			return fmt.Errorf("vm %s is suspended", vm.ID)
+		}
+		vol := d.adoptVolume(volume)
+		if vol.vmID != uuid.Nil {
+			return fmt.Errorf("volume %s is attached to vm %s", volume.ID, vol.vmID)
+		}
+		vol.vmID = vm.ID
+		return nil
+	})
+}
+
+// DetachVolume detaches the volume from the VM
+func (d *FakeDriver) DetachVolume(ctx context.Context, vm *models.VM, volume *models.Volume) error {
+	return d.updateVolumes(ctx, "volume-detach", func() error {
+		vol, exists := d.volumes[volume.ID]
+		if !exists {
+			return nil
+		}
+		if vol.vmID != uuid.Nil && vol.vmID != vm.ID {
+			return fmt.Errorf("volume %s is attached to vm %s", volume.ID, vol.vmID)
+		}
+		vol.vmID = uuid.Nil
+		return nil
+	})
+}
+
+// adoptVolume returns the state of a volume, registering volumes created before
+// the driver was running on first use
+func (d *FakeDriver) adoptVolume(volume *models.Volume) *fakeVolume {
+	vol, exists := d.volumes[volume.ID]
+	if !exists {
+		vol = &fakeVolume{sizeGb: volume.SizeGb}
+		if volume.VMID != nil {
+			vol.vmID = *volume.VMID
+		}
+		d.volumes[volume.ID] = vol
+	}
+	return vol
+}
+
+// Stats returns deterministic statistics derived from the VM ID and the number of samples taken
+func (d *FakeDriver) Stats(ctx context.Context, vm *models.VM) (*models.VMStats, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.takeFailure("stats"); err != nil {
+		return nil, err
+	}
+
+	inst, exists := d.instances[vm.ID]
+	if !exists || inst.state != fakeStateRunning {
+		return nil, fmt.Errorf("vm %s is not running", vm.ID)
+	}
+
+	inst.samples++
+	seed := vmSeed(vm.ID) + uint32(inst.samples)
+	inst.rxBytes += int64(seed%1024) * 1024
+	inst.txBytes += int64(seed%512) * 1024
+
+	return &models.VMStats{
+		CPUUsagePercent:  10 + float64(seed%80),
+		RAMUsagePercent:  20 + float64(seed%70),
+		DiskUsagePercent: 10 + float64(seed%50),
+		NetworkRxBytes:   inst.rxBytes,
+		NetworkTxBytes:   inst.txBytes,
+		UptimeSeconds:    int64(time.Since(inst.startedAt).Seconds()),
+		LastStatsUpdate:  time.Now(),
+	}, nil
+}
+
+// powerOff stops a running or suspended instance
+func (d *FakeDriver) powerOff(inst *fakeInstance, exists bool) (*fakeInstance, error) {
+	if !exists {
+		return &fakeInstance{state: fakeStateStopped}, nil
+	}
+	inst.state = fakeStateStopped
+	inst.startedAt = time.Time{}
+	return inst, nil
+}
+
+// transition applies fn to the instance of vm after the configured delay
+func (d *FakeDriver) transition(ctx context.Context, operation string, vm *models.VM, fn func(*fakeInstance, bool) (*fakeInstance, error)) error {
+	if err := d.sleep(ctx); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.takeFailure(operation); err != nil {
+		return err
+	}
+
+	inst, exists := d.instances[vm.ID]
+	next, err := fn(inst, exists)
+	if err != nil {
+		return err
+	}
+
+	if next == nil {
+		delete(d.instances, vm.ID)
+	} else {
+		d.instances[vm.ID] = next
+	}
+	return nil
+}
+
+// updateVolumes runs fn with the driver locked after the configured delay
+func (d *FakeDriver) updateVolumes(ctx context.Context, operation string, fn func() error) error {
+	if err := d.sleep(ctx); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.takeFailure(operation); err != nil {
+		return err
+	}
+	return fn()
+}
+
+// sleep blocks for the configured delay
+func (d *FakeDriver) sleep(ctx context.Context) error {
+	if d.delay > 0 {
+		select {
+		case <-time.After(d.delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// takeFailure returns and clears an injected failure
+func (d *FakeDriver) takeFailure(operation string) error {
+	err, ok := d.failures[operation]
+	if !ok {
+		return nil
+	}
+	delete(d.failures, operation)
+	return err
+}
+
+// vmSeed derives a stable number from a VM ID
+func vmSeed(id uuid.UUID) uint32 {
+	h := fnv.New32a()
+	h.Write(id[:])
+	return h.Sum32()
+}
@@ -0,0 +1,792 @@
+package hypervisor
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// clockTicks is the kernel USER_HZ value used by /proc/<pid>/stat
+const clockTicks = 100
+
+// diskDriveID is the QEMU drive ID of the VM disk, used to address it over QMP
+const diskDriveID = "disk0"
+
+// volumeControllerID is the QEMU device ID of the SCSI controller volumes are plugged into.
+// SCSI disks can be hot-plugged without reserving PCIe ports on the q35 machine.
+const volumeControllerID = "scsi0"
+
+// cpuSample is a previous CPU time reading used to compute usage deltas
+type cpuSample struct {
+	ticks int64
+	at    time.Time
+}
+
+// QEMUDriver runs each VM as a daemonized QEMU/KVM process controlled through QMP
+type QEMUDriver struct {
+	cfg     *config.HypervisorConfig
+	logger  *logger.Logger
+	mu      sync.Mutex
+	samples map[uuid.UUID]cpuSample
+}
+
+// NewQEMUDriver creates a new QEMU driver and prepares its working directories
+func NewQEMUDriver(cfg *config.HypervisorConfig, log *logger.Logger) (*QEMUDriver, error) {
+	if _, err := exec.LookPath(cfg.QEMUBinary); err != nil {
+		return nil, fmt.Errorf("qemu binary not found: %w", err)
+	}
+	if _, err := exec.LookPath(cfg.QEMUImgBinary); err != nil {
+		return nil, fmt.Errorf("qemu-img binary not found: %w", err)
+	}
+
+	for _, dir := range []string{cfg.DataDir, filepath.Join(cfg.DataDir, "volumes"), cfg.RunDir} {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+	}
+
+	return &QEMUDriver{
+		cfg:     cfg,
+		logger:  log.WithComponent("qemu-driver"),
+		samples: make(map[uuid.UUID]cpuSample),
+	}, nil
+}
+
+// Name returns the driver name
+func (d *QEMUDriver) Name() string {
+	return DriverQEMU
+}
+
+// Provision creates a copy-on-write disk backed by the VM image
+func (d *QEMUDriver) Provision(ctx context.Context, vm *models.VM, base *BaseImage) error {
+	if base.Path == "" {
+		return fmt.Errorf("base image %s has not been uploaded", base.Reference)
+	}
+	if _, err := os.Stat(base.Path); err != nil {
+		return fmt.Errorf("base image %s not available: %w", base.Reference, err)
+	}
+
+	args := []string{
+		"create", "-f", "qcow2",
+		"-F", string(base.Format), "-b", base.Path,
+		d.diskPath(vm.ID),
+		fmt.Sprintf("%dG", vm.Spec.DiskGb),
+	}
+
+	if out, err := exec.CommandContext(ctx, d.cfg.QEMUImgBinary, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("qemu-img create failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	d.logger.Infof("Provisioned disk for VM %s", vm.ID)
+	return nil
+}
+
+// Start launches the QEMU process for the VM
+func (d *QEMUDriver) Start(ctx context.Context, vm *models.VM) error {
+	return d.launch(ctx, vm)
+}
+
+// Stop sends an ACPI power-down and waits for the guest to exit
+func (d *QEMUDriver) Stop(ctx context.Context, vm *models.VM) error {
+	if err := d.qmp(ctx, vm.ID, "system_powerdown"); err != nil {
+		return err
+	}
+
+	timeout := d.cfg.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+
+	if err := d.waitForExit(ctx, vm.ID, timeout); err != nil {
+		d.logger.Warnf("Graceful shutdown of VM %s timed out, forcing power off", vm.ID)
+		return d.ForceStop(ctx, vm)
+	}
+
+	d.cleanup(vm.ID)
+	return nil
+}
+
+// ForceStop terminates the QEMU process
+func (d *QEMUDriver) ForceStop(ctx context.Context, vm *models.VM) error {
+	if err := d.qmp(ctx, vm.ID, "quit"); err != nil {
+		pid, pidErr := d.pid(vm.ID)
+		if pidErr != nil {
+			return err
+		}
+		if killErr := syscall.Kill(pid, syscall.SIGKILL); killErr != nil && killErr != syscall.ESRCH {
+			return fmt.Errorf("failed to kill qemu process %d: %w", pid, killErr)
+		}
+	}
+
+	if err := d.waitForExit(ctx, vm.ID, 10*time.Second); err != nil {
+		return err
+	}
+
+	d.cleanup(vm.ID)
+	return nil
+}
+
+// Suspend pauses guest execution
+func (d *QEMUDriver) Suspend(ctx context.Context, vm *models.VM) error {
+	return d.qmp(ctx, vm.ID, "stop")
+}
+
+// Resume continues guest execution
+func (d *QEMUDriver) Resume(ctx context.Context, vm *models.VM) error {
+	return d.qmp(ctx, vm.ID, "cont")
+}
+
+// Destroy removes the VM disk and control files, and linked-clone bases no other disk is backed by
+func (d *QEMUDriver) Destroy(ctx context.Context, vm *models.VM) error {
+	if pid, err := d.pid(vm.ID); err == nil && processAlive(pid) {
+		return fmt.Errorf("vm %s is still running (pid %d)", vm.ID, pid)
+	}
+
+	d.cleanup(vm.ID)
+	if err := os.Remove(d.diskPath(vm.ID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove disk of vm %s: %w", vm.ID, err)
+	}
+	if err := d.releaseBases(vm.ID.String()); err != nil {
+		return err
+	}
+
+	d.logger.Infof("Destroyed VM %s", vm.ID)
+	return nil
+}
+
+// CreateSnapshot creates an internal qcow2 snapshot. Running VMs are snapshotted
+// live; memory snapshots additionally save the device and memory state.
+func (d *QEMUDriver) CreateSnapshot(ctx context.Context, vm *models.VM, snapshot *models.Snapshot) error {
+	tag := snapshotTag(snapshot)
+
+	if !d.running(vm.ID) {
+		if snapshot.Type == models.SnapshotTypeMemory {
+			return fmt.Errorf("vm %s is not running", vm.ID)
+		}
+		return d.qemuImg(ctx, "snapshot", "-c", tag, d.diskPath(vm.ID))
+	}
+
+	if snapshot.Type == models.SnapshotTypeMemory {
+		return d.hmp(ctx, vm.ID, "savevm "+tag)
+	}
+	return d.qmpCall(ctx, vm.ID, "blockdev-snapshot-internal-sync", map[string]string{
+		"device": diskDriveID,
+		"name":   tag,
+	}, nil)
+}
+
+// RevertSnapshot applies a snapshot to the disk of a stopped VM. Memory
+// snapshots are restored by booting QEMU from the saved state.
+func (d *QEMUDriver) RevertSnapshot(ctx context.Context, vm *models.VM, snapshot *models.Snapshot) error {
+	if d.running(vm.ID) {
+		return fmt.Errorf("vm %s is still running", vm.ID)
+	}
+
+	tag := snapshotTag(snapshot)
+	if snapshot.Type == models.SnapshotTypeMemory {
+		return d.launch(ctx, vm, "-loadvm", tag)
+	}
+	return d.qemuImg(ctx, "snapshot", "-a", tag, d.diskPath(vm.ID))
+}
+
+// DeleteSnapshot removes an internal qcow2 snapshot
+func (d *QEMUDriver) DeleteSnapshot(ctx context.Context, vm *models.VM, snapshot *models.Snapshot) error {
+	tag := snapshotTag(snapshot)
+
+	if !d.running(vm.ID) {
+		if _, err := os.Stat(d.diskPath(vm.ID)); os.IsNotExist(err) {
+			return nil
+		}
+		err := d.qemuImg(ctx, "snapshot", "-d", tag, d.diskPath(vm.ID))
+		if err != nil && strings.Contains(err.Error(), "not found") {
+			return nil
+		}
+		return err
+	}
+
+	if snapshot.Type == models.SnapshotTypeMemory {
+		return d.hmp(ctx, vm.ID, "delvm "+tag)
+	}
+	return d.qmpCall(ctx, vm.ID, "blockdev-snapshot-delete-internal-sync", map[string]string{
+		"device": diskDriveID,
+		"name":   tag,
+	}, nil)
+}
+
+// Clone creates the disk of a new VM from the disk of a stopped source VM, or from one of its
+// snapshots. Full clones copy the flattened disk. Linked clones freeze the current source disk
+// into a shared read-only base and give both VMs a new copy-on-write overlay on top of it.
+func (d *QEMUDriver) Clone(ctx context.Context, source, clone *models.VM, snapshot *models.Snapshot, mode models.CloneMode) error {
+	if mode == models.CloneModeLinked {
+		if snapshot != nil {
+			return fmt.Errorf("linked clones cannot be created from snapshots")
+		}
+		return d.linkedClone(ctx, source, clone)
+	}
+
+	// Reading a snapshot of a running VM requires skipping the image lock
+	args := []string{"convert", "-U", "-O", "qcow2"}
+	if snapshot != nil {
+		args = append(args, "-l", "snapshot.name="+snapshotTag(snapshot))
+	}
+	args = append(args, d.diskPath(source.ID), d.diskPath(clone.ID))
+	if err := d.qemuImg(ctx, args...); err != nil {
+		os.Remove(d.diskPath(clone.ID))
+		return err
+	}
+
+	if clone.Spec.DiskGb > source.Spec.DiskGb {
+		if err := d.qemuImg(ctx, "resize", d.diskPath(clone.ID), fmt.Sprintf("%dG", clone.Spec.DiskGb)); err != nil {
+			os.Remove(d.diskPath(clone.ID))
+			return err
+		}
+	}
+
+	d.logger.Infof("Cloned disk of VM %s to VM %s", source.ID, clone.ID)
+	return nil
+}
+
+// linkedClone moves the source disk into the base directory and backs both VMs by it.
+// The bases the frozen source disk was itself backed by are now held by the new base.
+func (d *QEMUDriver) linkedClone(ctx context.Context, source, clone *models.VM) error {
+	if d.running(source.ID) {
+		return fmt.Errorf("vm %s is still running", source.ID)
+	}
+
+	baseDir := d.basesDir()
+	if err := os.MkdirAll(baseDir, 0750); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", baseDir, err)
+	}
+	baseID := uuid.New().String()
+	base := filepath.Join(baseDir, baseID+".qcow2")
+
+	if err := os.Rename(d.diskPath(source.ID), base); err != nil {
+		return fmt.Errorf("failed to freeze disk of vm %s: %w", source.ID, err)
+	}
+	if err := d.createOverlay(ctx, base, source); err != nil {
+		os.Rename(base, d.diskPath(source.ID))
+		return err
+	}
+	os.Chmod(base, 0440)
+
+	if err := d.moveBaseRefs(source.ID.String(), baseID); err != nil {
+		return err
+	}
+	if err := d.holdBase(baseID, source.ID.String()); err != nil {
+		return err
+	}
+
+	if err := d.createOverlay(ctx, base, clone); err != nil {
+		return err
+	}
+	if err := d.holdBase(baseID, clone.ID.String()); err != nil {
+		return err
+	}
+
+	d.logger.Infof("Created linked clone %s of VM %s on base %s", clone.ID, source.ID, base)
+	return nil
+}
+
+// holdBase records that the disk of holder, a VM or another base, is an overlay of a base.
+// Each base has a directory next to it with one empty file per holder.
+func (d *QEMUDriver) holdBase(baseID, holder string) error {
+	refs := d.baseRefsDir(baseID)
+	if err := os.MkdirAll(refs, 0750); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", refs, err)
+	}
+	f, err := os.Create(filepath.Join(refs, holder))
+	if err != nil {
+		return fmt.Errorf("failed to reference base %s: %w", baseID, err)
+	}
+	return f.Close()
+}
+
+// moveBaseRefs hands the base references of one holder over to another
+func (d *QEMUDriver) moveBaseRefs(from, to string) error {
+	refs, _ := filepath.Glob(filepath.Join(d.basesDir(), "*.refs", from))
+	for _, ref := range refs {
+		if err := os.Rename(ref, filepath.Join(filepath.Dir(ref), to)); err != nil {
+			return fmt.Errorf("failed to move base reference %s: %w", ref, err)
+		}
+	}
+	return nil
+}
+
+// releaseBases drops the base references of a holder and removes bases that are no longer
+// referenced, which in turn releases the bases they were backed by
+func (d *QEMUDriver) releaseBases(holder string) error {
+	refs, _ := filepath.Glob(filepath.Join(d.basesDir(), "*.refs", holder))
+	for _, ref := range refs {
+		if err := os.Remove(ref); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to release base reference %s: %w", ref, err)
+		}
+
+		dir := filepath.Dir(ref)
+		if remaining, err := os.ReadDir(dir); err != nil || len(remaining) > 0 {
+			continue
+		}
+
+		baseID := strings.TrimSuffix(filepath.Base(dir), ".refs")
+		base := filepath.Join(d.basesDir(), baseID+".qcow2")
+		if err := os.Remove(base); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove base %s: %w", base, err)
+		}
+		os.Remove(dir)
+		d.logger.Infof("Removed unreferenced base %s", base)
+
+		if err := d.releaseBases(baseID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createOverlay creates the disk of a VM as a copy-on-write overlay of a qcow2 base
+func (d *QEMUDriver) createOverlay(ctx context.Context, base string, vm *models.VM) error {
+	return d.qemuImg(ctx, "create", "-f", "qcow2", "-F", "qcow2", "-b", base,
+		d.diskPath(vm.ID), fmt.Sprintf("%dG", vm.Spec.DiskGb))
+}
+
+// CreateVolume creates an empty qcow2 file for a volume
+func (d *QEMUDriver) CreateVolume(ctx context.Context, volume *models.Volume) error {
+	if err := d.qemuImg(ctx, "create", "-f", "qcow2", d.volumePath(volume.ID), fmt.Sprintf("%dG", volume.SizeGb)); err != nil {
+		return err
+	}
+	d.logger.Infof("Created volume %s", volume.ID)
+	return nil
+}
+
+// ResizeVolume grows the file of a volume, through QMP if it is attached to a running VM
+func (d *QEMUDriver) ResizeVolume(ctx context.Context, volume *models.Volume) error {
+	if volume.VMID != nil && d.running(*volume.VMID) {
+		return d.qmpCall(ctx, *volume.VMID, "block_resize", map[string]interface{}{
+			"node-name": volumeNodeName(volume.ID),
+			"size":      int64(volume.SizeGb) << 30,
+		}, nil)
+	}
+	return d.qemuImg(ctx, "resize", d.volumePath(volume.ID), fmt.Sprintf("%dG", volume.SizeGb))
+}
+
+// DeleteVolume removes the file of a volume
+func (d *QEMUDriver) DeleteVolume(ctx context.Context, volume *models.Volume) error {
+	if err := os.Remove(d.volumePath(volume.ID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove volume %s: %w", volume.ID, err)
+	}
+	d.logger.Infof("Deleted volume %s", volume.ID)
+	return nil
+}
+
+// AttachVolume hot-plugs a volume into a running VM. Stopped VMs get their
+// attached volumes on the command line when they are started.
+func (d *QEMUDriver) AttachVolume(ctx context.Context, vm *models.VM, volume *models.Volume) error {
+	if _, err := os.Stat(d.volumePath(volume.ID)); err != nil {
+		return fmt.Errorf("volume %s not available: %w", volume.ID, err)
+	}
+	if !d.running(vm.ID) {
+		return nil
+	}
+
+	node := volumeNodeName(volume.ID)
+	if err := d.qmpCall(ctx, vm.ID, "blockdev-add", map[string]interface{}{
+		"driver":    "qcow2",
+		"node-name": node,
+		"file":      map[string]string{"driver": "file", "filename": d.volumePath(volume.ID)},
+	}, nil); err != nil {
+		return err
+	}
+	if err := d.qmpCall(ctx, vm.ID, "device_add", map[string]string{
+		"driver": "scsi-hd",
+		"bus":    volumeControllerID + ".0",
+		"drive":  node,
+		"id":     volumeDeviceID(volume.ID),
+		"serial": volumeSerial(volume.ID),
+	}, nil); err != nil {
+		d.qmpCall(ctx, vm.ID, "blockdev-del", map[string]string{"node-name": node}, nil)
+		return err
+	}
+
+	d.logger.Infof("Hot-plugged volume %s into VM %s", volume.ID, vm.ID)
+	return nil
+}
+
+// DetachVolume unplugs a volume from a running VM
+func (d *QEMUDriver) DetachVolume(ctx context.Context, vm *models.VM, volume *models.Volume) error {
+	if !d.running(vm.ID) {
+		return nil
+	}
+
+	if err := d.qmpCall(ctx, vm.ID, "device_del", map[string]string{"id": volumeDeviceID(volume.ID)}, nil); err != nil {
+		return err
+	}
+	if err := d.qmpCall(ctx, vm.ID, "blockdev-del", map[string]string{"node-name": volumeNodeName(volume.ID)}, nil); err != nil {
+		return err
+	}
+
+	d.logger.Infof("Unplugged volume %s from VM %s", volume.ID, vm.ID)
+	return nil
+}
+
+// Stats reads process statistics from /proc
+func (d *QEMUDriver) Stats(ctx context.Context, vm *models.VM) (*models.VMStats, error) {
+	pid, err := d.pid(vm.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ticks, err := processCPUTicks(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	rssBytes, err := processRSS(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	stats := &models.VMStats{
+		NetworkRxBytes:  vm.Stats.NetworkRxBytes,
+		NetworkTxBytes:  vm.Stats.NetworkTxBytes,
+		LastStatsUpdate: now,
+	}
+
+	// CPU usage is relative to the vCPUs assigned to the VM
+	d.mu.Lock()
+	if prev, ok := d.samples[vm.ID]; ok && vm.Spec.CPUCores > 0 {
+		elapsed := now.Sub(prev.at).Seconds()
+		if elapsed > 0 {
+			used := float64(ticks-prev.ticks) / clockTicks
+			stats.CPUUsagePercent = clampPercent(used / elapsed / float64(vm.Spec.CPUCores) * 100)
+		}
+	}
+	d.samples[vm.ID] = cpuSample{ticks: ticks, at: now}
+	d.mu.Unlock()
+
+	if vm.Spec.RAMMb > 0 {
+		stats.RAMUsagePercent = clampPercent(float64(rssBytes) / float64(int64(vm.Spec.RAMMb)*1024*1024) * 100)
+	}
+
+	if info, err := os.Stat(d.diskPath(vm.ID)); err == nil && vm.Spec.DiskGb > 0 {
+		stats.DiskUsagePercent = clampPercent(float64(info.Size()) / float64(int64(vm.Spec.DiskGb)*1024*1024*1024) * 100)
+	}
+
+	if info, err := os.Stat(d.pidPath(vm.ID)); err == nil {
+		stats.UptimeSeconds = int64(now.Sub(info.ModTime()).Seconds())
+	}
+
+	return stats, nil
+}
+
+// buildArgs builds the QEMU command line for a VM
+func (d *QEMUDriver) buildArgs(vm *models.VM) []string {
+	machine := "q35"
+	if d.cfg.EnableKVM {
+		machine += ",accel=kvm"
+	}
+
+	args := []string{
+		"-name", vm.Name,
+		"-uuid", vm.ID.String(),
+		"-machine", machine,
+		"-smp", strconv.Itoa(vm.Spec.CPUCores),
+		"-m", strconv.Itoa(vm.Spec.RAMMb),
+		"-drive", fmt.Sprintf("file=%s,if=virtio,format=qcow2,id=%s", d.diskPath(vm.ID), diskDriveID),
+		"-qmp", fmt.Sprintf("unix:%s,server=on,wait=off", d.socketPath(vm.ID)),
+		"-pidfile", d.pidPath(vm.ID),
+		"-display", "none",
+		"-daemonize",
+	}
+
+	if d.cfg.EnableKVM {
+		args = append(args, "-cpu", "host")
+	}
+
+	if vm.Spec.BootOrder != "" {
+		args = append(args, "-boot", "order="+bootDevices(vm.Spec.BootOrder))
+	}
+
+	// VMs without managed interfaces get a single NIC with an address chosen by the network
+	if len(vm.Interfaces) == 0 {
+		args = append(args, "-netdev", d.netdev(vm.Spec.NetworkType, "net0", nil), "-device", "virtio-net-pci,netdev=net0")
+	}
+	for _, iface := range vm.Interfaces {
+		id := fmt.Sprintf("net%d", iface.DeviceIndex)
+		args = append(args,
+			"-netdev", d.netdev(vm.Spec.NetworkType, id, iface),
+			"-device", fmt.Sprintf("virtio-net-pci,netdev=%s,mac=%s", id, iface.MACAddress),
+		)
+	}
+
+	// Volumes are plugged into a SCSI controller that is always present so they can be hot-plugged
+	args = append(args, "-device", "virtio-scsi-pci,id="+volumeControllerID)
+	for _, volume := range vm.Volumes {
+		node := volumeNodeName(volume.ID)
+		args = append(args,
+			"-blockdev", fmt.Sprintf("driver=qcow2,node-name=%s,file.driver=file,file.filename=%s", node, d.volumePath(volume.ID)),
+			"-device", fmt.Sprintf("scsi-hd,bus=%s.0,drive=%s,id=%s,serial=%s",
+				volumeControllerID, node, volumeDeviceID(volume.ID), volumeSerial(volume.ID)),
+		)
+	}
+
+	return args
+}
+
+// netdev returns the QEMU network backend of a NIC. User-mode networking hands the allocated
+// address of an interface to the guest over DHCP; bridged guests get it from the DHCP server
+// of the bridged network.
+func (d *QEMUDriver) netdev(networkType models.NetworkType, id string, iface *models.NetworkInterface) string {
+	if networkType == models.NetworkTypeBridge {
+		return fmt.Sprintf("bridge,id=%s,br=%s", id, d.cfg.BridgeName)
+	}
+	if iface == nil {
+		return "user,id=" + id
+	}
+
+	addr, err := netip.ParseAddr(iface.IPAddress)
+	if err != nil {
+		return "user,id=" + id
+	}
+	prefix := netip.PrefixFrom(addr, iface.PrefixLength).Masked()
+	return fmt.Sprintf("user,id=%s,net=%s,host=%s,dhcpstart=%s", id, prefix, iface.Gateway, iface.IPAddress)
+}
+
+// launch starts the daemonized QEMU process of a VM with optional extra arguments
+func (d *QEMUDriver) launch(ctx context.Context, vm *models.VM, extraArgs ...string) error {
+	if pid, err := d.pid(vm.ID); err == nil && processAlive(pid) {
+		return fmt.Errorf("vm %s is already running (pid %d)", vm.ID, pid)
+	}
+
+	// Remove stale control files from a previous run
+	os.Remove(d.socketPath(vm.ID))
+	os.Remove(d.pidPath(vm.ID))
+
+	args := append(d.buildArgs(vm), extraArgs...)
+	cmd := exec.CommandContext(ctx, d.cfg.QEMUBinary, args...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to launch qemu: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	d.logger.Infof("Started QEMU process for VM %s", vm.ID)
+	return nil
+}
+
+// running checks whether the QEMU process of the VM is alive
+func (d *QEMUDriver) running(id uuid.UUID) bool {
+	pid, err := d.pid(id)
+	return err == nil && processAlive(pid)
+}
+
+// qemuImg runs a qemu-img subcommand
+func (d *QEMUDriver) qemuImg(ctx context.Context, args ...string) error {
+	if out, err := exec.CommandContext(ctx, d.cfg.QEMUImgBinary, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("qemu-img %s failed: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// qmp executes a single QMP command against the VM
+func (d *QEMUDriver) qmp(ctx context.Context, id uuid.UUID, command string) error {
+	return d.qmpCall(ctx, id, command, nil, nil)
+}
+
+// qmpCall executes a single QMP command with arguments against the VM
+func (d *QEMUDriver) qmpCall(ctx context.Context, id uuid.UUID, command string, args interface{}, result interface{}) error {
+	client, err := dialQMP(ctx, d.socketPath(id))
+	if err != nil {
+		return err
+	}
+	defer client.close()
+
+	return client.call(command, args, result)
+}
+
+// hmp executes a human monitor command, which reports failures as output instead of QMP errors
+func (d *QEMUDriver) hmp(ctx context.Context, id uuid.UUID, commandLine string) error {
+	var output string
+	if err := d.qmpCall(ctx, id, "human-monitor-command", map[string]string{"command-line": commandLine}, &output); err != nil {
+		return err
+	}
+	if output = strings.TrimSpace(output); output != "" {
+		return fmt.Errorf("%s failed: %s", strings.Fields(commandLine)[0], output)
+	}
+	return nil
+}
+
+// waitForExit polls until the QEMU process of the VM has exited
+func (d *QEMUDriver) waitForExit(ctx context.Context, id uuid.UUID, timeout time.Duration) error {
+	pid, err := d.pid(id)
+	if err != nil {
+		return nil // No pid file means the process is gone
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for processAlive(pid) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline.C:
+			return fmt.Errorf("qemu process %d did not exit within %s", pid, timeout)
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// cleanup removes runtime control files of a VM
+func (d *QEMUDriver) cleanup(id uuid.UUID) {
+	os.Remove(d.socketPath(id))
+	os.Remove(d.pidPath(id))
+
+	d.mu.Lock()
+	delete(d.samples, id)
+	d.mu.Unlock()
+}
+
+// pid reads the QEMU process ID from the pid file
+func (d *QEMUDriver) pid(id uuid.UUID) (int, error) {
+	data, err := os.ReadFile(d.pidPath(id))
+	if err != nil {
+		return 0, fmt.Errorf("vm %s is not running: %w", id, err)
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+func (d *QEMUDriver) diskPath(id uuid.UUID) string {
+	return filepath.Join(d.cfg.DataDir, id.String()+".qcow2")
+}
+
+func (d *QEMUDriver) basesDir() string {
+	return filepath.Join(d.cfg.DataDir, "bases")
+}
+
+func (d *QEMUDriver) baseRefsDir(baseID string) string {
+	return filepath.Join(d.basesDir(), baseID+".refs")
+}
+
+func (d *QEMUDriver) volumePath(id uuid.UUID) string {
+	return filepath.Join(d.cfg.DataDir, "volumes", id.String()+".qcow2")
+}
+
+func (d *QEMUDriver) socketPath(id uuid.UUID) string {
+	return filepath.Join(d.cfg.RunDir, id.String()+".qmp")
+}
+
+func (d *QEMUDriver) pidPath(id uuid.UUID) string {
+	return filepath.Join(d.cfg.RunDir, id.String()+".pid")
+}
+
+// snapshotTag returns the name of a snapshot inside the qcow2 image
+func snapshotTag(snapshot *models.Snapshot) string {
+	return snapshot.ID.String()
+}
+
+// volumeNodeName returns the QEMU block node name of a volume; node names are limited to 31 characters
+func volumeNodeName(id uuid.UUID) string {
+	return "vol" + hex.EncodeToString(id[:12])
+}
+
+// volumeDeviceID returns the QEMU device ID of a volume
+func volumeDeviceID(id uuid.UUID) string {
+	return "volume-" + id.String()
+}
+
+// volumeSerial returns the disk serial of a volume, which guests see under /dev/disk/by-id
+func volumeSerial(id uuid.UUID) string {
+	return id.String()[:20]
+}
+
+// bootDevices maps the boot order of the VM spec to QEMU boot device letters
+func bootDevices(order string) string {
+	var devices strings.Builder
+	for _, dev := range strings.Split(order, ",") {
+		switch strings.TrimSpace(dev) {
+		case "hd":
+			devices.WriteString("c")
+		case "cdrom":
+			devices.WriteString("d")
+		case "network":
+			devices.WriteString("n")
+		}
+	}
+	if devices.Len() == 0 {
+		return "c"
+	}
+	return devices.String()
+}
+
+// processAlive checks whether a process exists
+func processAlive(pid int) bool {
+	return syscall.Kill(pid, 0) == nil
+}
+
+// processCPUTicks returns user and system CPU time of a process in clock ticks
+func processCPUTicks(pid int) (int64, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read process stats: %w", err)
+	}
+
+	// The command name may contain spaces, so fields are counted after the closing parenthesis
+	content := string(data)
+	fields := strings.Fields(content[strings.LastIndex(content, ")")+1:])
+	if len(fields) < 13 {
+		return 0, fmt.Errorf("unexpected format of /proc/%d/stat", pid)
+	}
+
+	utime, err := strconv.ParseInt(fields[11], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	stime, err := strconv.ParseInt(fields[12], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return utime + stime, nil
+}
+
+// processRSS returns the resident set size of a process in bytes
+func processRSS(pid int) (int64, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/statm", pid))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read process memory: %w", err)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("unexpected format of /proc/%d/statm", pid)
+	}
+
+	pages, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return pages * int64(os.Getpagesize()), nil
+}
+
+func clampPercent(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 100 {
+		return 100
+	}
+	return v
+}
@@ -0,0 +1,102 @@
+package hypervisor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+)
+
+// qmpClient is a minimal client for the QEMU Machine Protocol
+type qmpClient struct {
+	conn net.Conn
+	dec  *json.Decoder
+}
+
+// qmpMessage represents any message received from QEMU
+type qmpMessage struct {
+	Event  string          `json:"event,omitempty"`
+	Return json.RawMessage `json:"return,omitempty"`
+	Error  *struct {
+		Class string `json:"class"`
+		Desc  string `json:"desc"`
+	} `json:"error,omitempty"`
+}
+
+// dialQMP connects to a QMP unix socket and negotiates capabilities
+func dialQMP(ctx context.Context, socketPath string) (*qmpClient, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to QMP socket %s: %w", socketPath, err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Now().Add(30 * time.Second))
+	}
+
+	client := &qmpClient{
+		conn: conn,
+		dec:  json.NewDecoder(conn),
+	}
+
+	// Read greeting
+	var greeting map[string]interface{}
+	if err := client.dec.Decode(&greeting); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read QMP greeting: %w", err)
+	}
+
+	if err := client.execute("qmp_capabilities"); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// execute runs a QMP command without arguments and waits for its result
+func (c *qmpClient) execute(command string) error {
+	return c.call(command, nil, nil)
+}
+
+// call runs a QMP command with optional arguments and decodes its return value into result, if given
+func (c *qmpClient) call(command string, args interface{}, result interface{}) error {
+	request := map[string]interface{}{"execute": command}
+	if args != nil {
+		request["arguments"] = args
+	}
+	if err := json.NewEncoder(c.conn).Encode(request); err != nil {
+		return fmt.Errorf("failed to send QMP command %s: %w", command, err)
+	}
+
+	for {
+		var msg qmpMessage
+		if err := c.dec.Decode(&msg); err != nil {
+			return fmt.Errorf("failed to read QMP response for %s: %w", command, err)
+		}
+
+		// Asynchronous events may arrive before the response
+		if msg.Event != "" {
+			continue
+		}
+
+		if msg.Error != nil {
+			return fmt.Errorf("QMP command %s failed: %s: %s", command, msg.Error.Class, msg.Error.Desc)
+		}
+		if result != nil && len(msg.Return) > 0 {
+			if err := json.Unmarshal(msg.Return, result); err != nil {
+				return fmt.Errorf("failed to decode QMP response for %s: %w", command, err)
+			}
+		}
+		return nil
+	}
+}
+
+// close closes the QMP connection
+func (c *qmpClient) close() error {
+	return c.conn.Close()
+}
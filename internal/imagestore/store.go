@@ -0,0 +1,284 @@
+// Package imagestore keeps uploaded image files in a local directory.
+//
+// Uploads are written to "<dir>/uploads/<image id>.part" and may arrive in any
+// number of chunks, each continuing where the stored data ends, so an interrupted
+// upload resumes instead of starting over. A finished upload is verified and then
+// moved to "<dir>/<image id>.<format>", from where it backs the disks of VMs
+// created from the image.
+package imagestore
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+)
+
+var (
+	// ErrBusy is returned while another request writes the same upload
+	ErrBusy = errors.New("upload is in progress")
+	// ErrOffset is returned for chunks that do not start where the stored data ends
+	ErrOffset = errors.New("chunk does not start at the upload offset")
+	// ErrTooLarge is returned for uploads exceeding the size limit
+	ErrTooLarge = errors.New("upload exceeds the size limit")
+	// ErrExists is returned when finishing an upload whose file is already stored
+	ErrExists = errors.New("image file already exists")
+)
+
+// qcow2Magic starts every qcow2 file; files without it are stored as raw images
+var qcow2Magic = []byte{'Q', 'F', 'I', 0xfb}
+
+// File describes a finished upload
+type File struct {
+	Path   string
+	Format models.DiskFormat
+	Size   int64
+	// Checksum of the file as "<algorithm>:<hex digest>"
+	Checksum string
+}
+
+// Store keeps image files and partial uploads in a directory
+type Store struct {
+	dir     string
+	maxSize int64
+
+	mu     sync.Mutex
+	active map[uuid.UUID]struct{}
+}
+
+// New creates a store in dir, creating the directory if necessary
+func New(dir string, maxSize int64) (*Store, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "uploads"), 0o755); err != nil {
+		return nil, fmt.Errorf("create image store: %w", err)
+	}
+	return &Store{
+		dir:     dir,
+		maxSize: maxSize,
+		active:  make(map[uuid.UUID]struct{}),
+	}, nil
+}
+
+// MaxSize returns the size limit of uploads
+func (s *Store) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Path returns where the file of an image is stored. Files are named by image ID, as
+// references are neither unique across projects nor safe to use as file names.
+func (s *Store) Path(id uuid.UUID, format models.DiskFormat) string {
+	return filepath.Join(s.dir, id.String()+"."+string(format))
+}
+
+// partPath returns where the partial upload of an image is stored
+func (s *Store) partPath(id uuid.UUID) string {
+	return filepath.Join(s.dir, "uploads", id.String()+".part")
+}
+
+// Offset returns how many bytes of an upload have been received
+func (s *Store) Offset(id uuid.UUID) (int64, error) {
+	info, err := os.Stat(s.partPath(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// Write appends a chunk to an upload and returns the new offset. A chunk at offset
+// zero starts the upload over; any other offset must match the data received so far.
+func (s *Store) Write(id uuid.UUID, offset int64, r io.Reader) (int64, error) {
+	if err := s.acquire(id); err != nil {
+		return 0, err
+	}
+	defer s.release(id)
+
+	flags := os.O_WRONLY | os.O_CREATE
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(s.partPath(id), flags, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if size != offset {
+		return size, ErrOffset
+	}
+
+	// Read one byte beyond the limit to detect oversized uploads
+	written, err := io.Copy(f, io.LimitReader(r, s.maxSize-offset+1))
+	size += written
+	if err != nil {
+		// Keep what has been received so the client can resume from there
+		f.Sync()
+		return size, err
+	}
+	if size > s.maxSize {
+		f.Close()
+		s.discard(id)
+		return 0, ErrTooLarge
+	}
+	return size, f.Sync()
+}
+
+// Finish verifies a complete upload and moves it to the stored file of the image.
+// verify receives the file with its checksum computed using algorithm; uploads failing
+// verification are discarded.
+func (s *Store) Finish(id uuid.UUID, algorithm string, verify func(*File) error) (*File, error) {
+	if err := s.acquire(id); err != nil {
+		return nil, err
+	}
+	defer s.release(id)
+
+	file, err := s.inspect(id, algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(file); err != nil {
+		s.discard(id)
+		return nil, err
+	}
+
+	file.Path = s.Path(id, file.Format)
+	if _, err := os.Stat(file.Path); err == nil {
+		return nil, ErrExists
+	}
+	if err := os.Rename(s.partPath(id), file.Path); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// inspect hashes a partial upload and detects its format
+func (s *Store) inspect(id uuid.UUID, algorithm string) (*File, error) {
+	var h hash.Hash
+	switch algorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return nil, fmt.Errorf("unsupported checksum algorithm %q", algorithm)
+	}
+
+	f, err := os.Open(s.partPath(id))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	header := make([]byte, len(qcow2Magic))
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	h.Write(header[:n])
+	rest, err := io.Copy(h, f)
+	if err != nil {
+		return nil, err
+	}
+
+	format := models.DiskFormatRaw
+	if bytes.Equal(header[:n], qcow2Magic) {
+		format = models.DiskFormatQCOW2
+	}
+	return &File{
+		Format:   format,
+		Size:     int64(n) + rest,
+		Checksum: algorithm + ":" + hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+// Discard removes a partial upload
+func (s *Store) Discard(id uuid.UUID) error {
+	if err := s.acquire(id); err != nil {
+		return err
+	}
+	defer s.release(id)
+	return s.discard(id)
+}
+
+func (s *Store) discard(id uuid.UUID) error {
+	if err := os.Remove(s.partPath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Open opens a stored image file for reading
+func (s *Store) Open(id uuid.UUID, format models.DiskFormat) (*os.File, error) {
+	return os.Open(s.Path(id, format))
+}
+
+// Remove deletes a stored image file; missing files are ignored
+func (s *Store) Remove(id uuid.UUID, format models.DiskFormat) error {
+	if err := os.Remove(s.Path(id, format)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// RemoveStaleUploads deletes partial uploads that have not been written to since before
+// and returns how many were removed
+func (s *Store) RemoveStaleUploads(before time.Time) (int, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dir, "uploads"))
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		id, err := uuid.Parse(strings.TrimSuffix(entry.Name(), ".part"))
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(before) {
+			continue
+		}
+		if err := s.Discard(id); err != nil {
+			if errors.Is(err, ErrBusy) {
+				continue
+			}
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// acquire marks an upload as being written, failing if another request writes it
+func (s *Store) acquire(id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.active[id]; ok {
+		return ErrBusy
+	}
+	s.active[id] = struct{}{}
+	return nil
+}
+
+func (s *Store) release(id uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.active, id)
+}
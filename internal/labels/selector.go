@@ -0,0 +1,183 @@
+// Package labels parses Kubernetes-style label selectors such as
+// "env=prod,tier in (web,api),!deprecated".
+//
+// A selector is a comma separated list of requirements that must all hold.
+// Repositories translate selectors into queries and watchers evaluate them
+// in memory with Matches.
+package labels
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Operator is the comparison a requirement makes against a label
+type Operator string
+
+const (
+	Equals       Operator = "="
+	NotEquals    Operator = "!="
+	In           Operator = "in"
+	NotIn        Operator = "notin"
+	Exists       Operator = "exists"
+	DoesNotExist Operator = "!"
+)
+
+// maxKeyLength is the maximum length of a label key in a selector
+const maxKeyLength = 253
+
+// setRequirement matches "key in (a,b)" and "key notin (a,b)"
+var setRequirement = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+
+// Requirement is a single condition on a label
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string
+}
+
+// Matches checks if the labels satisfy the requirement.
+// As in Kubernetes, != and notin also match labels without the key.
+func (r Requirement) Matches(labels map[string]string) bool {
+	value, exists := labels[r.Key]
+
+	switch r.Operator {
+	case Exists:
+		return exists
+	case DoesNotExist:
+		return !exists
+	case Equals, In:
+		return exists && contains(r.Values, value)
+	case NotEquals, NotIn:
+		return !exists || !contains(r.Values, value)
+	default:
+		return false
+	}
+}
+
+// Selector is a list of requirements that must all hold
+type Selector []Requirement
+
+// Matches checks if the labels satisfy every requirement of the selector
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		if !r.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// Parse parses a selector. An empty string selects everything.
+func Parse(selector string) (Selector, error) {
+	var s Selector
+	for _, part := range split(selector) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("invalid label selector %q: empty requirement", selector)
+		}
+
+		r, err := parseRequirement(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector %q: %w", selector, err)
+		}
+		s = append(s, r)
+	}
+	return s, nil
+}
+
+// parseRequirement parses a single requirement
+func parseRequirement(part string) (Requirement, error) {
+	var r Requirement
+
+	switch {
+	case strings.HasPrefix(part, "!"):
+		r = Requirement{Key: strings.TrimSpace(part[1:]), Operator: DoesNotExist}
+
+	case setRequirement.MatchString(part):
+		match := setRequirement.FindStringSubmatch(part)
+		r = Requirement{Key: match[1], Operator: Operator(match[2])}
+		for _, value := range strings.Split(match[3], ",") {
+			r.Values = append(r.Values, strings.TrimSpace(value))
+		}
+
+	case strings.Contains(part, "!="):
+		key, value, _ := strings.Cut(part, "!=")
+		r = Requirement{Key: strings.TrimSpace(key), Operator: NotEquals, Values: []string{strings.TrimSpace(value)}}
+
+	case strings.Contains(part, "="):
+		key, value, _ := strings.Cut(part, "=")
+		value = strings.TrimPrefix(value, "=")
+		r = Requirement{Key: strings.TrimSpace(key), Operator: Equals, Values: []string{strings.TrimSpace(value)}}
+
+	default:
+		r = Requirement{Key: part, Operator: Exists}
+	}
+
+	if err := validateKey(r.Key); err != nil {
+		return r, err
+	}
+	for _, value := range r.Values {
+		if err := validateValue(value); err != nil {
+			return r, err
+		}
+	}
+	return r, nil
+}
+
+// split splits a selector at the commas outside of value sets
+func split(selector string) []string {
+	if strings.TrimSpace(selector) == "" {
+		return nil
+	}
+
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, selector[start:])
+}
+
+// validateKey checks that a key is non-empty and free of selector syntax
+func validateKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("missing label key")
+	}
+	if len(key) > maxKeyLength {
+		return fmt.Errorf("label key %q is longer than %d characters", key, maxKeyLength)
+	}
+	if strings.ContainsAny(key, " \t,()=!") {
+		return fmt.Errorf("invalid label key %q", key)
+	}
+	return nil
+}
+
+// validateValue checks that a value is free of selector syntax
+func validateValue(value string) error {
+	if strings.ContainsAny(value, " \t,()=!") {
+		return fmt.Errorf("invalid label value %q", value)
+	}
+	return nil
+}
+
+// contains reports whether the values include the value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
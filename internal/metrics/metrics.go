@@ -0,0 +1,229 @@
+// Package metrics exposes Prometheus collectors for the API, VM inventory and database.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/internal/database"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// scrapeTimeout bounds the database queries made while collecting metrics
+const scrapeTimeout = 5 * time.Second
+
+// VMCounter provides the number of VMs per node and status
+type VMCounter interface {
+	CountByNodeAndStatus(ctx context.Context) ([]*models.VMCount, error)
+}
+
+// DatabaseStatsProvider provides connection pool statistics
+type DatabaseStatsProvider interface {
+	GetStats() (*database.DatabaseStats, error)
+}
+
+// Metrics holds the Prometheus registry and the collectors updated by the application.
+// All observation methods are safe to call on a nil *Metrics, which disables them.
+type Metrics struct {
+	cfg      config.MetricsConfig
+	registry *prometheus.Registry
+	logger   *logger.Logger
+
+	httpRequests      *prometheus.CounterVec
+	httpDuration      *prometheus.HistogramVec
+	operationDuration *prometheus.HistogramVec
+	operationFailures *prometheus.CounterVec
+}
+
+// New creates the collectors and registers them under the configured namespace and subsystem
+func New(cfg config.MetricsConfig, logger *logger.Logger) *Metrics {
+	m := &Metrics{
+		cfg:      cfg,
+		registry: prometheus.NewRegistry(),
+		logger:   logger.WithComponent("metrics"),
+
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.Namespace,
+			Subsystem: cfg.Subsystem,
+			Name:      "http_requests_total",
+			Help:      "Total number of HTTP requests by route, method and status code.",
+		}, []string{"method", "route", "status"}),
+
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.Namespace,
+			Subsystem: cfg.Subsystem,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of HTTP requests by route and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+
+		operationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.Namespace,
+			Subsystem: cfg.Subsystem,
+			Name:      "operation_duration_seconds",
+			Help:      "Duration of VM lifecycle operations by type and final state.",
+			Buckets:   []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+		}, []string{"type", "state"}),
+
+		operationFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.Namespace,
+			Subsystem: cfg.Subsystem,
+			Name:      "operation_failures_total",
+			Help:      "Total number of failed VM lifecycle operations by type.",
+		}, []string{"type"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.operationDuration,
+		m.operationFailures,
+	)
+
+	return m
+}
+
+// Handler returns the HTTP handler serving the registered metrics
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Registry returns the registry the collectors are registered with
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// ObserveRequest records a completed HTTP request
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// ObserveOperation records a VM operation that reached a final state
+func (m *Metrics) ObserveOperation(op *models.Operation) {
+	if m == nil || !op.IsDone() {
+		return
+	}
+
+	if op.StartedAt != nil && op.CompletedAt != nil {
+		m.operationDuration.WithLabelValues(string(op.Type), string(op.State)).
+			Observe(op.CompletedAt.Sub(*op.StartedAt).Seconds())
+	}
+	if op.State == models.OperationStateFailed {
+		m.operationFailures.WithLabelValues(string(op.Type)).Inc()
+	}
+}
+
+// RegisterVMCounts exposes the number of VMs per node and status, queried on every scrape
+func (m *Metrics) RegisterVMCounts(counter VMCounter) {
+	m.registry.MustRegister(&vmCollector{
+		counter: counter,
+		logger:  m.logger,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(m.cfg.Namespace, m.cfg.Subsystem, "vms"),
+			"Number of virtual machines by node and status.",
+			[]string{"node", "status"}, nil,
+		),
+	})
+}
+
+// RegisterDatabaseStats exposes connection pool statistics, read on every scrape
+func (m *Metrics) RegisterDatabaseStats(provider DatabaseStatsProvider) {
+	name := func(metric string) string {
+		return prometheus.BuildFQName(m.cfg.Namespace, m.cfg.Subsystem, "db_"+metric)
+	}
+
+	m.registry.MustRegister(&databaseCollector{
+		provider:          provider,
+		logger:            m.logger,
+		openConnections:   prometheus.NewDesc(name("open_connections"), "Number of established database connections.", nil, nil),
+		inUse:             prometheus.NewDesc(name("connections_in_use"), "Number of database connections currently in use.", nil, nil),
+		idle:              prometheus.NewDesc(name("connections_idle"), "Number of idle database connections.", nil, nil),
+		waitCount:         prometheus.NewDesc(name("wait_count_total"), "Total number of connections waited for.", nil, nil),
+		waitDuration:      prometheus.NewDesc(name("wait_duration_seconds_total"), "Total time blocked waiting for a new connection.", nil, nil),
+		maxIdleClosed:     prometheus.NewDesc(name("max_idle_closed_total"), "Total number of connections closed due to the idle limit.", nil, nil),
+		maxLifetimeClosed: prometheus.NewDesc(name("max_lifetime_closed_total"), "Total number of connections closed due to the lifetime limit.", nil, nil),
+	})
+}
+
+// vmCollector reports VM counts from the repository
+type vmCollector struct {
+	counter VMCounter
+	logger  *logger.Logger
+	desc    *prometheus.Desc
+}
+
+// Describe implements prometheus.Collector
+func (c *vmCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector
+func (c *vmCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+
+	counts, err := c.counter.CountByNodeAndStatus(ctx)
+	if err != nil {
+		c.logger.Errorf("Failed to count VMs for metrics: %v", err)
+		return
+	}
+
+	for _, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count.Count), count.NodeID, string(count.Status))
+	}
+}
+
+// databaseCollector reports connection pool statistics
+type databaseCollector struct {
+	provider DatabaseStatsProvider
+	logger   *logger.Logger
+
+	openConnections   *prometheus.Desc
+	inUse             *prometheus.Desc
+	idle              *prometheus.Desc
+	waitCount         *prometheus.Desc
+	waitDuration      *prometheus.Desc
+	maxIdleClosed     *prometheus.Desc
+	maxLifetimeClosed *prometheus.Desc
+}
+
+// Describe implements prometheus.Collector
+func (c *databaseCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.openConnections
+	ch <- c.inUse
+	ch <- c.idle
+	ch <- c.waitCount
+	ch <- c.waitDuration
+	ch <- c.maxIdleClosed
+	ch <- c.maxLifetimeClosed
+}
+
+// Collect implements prometheus.Collector
+func (c *databaseCollector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := c.provider.GetStats()
+	if err != nil {
+		c.logger.Errorf("Failed to read database stats for metrics: %v", err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.openConnections, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.inUse, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(c.waitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.maxIdleClosed, prometheus.CounterValue, float64(stats.MaxIdleClosed))
+	ch <- prometheus.MustNewConstMetric(c.maxLifetimeClosed, prometheus.CounterValue, float64(stats.MaxLifetimeClosed))
+}
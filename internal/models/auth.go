@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// TokenResponse represents an issued access token
+type TokenResponse struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type" example:"Bearer"`
+	ExpiresIn   int64     `json:"expires_in" example:"86400"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
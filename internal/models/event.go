@@ -0,0 +1,118 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VMEventType represents the kind of change recorded for a virtual machine
+type VMEventType string
+
+const (
+	VMEventCreated       VMEventType = "created"
+	VMEventUpdated       VMEventType = "updated"
+	VMEventDeleted       VMEventType = "deleted"
+	VMEventStatusChanged VMEventType = "status_changed"
+
+	VMEventSnapshotCreated  VMEventType = "snapshot_created"
+	VMEventSnapshotDeleted  VMEventType = "snapshot_deleted"
+	VMEventSnapshotReverted VMEventType = "snapshot_reverted"
+
+	VMEventVolumeAttached VMEventType = "volume_attached"
+	VMEventVolumeDetached VMEventType = "volume_detached"
+)
+
+// VMEvent represents an entry in the audit trail of a virtual machine
+type VMEvent struct {
+	ID   uuid.UUID   `json:"id" gorm:"type:uuid;primary_key"`
+	Type VMEventType `json:"type" gorm:"type:varchar(20);not null;index"`
+
+	// Affected VM; the name is kept so events stay readable after deletion
+	VMID   uuid.UUID `json:"vm_id" gorm:"type:uuid;not null;index"`
+	VMName string    `json:"vm_name" gorm:"size:255"`
+
+	// Who changed what and why
+	Actor     string   `json:"actor" gorm:"size:255;index"`
+	Reason    string   `json:"reason,omitempty" gorm:"size:1000"`
+	OldStatus VMStatus `json:"old_status,omitempty" gorm:"type:varchar(20)"`
+	NewStatus VMStatus `json:"new_status,omitempty" gorm:"type:varchar(20)"`
+	Message   string   `json:"message,omitempty" gorm:"size:2000"`
+
+	// Correlation
+	RequestID   string     `json:"request_id,omitempty" gorm:"size:64;index"`
+	OperationID *uuid.UUID `json:"operation_id,omitempty" gorm:"type:uuid"`
+
+	// Timestamp
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+// BeforeCreate hook
+func (e *VMEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	return nil
+}
+
+// TableName returns the table name for VMEvent
+func (VMEvent) TableName() string {
+	return "vm_events"
+}
+
+// NewVMEvent creates an event for a VM
+func NewVMEvent(eventType VMEventType, vm *VM, actor, reason, requestID string) *VMEvent {
+	return &VMEvent{
+		Type:      eventType,
+		VMID:      vm.ID,
+		VMName:    vm.Name,
+		Actor:     actor,
+		Reason:    reason,
+		RequestID: requestID,
+	}
+}
+
+// WithStatusChange records the status transition of the event
+func (e *VMEvent) WithStatusChange(oldStatus, newStatus VMStatus) *VMEvent {
+	e.OldStatus = oldStatus
+	e.NewStatus = newStatus
+	return e
+}
+
+// WithOperation links the event to the operation that caused it
+func (e *VMEvent) WithOperation(op *Operation) *VMEvent {
+	if op != nil {
+		id := op.ID
+		e.OperationID = &id
+	}
+	return e
+}
+
+// WithMessage adds a human-readable description to the event
+func (e *VMEvent) WithMessage(message string) *VMEvent {
+	e.Message = message
+	return e
+}
+
+// VMEventListOptions represents options for listing VM events
+type VMEventListOptions struct {
+	Page      int         `form:"page,default=1" binding:"min=1"`
+	Limit     int         `form:"limit,default=50" binding:"min=1,max=500"`
+	VMID      string      `form:"vm_id" binding:"omitempty,uuid"`
+	Type      VMEventType `form:"type" binding:"omitempty,oneof=created updated deleted status_changed snapshot_created snapshot_deleted snapshot_reverted volume_attached volume_detached"`
+	Actor     string      `form:"actor"`
+	RequestID string      `form:"request_id"`
+	NewStatus VMStatus    `form:"new_status" binding:"omitempty,oneof=pending stopped starting running stopping suspended error"`
+	Since     time.Time   `form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+	Until     time.Time   `form:"until" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// VMEventListResponse represents paginated VM event list response
+type VMEventListResponse struct {
+	Events     []*VMEvent `json:"events"`
+	Pagination Pagination `json:"pagination"`
+}
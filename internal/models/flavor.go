@@ -0,0 +1,127 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Flavor is a named VM size from the instance type catalog
+type Flavor struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	Name        string    `json:"name" gorm:"uniqueIndex;not null;size:63"`
+	Description string    `json:"description" gorm:"size:1000"`
+	CPUCores    int       `json:"cpu_cores" gorm:"not null"`
+	RAMMb       int       `json:"ram_mb" gorm:"not null"`
+	DiskGb      int       `json:"disk_gb" gorm:"not null"`
+
+	// Timestamps
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Audit fields
+	CreatedBy string `json:"created_by" gorm:"size:255"`
+	UpdatedBy string `json:"updated_by" gorm:"size:255"`
+}
+
+// BeforeCreate hook
+func (f *Flavor) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	f.CreatedAt = time.Now()
+	f.UpdatedAt = time.Now()
+	return nil
+}
+
+// BeforeUpdate hook
+func (f *Flavor) BeforeUpdate(tx *gorm.DB) error {
+	f.UpdatedAt = time.Now()
+	return nil
+}
+
+// TableName returns the table name for Flavor
+func (Flavor) TableName() string {
+	return "flavors"
+}
+
+// ApplyTo sets the resources of a VM spec to those of the flavor
+func (f *Flavor) ApplyTo(spec *VMSpec) {
+	spec.CPUCores = f.CPUCores
+	spec.RAMMb = f.RAMMb
+	spec.DiskGb = f.DiskGb
+}
+
+// ProjectFlavor allows a project to use a flavor. Projects without any may use every flavor and custom sizes.
+type ProjectFlavor struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	ProjectID  uuid.UUID `json:"project_id" gorm:"type:uuid;not null;uniqueIndex:idx_project_flavors_project_flavor"`
+	FlavorName string    `json:"flavor_name" gorm:"not null;size:63;uniqueIndex:idx_project_flavors_project_flavor;index"`
+	CreatedAt  time.Time `json:"created_at"`
+	CreatedBy  string    `json:"created_by" gorm:"size:255"`
+}
+
+// BeforeCreate hook
+func (p *ProjectFlavor) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	p.CreatedAt = time.Now()
+	return nil
+}
+
+// TableName returns the table name for ProjectFlavor
+func (ProjectFlavor) TableName() string {
+	return "project_flavors"
+}
+
+// ProjectFlavors reports the flavors a project may use
+type ProjectFlavors struct {
+	Project   string    `json:"project"`
+	ProjectID uuid.UUID `json:"project_id"`
+
+	// Restricted projects only accept VMs sized by one of their flavors
+	Restricted bool      `json:"restricted"`
+	Flavors    []*Flavor `json:"flavors"`
+}
+
+// FlavorCreateRequest represents a request to add a flavor to the catalog
+type FlavorCreateRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=63" example:"m1.small"`
+	Description string `json:"description" binding:"max=1000" example:"General purpose, 1 vCPU"`
+	CPUCores    int    `json:"cpu_cores" binding:"required,min=1,max=64" example:"1"`
+	RAMMb       int    `json:"ram_mb" binding:"required,min=512,max=524288" example:"2048"`
+	DiskGb      int    `json:"disk_gb" binding:"required,min=10,max=10240" example:"20"`
+	CreatedBy   string `json:"-"`
+}
+
+// ToFlavor converts create request to Flavor model
+func (req *FlavorCreateRequest) ToFlavor() *Flavor {
+	return &Flavor{
+		Name:        req.Name,
+		Description: req.Description,
+		CPUCores:    req.CPUCores,
+		RAMMb:       req.RAMMb,
+		DiskGb:      req.DiskGb,
+		CreatedBy:   req.CreatedBy,
+		UpdatedBy:   req.CreatedBy,
+	}
+}
+
+// FlavorUpdateRequest represents a request to replace the size of a flavor.
+// VMs already sized by the flavor keep their resources.
+type FlavorUpdateRequest struct {
+	Description string `json:"description" binding:"max=1000"`
+	CPUCores    int    `json:"cpu_cores" binding:"required,min=1,max=64" example:"1"`
+	RAMMb       int    `json:"ram_mb" binding:"required,min=512,max=524288" example:"2048"`
+	DiskGb      int    `json:"disk_gb" binding:"required,min=10,max=10240" example:"20"`
+	UpdatedBy   string `json:"-"`
+}
+
+// ProjectFlavorsUpdateRequest represents a request to replace the flavors a project may use.
+// An empty list lifts the restriction.
+type ProjectFlavorsUpdateRequest struct {
+	Flavors   []string `json:"flavors" binding:"dive,min=1,max=63" example:"m1.small,m1.medium"`
+	UpdatedBy string   `json:"-"`
+}
@@ -0,0 +1,276 @@
+package models
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OSFamily represents the operating system family of an image
+type OSFamily string
+
+const (
+	OSFamilyLinux   OSFamily = "linux"
+	OSFamilyWindows OSFamily = "windows"
+	OSFamilyBSD     OSFamily = "bsd"
+	OSFamilyOther   OSFamily = "other"
+)
+
+// ImageVisibility represents who may create VMs from an image
+type ImageVisibility string
+
+const (
+	// ImageVisibilityPublic images are available to every project
+	ImageVisibilityPublic ImageVisibility = "public"
+	// ImageVisibilityPrivate images are only available to the project owning them
+	ImageVisibilityPrivate ImageVisibility = "private"
+)
+
+// ImageState represents the deprecation state of an image
+type ImageState string
+
+const (
+	ImageStateActive     ImageState = "active"
+	ImageStateDeprecated ImageState = "deprecated"
+)
+
+// DiskFormat represents the format of an uploaded image file
+type DiskFormat string
+
+const (
+	DiskFormatQCOW2 DiskFormat = "qcow2"
+	DiskFormatRaw   DiskFormat = "raw"
+)
+
+// DefaultImageVersion is the version of image references without one
+const DefaultImageVersion = "latest"
+
+// checksumLengths maps the supported checksum algorithms to the length of their hex digest
+var checksumLengths = map[string]int{
+	"sha256": 64,
+	"sha512": 128,
+}
+
+// Image is a registered VM base image, referenced by VMs as "<name>:<version>"
+type Image struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	Name        string    `json:"name" gorm:"not null;size:128;uniqueIndex:idx_images_name_version"`
+	Version     string    `json:"version" gorm:"not null;size:63;uniqueIndex:idx_images_name_version"`
+	Description string    `json:"description" gorm:"size:1000"`
+	OSFamily    OSFamily  `json:"os_family" gorm:"type:varchar(20);not null;index"`
+
+	// Requirements VMs created from the image must meet
+	MinDiskGb int `json:"min_disk_gb" gorm:"not null;default:0"`
+	MinRAMMb  int `json:"min_ram_mb" gorm:"not null;default:0"`
+
+	// Checksum of the image file as "<algorithm>:<hex digest>", empty if unknown
+	Checksum string `json:"checksum,omitempty" gorm:"size:135"`
+
+	// Private images belong to a project
+	Visibility ImageVisibility `json:"visibility" gorm:"type:varchar(20);not null;default:'public'"`
+	ProjectID  *uuid.UUID      `json:"project_id,omitempty" gorm:"type:uuid;index"`
+
+	// Deprecated images cannot be used for new VMs; existing VMs keep running
+	State        ImageState `json:"state" gorm:"type:varchar(20);not null;default:'active';index"`
+	DeprecatedAt *time.Time `json:"deprecated_at,omitempty"`
+	Replacement  string     `json:"replacement,omitempty" gorm:"size:192"`
+
+	// Image file in the local image store, set once it has been uploaded
+	DiskFormat DiskFormat `json:"disk_format,omitempty" gorm:"type:varchar(20)"`
+	SizeBytes  int64      `json:"size_bytes,omitempty" gorm:"not null;default:0"`
+	UploadedAt *time.Time `json:"uploaded_at,omitempty" gorm:"index"`
+
+	// UnreferencedSince is when garbage collection first found no VM or snapshot using the uploaded file
+	UnreferencedSince *time.Time `json:"unreferenced_since,omitempty"`
+
+	// Timestamps
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Audit fields
+	CreatedBy string `json:"created_by" gorm:"size:255"`
+	UpdatedBy string `json:"updated_by" gorm:"size:255"`
+}
+
+// BeforeCreate hook
+func (i *Image) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	i.CreatedAt = time.Now()
+	i.UpdatedAt = time.Now()
+	return nil
+}
+
+// BeforeUpdate hook
+func (i *Image) BeforeUpdate(tx *gorm.DB) error {
+	i.UpdatedAt = time.Now()
+	return nil
+}
+
+// TableName returns the table name for Image
+func (Image) TableName() string {
+	return "images"
+}
+
+// Reference returns the name VMs refer to the image by
+func (i *Image) Reference() string {
+	return i.Name + ":" + i.Version
+}
+
+// References returns the image names VMs created from the image may carry
+func (i *Image) References() []string {
+	references := []string{i.Reference()}
+	if i.Version == DefaultImageVersion {
+		references = append(references, i.Name)
+	}
+	return references
+}
+
+// IsUploaded reports whether the image file is in the local image store
+func (i *Image) IsUploaded() bool {
+	return i.UploadedAt != nil
+}
+
+// AvailableTo reports whether VMs of the project may be created from the image
+func (i *Image) AvailableTo(projectID uuid.UUID) bool {
+	if i.Visibility != ImageVisibilityPrivate {
+		return true
+	}
+	return i.ProjectID != nil && *i.ProjectID == projectID
+}
+
+// IsDeprecated reports whether the image is deprecated
+func (i *Image) IsDeprecated() bool {
+	return i.State == ImageStateDeprecated
+}
+
+// Deprecate marks the image as deprecated, optionally naming the image to use instead
+func (i *Image) Deprecate(replacement string) {
+	if !i.IsDeprecated() {
+		now := time.Now()
+		i.DeprecatedAt = &now
+	}
+	i.State = ImageStateDeprecated
+	i.Replacement = replacement
+}
+
+// Undeprecate makes a deprecated image available again
+func (i *Image) Undeprecate() {
+	i.State = ImageStateActive
+	i.DeprecatedAt = nil
+	i.Replacement = ""
+}
+
+// ParseImageReference splits an image reference such as "ubuntu:22.04" into name and version.
+// References without a version refer to the "latest" version.
+func ParseImageReference(reference string) (name, version string) {
+	// Registry-style names may contain a port, so only a colon after the last slash separates the version
+	slash := strings.LastIndex(reference, "/")
+	colon := strings.LastIndex(reference, ":")
+	if colon <= slash {
+		return reference, DefaultImageVersion
+	}
+	return reference[:colon], reference[colon+1:]
+}
+
+// ValidateChecksum checks that a checksum has the form "<algorithm>:<hex digest>"
+// with a supported algorithm
+func ValidateChecksum(checksum string) error {
+	algorithm, digest, ok := strings.Cut(checksum, ":")
+	if !ok {
+		return fmt.Errorf("checksum must have the form <algorithm>:<hex digest>")
+	}
+	length, supported := checksumLengths[algorithm]
+	if !supported {
+		return fmt.Errorf("unsupported checksum algorithm %q, use sha256 or sha512", algorithm)
+	}
+	if len(digest) != length {
+		return fmt.Errorf("%s digest must have %d hex characters", algorithm, length)
+	}
+	if _, err := hex.DecodeString(digest); err != nil {
+		return fmt.Errorf("%s digest is not hex encoded", algorithm)
+	}
+	return nil
+}
+
+// ImageListOptions represents filters for listing images
+type ImageListOptions struct {
+	OSFamily          OSFamily `form:"os_family" binding:"omitempty,oneof=linux windows bsd other"`
+	Name              string   `form:"name" binding:"omitempty,max=128"`
+	IncludeDeprecated bool     `form:"include_deprecated"`
+}
+
+// ImageCreateRequest represents a request to register an image
+type ImageCreateRequest struct {
+	Name        string          `json:"name" binding:"required,min=1,max=128,excludesall=:" example:"ubuntu"`
+	Version     string          `json:"version" binding:"required,min=1,max=63,excludesall=:/" example:"22.04"`
+	Description string          `json:"description" binding:"max=1000" example:"Ubuntu 22.04 LTS server"`
+	OSFamily    OSFamily        `json:"os_family" binding:"required,oneof=linux windows bsd other" example:"linux"`
+	MinDiskGb   int             `json:"min_disk_gb" binding:"min=0,max=10240" example:"10"`
+	MinRAMMb    int             `json:"min_ram_mb" binding:"min=0,max=524288" example:"1024"`
+	Checksum    string          `json:"checksum,omitempty" binding:"omitempty,max=135" example:"sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	Visibility  ImageVisibility `json:"visibility" binding:"omitempty,oneof=public private" example:"public"`
+	Project     string          `json:"project,omitempty" binding:"omitempty,max=63" example:"team-a"`
+	CreatedBy   string          `json:"-"`
+}
+
+// ToImage converts create request to Image model; the project of private images is resolved by the caller
+func (req *ImageCreateRequest) ToImage() *Image {
+	image := &Image{
+		Name:        req.Name,
+		Version:     req.Version,
+		Description: req.Description,
+		OSFamily:    req.OSFamily,
+		MinDiskGb:   req.MinDiskGb,
+		MinRAMMb:    req.MinRAMMb,
+		Checksum:    req.Checksum,
+		Visibility:  req.Visibility,
+		State:       ImageStateActive,
+		CreatedBy:   req.CreatedBy,
+		UpdatedBy:   req.CreatedBy,
+	}
+	if image.Visibility == "" {
+		image.Visibility = ImageVisibilityPublic
+	}
+	return image
+}
+
+// ImageUpdateRequest represents a request to replace the mutable fields of an image.
+// Name, version, OS family and visibility cannot be changed.
+type ImageUpdateRequest struct {
+	Description string `json:"description" binding:"max=1000"`
+	MinDiskGb   int    `json:"min_disk_gb" binding:"min=0,max=10240" example:"10"`
+	MinRAMMb    int    `json:"min_ram_mb" binding:"min=0,max=524288" example:"1024"`
+	Checksum    string `json:"checksum,omitempty" binding:"omitempty,max=135"`
+
+	// Deprecated images cannot be used for new VMs; the replacement is suggested instead
+	Deprecated  bool   `json:"deprecated"`
+	Replacement string `json:"replacement,omitempty" binding:"omitempty,max=192" example:"ubuntu:24.04"`
+	UpdatedBy   string `json:"-"`
+}
+
+// ImageUploadRequest represents a chunk of an image file upload
+type ImageUploadRequest struct {
+	// Offset is the position of the chunk in the file; zero starts the upload over
+	Offset int64
+	// Total is the size of the whole file, or -1 if the chunk runs to the end of the file
+	Total int64
+	Body  io.Reader
+	// Checksum the file must match as "<algorithm>:<hex digest>", required unless the image has one
+	Checksum  string
+	UpdatedBy string
+}
+
+// ImageUpload reports the progress of an image file upload
+type ImageUpload struct {
+	Offset   int64  `json:"offset"`
+	Total    int64  `json:"total,omitempty"`
+	Complete bool   `json:"complete"`
+	Image    *Image `json:"image,omitempty"`
+}
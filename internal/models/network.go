@@ -0,0 +1,301 @@
+package models
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// macPrefix is the locally administered prefix of generated MAC addresses, the one QEMU uses
+var macPrefix = [3]byte{0x52, 0x54, 0x00}
+
+// AddressList is a list of IP addresses stored as a JSON array
+type AddressList []string
+
+// Value implements driver.Valuer
+func (l AddressList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (l *AddressList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return fmt.Errorf("cannot scan %T into AddressList", value)
+	}
+}
+
+// Network is an IPv4 network of a project. VMs get an interface with an address from
+// the DHCP range of each network they are connected to.
+type Network struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	ProjectID   uuid.UUID `json:"project_id" gorm:"type:uuid;not null;uniqueIndex:idx_networks_project_name"`
+	Name        string    `json:"name" gorm:"not null;size:63;uniqueIndex:idx_networks_project_name"`
+	Description string    `json:"description" gorm:"size:1000"`
+
+	// Addressing; only the DNS servers can change once VMs are connected
+	CIDR       string      `json:"cidr" gorm:"not null;size:18"`
+	Gateway    string      `json:"gateway" gorm:"not null;size:15"`
+	DNSServers AddressList `json:"dns_servers" gorm:"type:jsonb;not null"`
+	DHCPStart  string      `json:"dhcp_start" gorm:"not null;size:15"`
+	DHCPEnd    string      `json:"dhcp_end" gorm:"not null;size:15"`
+
+	// Timestamps
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Audit fields
+	CreatedBy string `json:"created_by" gorm:"size:255"`
+	UpdatedBy string `json:"updated_by" gorm:"size:255"`
+}
+
+// BeforeCreate hook
+func (n *Network) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	n.CreatedAt = time.Now()
+	n.UpdatedAt = time.Now()
+	return nil
+}
+
+// BeforeUpdate hook
+func (n *Network) BeforeUpdate(tx *gorm.DB) error {
+	n.UpdatedAt = time.Now()
+	return nil
+}
+
+// TableName returns the table name for Network
+func (Network) TableName() string {
+	return "networks"
+}
+
+// Prefix returns the parsed CIDR of the network
+func (n *Network) Prefix() netip.Prefix {
+	prefix, _ := netip.ParsePrefix(n.CIDR)
+	return prefix
+}
+
+// FreeAddress returns the lowest address of the DHCP range that is neither the gateway
+// nor allocated, or false if the range is exhausted
+func (n *Network) FreeAddress(allocated []string) (string, bool) {
+	used := make(map[netip.Addr]bool, len(allocated)+1)
+	for _, address := range allocated {
+		if addr, err := netip.ParseAddr(address); err == nil {
+			used[addr] = true
+		}
+	}
+	if gateway, err := netip.ParseAddr(n.Gateway); err == nil {
+		used[gateway] = true
+	}
+
+	start, err := netip.ParseAddr(n.DHCPStart)
+	if err != nil {
+		return "", false
+	}
+	end, err := netip.ParseAddr(n.DHCPEnd)
+	if err != nil {
+		return "", false
+	}
+	for addr := start; addr.IsValid() && addr.Compare(end) <= 0; addr = addr.Next() {
+		if !used[addr] {
+			return addr.String(), true
+		}
+	}
+	return "", false
+}
+
+// NewInterface returns an interface of the network for the VM with the address and MAC address
+func (n *Network) NewInterface(vmID uuid.UUID, deviceIndex int, address, mac string) *NetworkInterface {
+	return &NetworkInterface{
+		VMID:         vmID,
+		NetworkID:    n.ID,
+		Network:      n.Name,
+		DeviceIndex:  deviceIndex,
+		MACAddress:   mac,
+		IPAddress:    address,
+		PrefixLength: n.Prefix().Bits(),
+		Gateway:      n.Gateway,
+	}
+}
+
+// NetworkInterface connects a VM to a network with an allocated IP address.
+// The addressing of the network is copied so the interface can be configured without it.
+type NetworkInterface struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	VMID        uuid.UUID `json:"vm_id" gorm:"type:uuid;not null;uniqueIndex:idx_network_interfaces_vm_device"`
+	NetworkID   uuid.UUID `json:"network_id" gorm:"type:uuid;not null;uniqueIndex:idx_network_interfaces_network_ip"`
+	Network     string    `json:"network" gorm:"not null;size:63"`
+	DeviceIndex int       `json:"device_index" gorm:"not null;default:0;uniqueIndex:idx_network_interfaces_vm_device"`
+
+	// Addressing
+	MACAddress   string `json:"mac_address" gorm:"not null;size:17;uniqueIndex"`
+	IPAddress    string `json:"ip_address" gorm:"not null;size:15;uniqueIndex:idx_network_interfaces_network_ip"`
+	PrefixLength int    `json:"prefix_length" gorm:"not null"`
+	Gateway      string `json:"gateway" gorm:"not null;size:15"`
+
+	// Timestamps
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BeforeCreate hook
+func (i *NetworkInterface) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	i.CreatedAt = time.Now()
+	return nil
+}
+
+// TableName returns the table name for NetworkInterface
+func (NetworkInterface) TableName() string {
+	return "network_interfaces"
+}
+
+// SortInterfacesByAddress sorts interfaces by IP address in numeric order
+func SortInterfacesByAddress(interfaces []*NetworkInterface) {
+	sort.Slice(interfaces, func(i, j int) bool {
+		a, _ := netip.ParseAddr(interfaces[i].IPAddress)
+		b, _ := netip.ParseAddr(interfaces[j].IPAddress)
+		return a.Less(b)
+	})
+}
+
+// NewMACAddress returns a random MAC address with the locally administered QEMU prefix
+func NewMACAddress() (string, error) {
+	var suffix [3]byte
+	if _, err := rand.Read(suffix[:]); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%02x:%02x:%02x:%02x:%02x:%02x",
+		macPrefix[0], macPrefix[1], macPrefix[2], suffix[0], suffix[1], suffix[2]), nil
+}
+
+// NetworkCreateRequest represents a request to create a network. The gateway defaults
+// to the first address of the CIDR and the DHCP range to the addresses after the gateway.
+type NetworkCreateRequest struct {
+	Name        string    `json:"name" binding:"required,min=1,max=63" example:"frontend"`
+	Description string    `json:"description" binding:"max=1000" example:"Web tier"`
+	CIDR        string    `json:"cidr" binding:"required,cidrv4" example:"10.0.1.0/24"`
+	Gateway     string    `json:"gateway,omitempty" binding:"omitempty,ipv4" example:"10.0.1.1"`
+	DNSServers  []string  `json:"dns_servers,omitempty" binding:"omitempty,max=4,dive,ip" example:"10.0.0.53"`
+	DHCPStart   string    `json:"dhcp_start,omitempty" binding:"omitempty,ipv4" example:"10.0.1.10"`
+	DHCPEnd     string    `json:"dhcp_end,omitempty" binding:"omitempty,ipv4" example:"10.0.1.250"`
+	ProjectID   uuid.UUID `json:"-"`
+	CreatedBy   string    `json:"-"`
+}
+
+// ToNetwork converts create request to Network model, filling in the defaults and checking
+// that the gateway and DHCP range are host addresses of the CIDR
+func (req *NetworkCreateRequest) ToNetwork() (*Network, error) {
+	prefix, err := netip.ParsePrefix(req.CIDR)
+	if err != nil || !prefix.Addr().Is4() {
+		return nil, fmt.Errorf("cidr must be an IPv4 network such as 10.0.1.0/24")
+	}
+	if prefix != prefix.Masked() {
+		return nil, fmt.Errorf("cidr %s has host bits set, use %s", req.CIDR, prefix.Masked())
+	}
+	if prefix.Bits() > 29 {
+		return nil, fmt.Errorf("cidr must be /29 or larger to leave room for VMs")
+	}
+
+	// Host addresses exclude the network and broadcast addresses
+	first := prefix.Addr().Next()
+	last := lastAddress(prefix).Prev()
+
+	gateway, err := parseHostAddress("gateway", req.Gateway, first, first, last)
+	if err != nil {
+		return nil, err
+	}
+	start, err := parseHostAddress("dhcp_start", req.DHCPStart, gateway.Next(), first, last)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseHostAddress("dhcp_end", req.DHCPEnd, last, first, last)
+	if err != nil {
+		return nil, err
+	}
+	if start.Compare(end) > 0 {
+		return nil, fmt.Errorf("dhcp_start %s is after dhcp_end %s", start, end)
+	}
+	if start == end && start == gateway {
+		return nil, fmt.Errorf("dhcp range only contains the gateway %s", gateway)
+	}
+
+	dnsServers := AddressList(req.DNSServers)
+	if dnsServers == nil {
+		dnsServers = AddressList{}
+	}
+
+	return &Network{
+		ProjectID:   req.ProjectID,
+		Name:        req.Name,
+		Description: req.Description,
+		CIDR:        prefix.String(),
+		Gateway:     gateway.String(),
+		DNSServers:  dnsServers,
+		DHCPStart:   start.String(),
+		DHCPEnd:     end.String(),
+		CreatedBy:   req.CreatedBy,
+		UpdatedBy:   req.CreatedBy,
+	}, nil
+}
+
+// parseHostAddress parses an optional address that must lie between first and last
+func parseHostAddress(field, value string, fallback, first, last netip.Addr) (netip.Addr, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("%s %q is not an IPv4 address", field, value)
+	}
+	if addr.Compare(first) < 0 || addr.Compare(last) > 0 {
+		return netip.Addr{}, fmt.Errorf("%s %s must be a host address between %s and %s", field, addr, first, last)
+	}
+	return addr, nil
+}
+
+// lastAddress returns the broadcast address of an IPv4 prefix
+func lastAddress(prefix netip.Prefix) netip.Addr {
+	addr := prefix.Addr().As4()
+	hostBits := 32 - prefix.Bits()
+	for i := 3; i >= 0 && hostBits > 0; i-- {
+		bits := hostBits
+		if bits > 8 {
+			bits = 8
+		}
+		addr[i] |= byte(1<<bits - 1)
+		hostBits -= bits
+	}
+	return netip.AddrFrom4(addr)
+}
+
+// NetworkUpdateRequest represents a request to change the mutable fields of a network.
+// The addressing of a network cannot change.
+type NetworkUpdateRequest struct {
+	Description string   `json:"description" binding:"max=1000"`
+	DNSServers  []string `json:"dns_servers" binding:"omitempty,max=4,dive,ip" example:"10.0.0.53"`
+	UpdatedBy   string   `json:"-"`
+}
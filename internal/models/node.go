@@ -0,0 +1,118 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NodeState represents the state of a compute node
+type NodeState string
+
+const (
+	NodeStateReady       NodeState = "ready"
+	NodeStateUnreachable NodeState = "unreachable"
+	NodeStateMaintenance NodeState = "maintenance"
+)
+
+// Node represents a compute node that hosts virtual machines
+type Node struct {
+	ID      uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	Name    string    `json:"name" gorm:"uniqueIndex;not null;size:255"`
+	Address string    `json:"address" gorm:"size:255"`
+
+	// Total resources of the node
+	Capacity NodeCapacity `json:"capacity" gorm:"embedded;embeddedPrefix:capacity_"`
+
+	// Current state
+	State           NodeState  `json:"state" gorm:"type:varchar(20);default:'ready';index"`
+	LastHeartbeatAt *time.Time `json:"last_heartbeat_at,omitempty"`
+
+	// Metadata
+	Labels json.RawMessage `json:"labels,omitempty" gorm:"type:jsonb"`
+
+	// Timestamps
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// NodeCapacity represents the resources of a compute node
+type NodeCapacity struct {
+	CPUCores int `json:"cpu_cores" gorm:"not null"`
+	RAMMb    int `json:"ram_mb" gorm:"not null"`
+	DiskGb   int `json:"disk_gb" gorm:"not null"`
+}
+
+// BeforeCreate hook
+func (n *Node) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	n.CreatedAt = time.Now()
+	n.UpdatedAt = time.Now()
+	return nil
+}
+
+// BeforeUpdate hook
+func (n *Node) BeforeUpdate(tx *gorm.DB) error {
+	n.UpdatedAt = time.Now()
+	return nil
+}
+
+// TableName returns the table name for Node
+func (Node) TableName() string {
+	return "nodes"
+}
+
+// IsSchedulable checks if new VMs may be placed on the node
+func (n *Node) IsSchedulable() bool {
+	return n.State == NodeStateReady
+}
+
+// NodeRegisterRequest represents a request to register a node or refresh its inventory
+type NodeRegisterRequest struct {
+	Name     string            `json:"name" binding:"required,min=3,max=63" example:"node-01"`
+	Address  string            `json:"address" binding:"omitempty,max=255" example:"10.0.0.11"`
+	CPUCores int               `json:"cpu_cores" binding:"required,min=1" example:"64"`
+	RAMMb    int               `json:"ram_mb" binding:"required,min=512" example:"262144"`
+	DiskGb   int               `json:"disk_gb" binding:"required,min=10" example:"4096"`
+	Labels   map[string]string `json:"labels,omitempty" example:"zone:eu01-1"`
+}
+
+// ApplyToNode applies the registration data to a node model
+func (req *NodeRegisterRequest) ApplyToNode(node *Node) error {
+	node.Name = req.Name
+	node.Address = req.Address
+	node.Capacity = NodeCapacity{
+		CPUCores: req.CPUCores,
+		RAMMb:    req.RAMMb,
+		DiskGb:   req.DiskGb,
+	}
+
+	if req.Labels != nil {
+		labelsJSON, err := json.Marshal(req.Labels)
+		if err != nil {
+			return err
+		}
+		node.Labels = labelsJSON
+	}
+
+	return nil
+}
+
+// NodeListOptions represents options for listing nodes
+type NodeListOptions struct {
+	State NodeState `form:"state" binding:"omitempty,oneof=ready unreachable maintenance"`
+}
+
+// NodeAllocation represents the resources allocated to VMs on a node
+type NodeAllocation struct {
+	NodeID   string `json:"node_id"`
+	CPUCores int    `json:"cpu_cores"`
+	RAMMb    int    `json:"ram_mb"`
+	DiskGb   int    `json:"disk_gb"`
+	VMCount  int    `json:"vm_count"`
+}
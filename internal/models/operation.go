@@ -0,0 +1,166 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OperationType represents the kind of work tracked by an operation
+type OperationType string
+
+const (
+	OperationTypeProvision OperationType = "provision"
+	OperationTypeStart     OperationType = "start"
+	OperationTypeStop      OperationType = "stop"
+	OperationTypeForceStop OperationType = "force-stop"
+	OperationTypeRestart   OperationType = "restart"
+	OperationTypeSuspend   OperationType = "suspend"
+	OperationTypeResume    OperationType = "resume"
+	OperationTypeDelete    OperationType = "delete"
+	OperationTypeClone     OperationType = "clone"
+
+	OperationTypeSnapshotCreate OperationType = "snapshot-create"
+	OperationTypeSnapshotDelete OperationType = "snapshot-delete"
+	OperationTypeSnapshotRevert OperationType = "snapshot-revert"
+)
+
+// OperationState represents the state of an operation
+type OperationState string
+
+const (
+	OperationStatePending   OperationState = "pending"
+	OperationStateRunning   OperationState = "running"
+	OperationStateSucceeded OperationState = "succeeded"
+	OperationStateFailed    OperationState = "failed"
+)
+
+// Operation represents an asynchronous state change of a virtual machine
+type Operation struct {
+	ID   uuid.UUID     `json:"id" gorm:"type:uuid;primary_key"`
+	Type OperationType `json:"type" gorm:"type:varchar(20);not null;index"`
+
+	// Target of the operation
+	VMID       uuid.UUID  `json:"vm_id" gorm:"type:uuid;not null;index"`
+	SnapshotID *uuid.UUID `json:"snapshot_id,omitempty" gorm:"type:uuid;index"`
+
+	// Progress tracking
+	State    OperationState `json:"state" gorm:"type:varchar(20);default:'pending';index"`
+	Progress int            `json:"progress" gorm:"default:0"`
+	Error    string         `json:"error,omitempty" gorm:"size:2000"`
+
+	// Timestamps
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	// Audit fields
+	CreatedBy string `json:"created_by" gorm:"size:255"`
+}
+
+// BeforeCreate hook
+func (op *Operation) BeforeCreate(tx *gorm.DB) error {
+	if op.ID == uuid.Nil {
+		op.ID = uuid.New()
+	}
+	op.CreatedAt = time.Now()
+	op.UpdatedAt = time.Now()
+	return nil
+}
+
+// BeforeUpdate hook
+func (op *Operation) BeforeUpdate(tx *gorm.DB) error {
+	op.UpdatedAt = time.Now()
+	return nil
+}
+
+// TableName returns the table name for Operation
+func (Operation) TableName() string {
+	return "operations"
+}
+
+// NewOperation creates a pending operation for a VM
+func NewOperation(opType OperationType, vmID uuid.UUID, createdBy string) *Operation {
+	return &Operation{
+		Type:      opType,
+		VMID:      vmID,
+		State:     OperationStatePending,
+		CreatedBy: createdBy,
+	}
+}
+
+// NewSnapshotOperation creates a pending operation working on a snapshot of a VM
+func NewSnapshotOperation(opType OperationType, snapshot *Snapshot, createdBy string) *Operation {
+	op := NewOperation(opType, snapshot.VMID, createdBy)
+	op.SnapshotID = &snapshot.ID
+	return op
+}
+
+// IsDone checks if the operation has reached a final state
+func (op *Operation) IsDone() bool {
+	return op.State == OperationStateSucceeded || op.State == OperationStateFailed
+}
+
+// Start marks the operation as running
+func (op *Operation) Start() {
+	if op.IsDone() {
+		return
+	}
+	now := time.Now()
+	op.State = OperationStateRunning
+	op.StartedAt = &now
+}
+
+// SetProgress records the completion percentage of a running operation
+func (op *Operation) SetProgress(progress int) {
+	if op.IsDone() {
+		return
+	}
+	if progress < 0 {
+		progress = 0
+	}
+	if progress > 100 {
+		progress = 100
+	}
+	op.Progress = progress
+}
+
+// Succeed marks the operation as successfully completed
+func (op *Operation) Succeed() {
+	if op.IsDone() {
+		return
+	}
+	now := time.Now()
+	if op.StartedAt == nil {
+		op.StartedAt = &now
+	}
+	op.State = OperationStateSucceeded
+	op.Progress = 100
+	op.CompletedAt = &now
+}
+
+// Fail marks the operation as failed with the given error
+func (op *Operation) Fail(err error) {
+	if op.IsDone() {
+		return
+	}
+	now := time.Now()
+	if op.StartedAt == nil {
+		op.StartedAt = &now
+	}
+	op.State = OperationStateFailed
+	op.CompletedAt = &now
+	if err != nil {
+		op.Error = err.Error()
+	}
+}
+
+// OperationListOptions represents options for listing operations
+type OperationListOptions struct {
+	VMID  string         `form:"vm_id" binding:"omitempty,uuid"`
+	Type  OperationType  `form:"type" binding:"omitempty,oneof=provision start stop force-stop restart suspend resume delete clone snapshot-create snapshot-delete snapshot-revert"`
+	State OperationState `form:"state" binding:"omitempty,oneof=pending running succeeded failed"`
+	Limit int            `form:"limit,default=50" binding:"min=1,max=500"`
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultProjectName is the project that VMs created outside a project belong to.
+// Every authenticated caller is a member of it.
+const DefaultProjectName = "default"
+
+// Project represents a namespace of VMs owned by a group of subjects
+type Project struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	Name        string    `json:"name" gorm:"uniqueIndex;not null;size:63"`
+	Description string    `json:"description" gorm:"size:1000"`
+
+	// Timestamps
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Audit fields
+	CreatedBy string `json:"created_by" gorm:"size:255"`
+	UpdatedBy string `json:"updated_by" gorm:"size:255"`
+}
+
+// BeforeCreate hook
+func (p *Project) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	p.CreatedAt = time.Now()
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// BeforeUpdate hook
+func (p *Project) BeforeUpdate(tx *gorm.DB) error {
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// TableName returns the table name for Project
+func (Project) TableName() string {
+	return "projects"
+}
+
+// IsDefault reports whether the project is the default project
+func (p *Project) IsDefault() bool {
+	return p.Name == DefaultProjectName
+}
+
+// ProjectMember grants a subject access to the VMs of a project
+type ProjectMember struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	ProjectID uuid.UUID `json:"project_id" gorm:"type:uuid;not null;uniqueIndex:idx_project_members_project_subject"`
+	Subject   string    `json:"subject" gorm:"not null;size:255;uniqueIndex:idx_project_members_project_subject;index"`
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `json:"created_by" gorm:"size:255"`
+}
+
+// BeforeCreate hook
+func (m *ProjectMember) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	m.CreatedAt = time.Now()
+	return nil
+}
+
+// TableName returns the table name for ProjectMember
+func (ProjectMember) TableName() string {
+	return "project_members"
+}
+
+// ProjectCreateRequest represents a request to create a project
+type ProjectCreateRequest struct {
+	Name        string `json:"name" binding:"required,min=3,max=63" example:"team-a"`
+	Description string `json:"description" binding:"max=1000" example:"VMs of team A"`
+	CreatedBy   string `json:"-"`
+}
+
+// ProjectMemberCreateRequest represents a request to add a subject to a project
+type ProjectMemberCreateRequest struct {
+	Subject   string `json:"subject" binding:"required,max=255" example:"alice"`
+	CreatedBy string `json:"-"`
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Quota dimensions reported when a quota is exceeded
+const (
+	QuotaDimensionVMs        = "vms"
+	QuotaDimensionRunningVMs = "running_vms"
+	QuotaDimensionCPUCores   = "cpu_cores"
+	QuotaDimensionRAMMb      = "ram_mb"
+	QuotaDimensionDiskGb     = "disk_gb"
+)
+
+// QuotaLimits represents the maximum usage of a project; zero means unlimited
+type QuotaLimits struct {
+	MaxVMs        int `json:"max_vms" gorm:"not null;default:0" binding:"min=0"`
+	MaxRunningVMs int `json:"max_running_vms" gorm:"not null;default:0" binding:"min=0"`
+	MaxCPUCores   int `json:"max_cpu_cores" gorm:"not null;default:0" binding:"min=0"`
+	MaxRAMMb      int `json:"max_ram_mb" gorm:"not null;default:0" binding:"min=0"`
+	MaxDiskGb     int `json:"max_disk_gb" gorm:"not null;default:0" binding:"min=0"`
+}
+
+// Quota represents the limits configured for a project
+type Quota struct {
+	ProjectID uuid.UUID   `json:"project_id" gorm:"type:uuid;primary_key"`
+	Limits    QuotaLimits `json:"limits" gorm:"embedded"`
+	UpdatedAt time.Time   `json:"updated_at"`
+	UpdatedBy string      `json:"updated_by" gorm:"size:255"`
+}
+
+// TableName returns the table name for Quota
+func (Quota) TableName() string {
+	return "project_quotas"
+}
+
+// QuotaUsage represents the resources currently allocated to the VMs and volumes of a project
+type QuotaUsage struct {
+	VMs        int64 `json:"vms"`
+	RunningVMs int64 `json:"running_vms"`
+	CPUCores   int64 `json:"cpu_cores"`
+	RAMMb      int64 `json:"ram_mb"`
+	DiskGb     int64 `json:"disk_gb"`
+}
+
+// QuotaRequest represents the additional usage an operation needs
+type QuotaRequest struct {
+	VMs        int
+	RunningVMs int
+	CPUCores   int
+	RAMMb      int
+	DiskGb     int
+}
+
+// QuotaViolation describes the first dimension a request would exceed
+type QuotaViolation struct {
+	Dimension string
+	Limit     int64
+	Used      int64
+	Requested int64
+}
+
+// Exceeded returns the first dimension whose limit the request would exceed, or nil.
+// Requests that do not grow a dimension never exceed it, even if it is already over its limit.
+func (l QuotaLimits) Exceeded(usage *QuotaUsage, req QuotaRequest) *QuotaViolation {
+	checks := []struct {
+		dimension string
+		limit     int
+		used      int64
+		requested int
+	}{
+		{QuotaDimensionVMs, l.MaxVMs, usage.VMs, req.VMs},
+		{QuotaDimensionRunningVMs, l.MaxRunningVMs, usage.RunningVMs, req.RunningVMs},
+		{QuotaDimensionCPUCores, l.MaxCPUCores, usage.CPUCores, req.CPUCores},
+		{QuotaDimensionRAMMb, l.MaxRAMMb, usage.RAMMb, req.RAMMb},
+		{QuotaDimensionDiskGb, l.MaxDiskGb, usage.DiskGb, req.DiskGb},
+	}
+
+	for _, check := range checks {
+		if check.limit == 0 || check.requested <= 0 {
+			continue
+		}
+		if check.used+int64(check.requested) > int64(check.limit) {
+			return &QuotaViolation{
+				Dimension: check.dimension,
+				Limit:     int64(check.limit),
+				Used:      check.used,
+				Requested: int64(check.requested),
+			}
+		}
+	}
+	return nil
+}
+
+// QuotaStatus represents the limits and usage of a project
+type QuotaStatus struct {
+	Project   string      `json:"project"`
+	ProjectID uuid.UUID   `json:"project_id"`
+	Limits    QuotaLimits `json:"limits"`
+	Usage     QuotaUsage  `json:"usage"`
+	Default   bool        `json:"default"`
+}
+
+// QuotaUpdateRequest represents a request to replace the limits of a project
+type QuotaUpdateRequest struct {
+	QuotaLimits
+	UpdatedBy string `json:"-"`
+}
@@ -0,0 +1,293 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Permission represents an action that can be granted to a role
+type Permission string
+
+const (
+	PermissionVMRead         Permission = "vm:read"
+	PermissionVMCreate       Permission = "vm:create"
+	PermissionVMUpdate       Permission = "vm:update"
+	PermissionVMDelete       Permission = "vm:delete"
+	PermissionVMStart        Permission = "vm:start"
+	PermissionVMStop         Permission = "vm:stop"
+	PermissionVMRestart      Permission = "vm:restart"
+	PermissionVMSuspend      Permission = "vm:suspend"
+	PermissionVMResume       Permission = "vm:resume"
+	PermissionSnapshotRead   Permission = "snapshot:read"
+	PermissionSnapshotWrite  Permission = "snapshot:write"
+	PermissionSnapshotRevert Permission = "snapshot:revert"
+	PermissionFlavorRead     Permission = "flavor:read"
+	PermissionFlavorWrite    Permission = "flavor:write"
+	PermissionImageRead      Permission = "image:read"
+	PermissionImageWrite     Permission = "image:write"
+	PermissionVolumeRead     Permission = "volume:read"
+	PermissionVolumeWrite    Permission = "volume:write"
+	PermissionNetworkRead    Permission = "network:read"
+	PermissionNetworkWrite   Permission = "network:write"
+	PermissionStatsRead      Permission = "stats:read"
+	PermissionNodeRead       Permission = "node:read"
+	PermissionNodeWrite      Permission = "node:write"
+	PermissionOperationRead  Permission = "operation:read"
+	PermissionEventRead      Permission = "event:read"
+	PermissionProjectRead    Permission = "project:read"
+	PermissionProjectWrite   Permission = "project:write"
+	PermissionQuotaRead      Permission = "quota:read"
+	PermissionQuotaWrite     Permission = "quota:write"
+	PermissionRBACManage     Permission = "rbac:manage"
+
+	// PermissionAll grants every permission
+	PermissionAll Permission = "*"
+)
+
+// AllPermissions lists every permission that can be granted individually
+var AllPermissions = []Permission{
+	PermissionVMRead,
+	PermissionVMCreate,
+	PermissionVMUpdate,
+	PermissionVMDelete,
+	PermissionVMStart,
+	PermissionVMStop,
+	PermissionVMRestart,
+	PermissionVMSuspend,
+	PermissionVMResume,
+	PermissionSnapshotRead,
+	PermissionSnapshotWrite,
+	PermissionSnapshotRevert,
+	PermissionFlavorRead,
+	PermissionFlavorWrite,
+	PermissionImageRead,
+	PermissionImageWrite,
+	PermissionVolumeRead,
+	PermissionVolumeWrite,
+	PermissionNetworkRead,
+	PermissionNetworkWrite,
+	PermissionStatsRead,
+	PermissionNodeRead,
+	PermissionNodeWrite,
+	PermissionOperationRead,
+	PermissionEventRead,
+	PermissionProjectRead,
+	PermissionProjectWrite,
+	PermissionQuotaRead,
+	PermissionQuotaWrite,
+	PermissionRBACManage,
+}
+
+// IsValid checks if the permission is known
+func (p Permission) IsValid() bool {
+	if p == PermissionAll {
+		return true
+	}
+	for _, known := range AllPermissions {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Built-in role names
+const (
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+	RoleAdmin    = "admin"
+)
+
+// viewerPermissions are the read-only permissions shared by all built-in roles
+var viewerPermissions = PermissionList{
+	PermissionVMRead,
+	PermissionSnapshotRead,
+	PermissionFlavorRead,
+	PermissionImageRead,
+	PermissionVolumeRead,
+	PermissionNetworkRead,
+	PermissionStatsRead,
+	PermissionNodeRead,
+	PermissionOperationRead,
+	PermissionEventRead,
+	PermissionProjectRead,
+	PermissionQuotaRead,
+}
+
+// BuiltinRoles returns the roles that exist without being stored
+func BuiltinRoles() []*Role {
+	operatorPermissions := append(PermissionList{}, viewerPermissions...)
+	operatorPermissions = append(operatorPermissions,
+		PermissionVMStart,
+		PermissionVMStop,
+		PermissionVMRestart,
+		PermissionVMSuspend,
+		PermissionVMResume,
+		PermissionSnapshotWrite,
+	)
+
+	return []*Role{
+		{
+			Name:        RoleViewer,
+			Description: "Read access to VMs, snapshots, flavors, images, volumes, networks, projects, quotas, nodes, operations, events and statistics",
+			Permissions: append(PermissionList{}, viewerPermissions...),
+			Builtin:     true,
+		},
+		{
+			Name:        RoleOperator,
+			Description: "Viewer access plus starting, stopping, restarting, suspending and resuming VMs and taking snapshots",
+			Permissions: operatorPermissions,
+			Builtin:     true,
+		},
+		{
+			Name:        RoleAdmin,
+			Description: "Full access including role management",
+			Permissions: PermissionList{PermissionAll},
+			Builtin:     true,
+		},
+	}
+}
+
+// BuiltinRole returns the built-in role with the given name, or nil
+func BuiltinRole(name string) *Role {
+	for _, role := range BuiltinRoles() {
+		if role.Name == name {
+			return role
+		}
+	}
+	return nil
+}
+
+// PermissionList is a list of permissions stored as a JSON array
+type PermissionList []Permission
+
+// Value implements driver.Valuer
+func (l PermissionList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (l *PermissionList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return fmt.Errorf("cannot scan %T into PermissionList", value)
+	}
+}
+
+// Grants checks if the list contains the permission or the wildcard
+func (l PermissionList) Grants(permission Permission) bool {
+	for _, p := range l {
+		if p == permission || p == PermissionAll {
+			return true
+		}
+	}
+	return false
+}
+
+// Role represents a named set of permissions
+type Role struct {
+	ID          uuid.UUID      `json:"id,omitempty" gorm:"type:uuid;primary_key"`
+	Name        string         `json:"name" gorm:"uniqueIndex;not null;size:63"`
+	Description string         `json:"description" gorm:"size:1000"`
+	Permissions PermissionList `json:"permissions" gorm:"type:jsonb;not null"`
+	Builtin     bool           `json:"builtin" gorm:"-"`
+
+	// Timestamps
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+
+	// Audit fields
+	CreatedBy string `json:"created_by,omitempty" gorm:"size:255"`
+	UpdatedBy string `json:"updated_by,omitempty" gorm:"size:255"`
+}
+
+// BeforeCreate hook
+func (r *Role) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	r.CreatedAt = time.Now()
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// BeforeUpdate hook
+func (r *Role) BeforeUpdate(tx *gorm.DB) error {
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// TableName returns the table name for Role
+func (Role) TableName() string {
+	return "roles"
+}
+
+// RoleBinding grants a role to a subject in addition to the roles of its token
+type RoleBinding struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	Subject   string    `json:"subject" gorm:"not null;size:255;uniqueIndex:idx_role_bindings_subject_role"`
+	Role      string    `json:"role" gorm:"not null;size:63;uniqueIndex:idx_role_bindings_subject_role;index"`
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `json:"created_by" gorm:"size:255"`
+}
+
+// BeforeCreate hook
+func (b *RoleBinding) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	b.CreatedAt = time.Now()
+	return nil
+}
+
+// TableName returns the table name for RoleBinding
+func (RoleBinding) TableName() string {
+	return "role_bindings"
+}
+
+// RoleCreateRequest represents a request to create a custom role
+type RoleCreateRequest struct {
+	Name        string       `json:"name" binding:"required,min=3,max=63" example:"on-call"`
+	Description string       `json:"description" binding:"max=1000" example:"On-call operators"`
+	Permissions []Permission `json:"permissions" binding:"required,min=1" example:"vm:read,vm:restart"`
+	CreatedBy   string       `json:"-"`
+}
+
+// RoleUpdateRequest represents a request to replace the permissions of a custom role
+type RoleUpdateRequest struct {
+	Description string       `json:"description" binding:"max=1000"`
+	Permissions []Permission `json:"permissions" binding:"required,min=1"`
+	UpdatedBy   string       `json:"-"`
+}
+
+// RoleBindingCreateRequest represents a request to grant a role to a subject
+type RoleBindingCreateRequest struct {
+	Subject   string `json:"subject" binding:"required,max=255" example:"alice"`
+	Role      string `json:"role" binding:"required,max=63" example:"operator"`
+	CreatedBy string `json:"-"`
+}
+
+// RoleBindingListOptions represents options for listing role bindings
+type RoleBindingListOptions struct {
+	Subject string `form:"subject"`
+	Role    string `form:"role"`
+}
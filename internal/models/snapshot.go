@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SnapshotType represents what a snapshot captures
+type SnapshotType string
+
+const (
+	// SnapshotTypeDisk captures the disk only; reverting leaves the VM stopped
+	SnapshotTypeDisk SnapshotType = "disk"
+	// SnapshotTypeMemory captures disk and memory; reverting brings the VM back running
+	SnapshotTypeMemory SnapshotType = "memory"
+)
+
+// SnapshotStatus represents the state of a snapshot
+type SnapshotStatus string
+
+const (
+	SnapshotStatusCreating SnapshotStatus = "creating"
+	SnapshotStatusReady    SnapshotStatus = "ready"
+	SnapshotStatusDeleting SnapshotStatus = "deleting"
+	SnapshotStatusError    SnapshotStatus = "error"
+)
+
+// IsBusy checks if an operation is still working on the snapshot
+func (s SnapshotStatus) IsBusy() bool {
+	return s == SnapshotStatusCreating || s == SnapshotStatusDeleting
+}
+
+// Snapshot represents a point-in-time capture of a virtual machine
+type Snapshot struct {
+	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
+	VMID        uuid.UUID      `json:"vm_id" gorm:"type:uuid;not null;uniqueIndex:idx_snapshots_vm_name"`
+	Name        string         `json:"name" gorm:"not null;size:63;uniqueIndex:idx_snapshots_vm_name"`
+	Description string         `json:"description" gorm:"size:1000"`
+	Type        SnapshotType   `json:"type" gorm:"type:varchar(20);not null"`
+	Status      SnapshotStatus `json:"status" gorm:"type:varchar(20);not null;index"`
+
+	// VMStatus is the status of the VM when the snapshot was taken
+	VMStatus VMStatus `json:"vm_status" gorm:"type:varchar(20)"`
+	Error    string   `json:"error,omitempty" gorm:"size:2000"`
+
+	// Timestamps
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Audit fields
+	CreatedBy string `json:"created_by" gorm:"size:255"`
+}
+
+// BeforeCreate hook
+func (s *Snapshot) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	s.CreatedAt = time.Now()
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// BeforeUpdate hook
+func (s *Snapshot) BeforeUpdate(tx *gorm.DB) error {
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// TableName returns the table name for Snapshot
+func (Snapshot) TableName() string {
+	return "snapshots"
+}
+
+// SnapshotCreateRequest represents a request to snapshot a VM
+type SnapshotCreateRequest struct {
+	Name        string       `json:"name" binding:"required,min=1,max=63" example:"before-upgrade"`
+	Description string       `json:"description" binding:"max=1000" example:"State before the kernel upgrade"`
+	Type        SnapshotType `json:"type" binding:"omitempty,oneof=disk memory" example:"disk"`
+	CreatedBy   string       `json:"-"`
+	RequestID   string       `json:"-"`
+}
@@ -1,10 +1,15 @@
 package models
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/labels"
 	"gorm.io/gorm"
 )
 
@@ -19,8 +24,22 @@ const (
 	VMStatusStopping  VMStatus = "stopping"
 	VMStatusSuspended VMStatus = "suspended"
 	VMStatusError     VMStatus = "error"
+	VMStatusDeleting  VMStatus = "deleting"
 )
 
+// IsPoweredOn reports whether VMs in the status count as running against quotas
+func (s VMStatus) IsPoweredOn() bool {
+	switch s {
+	case VMStatusStarting, VMStatusRunning, VMStatusStopping, VMStatusSuspended:
+		return true
+	default:
+		return false
+	}
+}
+
+// PoweredOnStatuses lists the statuses of VMs that count as running against quotas
+var PoweredOnStatuses = []VMStatus{VMStatusStarting, VMStatusRunning, VMStatusStopping, VMStatusSuspended}
+
 // NetworkType represents the network configuration type
 type NetworkType string
 
@@ -30,14 +49,26 @@ const (
 	NetworkTypeHost   NetworkType = "host"
 )
 
+// CloneMode represents how the disk of a cloned VM relates to its source
+type CloneMode string
+
+const (
+	// CloneModeFull copies the source disk, so the clone is independent of its source
+	CloneModeFull CloneMode = "full"
+	// CloneModeLinked shares the source disk as a read-only base, so only changes take space
+	CloneModeLinked CloneMode = "linked"
+)
+
 // VM represents a virtual machine entity
 type VM struct {
-	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Name        string    `json:"name" gorm:"uniqueIndex;not null;size:255"`
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	ProjectID   uuid.UUID `json:"project_id" gorm:"type:uuid;not null;uniqueIndex:idx_virtual_machines_project_name,where:deleted_at IS NULL"`
+	Name        string    `json:"name" gorm:"not null;size:255;uniqueIndex:idx_virtual_machines_project_name,where:deleted_at IS NULL"`
 	Description string    `json:"description" gorm:"size:1000"`
 
-	// Resource specifications
-	Spec VMSpec `json:"spec" gorm:"embedded"`
+	// Resource specifications, expanded from the flavor for VMs sized by one
+	Flavor string `json:"flavor,omitempty" gorm:"size:63;index"`
+	Spec   VMSpec `json:"spec" gorm:"embedded"`
 
 	// Current state
 	Status     VMStatus `json:"status" gorm:"type:varchar(20);default:'stopped';index"`
@@ -50,9 +81,25 @@ type VM struct {
 	// Resource allocation
 	NodeID string `json:"node_id" gorm:"size:255;index"`
 
+	// Origin of cloned VMs
+	SourceVMID       *uuid.UUID `json:"source_vm_id,omitempty" gorm:"type:uuid;index"`
+	SourceSnapshotID *uuid.UUID `json:"source_snapshot_id,omitempty" gorm:"type:uuid"`
+	CloneMode        CloneMode  `json:"clone_mode,omitempty" gorm:"type:varchar(20)"`
+
 	// Statistics
 	Stats VMStats `json:"stats" gorm:"embedded"`
 
+	// Data volumes attached to the VM, in attachment order. They are stored in the
+	// volumes table and loaded by the repository.
+	Volumes []*Volume `json:"volumes,omitempty" gorm:"-"`
+
+	// Network interfaces of the VM, in device order. They are stored in the
+	// network_interfaces table and loaded by the repository.
+	Interfaces []*NetworkInterface `json:"interfaces,omitempty" gorm:"-"`
+
+	// Version bumped on every write, used for optimistic concurrency control
+	ResourceVersion int64 `json:"resource_version" gorm:"not null;default:1"`
+
 	// Timestamps
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
@@ -91,6 +138,9 @@ func (vm *VM) BeforeCreate(tx *gorm.DB) error {
 	if vm.ID == uuid.Nil {
 		vm.ID = uuid.New()
 	}
+	if vm.ResourceVersion == 0 {
+		vm.ResourceVersion = 1
+	}
 	vm.CreatedAt = time.Now()
 	vm.UpdatedAt = time.Now()
 	return nil
@@ -102,6 +152,11 @@ func (vm *VM) BeforeUpdate(tx *gorm.DB) error {
 	return nil
 }
 
+// ETag returns the entity tag of the VM's current resource version
+func (vm *VM) ETag() string {
+	return `"` + strconv.FormatInt(vm.ResourceVersion, 10) + `"`
+}
+
 // TableName returns the table name for VM
 func (VM) TableName() string {
 	return "virtual_machines"
@@ -111,12 +166,13 @@ func (VM) TableName() string {
 func (vm *VM) IsValidStatusTransition(newStatus VMStatus) bool {
 	validTransitions := map[VMStatus][]VMStatus{
 		VMStatusPending:   {VMStatusStopped, VMStatusStarting, VMStatusError},
-		VMStatusStopped:   {VMStatusStarting, VMStatusPending, VMStatusError},
+		VMStatusStopped:   {VMStatusStarting, VMStatusPending, VMStatusError, VMStatusDeleting},
 		VMStatusStarting:  {VMStatusRunning, VMStatusStopped, VMStatusError},
 		VMStatusRunning:   {VMStatusStopping, VMStatusSuspended, VMStatusError},
 		VMStatusStopping:  {VMStatusStopped, VMStatusError, VMStatusRunning},
 		VMStatusSuspended: {VMStatusRunning, VMStatusStopped, VMStatusError},
 		VMStatusError:     {VMStatusStopped, VMStatusStarting},
+		VMStatusDeleting:  {VMStatusStopped, VMStatusError},
 	}
 
 	validNext, exists := validTransitions[vm.Status]
@@ -137,7 +193,7 @@ func (vm *VM) CanPerformOperation(operation string) bool {
 	switch operation {
 	case "start":
 		return vm.Status == VMStatusStopped
-	case "stop":
+	case "stop", "force-stop":
 		return vm.Status == VMStatusRunning || vm.Status == VMStatusStarting
 	case "restart":
 		return vm.Status == VMStatusRunning
@@ -149,6 +205,18 @@ func (vm *VM) CanPerformOperation(operation string) bool {
 		return vm.Status == VMStatusStopped
 	case "delete":
 		return vm.Status == VMStatusStopped
+	case "snapshot", "snapshot-delete":
+		return vm.Status == VMStatusStopped || vm.Status == VMStatusRunning || vm.Status == VMStatusSuspended
+	case "snapshot-memory":
+		return vm.Status == VMStatusRunning || vm.Status == VMStatusSuspended
+	case "snapshot-revert":
+		return vm.Status == VMStatusStopped
+	case "clone":
+		return vm.Status == VMStatusStopped
+	case "clone-snapshot":
+		return vm.Status == VMStatusStopped || vm.Status == VMStatusRunning || vm.Status == VMStatusSuspended
+	case "volume-attach", "volume-detach", "volume-resize":
+		return vm.Status == VMStatusStopped || vm.Status == VMStatusRunning
 	default:
 		return false
 	}
@@ -162,6 +230,39 @@ func (vm *VM) GetUptime() int64 {
 	return 0
 }
 
+// IPAddresses returns the addresses of the network interfaces of the VM in device order
+func (vm *VM) IPAddresses() []string {
+	addresses := make([]string, 0, len(vm.Interfaces))
+	for _, iface := range vm.Interfaces {
+		if iface.IPAddress != "" {
+			addresses = append(addresses, iface.IPAddress)
+		}
+	}
+	return addresses
+}
+
+// NetworkNames returns the names of the networks the VM is connected to in device order
+func (vm *VM) NetworkNames() []string {
+	names := make([]string, len(vm.Interfaces))
+	for i, iface := range vm.Interfaces {
+		names[i] = iface.Network
+	}
+	return names
+}
+
+// newInterfaces returns the interfaces of a new VM connected to the named networks,
+// to be allocated when the VM is stored
+func newInterfaces(networks []string) []*NetworkInterface {
+	if len(networks) == 0 {
+		return nil
+	}
+	interfaces := make([]*NetworkInterface, len(networks))
+	for i, name := range networks {
+		interfaces[i] = &NetworkInterface{Network: name, DeviceIndex: i}
+	}
+	return interfaces
+}
+
 // AddLabel adds a label to the VM
 func (vm *VM) AddLabel(key, value string) error {
 	labels := make(map[string]string)
@@ -195,6 +296,15 @@ func (vm *VM) GetLabel(key string) (string, bool) {
 	return value, exists
 }
 
+// GetLabels returns all labels of the VM
+func (vm *VM) GetLabels() map[string]string {
+	labels := make(map[string]string)
+	if vm.Labels != nil {
+		json.Unmarshal(vm.Labels, &labels)
+	}
+	return labels
+}
+
 // AddAnnotation adds an annotation to the VM
 func (vm *VM) AddAnnotation(key, value string) error {
 	annotations := make(map[string]string)
@@ -228,21 +338,48 @@ func (vm *VM) UpdateStats(cpuUsage, ramUsage, diskUsage float64, networkRx, netw
 type VMCreateRequest struct {
 	Name        string            `json:"name" binding:"required,min=3,max=63" example:"web-server-01"`
 	Description string            `json:"description" binding:"max=1000" example:"Production web server"`
-	CPUCores    int               `json:"cpu_cores" binding:"required,min=1,max=64" example:"4"`
-	RAMMb       int               `json:"ram_mb" binding:"required,min=512,max=524288" example:"8192"`
-	DiskGb      int               `json:"disk_gb" binding:"required,min=10,max=10240" example:"100"`
+	Flavor      string            `json:"flavor,omitempty" binding:"omitempty,max=63" example:"m1.large"`
+	CPUCores    int               `json:"cpu_cores,omitempty" binding:"omitempty,min=1,max=64" example:"4"`
+	RAMMb       int               `json:"ram_mb,omitempty" binding:"omitempty,min=512,max=524288" example:"8192"`
+	DiskGb      int               `json:"disk_gb,omitempty" binding:"omitempty,min=10,max=10240" example:"100"`
 	ImageName   string            `json:"image_name" binding:"required" example:"ubuntu:22.04"`
 	NetworkType NetworkType       `json:"network_type" binding:"omitempty,oneof=nat bridge host" example:"nat"`
+	Networks    []string          `json:"networks,omitempty" binding:"omitempty,max=8,unique,dive,min=1,max=63" example:"frontend"`
 	Labels      map[string]string `json:"labels,omitempty" example:"environment:production,tier:web"`
 	Annotations map[string]string `json:"annotations,omitempty"`
-	CreatedBy   string            `json:"created_by" binding:"required" example:"user123"`
+	ProjectID   uuid.UUID         `json:"-"`
+	CreatedBy   string            `json:"-"`
+	RequestID   string            `json:"-"`
+}
+
+// CheckSizing verifies that the request sizes the VM either by a flavor or by all of
+// cpu_cores, ram_mb and disk_gb
+func (req *VMCreateRequest) CheckSizing() error {
+	if err := checkFlavorSizing(req.Flavor, req.CPUCores, req.RAMMb, req.DiskGb); err != nil {
+		return err
+	}
+	if req.Flavor == "" && (req.CPUCores == 0 || req.RAMMb == 0 || req.DiskGb == 0) {
+		return fmt.Errorf("cpu_cores, ram_mb and disk_gb are required without a flavor")
+	}
+	return nil
+}
+
+// checkFlavorSizing rejects requests that set both a flavor and custom sizes
+func checkFlavorSizing(flavor string, cpu, ram, disk int) error {
+	if flavor != "" && (cpu != 0 || ram != 0 || disk != 0) {
+		return fmt.Errorf("flavor cannot be combined with cpu_cores, ram_mb or disk_gb")
+	}
+	return nil
 }
 
-// ToVM converts create request to VM model
+// ToVM converts create request to VM model. The spec of a VM sized by a flavor
+// is expanded from the flavor catalog.
 func (req *VMCreateRequest) ToVM() *VM {
 	vm := &VM{
+		ProjectID:   req.ProjectID,
 		Name:        req.Name,
 		Description: req.Description,
+		Flavor:      req.Flavor,
 		Spec: VMSpec{
 			CPUCores:    req.CPUCores,
 			RAMMb:       req.RAMMb,
@@ -250,9 +387,10 @@ func (req *VMCreateRequest) ToVM() *VM {
 			ImageName:   req.ImageName,
 			NetworkType: req.NetworkType,
 		},
-		Status:    VMStatusPending,
-		CreatedBy: req.CreatedBy,
-		UpdatedBy: req.CreatedBy,
+		Status:     VMStatusPending,
+		Interfaces: newInterfaces(req.Networks),
+		CreatedBy:  req.CreatedBy,
+		UpdatedBy:  req.CreatedBy,
 	}
 
 	if req.Labels != nil {
@@ -270,16 +408,103 @@ func (req *VMCreateRequest) ToVM() *VM {
 	return vm
 }
 
+// VMCloneRequest represents a request to clone a VM. Unset resources are copied from the source,
+// or taken from a flavor; labels and annotations are merged over those of the source.
+type VMCloneRequest struct {
+	Name        string            `json:"name" binding:"required,min=3,max=63" example:"web-server-02"`
+	Description string            `json:"description" binding:"max=1000" example:"Clone of the production web server"`
+	Mode        CloneMode         `json:"mode" binding:"omitempty,oneof=full linked" example:"full"`
+	SnapshotID  *uuid.UUID        `json:"snapshot_id,omitempty"`
+	Flavor      string            `json:"flavor,omitempty" binding:"omitempty,max=63" example:"m1.medium"`
+	CPUCores    int               `json:"cpu_cores,omitempty" binding:"omitempty,min=1,max=64" example:"2"`
+	RAMMb       int               `json:"ram_mb,omitempty" binding:"omitempty,min=512,max=524288" example:"4096"`
+	DiskGb      int               `json:"disk_gb,omitempty" binding:"omitempty,min=10,max=10240" example:"100"`
+	Labels      map[string]string `json:"labels,omitempty" example:"environment:staging"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	CreatedBy   string            `json:"-"`
+	RequestID   string            `json:"-"`
+}
+
+// CheckSizing verifies that the request does not set both a flavor and custom sizes
+func (req *VMCloneRequest) CheckSizing() error {
+	return checkFlavorSizing(req.Flavor, req.CPUCores, req.RAMMb, req.DiskGb)
+}
+
+// ToVM builds the clone of a source VM described by the request
+func (req *VMCloneRequest) ToVM(source *VM) (*VM, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = CloneModeFull
+	}
+
+	sourceID := source.ID
+	vm := &VM{
+		ProjectID:        source.ProjectID,
+		Name:             req.Name,
+		Description:      req.Description,
+		Flavor:           source.Flavor,
+		Spec:             source.Spec,
+		Status:           VMStatusPending,
+		Labels:           source.Labels,
+		Annotations:      source.Annotations,
+		SourceVMID:       &sourceID,
+		SourceSnapshotID: req.SnapshotID,
+		CloneMode:        mode,
+		Interfaces:       newInterfaces(source.NetworkNames()),
+		CreatedBy:        req.CreatedBy,
+		UpdatedBy:        req.CreatedBy,
+	}
+
+	if req.Flavor != "" {
+		vm.Flavor = req.Flavor
+	}
+	if req.CPUCores > 0 || req.RAMMb > 0 || req.DiskGb > 0 {
+		vm.Flavor = ""
+	}
+	if req.CPUCores > 0 {
+		vm.Spec.CPUCores = req.CPUCores
+	}
+	if req.RAMMb > 0 {
+		vm.Spec.RAMMb = req.RAMMb
+	}
+	if req.DiskGb > 0 {
+		vm.Spec.DiskGb = req.DiskGb
+	}
+
+	for key, value := range req.Labels {
+		if err := vm.AddLabel(key, value); err != nil {
+			return nil, err
+		}
+	}
+	for key, value := range req.Annotations {
+		if err := vm.AddAnnotation(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return vm, nil
+}
+
 // VMUpdateRequest represents a request to update a VM
 type VMUpdateRequest struct {
 	Name        string            `json:"name,omitempty" binding:"omitempty,min=3,max=63"`
 	Description string            `json:"description,omitempty" binding:"omitempty,max=1000"`
+	Flavor      string            `json:"flavor,omitempty" binding:"omitempty,max=63"`
 	CPUCores    int               `json:"cpu_cores,omitempty" binding:"omitempty,min=1,max=64"`
 	RAMMb       int               `json:"ram_mb,omitempty" binding:"omitempty,min=512,max=524288"`
 	DiskGb      int               `json:"disk_gb,omitempty" binding:"omitempty,min=10,max=10240"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
-	UpdatedBy   string            `json:"updated_by,omitempty"`
+	UpdatedBy   string            `json:"-"`
+	RequestID   string            `json:"-"`
+
+	// ResourceVersion is the version required by If-Match, zero if none
+	ResourceVersion int64 `json:"-"`
+}
+
+// CheckSizing verifies that the request does not set both a flavor and custom sizes
+func (req *VMUpdateRequest) CheckSizing() error {
+	return checkFlavorSizing(req.Flavor, req.CPUCores, req.RAMMb, req.DiskGb)
 }
 
 // ApplyToVM applies update request to VM model
@@ -290,6 +515,13 @@ func (req *VMUpdateRequest) ApplyToVM(vm *VM) error {
 	if req.Description != "" {
 		vm.Description = req.Description
 	}
+	if req.Flavor != "" {
+		vm.Flavor = req.Flavor
+	}
+	if req.CPUCores > 0 || req.RAMMb > 0 || req.DiskGb > 0 {
+		// Custom sizes replace the flavor
+		vm.Flavor = ""
+	}
 	if req.CPUCores > 0 {
 		vm.Spec.CPUCores = req.CPUCores
 	}
@@ -322,37 +554,222 @@ func (req *VMUpdateRequest) ApplyToVM(vm *VM) error {
 	return nil
 }
 
+// VMPatchRequest represents a JSON Merge Patch or JSON Patch of a VM
+type VMPatchRequest struct {
+	// ContentType is the media type of the patch, which selects its format
+	ContentType string
+	Patch       []byte
+	UpdatedBy   string
+	RequestID   string
+
+	// ResourceVersion is the version required by If-Match, zero if none
+	ResourceVersion int64
+}
+
+// PatchDocument returns the document patches of the VM are applied to. It has
+// the fields of a create request so patched documents are validated the same way.
+// VMs sized by a flavor show the flavor instead of their resources.
+func (vm *VM) PatchDocument() (*VMCreateRequest, error) {
+	doc := &VMCreateRequest{
+		Name:        vm.Name,
+		Description: vm.Description,
+		Flavor:      vm.Flavor,
+		ImageName:   vm.Spec.ImageName,
+		NetworkType: vm.Spec.NetworkType,
+	}
+	if vm.Flavor == "" {
+		doc.CPUCores = vm.Spec.CPUCores
+		doc.RAMMb = vm.Spec.RAMMb
+		doc.DiskGb = vm.Spec.DiskGb
+	}
+
+	if vm.Labels != nil {
+		if err := json.Unmarshal(vm.Labels, &doc.Labels); err != nil {
+			return nil, err
+		}
+	}
+	if vm.Annotations != nil {
+		if err := json.Unmarshal(vm.Annotations, &doc.Annotations); err != nil {
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
+
+// ApplyPatchDocument replaces the mutable fields of the VM with those of a patched document.
+// Labels and annotations missing from the document are cleared.
+func (vm *VM) ApplyPatchDocument(doc *VMCreateRequest) error {
+	vm.Name = doc.Name
+	vm.Description = doc.Description
+	vm.Flavor = doc.Flavor
+	if doc.Flavor == "" {
+		vm.Spec.CPUCores = doc.CPUCores
+		vm.Spec.RAMMb = doc.RAMMb
+		vm.Spec.DiskGb = doc.DiskGb
+	}
+
+	vm.Labels = nil
+	if len(doc.Labels) > 0 {
+		labelsJSON, err := json.Marshal(doc.Labels)
+		if err != nil {
+			return err
+		}
+		vm.Labels = labelsJSON
+	}
+
+	vm.Annotations = nil
+	if len(doc.Annotations) > 0 {
+		annotationsJSON, err := json.Marshal(doc.Annotations)
+		if err != nil {
+			return err
+		}
+		vm.Annotations = annotationsJSON
+	}
+
+	return nil
+}
+
 // VMStateChangeRequest represents a request to change VM state
 type VMStateChangeRequest struct {
 	Force     bool   `json:"force,omitempty" example:"false"`
 	Reason    string `json:"reason,omitempty" example:"Scheduled maintenance"`
-	UpdatedBy string `json:"updated_by,omitempty" example:"user123"`
+	UpdatedBy string `json:"-"`
+	RequestID string `json:"-"`
+
+	// ResourceVersion is the version required by If-Match, zero if none
+	ResourceVersion int64 `json:"-"`
 }
 
 // VMListOptions represents options for listing VMs
 type VMListOptions struct {
-	Page         int      `form:"page,default=1" binding:"min=1"`
-	Limit        int      `form:"limit,default=20" binding:"min=1,max=100"`
-	Status       VMStatus `form:"status" binding:"omitempty,oneof=pending stopped starting running stopping suspended error"`
-	NodeID       string   `form:"node_id"`
-	CreatedBy    string   `form:"created_by"`
-	Search       string   `form:"search"`
-	SortBy       string   `form:"sort_by,default=created_at" binding:"omitempty,oneof=created_at updated_at name status"`
-	SortOrder    string   `form:"sort_order,default=desc" binding:"omitempty,oneof=asc desc"`
-	IncludeStats bool     `form:"include_stats,default=false"`
+	Page          int      `form:"page,default=1" binding:"min=1"`
+	Limit         int      `form:"limit,default=20" binding:"min=1,max=100"`
+	Status        VMStatus `form:"status" binding:"omitempty,oneof=pending stopped starting running stopping suspended error deleting"`
+	NodeID        string   `form:"node_id"`
+	CreatedBy     string   `form:"created_by"`
+	Search        string   `form:"search"`
+	LabelSelector string   `form:"labelSelector"`
+	SortBy        string   `form:"sort_by,default=created_at" binding:"omitempty,oneof=created_at updated_at name status"`
+	SortOrder     string   `form:"sort_order,default=desc" binding:"omitempty,oneof=asc desc"`
+	IncludeStats  bool     `form:"include_stats,default=false"`
+
+	// Continue is the next_cursor of the previous page; when set, Page is ignored
+	Continue string `form:"continue"`
+	// IncludeTotal counts all matching VMs, which is slow for large inventories.
+	// Pages read with Continue are never counted.
+	IncludeTotal bool `form:"include_total,default=true"`
+}
+
+// VMListCursor is the position of the last VM of a page in keyset pagination.
+// It holds the sort key and ID of the VM and the sort order it is valid for.
+type VMListCursor struct {
+	SortBy    string    `json:"s"`
+	SortOrder string    `json:"o"`
+	Value     string    `json:"v"`
+	ID        uuid.UUID `json:"id"`
+}
+
+// NewVMListCursor returns the cursor positioned at a VM
+func NewVMListCursor(vm *VM, sortBy, sortOrder string) VMListCursor {
+	cursor := VMListCursor{SortBy: sortBy, SortOrder: sortOrder, ID: vm.ID}
+	switch sortBy {
+	case "created_at":
+		cursor.Value = vm.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case "updated_at":
+		cursor.Value = vm.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	case "name":
+		cursor.Value = vm.Name
+	case "status":
+		cursor.Value = string(vm.Status)
+	}
+	return cursor
+}
+
+// Encode returns the opaque token handed to clients
+func (c VMListCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// SortValue returns the sort key in the type of its column
+func (c VMListCursor) SortValue() (interface{}, error) {
+	switch c.SortBy {
+	case "created_at", "updated_at":
+		return time.Parse(time.RFC3339Nano, c.Value)
+	case "name", "status":
+		return c.Value, nil
+	default:
+		return nil, fmt.Errorf("unknown sort key %q", c.SortBy)
+	}
+}
+
+// DecodeVMListCursor parses a token returned by Encode
+func DecodeVMListCursor(token string) (*VMListCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("malformed continue token")
+	}
+
+	var cursor VMListCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == uuid.Nil {
+		return nil, fmt.Errorf("malformed continue token")
+	}
+	if _, err := cursor.SortValue(); err != nil {
+		return nil, fmt.Errorf("malformed continue token")
+	}
+	return &cursor, nil
+}
+
+// VMPage is a page of VMs read from the repository
+type VMPage struct {
+	VMs []*VM `json:"vms"`
+	// Total is the number of matching VMs, nil unless requested
+	Total *int64 `json:"total,omitempty"`
+	// NextCursor continues after the last VM, empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Matches checks if a VM satisfies the filters of the options, mirroring the list query
+func (opts VMListOptions) Matches(vm *VM) bool {
+	if opts.Status != "" && vm.Status != opts.Status {
+		return false
+	}
+	if opts.NodeID != "" && vm.NodeID != opts.NodeID {
+		return false
+	}
+	if opts.CreatedBy != "" && vm.CreatedBy != opts.CreatedBy {
+		return false
+	}
+	if opts.Search != "" {
+		search := strings.ToLower(opts.Search)
+		if !strings.Contains(strings.ToLower(vm.Name), search) &&
+			!strings.Contains(strings.ToLower(vm.Description), search) {
+			return false
+		}
+	}
+	if opts.LabelSelector != "" {
+		selector, err := labels.Parse(opts.LabelSelector)
+		if err != nil || !selector.Matches(vm.GetLabels()) {
+			return false
+		}
+	}
+	return true
 }
 
 // VMResponse represents VM response data
 type VMResponse struct {
 	*VM
-	Uptime int64 `json:"uptime_seconds"`
+	Uptime      int64    `json:"uptime_seconds"`
+	IPAddresses []string `json:"ip_addresses"`
 }
 
 // NewVMResponse creates a VM response
 func NewVMResponse(vm *VM) *VMResponse {
 	return &VMResponse{
-		VM:     vm,
-		Uptime: vm.GetUptime(),
+		VM:          vm,
+		Uptime:      vm.GetUptime(),
+		IPAddresses: vm.IPAddresses(),
 	}
 }
 
@@ -360,16 +777,42 @@ func NewVMResponse(vm *VM) *VMResponse {
 type VMListResponse struct {
 	VMs        []*VMResponse `json:"vms"`
 	Pagination Pagination    `json:"pagination"`
+
+	// Continue token of the next page, empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+
+	// Watch resource version to resume from after listing
+	ResourceVersion uint64 `json:"resource_version"`
 }
 
 // Pagination represents pagination information
 type Pagination struct {
-	Page       int   `json:"page"`
-	Limit      int   `json:"limit"`
-	Total      int64 `json:"total"`
-	TotalPages int64 `json:"total_pages"`
-	HasNext    bool  `json:"has_next"`
-	HasPrev    bool  `json:"has_prev"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	Total      *int64 `json:"total,omitempty"`
+	TotalPages *int64 `json:"total_pages,omitempty"`
+	HasNext    bool   `json:"has_next"`
+	HasPrev    bool   `json:"has_prev"`
+}
+
+// NewPagination returns the pagination of a page of a list with a known total
+func NewPagination(page, limit int, total int64) Pagination {
+	totalPages := (total + int64(limit) - 1) / int64(limit)
+	return Pagination{
+		Page:       page,
+		Limit:      limit,
+		Total:      &total,
+		TotalPages: &totalPages,
+		HasNext:    int64(page) < totalPages,
+		HasPrev:    page > 1,
+	}
+}
+
+// VMCount represents the number of VMs in a status on a node
+type VMCount struct {
+	NodeID string   `json:"node_id"`
+	Status VMStatus `json:"status"`
+	Count  int64    `json:"count"`
 }
 
 // ResourceSummary represents overall resource usage
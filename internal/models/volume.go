@@ -0,0 +1,128 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VolumeStatus represents the attachment state of a volume
+type VolumeStatus string
+
+const (
+	VolumeStatusAvailable VolumeStatus = "available"
+	VolumeStatusInUse     VolumeStatus = "in-use"
+)
+
+// Volume represents a block volume of a project that can be attached to one VM at a time.
+// Volumes are independent of VMs: they outlive the VMs they were attached to.
+type Volume struct {
+	ID          uuid.UUID    `json:"id" gorm:"type:uuid;primary_key"`
+	ProjectID   uuid.UUID    `json:"project_id" gorm:"type:uuid;not null;uniqueIndex:idx_volumes_project_name"`
+	Name        string       `json:"name" gorm:"not null;size:63;uniqueIndex:idx_volumes_project_name"`
+	Description string       `json:"description" gorm:"size:1000"`
+	SizeGb      int          `json:"size_gb" gorm:"not null;check:size_gb >= 1 AND size_gb <= 10240"`
+	Status      VolumeStatus `json:"status" gorm:"type:varchar(20);not null;default:'available';index"`
+
+	// VM the volume is attached to, if any
+	VMID       *uuid.UUID `json:"vm_id,omitempty" gorm:"type:uuid;index"`
+	AttachedAt *time.Time `json:"attached_at,omitempty"`
+
+	// Timestamps
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Audit fields
+	CreatedBy string `json:"created_by" gorm:"size:255"`
+	UpdatedBy string `json:"updated_by" gorm:"size:255"`
+}
+
+// BeforeCreate hook
+func (v *Volume) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	v.CreatedAt = time.Now()
+	v.UpdatedAt = time.Now()
+	return nil
+}
+
+// BeforeUpdate hook
+func (v *Volume) BeforeUpdate(tx *gorm.DB) error {
+	v.UpdatedAt = time.Now()
+	return nil
+}
+
+// TableName returns the table name for Volume
+func (Volume) TableName() string {
+	return "volumes"
+}
+
+// IsAttached reports whether the volume is attached to a VM
+func (v *Volume) IsAttached() bool {
+	return v.VMID != nil
+}
+
+// Attach marks the volume as attached to a VM
+func (v *Volume) Attach(vmID uuid.UUID) {
+	now := time.Now()
+	v.VMID = &vmID
+	v.AttachedAt = &now
+	v.Status = VolumeStatusInUse
+}
+
+// Detach marks the volume as no longer attached to a VM
+func (v *Volume) Detach() {
+	v.VMID = nil
+	v.AttachedAt = nil
+	v.Status = VolumeStatusAvailable
+}
+
+// VolumeListOptions represents filters for listing volumes
+type VolumeListOptions struct {
+	Status VolumeStatus `form:"status" binding:"omitempty,oneof=available in-use"`
+	VMID   string       `form:"vm_id" binding:"omitempty,uuid"`
+}
+
+// VolumeCreateRequest represents a request to create a volume
+type VolumeCreateRequest struct {
+	Name        string    `json:"name" binding:"required,min=1,max=63" example:"pgdata"`
+	Description string    `json:"description" binding:"max=1000" example:"Database files"`
+	SizeGb      int       `json:"size_gb" binding:"required,min=1,max=10240" example:"100"`
+	ProjectID   uuid.UUID `json:"-"`
+	CreatedBy   string    `json:"-"`
+}
+
+// ToVolume converts create request to Volume model
+func (req *VolumeCreateRequest) ToVolume() *Volume {
+	return &Volume{
+		ProjectID:   req.ProjectID,
+		Name:        req.Name,
+		Description: req.Description,
+		SizeGb:      req.SizeGb,
+		Status:      VolumeStatusAvailable,
+		CreatedBy:   req.CreatedBy,
+		UpdatedBy:   req.CreatedBy,
+	}
+}
+
+// VolumeResizeRequest represents a request to grow a volume
+type VolumeResizeRequest struct {
+	SizeGb    int    `json:"size_gb" binding:"required,min=1,max=10240" example:"200"`
+	UpdatedBy string `json:"-"`
+	RequestID string `json:"-"`
+}
+
+// VolumeAttachRequest represents a request to attach a volume to a VM of the same project
+type VolumeAttachRequest struct {
+	VMID      uuid.UUID `json:"vm_id" binding:"required"`
+	UpdatedBy string    `json:"-"`
+	RequestID string    `json:"-"`
+}
+
+// VolumeDetachRequest represents a request to detach a volume from its VM
+type VolumeDetachRequest struct {
+	UpdatedBy string `json:"-"`
+	RequestID string `json:"-"`
+}
@@ -0,0 +1,117 @@
+// Package ratelimit implements the token buckets that limit requests per client.
+//
+// Buckets are kept in process memory, or in Redis so that every replica of the
+// API draws from the same bucket of a client.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit represents a token bucket refilled at RPS tokens per second up to Burst tokens
+type Limit struct {
+	RPS   float64
+	Burst int
+}
+
+// Result represents the outcome of taking a token from a bucket
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // until the next request is allowed, zero if this one was
+	ResetAfter time.Duration // until the bucket is full again
+}
+
+// Limiter takes tokens from the bucket of a key
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (*Result, error)
+}
+
+// bucket represents the state of a token bucket at its last update
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+// refill returns the tokens of the bucket at the given time
+func (b *bucket) refill(now time.Time) float64 {
+	elapsed := now.Sub(b.updated).Seconds()
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.RPS)
+}
+
+// memoryLimiter keeps buckets in process memory
+type memoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	cleanup   time.Duration
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryLimiter creates a limiter that keeps buckets in memory.
+// Every cleanup interval, buckets that have refilled completely are dropped.
+func NewMemoryLimiter(cleanup time.Duration) Limiter {
+	return &memoryLimiter{
+		buckets:   make(map[string]*bucket),
+		cleanup:   cleanup,
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Allow takes a token from the bucket of the key
+func (l *memoryLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if l.cleanup > 0 && now.Sub(l.lastSweep) >= l.cleanup {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now, limit: limit}
+		l.buckets[key] = b
+	}
+
+	// Clamp the tokens of buckets whose burst shrank
+	b.tokens = math.Min(b.refill(now), float64(limit.Burst))
+	b.updated = now
+	b.limit = limit
+
+	result := &Result{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / limit.RPS)
+	}
+	result.Remaining = int(math.Floor(b.tokens))
+	result.ResetAfter = seconds((float64(limit.Burst) - b.tokens) / limit.RPS)
+
+	return result, nil
+}
+
+// sweep drops buckets that are full again, since a new bucket starts out full
+func (l *memoryLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if b.refill(now) >= float64(b.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// seconds converts fractional seconds to a duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix namespaces rate limit buckets in Redis
+const keyPrefix = "ratelimit:"
+
+// takeToken refills and takes a token from the bucket in KEYS[1] atomically.
+// ARGV holds the refill rate per second and the burst. The clock of Redis is
+// used so that replicas with skewed clocks agree on the state of a bucket.
+// Buckets expire once they have refilled completely.
+var takeToken = redis.NewScript(`
+redis.replicate_commands()
+
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "updated")
+local tokens = tonumber(state[1])
+local updated = tonumber(state[2])
+if tokens == nil or updated == nil then
+	tokens = burst
+	updated = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - updated) * rate / 1000)
+
+local allowed = 0
+local retry_after = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry_after = math.ceil((1 - tokens) * 1000 / rate)
+end
+local reset_after = math.ceil((burst - tokens) * 1000 / rate)
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "updated", tostring(now))
+redis.call("PEXPIRE", KEYS[1], reset_after + 1000)
+
+return {allowed, math.floor(tokens), retry_after, reset_after}
+`)
+
+// redisLimiter keeps buckets in Redis so that they are shared between replicas
+type redisLimiter struct {
+	client *redis.Client
+}
+
+// NewRedisLimiter creates a limiter that keeps buckets in Redis
+func NewRedisLimiter(client *redis.Client) Limiter {
+	return &redisLimiter{client: client}
+}
+
+// Allow takes a token from the bucket of the key
+func (l *redisLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	values, err := takeToken.Run(ctx, l.client, []string{keyPrefix + key}, limit.RPS, limit.Burst).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+	if len(values) != 4 {
+		return nil, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	return &Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit.Burst,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		ResetAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,198 @@
+package repositories
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/tenancy"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+)
+
+// Cache keys of VMs and VM list pages
+const (
+	vmCacheKeyPrefix       = "vm:"
+	vmListCacheKeyPrefix   = "vms:list:"
+	vmListGenerationKey    = "vms:list:generation"
+	vmListCacheScopeAllKey = "*"
+)
+
+// cachedVMRepository caches VMs and VM list pages of another VMRepository in Redis.
+//
+// Individual VMs are cached under their ID and dropped when they are written.
+// List pages are cached under a generation that every write advances, so a
+// single write invalidates all pages at once. Writes inside transactions invalidate
+// once the transaction has committed. Reads inside transactions and failing Redis
+// calls go straight to the wrapped repository.
+type cachedVMRepository struct {
+	VMRepository
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewCachedVMRepository creates a VM repository that caches lookups and list pages of repo
+func NewCachedVMRepository(repo VMRepository, client *redis.Client, ttl time.Duration) VMRepository {
+	return &cachedVMRepository{
+		VMRepository: repo,
+		client:       client,
+		ttl:          ttl,
+	}
+}
+
+// Create creates a VM and invalidates cached list pages
+func (r *cachedVMRepository) Create(ctx context.Context, vm *models.VM) error {
+	if err := r.VMRepository.Create(ctx, vm); err != nil {
+		return err
+	}
+	r.invalidateAfterCommit(ctx, vm.ID)
+	return nil
+}
+
+// GetByID retrieves a VM by ID from the cache, or from the database on a miss
+func (r *cachedVMRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.VM, error) {
+	if inTransaction(ctx) {
+		return r.VMRepository.GetByID(ctx, id)
+	}
+
+	var vm models.VM
+	if r.get(ctx, vmCacheKeyPrefix+id.String(), &vm) {
+		if !tenancy.Allows(ctx, vm.ProjectID) {
+			return nil, errors.NotFoundError("VM", id.String())
+		}
+		return &vm, nil
+	}
+
+	// Load without the tenancy scope so that the cached VM serves every caller
+	loaded, err := r.VMRepository.GetByID(tenancy.Unrestricted(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	r.set(ctx, vmCacheKeyPrefix+id.String(), loaded)
+
+	if !tenancy.Allows(ctx, loaded.ProjectID) {
+		return nil, errors.NotFoundError("VM", id.String())
+	}
+	return loaded, nil
+}
+
+// Update updates a VM and invalidates its cache entries
+func (r *cachedVMRepository) Update(ctx context.Context, vm *models.VM) error {
+	if err := r.VMRepository.Update(ctx, vm); err != nil {
+		return err
+	}
+	r.invalidateAfterCommit(ctx, vm.ID)
+	return nil
+}
+
+// Delete deletes a VM and invalidates its cache entries
+func (r *cachedVMRepository) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	if err := r.VMRepository.Delete(ctx, id, version); err != nil {
+		return err
+	}
+	r.invalidateAfterCommit(ctx, id)
+	return nil
+}
+
+// List retrieves a page of VMs from the cache, or from the database on a miss
+func (r *cachedVMRepository) List(ctx context.Context, opts models.VMListOptions) (*models.VMPage, error) {
+	if inTransaction(ctx) {
+		return r.VMRepository.List(ctx, opts)
+	}
+
+	generation, err := r.client.Get(ctx, vmListGenerationKey).Int64()
+	if err != nil && err != redis.Nil {
+		return r.VMRepository.List(ctx, opts)
+	}
+
+	key := vmListCacheKeyPrefix + fmt.Sprintf("%d:", generation) + listCacheDigest(ctx, opts)
+	var page models.VMPage
+	if r.get(ctx, key, &page) {
+		return &page, nil
+	}
+
+	result, err := r.VMRepository.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	r.set(ctx, key, result)
+	return result, nil
+}
+
+// UpdateStatus updates the status of a VM and invalidates its cache entries
+func (r *cachedVMRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from models.VMStatus, version int64, to models.VMStatus) error {
+	if err := r.VMRepository.UpdateStatus(ctx, id, from, version, to); err != nil {
+		return err
+	}
+	r.invalidateAfterCommit(ctx, id)
+	return nil
+}
+
+// UpdateStats updates the statistics of a VM and invalidates its cache entries
+func (r *cachedVMRepository) UpdateStats(ctx context.Context, id uuid.UUID, stats models.VMStats) error {
+	if err := r.VMRepository.UpdateStats(ctx, id, stats); err != nil {
+		return err
+	}
+	r.invalidateAfterCommit(ctx, id)
+	return nil
+}
+
+// get decodes a cached value, reporting whether it was found
+func (r *cachedVMRepository) get(ctx context.Context, key string, value interface{}) bool {
+	data, err := r.client.Get(ctx, key).Bytes()
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, value) == nil
+}
+
+// set caches a value until the TTL expires
+func (r *cachedVMRepository) set(ctx context.Context, key string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	r.client.Set(ctx, key, data, r.ttl)
+}
+
+// invalidateAfterCommit invalidates the VM once the write is visible to other readers.
+// Invalidating inside a transaction would let a concurrent read cache the row as it was
+// before the commit until the TTL expires.
+func (r *cachedVMRepository) invalidateAfterCommit(ctx context.Context, id uuid.UUID) {
+	afterCommit(ctx, func() { r.invalidate(ctx, id) })
+}
+
+// invalidate drops the cached VM and every cached list page
+func (r *cachedVMRepository) invalidate(ctx context.Context, id uuid.UUID) {
+	// Writes must invalidate even when the request that made them was cancelled
+	ctx = context.WithoutCancel(ctx)
+
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, vmCacheKeyPrefix+id.String())
+	pipe.Incr(ctx, vmListGenerationKey)
+	pipe.Exec(ctx)
+}
+
+// listCacheDigest identifies the list options and the tenancy scope of a list page
+func listCacheDigest(ctx context.Context, opts models.VMListOptions) string {
+	scope := vmListCacheScopeAllKey
+	if projectIDs, ok := tenancy.Projects(ctx); ok {
+		ids := make([]string, len(projectIDs))
+		for i, id := range projectIDs {
+			ids[i] = id.String()
+		}
+		sort.Strings(ids)
+		scope = strings.Join(ids, ",")
+	}
+
+	data, _ := json.Marshal(opts)
+	sum := sha256.Sum256(append([]byte(scope+"|"), data...))
+	return hex.EncodeToString(sum[:])
+}
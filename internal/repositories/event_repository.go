@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// EventRepository interface defines VM event data access operations
+type EventRepository interface {
+	Create(ctx context.Context, event *models.VMEvent) error
+	List(ctx context.Context, opts models.VMEventListOptions) ([]*models.VMEvent, int64, error)
+}
+
+// eventRepository implements EventRepository interface
+type eventRepository struct {
+	db *gorm.DB
+}
+
+// NewEventRepository creates a new VM event repository
+func NewEventRepository(db *gorm.DB) EventRepository {
+	return &eventRepository{db: db}
+}
+
+// Create stores a new VM event
+func (r *eventRepository) Create(ctx context.Context, event *models.VMEvent) error {
+	if err := conn(ctx, r.db).Create(event).Error; err != nil {
+		return errors.DatabaseError("create VM event", err)
+	}
+	return nil
+}
+
+// List retrieves VM events with pagination and filtering, newest first
+func (r *eventRepository) List(ctx context.Context, opts models.VMEventListOptions) ([]*models.VMEvent, int64, error) {
+	var events []*models.VMEvent
+	var total int64
+
+	query := scopeToVMProjects(ctx, conn(ctx, r.db).Model(&models.VMEvent{}))
+
+	// Apply filters
+	if opts.VMID != "" {
+		query = query.Where("vm_id = ?", opts.VMID)
+	}
+
+	if opts.Type != "" {
+		query = query.Where("type = ?", opts.Type)
+	}
+
+	if opts.Actor != "" {
+		query = query.Where("actor = ?", opts.Actor)
+	}
+
+	if opts.RequestID != "" {
+		query = query.Where("request_id = ?", opts.RequestID)
+	}
+
+	if opts.NewStatus != "" {
+		query = query.Where("new_status = ?", opts.NewStatus)
+	}
+
+	if !opts.Since.IsZero() {
+		query = query.Where("created_at >= ?", opts.Since)
+	}
+
+	if !opts.Until.IsZero() {
+		query = query.Where("created_at < ?", opts.Until)
+	}
+
+	// Count total records
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.DatabaseError("count VM events", err)
+	}
+
+	// Apply pagination
+	offset := (opts.Page - 1) * opts.Limit
+	query = query.Order("created_at DESC").Offset(offset).Limit(opts.Limit)
+
+	if err := query.Find(&events).Error; err != nil {
+		return nil, 0, errors.DatabaseError("list VM events", err)
+	}
+
+	return events, total, nil
+}
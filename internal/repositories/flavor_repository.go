@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// FlavorRepository interface defines flavor catalog and project flavor data access operations
+type FlavorRepository interface {
+	Create(ctx context.Context, flavor *models.Flavor) error
+	GetByName(ctx context.Context, name string) (*models.Flavor, error)
+	ListByNames(ctx context.Context, names []string) ([]*models.Flavor, error)
+	List(ctx context.Context) ([]*models.Flavor, error)
+	Update(ctx context.Context, flavor *models.Flavor) error
+	Delete(ctx context.Context, name string) error
+	AllowedNames(ctx context.Context, projectID uuid.UUID) ([]string, error)
+	SetAllowed(ctx context.Context, projectID uuid.UUID, names []string, createdBy string) error
+	CountProjectsAllowing(ctx context.Context, name string) (int64, error)
+}
+
+// flavorRepository implements FlavorRepository interface
+type flavorRepository struct {
+	db *gorm.DB
+}
+
+// NewFlavorRepository creates a new flavor repository
+func NewFlavorRepository(db *gorm.DB) FlavorRepository {
+	return &flavorRepository{db: db}
+}
+
+// Create creates a new flavor
+func (r *flavorRepository) Create(ctx context.Context, flavor *models.Flavor) error {
+	if err := conn(ctx, r.db).Create(flavor).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "UNIQUE constraint") {
+			return errors.AlreadyExistsError("Flavor", flavor.Name)
+		}
+		return errors.DatabaseError("create flavor", err)
+	}
+	return nil
+}
+
+// GetByName retrieves a flavor by name
+func (r *flavorRepository) GetByName(ctx context.Context, name string) (*models.Flavor, error) {
+	var flavor models.Flavor
+	if err := conn(ctx, r.db).First(&flavor, "name = ?", name).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundError("Flavor", name)
+		}
+		return nil, errors.DatabaseError("get flavor by name", err)
+	}
+	return &flavor, nil
+}
+
+// ListByNames retrieves the flavors with the given names ordered by size, skipping unknown names
+func (r *flavorRepository) ListByNames(ctx context.Context, names []string) ([]*models.Flavor, error) {
+	var flavors []*models.Flavor
+	if len(names) == 0 {
+		return flavors, nil
+	}
+
+	if err := bySize(conn(ctx, r.db)).Where("name IN ?", names).Find(&flavors).Error; err != nil {
+		return nil, errors.DatabaseError("list flavors by name", err)
+	}
+	return flavors, nil
+}
+
+// List retrieves all flavors ordered by size
+func (r *flavorRepository) List(ctx context.Context) ([]*models.Flavor, error) {
+	var flavors []*models.Flavor
+	if err := bySize(conn(ctx, r.db)).Find(&flavors).Error; err != nil {
+		return nil, errors.DatabaseError("list flavors", err)
+	}
+	return flavors, nil
+}
+
+// Update updates a flavor
+func (r *flavorRepository) Update(ctx context.Context, flavor *models.Flavor) error {
+	if err := conn(ctx, r.db).Save(flavor).Error; err != nil {
+		return errors.DatabaseError("update flavor", err)
+	}
+	return nil
+}
+
+// Delete deletes a flavor
+func (r *flavorRepository) Delete(ctx context.Context, name string) error {
+	result := conn(ctx, r.db).Where("name = ?", name).Delete(&models.Flavor{})
+	if result.Error != nil {
+		return errors.DatabaseError("delete flavor", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.NotFoundError("Flavor", name)
+	}
+	return nil
+}
+
+// AllowedNames retrieves the names of the flavors a project is restricted to, none if it is unrestricted
+func (r *flavorRepository) AllowedNames(ctx context.Context, projectID uuid.UUID) ([]string, error) {
+	var names []string
+	if err := conn(ctx, r.db).
+		Model(&models.ProjectFlavor{}).
+		Where("project_id = ?", projectID).
+		Order("flavor_name ASC").
+		Pluck("flavor_name", &names).Error; err != nil {
+		return nil, errors.DatabaseError("list flavors of project", err)
+	}
+	return names, nil
+}
+
+// SetAllowed replaces the flavors a project is restricted to
+func (r *flavorRepository) SetAllowed(ctx context.Context, projectID uuid.UUID, names []string, createdBy string) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ?", projectID).Delete(&models.ProjectFlavor{}).Error; err != nil {
+			return errors.DatabaseError("clear flavors of project", err)
+		}
+
+		for _, name := range names {
+			allowed := &models.ProjectFlavor{ProjectID: projectID, FlavorName: name, CreatedBy: createdBy}
+			if err := tx.Create(allowed).Error; err != nil {
+				return errors.DatabaseError("allow flavor in project", err)
+			}
+		}
+		return nil
+	})
+}
+
+// CountProjectsAllowing counts the projects restricted to a set of flavors including the given one
+func (r *flavorRepository) CountProjectsAllowing(ctx context.Context, name string) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.db).
+		Model(&models.ProjectFlavor{}).
+		Where("flavor_name = ?", name).
+		Count(&count).Error; err != nil {
+		return 0, errors.DatabaseError("count projects allowing flavor", err)
+	}
+	return count, nil
+}
+
+// bySize orders flavors from the smallest to the largest
+func bySize(db *gorm.DB) *gorm.DB {
+	return db.Order("cpu_cores ASC").Order("ram_mb ASC").Order("disk_gb ASC").Order("name ASC")
+}
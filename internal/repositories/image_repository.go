@@ -0,0 +1,173 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/tenancy"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ImageRepository interface defines image catalog data access operations
+type ImageRepository interface {
+	Create(ctx context.Context, image *models.Image) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Image, error)
+	GetByReference(ctx context.Context, name, version string) (*models.Image, error)
+	List(ctx context.Context, opts models.ImageListOptions) ([]*models.Image, error)
+	ListUploaded(ctx context.Context) ([]*models.Image, error)
+	Update(ctx context.Context, image *models.Image) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	LockByReference(ctx context.Context, name, version string) (*models.Image, error)
+	WithLocked(ctx context.Context, id uuid.UUID, fn func(ctx context.Context, image *models.Image) error) error
+	CountByProject(ctx context.Context, projectID uuid.UUID) (int64, error)
+}
+
+// imageRepository implements ImageRepository interface
+type imageRepository struct {
+	db *gorm.DB
+}
+
+// NewImageRepository creates a new image repository
+func NewImageRepository(db *gorm.DB) ImageRepository {
+	return &imageRepository{db: db}
+}
+
+// scoped returns a query restricted to public images and the private images of the caller's projects
+func (r *imageRepository) scoped(ctx context.Context) *gorm.DB {
+	query := conn(ctx, r.db)
+	projectIDs, ok := tenancy.Projects(ctx)
+	if !ok {
+		return query
+	}
+	return query.Where("visibility = ? OR project_id IN ?", models.ImageVisibilityPublic, projectIDs)
+}
+
+// Create registers a new image
+func (r *imageRepository) Create(ctx context.Context, image *models.Image) error {
+	if err := conn(ctx, r.db).Create(image).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "UNIQUE constraint") {
+			return errors.AlreadyExistsError("Image", image.Reference())
+		}
+		return errors.DatabaseError("create image", err)
+	}
+	return nil
+}
+
+// GetByID retrieves an image by ID
+func (r *imageRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Image, error) {
+	var image models.Image
+	if err := r.scoped(ctx).First(&image, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundError("Image", id.String())
+		}
+		return nil, errors.DatabaseError("get image by ID", err)
+	}
+	return &image, nil
+}
+
+// GetByReference retrieves an image by name and version regardless of its visibility
+func (r *imageRepository) GetByReference(ctx context.Context, name, version string) (*models.Image, error) {
+	var image models.Image
+	if err := conn(ctx, r.db).First(&image, "name = ? AND version = ?", name, version).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundError("Image", name+":"+version)
+		}
+		return nil, errors.DatabaseError("get image by reference", err)
+	}
+	return &image, nil
+}
+
+// List retrieves the images visible to the caller ordered by name and version
+func (r *imageRepository) List(ctx context.Context, opts models.ImageListOptions) ([]*models.Image, error) {
+	query := r.scoped(ctx)
+	if opts.OSFamily != "" {
+		query = query.Where("os_family = ?", opts.OSFamily)
+	}
+	if opts.Name != "" {
+		query = query.Where("name = ?", opts.Name)
+	}
+	if !opts.IncludeDeprecated {
+		query = query.Where("state = ?", models.ImageStateActive)
+	}
+
+	var images []*models.Image
+	if err := query.Order("name ASC").Order("version DESC").Find(&images).Error; err != nil {
+		return nil, errors.DatabaseError("list images", err)
+	}
+	return images, nil
+}
+
+// ListUploaded retrieves the images whose file is in the local image store, oldest upload first
+func (r *imageRepository) ListUploaded(ctx context.Context) ([]*models.Image, error) {
+	var images []*models.Image
+	if err := r.scoped(ctx).Where("uploaded_at IS NOT NULL").Order("uploaded_at ASC").Find(&images).Error; err != nil {
+		return nil, errors.DatabaseError("list uploaded images", err)
+	}
+	return images, nil
+}
+
+// Update updates an image
+func (r *imageRepository) Update(ctx context.Context, image *models.Image) error {
+	if err := conn(ctx, r.db).Save(image).Error; err != nil {
+		return errors.DatabaseError("update image", err)
+	}
+	return nil
+}
+
+// Delete deletes an image
+func (r *imageRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.scoped(ctx).Delete(&models.Image{}, "id = ?", id)
+	if result.Error != nil {
+		return errors.DatabaseError("delete image", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.NotFoundError("Image", id.String())
+	}
+	return nil
+}
+
+// LockByReference retrieves an image by name and version regardless of its visibility and
+// keeps it from being deleted until the transaction carried by the context ends
+func (r *imageRepository) LockByReference(ctx context.Context, name, version string) (*models.Image, error) {
+	var image models.Image
+	if err := conn(ctx, r.db).Clauses(clause.Locking{Strength: "SHARE"}).
+		First(&image, "name = ? AND version = ?", name, version).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundError("Image", name+":"+version)
+		}
+		return nil, errors.DatabaseError("lock image", err)
+	}
+	return &image, nil
+}
+
+// WithLocked runs fn in a transaction holding a lock on the image, so no VM can be
+// created from it while fn decides whether to delete it. Repository calls made with
+// the context passed to fn take part in the transaction.
+func (r *imageRepository) WithLocked(ctx context.Context, id uuid.UUID, fn func(ctx context.Context, image *models.Image) error) error {
+	return transaction(ctx, r.db, func(txCtx context.Context, tx *gorm.DB) error {
+		var image models.Image
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&image, "id = ?", id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NotFoundError("Image", id.String())
+			}
+			return errors.DatabaseError("lock image", err)
+		}
+		return fn(txCtx, &image)
+	})
+}
+
+// CountByProject counts the private images of a project
+func (r *imageRepository) CountByProject(ctx context.Context, projectID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.scoped(ctx).
+		Model(&models.Image{}).
+		Where("project_id = ?", projectID).
+		Count(&count).Error; err != nil {
+		return 0, errors.DatabaseError("count images by project", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,206 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/tenancy"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// macAttempts is how often a random MAC address is drawn before giving up on finding an unused one
+const macAttempts = 8
+
+// NetworkRepository interface defines network and IP address allocation data access operations
+type NetworkRepository interface {
+	Create(ctx context.Context, network *models.Network) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Network, error)
+	GetByName(ctx context.Context, projectID uuid.UUID, name string) (*models.Network, error)
+	List(ctx context.Context) ([]*models.Network, error)
+	Update(ctx context.Context, network *models.Network) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListInterfaces(ctx context.Context, networkID uuid.UUID) ([]*models.NetworkInterface, error)
+	AllocateInterface(ctx context.Context, network *models.Network, vmID uuid.UUID, deviceIndex int) (*models.NetworkInterface, error)
+	ReleaseInterfaces(ctx context.Context, vmID uuid.UUID) error
+	CountByProject(ctx context.Context, projectID uuid.UUID) (int64, error)
+}
+
+// networkRepository implements NetworkRepository interface
+type networkRepository struct {
+	db *gorm.DB
+}
+
+// NewNetworkRepository creates a new network repository
+func NewNetworkRepository(db *gorm.DB) NetworkRepository {
+	return &networkRepository{db: db}
+}
+
+// scoped returns a query restricted to the networks of the caller's projects
+func (r *networkRepository) scoped(ctx context.Context) *gorm.DB {
+	return scopeToProjects(ctx, conn(ctx, r.db), "project_id")
+}
+
+// Create creates a new network
+func (r *networkRepository) Create(ctx context.Context, network *models.Network) error {
+	if !tenancy.Allows(ctx, network.ProjectID) {
+		return errors.NotFoundError("Project", network.ProjectID.String())
+	}
+	if err := conn(ctx, r.db).Create(network).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "UNIQUE constraint") {
+			return errors.AlreadyExistsError("Network", network.Name)
+		}
+		return errors.DatabaseError("create network", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a network by ID
+func (r *networkRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Network, error) {
+	var network models.Network
+	if err := r.scoped(ctx).First(&network, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundError("Network", id.String())
+		}
+		return nil, errors.DatabaseError("get network by ID", err)
+	}
+	return &network, nil
+}
+
+// GetByName retrieves a network by name within a project
+func (r *networkRepository) GetByName(ctx context.Context, projectID uuid.UUID, name string) (*models.Network, error) {
+	var network models.Network
+	if err := r.scoped(ctx).First(&network, "project_id = ? AND name = ?", projectID, name).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundError("Network", name)
+		}
+		return nil, errors.DatabaseError("get network by name", err)
+	}
+	return &network, nil
+}
+
+// List retrieves the networks of the caller's projects ordered by name
+func (r *networkRepository) List(ctx context.Context) ([]*models.Network, error) {
+	var networks []*models.Network
+	if err := r.scoped(ctx).Order("name ASC").Find(&networks).Error; err != nil {
+		return nil, errors.DatabaseError("list networks", err)
+	}
+	return networks, nil
+}
+
+// Update updates a network
+func (r *networkRepository) Update(ctx context.Context, network *models.Network) error {
+	if err := conn(ctx, r.db).Save(network).Error; err != nil {
+		return errors.DatabaseError("update network", err)
+	}
+	return nil
+}
+
+// Delete deletes a network
+func (r *networkRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.scoped(ctx).Delete(&models.Network{}, "id = ?", id)
+	if result.Error != nil {
+		return errors.DatabaseError("delete network", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.NotFoundError("Network", id.String())
+	}
+	return nil
+}
+
+// ListInterfaces retrieves the interfaces connected to a network ordered by IP address
+func (r *networkRepository) ListInterfaces(ctx context.Context, networkID uuid.UUID) ([]*models.NetworkInterface, error) {
+	var interfaces []*models.NetworkInterface
+	if err := conn(ctx, r.db).
+		Where("network_id = ?", networkID).
+		Find(&interfaces).Error; err != nil {
+		return nil, errors.DatabaseError("list network interfaces", err)
+	}
+	models.SortInterfacesByAddress(interfaces)
+	return interfaces, nil
+}
+
+// AllocateInterface connects a VM to a network with the lowest free address of its DHCP range
+// and an unused MAC address. It must run in a transaction, which holds the network locked until
+// the interface is committed.
+func (r *networkRepository) AllocateInterface(ctx context.Context, network *models.Network, vmID uuid.UUID, deviceIndex int) (*models.NetworkInterface, error) {
+	db := conn(ctx, r.db)
+
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&models.Network{}, "id = ?", network.ID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundError("Network", network.ID.String())
+		}
+		return nil, errors.DatabaseError("lock network", err)
+	}
+
+	var allocated []string
+	if err := db.Model(&models.NetworkInterface{}).
+		Where("network_id = ?", network.ID).
+		Pluck("ip_address", &allocated).Error; err != nil {
+		return nil, errors.DatabaseError("list allocated addresses", err)
+	}
+	address, ok := network.FreeAddress(allocated)
+	if !ok {
+		return nil, errors.AddressesExhaustedError(network.Name, network.DHCPStart, network.DHCPEnd)
+	}
+
+	mac, err := r.unusedMACAddress(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	iface := network.NewInterface(vmID, deviceIndex, address, mac)
+	if err := db.Create(iface).Error; err != nil {
+		return nil, errors.DatabaseError("allocate network interface", err)
+	}
+	return iface, nil
+}
+
+// unusedMACAddress draws random MAC addresses until one is not used by any interface
+func (r *networkRepository) unusedMACAddress(ctx context.Context) (string, error) {
+	for attempt := 0; attempt < macAttempts; attempt++ {
+		mac, err := models.NewMACAddress()
+		if err != nil {
+			return "", errors.InternalError("Failed to generate MAC address", err)
+		}
+
+		var count int64
+		if err := conn(ctx, r.db).
+			Model(&models.NetworkInterface{}).
+			Where("mac_address = ?", mac).
+			Count(&count).Error; err != nil {
+			return "", errors.DatabaseError("check MAC address", err)
+		}
+		if count == 0 {
+			return mac, nil
+		}
+	}
+	return "", errors.InternalError("Failed to generate MAC address",
+		fmt.Errorf("no unused MAC address after %d attempts", macAttempts))
+}
+
+// ReleaseInterfaces deletes the interfaces of a VM, returning their addresses to the pools
+func (r *networkRepository) ReleaseInterfaces(ctx context.Context, vmID uuid.UUID) error {
+	if err := conn(ctx, r.db).Delete(&models.NetworkInterface{}, "vm_id = ?", vmID).Error; err != nil {
+		return errors.DatabaseError("release network interfaces", err)
+	}
+	return nil
+}
+
+// CountByProject counts the networks of a project
+func (r *networkRepository) CountByProject(ctx context.Context, projectID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.scoped(ctx).
+		Model(&models.Network{}).
+		Where("project_id = ?", projectID).
+		Count(&count).Error; err != nil {
+		return 0, errors.DatabaseError("count networks by project", err)
+	}
+	return count, nil
+}
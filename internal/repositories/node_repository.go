@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NodeRepository interface defines node data access operations
+type NodeRepository interface {
+	Create(ctx context.Context, node *models.Node) error
+	GetByName(ctx context.Context, name string) (*models.Node, error)
+	Update(ctx context.Context, node *models.Node) error
+	List(ctx context.Context, opts models.NodeListOptions) ([]*models.Node, error)
+	LockAll(ctx context.Context) ([]*models.Node, error)
+	RecordHeartbeat(ctx context.Context, name string, at time.Time) error
+	MarkUnreachable(ctx context.Context, cutoff time.Time) (int64, error)
+	Count(ctx context.Context) (int64, error)
+	CountByState(ctx context.Context, state models.NodeState) (int64, error)
+}
+
+// nodeRepository implements NodeRepository interface
+type nodeRepository struct {
+	db *gorm.DB
+}
+
+// NewNodeRepository creates a new node repository
+func NewNodeRepository(db *gorm.DB) NodeRepository {
+	return &nodeRepository{db: db}
+}
+
+// Create creates a new node
+func (r *nodeRepository) Create(ctx context.Context, node *models.Node) error {
+	if err := conn(ctx, r.db).Create(node).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "UNIQUE constraint") {
+			return errors.AlreadyExistsError("Node", node.Name)
+		}
+		return errors.DatabaseError("create node", err)
+	}
+	return nil
+}
+
+// GetByName retrieves a node by name
+func (r *nodeRepository) GetByName(ctx context.Context, name string) (*models.Node, error) {
+	var node models.Node
+	if err := conn(ctx, r.db).First(&node, "name = ?", name).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundError("Node", name)
+		}
+		return nil, errors.DatabaseError("get node by name", err)
+	}
+	return &node, nil
+}
+
+// Update updates a node
+func (r *nodeRepository) Update(ctx context.Context, node *models.Node) error {
+	if err := conn(ctx, r.db).Save(node).Error; err != nil {
+		return errors.DatabaseError("update node", err)
+	}
+	return nil
+}
+
+// List retrieves nodes ordered by name
+func (r *nodeRepository) List(ctx context.Context, opts models.NodeListOptions) ([]*models.Node, error) {
+	var nodes []*models.Node
+
+	query := conn(ctx, r.db).Model(&models.Node{})
+	if opts.State != "" {
+		query = query.Where("state = ?", opts.State)
+	}
+
+	if err := query.Order("name ASC").Find(&nodes).Error; err != nil {
+		return nil, errors.DatabaseError("list nodes", err)
+	}
+	return nodes, nil
+}
+
+// LockAll retrieves all nodes ordered by name and keeps them locked until the transaction
+// carried by the context ends, so that placement decisions of concurrent requests, on any
+// replica, are made one after the other
+func (r *nodeRepository) LockAll(ctx context.Context) ([]*models.Node, error) {
+	var nodes []*models.Node
+	if err := conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Order("name ASC").
+		Find(&nodes).Error; err != nil {
+		return nil, errors.DatabaseError("lock nodes", err)
+	}
+	return nodes, nil
+}
+
+// RecordHeartbeat stores a heartbeat and brings unreachable nodes back to ready
+func (r *nodeRepository) RecordHeartbeat(ctx context.Context, name string, at time.Time) error {
+	result := conn(ctx, r.db).Model(&models.Node{}).
+		Where("name = ?", name).
+		Updates(map[string]interface{}{
+			"last_heartbeat_at": at,
+			"state":             gorm.Expr("CASE WHEN state = ? THEN ? ELSE state END", models.NodeStateUnreachable, models.NodeStateReady),
+			"updated_at":        at,
+		})
+
+	if result.Error != nil {
+		return errors.DatabaseError("record node heartbeat", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.NotFoundError("Node", name)
+	}
+
+	return nil
+}
+
+// MarkUnreachable marks ready nodes whose last heartbeat is older than cutoff as unreachable
+func (r *nodeRepository) MarkUnreachable(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := conn(ctx, r.db).Model(&models.Node{}).
+		Where("state = ?", models.NodeStateReady).
+		Where("last_heartbeat_at IS NULL OR last_heartbeat_at < ?", cutoff).
+		Updates(map[string]interface{}{
+			"state":      models.NodeStateUnreachable,
+			"updated_at": time.Now(),
+		})
+
+	if result.Error != nil {
+		return 0, errors.DatabaseError("mark nodes unreachable", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// Count counts all registered nodes
+func (r *nodeRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.db).Model(&models.Node{}).Count(&count).Error; err != nil {
+		return 0, errors.DatabaseError("count nodes", err)
+	}
+	return count, nil
+}
+
+// CountByState counts nodes by state
+func (r *nodeRepository) CountByState(ctx context.Context, state models.NodeState) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.db).
+		Model(&models.Node{}).
+		Where("state = ?", state).
+		Count(&count).Error; err != nil {
+		return 0, errors.DatabaseError("count nodes by state", err)
+	}
+	return count, nil
+}
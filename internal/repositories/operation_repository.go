@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// OperationRepository interface defines operation data access operations
+type OperationRepository interface {
+	Create(ctx context.Context, op *models.Operation) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Operation, error)
+	Update(ctx context.Context, op *models.Operation) error
+	List(ctx context.Context, opts models.OperationListOptions) ([]*models.Operation, error)
+	FailUnfinished(ctx context.Context, reason string) (int64, error)
+}
+
+// operationRepository implements OperationRepository interface
+type operationRepository struct {
+	db *gorm.DB
+}
+
+// NewOperationRepository creates a new operation repository
+func NewOperationRepository(db *gorm.DB) OperationRepository {
+	return &operationRepository{db: db}
+}
+
+// Create creates a new operation
+func (r *operationRepository) Create(ctx context.Context, op *models.Operation) error {
+	if err := conn(ctx, r.db).Create(op).Error; err != nil {
+		return errors.DatabaseError("create operation", err)
+	}
+	return nil
+}
+
+// GetByID retrieves an operation by ID
+func (r *operationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Operation, error) {
+	var op models.Operation
+	if err := scopeToVMProjects(ctx, conn(ctx, r.db)).First(&op, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundError("Operation", id.String())
+		}
+		return nil, errors.DatabaseError("get operation by ID", err)
+	}
+	return &op, nil
+}
+
+// Update updates an operation
+func (r *operationRepository) Update(ctx context.Context, op *models.Operation) error {
+	if err := conn(ctx, r.db).Save(op).Error; err != nil {
+		return errors.DatabaseError("update operation", err)
+	}
+	return nil
+}
+
+// List retrieves operations, newest first
+func (r *operationRepository) List(ctx context.Context, opts models.OperationListOptions) ([]*models.Operation, error) {
+	var ops []*models.Operation
+
+	query := scopeToVMProjects(ctx, conn(ctx, r.db).Model(&models.Operation{}))
+
+	if opts.VMID != "" {
+		query = query.Where("vm_id = ?", opts.VMID)
+	}
+
+	if opts.Type != "" {
+		query = query.Where("type = ?", opts.Type)
+	}
+
+	if opts.State != "" {
+		query = query.Where("state = ?", opts.State)
+	}
+
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+
+	if err := query.Order("created_at DESC").Find(&ops).Error; err != nil {
+		return nil, errors.DatabaseError("list operations", err)
+	}
+	return ops, nil
+}
+
+// FailUnfinished marks all pending and running operations as failed
+func (r *operationRepository) FailUnfinished(ctx context.Context, reason string) (int64, error) {
+	now := time.Now()
+	result := conn(ctx, r.db).Model(&models.Operation{}).
+		Where("state IN ?", []models.OperationState{models.OperationStatePending, models.OperationStateRunning}).
+		Updates(map[string]interface{}{
+			"state":        models.OperationStateFailed,
+			"error":        reason,
+			"completed_at": now,
+			"updated_at":   now,
+		})
+
+	if result.Error != nil {
+		return 0, errors.DatabaseError("fail unfinished operations", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
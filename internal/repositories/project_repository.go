@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// ProjectRepository interface defines project and membership data access operations
+type ProjectRepository interface {
+	Create(ctx context.Context, project *models.Project) error
+	GetByName(ctx context.Context, name string) (*models.Project, error)
+	List(ctx context.Context) ([]*models.Project, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	AddMember(ctx context.Context, member *models.ProjectMember) error
+	ListMembers(ctx context.Context, projectID uuid.UUID) ([]*models.ProjectMember, error)
+	RemoveMember(ctx context.Context, projectID uuid.UUID, subject string) error
+	ProjectIDsForSubject(ctx context.Context, subject string, includeDefault bool) ([]uuid.UUID, error)
+}
+
+// projectRepository implements ProjectRepository interface
+type projectRepository struct {
+	db *gorm.DB
+}
+
+// NewProjectRepository creates a new project repository
+func NewProjectRepository(db *gorm.DB) ProjectRepository {
+	return &projectRepository{db: db}
+}
+
+// scoped returns a query restricted to the caller's projects
+func (r *projectRepository) scoped(ctx context.Context) *gorm.DB {
+	return scopeToProjects(ctx, conn(ctx, r.db), "id")
+}
+
+// Create creates a new project
+func (r *projectRepository) Create(ctx context.Context, project *models.Project) error {
+	if err := conn(ctx, r.db).Create(project).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "UNIQUE constraint") {
+			return errors.AlreadyExistsError("Project", project.Name)
+		}
+		return errors.DatabaseError("create project", err)
+	}
+	return nil
+}
+
+// GetByName retrieves a project by name
+func (r *projectRepository) GetByName(ctx context.Context, name string) (*models.Project, error) {
+	var project models.Project
+	if err := r.scoped(ctx).First(&project, "name = ?", name).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundError("Project", name)
+		}
+		return nil, errors.DatabaseError("get project by name", err)
+	}
+	return &project, nil
+}
+
+// List retrieves projects ordered by name
+func (r *projectRepository) List(ctx context.Context) ([]*models.Project, error) {
+	var projects []*models.Project
+	if err := r.scoped(ctx).Order("name ASC").Find(&projects).Error; err != nil {
+		return nil, errors.DatabaseError("list projects", err)
+	}
+	return projects, nil
+}
+
+// Delete deletes a project and its memberships. Soft-deleted VMs still refer to the
+// project, so they are purged with it.
+func (r *projectRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Delete(&models.VM{}, "project_id = ? AND deleted_at IS NOT NULL", id).Error; err != nil {
+			return errors.DatabaseError("purge deleted VMs of project", err)
+		}
+
+		result := scopeToProjects(ctx, tx, "id").Delete(&models.Project{}, "id = ?", id)
+		if result.Error != nil {
+			return errors.DatabaseError("delete project", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errors.NotFoundError("Project", id.String())
+		}
+
+		if err := tx.Delete(&models.ProjectMember{}, "project_id = ?", id).Error; err != nil {
+			return errors.DatabaseError("delete project members", err)
+		}
+		if err := tx.Delete(&models.Quota{}, "project_id = ?", id).Error; err != nil {
+			return errors.DatabaseError("delete project quota", err)
+		}
+		if err := tx.Delete(&models.ProjectFlavor{}, "project_id = ?", id).Error; err != nil {
+			return errors.DatabaseError("delete project flavors", err)
+		}
+		return nil
+	})
+}
+
+// AddMember adds a subject to a project
+func (r *projectRepository) AddMember(ctx context.Context, member *models.ProjectMember) error {
+	if err := conn(ctx, r.db).Create(member).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "UNIQUE constraint") {
+			return errors.AlreadyExistsError("ProjectMember", member.Subject)
+		}
+		return errors.DatabaseError("add project member", err)
+	}
+	return nil
+}
+
+// ListMembers retrieves the members of a project ordered by subject
+func (r *projectRepository) ListMembers(ctx context.Context, projectID uuid.UUID) ([]*models.ProjectMember, error) {
+	var members []*models.ProjectMember
+	if err := scopeToProjects(ctx, conn(ctx, r.db), "project_id").
+		Where("project_id = ?", projectID).
+		Order("subject ASC").
+		Find(&members).Error; err != nil {
+		return nil, errors.DatabaseError("list project members", err)
+	}
+	return members, nil
+}
+
+// RemoveMember removes a subject from a project
+func (r *projectRepository) RemoveMember(ctx context.Context, projectID uuid.UUID, subject string) error {
+	result := scopeToProjects(ctx, conn(ctx, r.db), "project_id").
+		Delete(&models.ProjectMember{}, "project_id = ? AND subject = ?", projectID, subject)
+	if result.Error != nil {
+		return errors.DatabaseError("remove project member", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.NotFoundError("ProjectMember", subject)
+	}
+	return nil
+}
+
+// ProjectIDsForSubject retrieves the IDs of the projects the subject is a member of,
+// and of the default project if every subject is to be treated as its member
+func (r *projectRepository) ProjectIDsForSubject(ctx context.Context, subject string, includeDefault bool) ([]uuid.UUID, error) {
+	members := conn(ctx, r.db).Model(&models.ProjectMember{}).Select("project_id").Where("subject = ?", subject)
+
+	query := conn(ctx, r.db).Model(&models.Project{})
+	if includeDefault {
+		query = query.Where("name = ? OR id IN (?)", models.DefaultProjectName, members)
+	} else {
+		query = query.Where("id IN (?)", members)
+	}
+
+	var ids []uuid.UUID
+	if err := query.Pluck("id", &ids).Error; err != nil {
+		return nil, errors.DatabaseError("list projects of subject", err)
+	}
+	return ids, nil
+}
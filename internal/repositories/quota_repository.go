@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QuotaRepository interface defines project quota data access operations
+type QuotaRepository interface {
+	Get(ctx context.Context, projectID uuid.UUID) (*models.Quota, error)
+	Upsert(ctx context.Context, quota *models.Quota) error
+	Usage(ctx context.Context, projectID uuid.UUID) (*models.QuotaUsage, error)
+	WithLockedUsage(ctx context.Context, projectID uuid.UUID, fn func(ctx context.Context, quota *models.Quota, usage *models.QuotaUsage) error) error
+}
+
+// quotaRepository implements QuotaRepository interface
+type quotaRepository struct {
+	db *gorm.DB
+}
+
+// NewQuotaRepository creates a new quota repository
+func NewQuotaRepository(db *gorm.DB) QuotaRepository {
+	return &quotaRepository{db: db}
+}
+
+// Get retrieves the quota configured for a project
+func (r *quotaRepository) Get(ctx context.Context, projectID uuid.UUID) (*models.Quota, error) {
+	var quota models.Quota
+	if err := scopeToProjects(ctx, conn(ctx, r.db), "project_id").
+		First(&quota, "project_id = ?", projectID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundError("Quota", projectID.String())
+		}
+		return nil, errors.DatabaseError("get quota", err)
+	}
+	return &quota, nil
+}
+
+// Upsert creates or replaces the quota of a project
+func (r *quotaRepository) Upsert(ctx context.Context, quota *models.Quota) error {
+	quota.UpdatedAt = time.Now()
+	if err := conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}},
+		UpdateAll: true,
+	}).Create(quota).Error; err != nil {
+		return errors.DatabaseError("upsert quota", err)
+	}
+	return nil
+}
+
+// Usage sums the resources allocated to the VMs of a project. The disk usage
+// includes the volumes of the project, whether attached or not.
+func (r *quotaRepository) Usage(ctx context.Context, projectID uuid.UUID) (*models.QuotaUsage, error) {
+	var usage models.QuotaUsage
+	if err := scopeToProjects(ctx, conn(ctx, r.db), "project_id").
+		Model(&models.VM{}).
+		Select(`
+			COUNT(*) as vms,
+			COUNT(CASE WHEN status IN ? THEN 1 END) as running_vms,
+			COALESCE(SUM(cpu_cores), 0) as cpu_cores,
+			COALESCE(SUM(ram_mb), 0) as ram_mb,
+			COALESCE(SUM(disk_gb), 0) as disk_gb
+		`, models.PoweredOnStatuses).
+		Where("project_id = ?", projectID).
+		Scan(&usage).Error; err != nil {
+		return nil, errors.DatabaseError("get quota usage", err)
+	}
+
+	var volumeGb int64
+	if err := scopeToProjects(ctx, conn(ctx, r.db), "project_id").
+		Model(&models.Volume{}).
+		Select("COALESCE(SUM(size_gb), 0)").
+		Where("project_id = ?", projectID).
+		Scan(&volumeGb).Error; err != nil {
+		return nil, errors.DatabaseError("get volume quota usage", err)
+	}
+	usage.DiskGb += volumeGb
+	return &usage, nil
+}
+
+// WithLockedUsage runs fn in a transaction holding a lock on the project, so
+// concurrent requests of the project see each other's changes to its usage.
+// The quota is nil if none is configured. Repository calls made with the
+// context passed to fn take part in the transaction.
+func (r *quotaRepository) WithLockedUsage(ctx context.Context, projectID uuid.UUID, fn func(ctx context.Context, quota *models.Quota, usage *models.QuotaUsage) error) error {
+	return transaction(ctx, r.db, func(txCtx context.Context, tx *gorm.DB) error {
+		var project models.Project
+		if err := scopeToProjects(txCtx, tx, "id").
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&project, "id = ?", projectID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NotFoundError("Project", projectID.String())
+			}
+			return errors.DatabaseError("lock project", err)
+		}
+
+		quota, err := r.Get(txCtx, projectID)
+		if err != nil && !errors.Is(err, errors.ErrNotFound) {
+			return err
+		}
+
+		usage, err := r.Usage(txCtx, projectID)
+		if err != nil {
+			return err
+		}
+
+		return fn(txCtx, quota, usage)
+	})
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// RoleBindingRepository interface defines role binding data access operations
+type RoleBindingRepository interface {
+	Create(ctx context.Context, binding *models.RoleBinding) error
+	List(ctx context.Context, opts models.RoleBindingListOptions) ([]*models.RoleBinding, error)
+	RolesForSubject(ctx context.Context, subject string) ([]string, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// roleBindingRepository implements RoleBindingRepository interface
+type roleBindingRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleBindingRepository creates a new role binding repository
+func NewRoleBindingRepository(db *gorm.DB) RoleBindingRepository {
+	return &roleBindingRepository{db: db}
+}
+
+// Create creates a new role binding
+func (r *roleBindingRepository) Create(ctx context.Context, binding *models.RoleBinding) error {
+	if err := conn(ctx, r.db).Create(binding).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "UNIQUE constraint") {
+			return errors.AlreadyExistsError("RoleBinding", binding.Subject+"/"+binding.Role)
+		}
+		return errors.DatabaseError("create role binding", err)
+	}
+	return nil
+}
+
+// List retrieves role bindings ordered by subject and role
+func (r *roleBindingRepository) List(ctx context.Context, opts models.RoleBindingListOptions) ([]*models.RoleBinding, error) {
+	var bindings []*models.RoleBinding
+
+	query := conn(ctx, r.db).Model(&models.RoleBinding{})
+	if opts.Subject != "" {
+		query = query.Where("subject = ?", opts.Subject)
+	}
+	if opts.Role != "" {
+		query = query.Where("role = ?", opts.Role)
+	}
+
+	if err := query.Order("subject ASC, role ASC").Find(&bindings).Error; err != nil {
+		return nil, errors.DatabaseError("list role bindings", err)
+	}
+	return bindings, nil
+}
+
+// RolesForSubject retrieves the names of the roles bound to a subject
+func (r *roleBindingRepository) RolesForSubject(ctx context.Context, subject string) ([]string, error) {
+	var roles []string
+	if err := conn(ctx, r.db).Model(&models.RoleBinding{}).
+		Where("subject = ?", subject).
+		Pluck("role", &roles).Error; err != nil {
+		return nil, errors.DatabaseError("list roles of subject", err)
+	}
+	return roles, nil
+}
+
+// Delete deletes a role binding
+func (r *roleBindingRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := conn(ctx, r.db).Delete(&models.RoleBinding{}, "id = ?", id)
+	if result.Error != nil {
+		return errors.DatabaseError("delete role binding", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.NotFoundError("RoleBinding", id.String())
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// RoleRepository interface defines custom role data access operations
+type RoleRepository interface {
+	Create(ctx context.Context, role *models.Role) error
+	GetByName(ctx context.Context, name string) (*models.Role, error)
+	ListByNames(ctx context.Context, names []string) ([]*models.Role, error)
+	List(ctx context.Context) ([]*models.Role, error)
+	Update(ctx context.Context, role *models.Role) error
+	Delete(ctx context.Context, name string) error
+}
+
+// roleRepository implements RoleRepository interface
+type roleRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository creates a new role repository
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+// Create creates a new custom role
+func (r *roleRepository) Create(ctx context.Context, role *models.Role) error {
+	if err := conn(ctx, r.db).Create(role).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "UNIQUE constraint") {
+			return errors.AlreadyExistsError("Role", role.Name)
+		}
+		return errors.DatabaseError("create role", err)
+	}
+	return nil
+}
+
+// GetByName retrieves a custom role by name
+func (r *roleRepository) GetByName(ctx context.Context, name string) (*models.Role, error) {
+	var role models.Role
+	if err := conn(ctx, r.db).First(&role, "name = ?", name).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundError("Role", name)
+		}
+		return nil, errors.DatabaseError("get role by name", err)
+	}
+	return &role, nil
+}
+
+// ListByNames retrieves the custom roles with the given names, skipping unknown names
+func (r *roleRepository) ListByNames(ctx context.Context, names []string) ([]*models.Role, error) {
+	var roles []*models.Role
+	if len(names) == 0 {
+		return roles, nil
+	}
+
+	if err := conn(ctx, r.db).Where("name IN ?", names).Find(&roles).Error; err != nil {
+		return nil, errors.DatabaseError("list roles by name", err)
+	}
+	return roles, nil
+}
+
+// List retrieves all custom roles ordered by name
+func (r *roleRepository) List(ctx context.Context) ([]*models.Role, error) {
+	var roles []*models.Role
+	if err := conn(ctx, r.db).Order("name ASC").Find(&roles).Error; err != nil {
+		return nil, errors.DatabaseError("list roles", err)
+	}
+	return roles, nil
+}
+
+// Update updates a custom role
+func (r *roleRepository) Update(ctx context.Context, role *models.Role) error {
+	if err := conn(ctx, r.db).Save(role).Error; err != nil {
+		return errors.DatabaseError("update role", err)
+	}
+	return nil
+}
+
+// Delete deletes a custom role together with its bindings
+func (r *roleRepository) Delete(ctx context.Context, name string) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("name = ?", name).Delete(&models.Role{})
+		if result.Error != nil {
+			return errors.DatabaseError("delete role", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errors.NotFoundError("Role", name)
+		}
+
+		if err := tx.Where("role = ?", name).Delete(&models.RoleBinding{}).Error; err != nil {
+			return errors.DatabaseError("delete role bindings", err)
+		}
+		return nil
+	})
+}
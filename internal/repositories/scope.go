@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/stackit/enterprise-vm-manager/internal/tenancy"
+	"gorm.io/gorm"
+)
+
+// scopeToProjects restricts a query to rows whose project column is in the
+// tenancy scope of the context. Unrestricted contexts are left untouched.
+func scopeToProjects(ctx context.Context, query *gorm.DB, column string) *gorm.DB {
+	projectIDs, ok := tenancy.Projects(ctx)
+	if !ok {
+		return query
+	}
+	return query.Where(column+" IN ?", projectIDs)
+}
+
+// scopeToVMProjects restricts a query of VM-owned rows to the VMs of the
+// projects in the tenancy scope of the context, including deleted VMs
+func scopeToVMProjects(ctx context.Context, query *gorm.DB) *gorm.DB {
+	projectIDs, ok := tenancy.Projects(ctx)
+	if !ok {
+		return query
+	}
+	return query.Where("vm_id IN (SELECT id FROM virtual_machines WHERE project_id IN ?)", projectIDs)
+}
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// SnapshotRepository interface defines snapshot data access operations
+type SnapshotRepository interface {
+	Create(ctx context.Context, snapshot *models.Snapshot) error
+	GetByID(ctx context.Context, vmID, id uuid.UUID) (*models.Snapshot, error)
+	ListByVM(ctx context.Context, vmID uuid.UUID) ([]*models.Snapshot, error)
+	Update(ctx context.Context, snapshot *models.Snapshot) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	DeleteByVM(ctx context.Context, vmID uuid.UUID) error
+	CountByImage(ctx context.Context, imageNames []string) (int64, error)
+}
+
+// snapshotRepository implements SnapshotRepository interface
+type snapshotRepository struct {
+	db *gorm.DB
+}
+
+// NewSnapshotRepository creates a new snapshot repository
+func NewSnapshotRepository(db *gorm.DB) SnapshotRepository {
+	return &snapshotRepository{db: db}
+}
+
+// Create creates a new snapshot
+func (r *snapshotRepository) Create(ctx context.Context, snapshot *models.Snapshot) error {
+	if err := conn(ctx, r.db).Create(snapshot).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "UNIQUE constraint") {
+			return errors.AlreadyExistsError("Snapshot", snapshot.Name)
+		}
+		return errors.DatabaseError("create snapshot", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a snapshot of a VM by ID
+func (r *snapshotRepository) GetByID(ctx context.Context, vmID, id uuid.UUID) (*models.Snapshot, error) {
+	var snapshot models.Snapshot
+	err := scopeToVMProjects(ctx, conn(ctx, r.db)).
+		First(&snapshot, "id = ? AND vm_id = ?", id, vmID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundError("Snapshot", id.String())
+		}
+		return nil, errors.DatabaseError("get snapshot by ID", err)
+	}
+	return &snapshot, nil
+}
+
+// ListByVM retrieves the snapshots of a VM, oldest first
+func (r *snapshotRepository) ListByVM(ctx context.Context, vmID uuid.UUID) ([]*models.Snapshot, error) {
+	var snapshots []*models.Snapshot
+	err := scopeToVMProjects(ctx, conn(ctx, r.db)).
+		Where("vm_id = ?", vmID).
+		Order("created_at ASC").
+		Find(&snapshots).Error
+	if err != nil {
+		return nil, errors.DatabaseError("list snapshots", err)
+	}
+	return snapshots, nil
+}
+
+// Update updates a snapshot
+func (r *snapshotRepository) Update(ctx context.Context, snapshot *models.Snapshot) error {
+	if err := conn(ctx, r.db).Save(snapshot).Error; err != nil {
+		return errors.DatabaseError("update snapshot", err)
+	}
+	return nil
+}
+
+// Delete deletes a snapshot
+func (r *snapshotRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := conn(ctx, r.db).Delete(&models.Snapshot{}, "id = ?", id)
+	if result.Error != nil {
+		return errors.DatabaseError("delete snapshot", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.NotFoundError("Snapshot", id.String())
+	}
+	return nil
+}
+
+// DeleteByVM deletes all snapshots of a VM
+func (r *snapshotRepository) DeleteByVM(ctx context.Context, vmID uuid.UUID) error {
+	if err := conn(ctx, r.db).Delete(&models.Snapshot{}, "vm_id = ?", vmID).Error; err != nil {
+		return errors.DatabaseError("delete snapshots of VM", err)
+	}
+	return nil
+}
+
+// CountByImage counts the snapshots of VMs created from any of the given image names,
+// including VMs that have since been deleted
+func (r *snapshotRepository) CountByImage(ctx context.Context, imageNames []string) (int64, error) {
+	var count int64
+	err := scopeToVMProjects(ctx, conn(ctx, r.db)).
+		Model(&models.Snapshot{}).
+		Where("vm_id IN (SELECT id FROM virtual_machines WHERE image_name IN ?)", imageNames).
+		Count(&count).Error
+	if err != nil {
+		return 0, errors.DatabaseError("count snapshots by image", err)
+	}
+	return count, nil
+}
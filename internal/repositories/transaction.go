@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// txKey is the context key of the transaction repositories take part in
+type txKey struct{}
+
+// txState is a transaction and the functions to run once it has committed
+type txState struct {
+	tx          *gorm.DB
+	afterCommit []func()
+}
+
+// transaction runs fn in a transaction, or in a savepoint of the transaction the context
+// already carries. Repository calls made with the context passed to fn take part in it.
+// Functions registered with afterCommit run once the outermost transaction has committed.
+func transaction(ctx context.Context, db *gorm.DB, fn func(ctx context.Context, tx *gorm.DB) error) error {
+	outer, nested := ctx.Value(txKey{}).(*txState)
+
+	state := &txState{}
+	err := conn(ctx, db).Transaction(func(tx *gorm.DB) error {
+		state.tx = tx
+		return fn(context.WithValue(ctx, txKey{}, state), tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Changes of a savepoint are only visible once the outer transaction commits
+	if nested {
+		outer.afterCommit = append(outer.afterCommit, state.afterCommit...)
+		return nil
+	}
+	for _, f := range state.afterCommit {
+		f()
+	}
+	return nil
+}
+
+// afterCommit runs fn once the transaction carried by the context has committed, or
+// right away outside of transactions. Functions of rolled back transactions never run.
+func afterCommit(ctx context.Context, fn func()) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, fn)
+		return
+	}
+	fn()
+}
+
+// conn returns the transaction carried by the context, or the database otherwise
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// inTransaction reports whether the context carries a transaction
+func inTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*txState)
+	return ok
+}
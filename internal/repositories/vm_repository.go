@@ -2,29 +2,39 @@ package repositories
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/labels"
 	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/tenancy"
 	"github.com/stackit/enterprise-vm-manager/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // VMRepository interface defines VM data access operations
 type VMRepository interface {
 	Create(ctx context.Context, vm *models.VM) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.VM, error)
-	GetByName(ctx context.Context, name string) (*models.VM, error)
+	GetByName(ctx context.Context, projectID uuid.UUID, name string) (*models.VM, error)
 	Update(ctx context.Context, vm *models.VM) error
-	Delete(ctx context.Context, id uuid.UUID) error
-	List(ctx context.Context, opts models.VMListOptions) ([]*models.VM, int64, error)
-	UpdateStatus(ctx context.Context, id uuid.UUID, status models.VMStatus) error
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
+	List(ctx context.Context, opts models.VMListOptions) (*models.VMPage, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, from models.VMStatus, version int64, to models.VMStatus) error
 	UpdateStats(ctx context.Context, id uuid.UUID, stats models.VMStats) error
 	GetResourceSummary(ctx context.Context) (*models.ResourceSummary, error)
-	ExistsByName(ctx context.Context, name string) (bool, error)
+	ExistsByName(ctx context.Context, projectID uuid.UUID, name string) (bool, error)
+	CountByProject(ctx context.Context, projectID uuid.UUID) (int64, error)
+	CountByImage(ctx context.Context, imageNames []string) (int64, error)
 	GetByNodeID(ctx context.Context, nodeID string) ([]*models.VM, error)
 	CountByStatus(ctx context.Context, status models.VMStatus) (int64, error)
+	GetNodeAllocations(ctx context.Context) (map[string]*models.NodeAllocation, error)
+	CountByNodeAndStatus(ctx context.Context) ([]*models.VMCount, error)
+	ListClones(ctx context.Context, sourceID uuid.UUID, status models.VMStatus) ([]*models.VM, error)
+	WithLocked(ctx context.Context, id uuid.UUID, fn func(ctx context.Context, vm *models.VM) error) error
 }
 
 // vmRepository implements VMRepository interface
@@ -37,9 +47,17 @@ func NewVMRepository(db *gorm.DB) VMRepository {
 	return &vmRepository{db: db}
 }
 
+// scoped returns a query restricted to the VMs of the caller's projects
+func (r *vmRepository) scoped(ctx context.Context) *gorm.DB {
+	return scopeToProjects(ctx, conn(ctx, r.db), "project_id")
+}
+
 // Create creates a new VM
 func (r *vmRepository) Create(ctx context.Context, vm *models.VM) error {
-	if err := r.db.WithContext(ctx).Create(vm).Error; err != nil {
+	if !tenancy.Allows(ctx, vm.ProjectID) {
+		return errors.NotFoundError("Project", vm.ProjectID.String())
+	}
+	if err := conn(ctx, r.db).Create(vm).Error; err != nil {
 		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "UNIQUE constraint") {
 			return errors.AlreadyExistsError("VM", vm.Name)
 		}
@@ -51,56 +69,100 @@ func (r *vmRepository) Create(ctx context.Context, vm *models.VM) error {
 // GetByID retrieves a VM by ID
 func (r *vmRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.VM, error) {
 	var vm models.VM
-	if err := r.db.WithContext(ctx).First(&vm, "id = ?", id).Error; err != nil {
+	if err := r.scoped(ctx).First(&vm, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.NotFoundError("VM", id.String())
 		}
 		return nil, errors.DatabaseError("get VM by ID", err)
 	}
+	if err := r.loadDevices(ctx, &vm); err != nil {
+		return nil, err
+	}
 	return &vm, nil
 }
 
-// GetByName retrieves a VM by name
-func (r *vmRepository) GetByName(ctx context.Context, name string) (*models.VM, error) {
+// WithLocked runs fn in a transaction holding a lock on the VM, so that changes of other
+// requests to the VM wait until fn is done. Repository calls made with the context passed
+// to fn take part in the transaction.
+func (r *vmRepository) WithLocked(ctx context.Context, id uuid.UUID, fn func(ctx context.Context, vm *models.VM) error) error {
+	return transaction(ctx, r.db, func(txCtx context.Context, tx *gorm.DB) error {
+		var vm models.VM
+		if err := r.scoped(txCtx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&vm, "id = ?", id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NotFoundError("VM", id.String())
+			}
+			return errors.DatabaseError("lock VM", err)
+		}
+		return fn(txCtx, &vm)
+	})
+}
+
+// GetByName retrieves a VM by name within a project
+func (r *vmRepository) GetByName(ctx context.Context, projectID uuid.UUID, name string) (*models.VM, error) {
 	var vm models.VM
-	if err := r.db.WithContext(ctx).First(&vm, "name = ?", name).Error; err != nil {
+	if err := r.scoped(ctx).First(&vm, "project_id = ? AND name = ?", projectID, name).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.NotFoundError("VM", name)
 		}
 		return nil, errors.DatabaseError("get VM by name", err)
 	}
+	if err := r.loadDevices(ctx, &vm); err != nil {
+		return nil, err
+	}
 	return &vm, nil
 }
 
-// Update updates a VM
+// Update updates a VM if it is still at the resource version it was read at,
+// and bumps the version
 func (r *vmRepository) Update(ctx context.Context, vm *models.VM) error {
-	if err := r.db.WithContext(ctx).Save(vm).Error; err != nil {
-		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "UNIQUE constraint") {
+	readVersion := vm.ResourceVersion
+	vm.ResourceVersion++
+
+	result := conn(ctx, r.db).Model(vm).
+		Where("resource_version = ?", readVersion).
+		Select("*").
+		Omit("id", "created_at", "deleted_at").
+		Updates(vm)
+	if result.Error != nil {
+		vm.ResourceVersion = readVersion
+		if strings.Contains(result.Error.Error(), "duplicate key") || strings.Contains(result.Error.Error(), "UNIQUE constraint") {
 			return errors.AlreadyExistsError("VM", vm.Name)
 		}
-		return errors.DatabaseError("update VM", err)
+		return errors.DatabaseError("update VM", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		vm.ResourceVersion = readVersion
+		return errors.VersionConflictError("VM", vm.ID.String(), readVersion)
 	}
 	return nil
 }
 
-// Delete deletes a VM (soft delete)
-func (r *vmRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	result := r.db.WithContext(ctx).Delete(&models.VM{}, "id = ?", id)
+// Delete deletes a VM (soft delete) that still has the resource version the caller read
+func (r *vmRepository) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	result := r.scoped(ctx).Delete(&models.VM{}, "id = ? AND resource_version = ?", id, version)
 	if result.Error != nil {
 		return errors.DatabaseError("delete VM", result.Error)
 	}
 	if result.RowsAffected == 0 {
-		return errors.NotFoundError("VM", id.String())
+		var count int64
+		if err := r.scoped(ctx).Model(&models.VM{}).Where("id = ?", id).Count(&count).Error; err != nil {
+			return errors.DatabaseError("get VM", err)
+		}
+		if count == 0 {
+			return errors.NotFoundError("VM", id.String())
+		}
+		return errors.VersionConflictError("VM", id.String(), version)
 	}
 	return nil
 }
 
-// List retrieves VMs with pagination and filtering
-func (r *vmRepository) List(ctx context.Context, opts models.VMListOptions) ([]*models.VM, int64, error) {
+// List retrieves a page of VMs with filtering. Pages are read after the position
+// of a continue token if one is given and at an offset otherwise.
+func (r *vmRepository) List(ctx context.Context, opts models.VMListOptions) (*models.VMPage, error) {
 	var vms []*models.VM
-	var total int64
+	page := &models.VMPage{}
 
-	query := r.db.WithContext(ctx).Model(&models.VM{})
+	query := r.scoped(ctx).Model(&models.VM{})
 
 	// Apply filters
 	if opts.Status != "" {
@@ -120,34 +182,125 @@ func (r *vmRepository) List(ctx context.Context, opts models.VMListOptions) ([]*
 		query = query.Where("name ILIKE ? OR description ILIKE ?", searchPattern, searchPattern)
 	}
 
-	// Count total records
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, errors.DatabaseError("count VMs", err)
+	if opts.LabelSelector != "" {
+		selector, err := labels.Parse(opts.LabelSelector)
+		if err != nil {
+			return nil, errors.ErrValidationFailed.WithDetails(err.Error())
+		}
+		query = applyLabelSelector(query, selector)
+	}
+
+	// Count total records if requested; cursor pages skip the count, which the first page reported
+	if opts.IncludeTotal && opts.Continue == "" {
+		var total int64
+		if err := query.Count(&total).Error; err != nil {
+			return nil, errors.DatabaseError("count VMs", err)
+		}
+		page.Total = &total
 	}
 
-	// Apply sorting
-	orderClause := fmt.Sprintf("%s %s", opts.SortBy, strings.ToUpper(opts.SortOrder))
-	query = query.Order(orderClause)
+	// Apply sorting, with the ID breaking ties so that the order is stable
+	direction := strings.ToUpper(opts.SortOrder)
+	query = query.Order(fmt.Sprintf("%s %s, id %s", opts.SortBy, direction, direction))
 
 	// Apply pagination
-	offset := (opts.Page - 1) * opts.Limit
-	query = query.Offset(offset).Limit(opts.Limit)
+	if opts.Continue != "" {
+		cursor, err := models.DecodeVMListCursor(opts.Continue)
+		if err != nil {
+			return nil, errors.ErrValidationFailed.WithDetails(err.Error())
+		}
+		if cursor.SortBy != opts.SortBy || cursor.SortOrder != opts.SortOrder {
+			return nil, errors.ErrValidationFailed.WithDetails("continue token was issued for a different sort order")
+		}
+
+		value, _ := cursor.SortValue()
+		comparison := ">"
+		if direction == "DESC" {
+			comparison = "<"
+		}
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", opts.SortBy, comparison), value, cursor.ID)
+	} else {
+		query = query.Offset((opts.Page - 1) * opts.Limit)
+	}
+
+	// Read one VM more than requested to learn whether another page follows
+	if err := query.Limit(opts.Limit + 1).Find(&vms).Error; err != nil {
+		return nil, errors.DatabaseError("list VMs", err)
+	}
 
-	// Execute query
-	if err := query.Find(&vms).Error; err != nil {
-		return nil, 0, errors.DatabaseError("list VMs", err)
+	if len(vms) > opts.Limit {
+		vms = vms[:opts.Limit]
+		page.NextCursor = models.NewVMListCursor(vms[len(vms)-1], opts.SortBy, opts.SortOrder).Encode()
+	}
+	if err := r.loadDevices(ctx, vms...); err != nil {
+		return nil, err
 	}
+	page.VMs = vms
 
-	return vms, total, nil
+	return page, nil
 }
 
-// UpdateStatus updates VM status
-func (r *vmRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status models.VMStatus) error {
-	result := r.db.WithContext(ctx).Model(&models.VM{}).
-		Where("id = ?", id).
+// loadDevices sets the volumes and network interfaces of each of the VMs
+func (r *vmRepository) loadDevices(ctx context.Context, vms ...*models.VM) error {
+	if len(vms) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*models.VM, len(vms))
+	ids := make([]uuid.UUID, len(vms))
+	for i, vm := range vms {
+		byID[vm.ID] = vm
+		ids[i] = vm.ID
+	}
+
+	if err := r.loadVolumes(ctx, byID, ids); err != nil {
+		return err
+	}
+	return r.loadInterfaces(ctx, byID, ids)
+}
+
+// loadVolumes sets the volumes attached to each of the VMs, in attachment order
+func (r *vmRepository) loadVolumes(ctx context.Context, byID map[uuid.UUID]*models.VM, ids []uuid.UUID) error {
+	var volumes []*models.Volume
+	if err := conn(ctx, r.db).
+		Where("vm_id IN ?", ids).
+		Order("attached_at ASC").
+		Find(&volumes).Error; err != nil {
+		return errors.DatabaseError("load volumes of VMs", err)
+	}
+	for _, volume := range volumes {
+		vm := byID[*volume.VMID]
+		vm.Volumes = append(vm.Volumes, volume)
+	}
+	return nil
+}
+
+// loadInterfaces sets the network interfaces of each of the VMs, in device order
+func (r *vmRepository) loadInterfaces(ctx context.Context, byID map[uuid.UUID]*models.VM, ids []uuid.UUID) error {
+	var interfaces []*models.NetworkInterface
+	if err := conn(ctx, r.db).
+		Where("vm_id IN ?", ids).
+		Order("device_index ASC").
+		Find(&interfaces).Error; err != nil {
+		return errors.DatabaseError("load network interfaces of VMs", err)
+	}
+	for _, iface := range interfaces {
+		vm := byID[iface.VMID]
+		vm.Interfaces = append(vm.Interfaces, iface)
+	}
+	return nil
+}
+
+// UpdateStatus changes the status of a VM that still has the status and resource version
+// the caller read. It fails with a state error if the status changed in the meantime and
+// with a version conflict if only the version did.
+func (r *vmRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from models.VMStatus, version int64, to models.VMStatus) error {
+	result := r.scoped(ctx).Model(&models.VM{}).
+		Where("id = ? AND status = ? AND resource_version = ?", id, from, version).
 		Updates(map[string]interface{}{
-			"status":     status,
-			"updated_at": "NOW()",
+			"status":           to,
+			"resource_version": gorm.Expr("resource_version + 1"),
+			"updated_at":       "NOW()",
 		})
 
 	if result.Error != nil {
@@ -155,7 +308,17 @@ func (r *vmRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status mo
 	}
 
 	if result.RowsAffected == 0 {
-		return errors.NotFoundError("VM", id.String())
+		var current models.VM
+		if err := r.scoped(ctx).Select("status", "resource_version").First(&current, "id = ?", id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NotFoundError("VM", id.String())
+			}
+			return errors.DatabaseError("get VM status", err)
+		}
+		if current.Status != from {
+			return errors.VMStateError(id.String(), string(current.Status), string(from))
+		}
+		return errors.VersionConflictError("VM", id.String(), version)
 	}
 
 	return nil
@@ -163,7 +326,7 @@ func (r *vmRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status mo
 
 // UpdateStats updates VM statistics
 func (r *vmRepository) UpdateStats(ctx context.Context, id uuid.UUID, stats models.VMStats) error {
-	result := r.db.WithContext(ctx).Model(&models.VM{}).
+	result := r.scoped(ctx).Model(&models.VM{}).
 		Where("id = ?", id).
 		Updates(map[string]interface{}{
 			"cpu_usage_percent":  stats.CPUUsagePercent,
@@ -173,6 +336,7 @@ func (r *vmRepository) UpdateStats(ctx context.Context, id uuid.UUID, stats mode
 			"network_tx_bytes":   stats.NetworkTxBytes,
 			"uptime_seconds":     stats.UptimeSeconds,
 			"last_stats_update":  stats.LastStatsUpdate,
+			"resource_version":   gorm.Expr("resource_version + 1"),
 			"updated_at":         "NOW()",
 		})
 
@@ -197,7 +361,7 @@ func (r *vmRepository) GetResourceSummary(ctx context.Context) (*models.Resource
 		Count  int64           `json:"count"`
 	}{}
 
-	if err := r.db.WithContext(ctx).
+	if err := r.scoped(ctx).
 		Model(&models.VM{}).
 		Select("status, COUNT(*) as count").
 		Group("status").
@@ -231,7 +395,7 @@ func (r *vmRepository) GetResourceSummary(ctx context.Context) (*models.Resource
 		RunningVMs int `json:"running_vms"`
 	}
 
-	if err := r.db.WithContext(ctx).
+	if err := r.scoped(ctx).
 		Model(&models.VM{}).
 		Select(`
 			COALESCE(SUM(cpu_cores), 0) as total_cpu,
@@ -262,6 +426,26 @@ func (r *vmRepository) GetResourceSummary(ctx context.Context) (*models.Resource
 		summary.Resources.RAM.Usage = float64(resourceStats.UsedRAM) / float64(resourceStats.TotalRAM) * 100
 	}
 
+	// Volumes add their capacity to the disk stats and are in use while attached to a running VM
+	var volumeStats struct {
+		TotalDisk int `json:"total_disk"`
+		UsedDisk  int `json:"used_disk"`
+	}
+
+	if err := scopeToProjects(ctx, conn(ctx, r.db), "project_id").
+		Model(&models.Volume{}).
+		Select(`
+			COALESCE(SUM(size_gb), 0) as total_disk,
+			COALESCE(SUM(CASE WHEN vm_id IN (
+				SELECT id FROM virtual_machines WHERE status = 'running' AND deleted_at IS NULL
+			) THEN size_gb ELSE 0 END), 0) as used_disk
+		`).
+		Scan(&volumeStats).Error; err != nil {
+		return nil, errors.DatabaseError("get volume stats", err)
+	}
+	resourceStats.TotalDisk += volumeStats.TotalDisk
+	resourceStats.UsedDisk += volumeStats.UsedDisk
+
 	// Set Disk stats
 	summary.Resources.Disk.Total = resourceStats.TotalDisk
 	summary.Resources.Disk.Used = resourceStats.UsedDisk
@@ -270,38 +454,60 @@ func (r *vmRepository) GetResourceSummary(ctx context.Context) (*models.Resource
 		summary.Resources.Disk.Usage = float64(resourceStats.UsedDisk) / float64(resourceStats.TotalDisk) * 100
 	}
 
-	// Get node counts (simplified - in real implementation this would query a nodes table)
-	uniqueNodes := []string{}
-	if err := r.db.WithContext(ctx).
+	return summary, nil
+}
+
+// ExistsByName checks if a VM with the given name exists in a project
+func (r *vmRepository) ExistsByName(ctx context.Context, projectID uuid.UUID, name string) (bool, error) {
+	var count int64
+	if err := r.scoped(ctx).
 		Model(&models.VM{}).
-		Distinct("node_id").
-		Where("node_id IS NOT NULL AND node_id != ''").
-		Pluck("node_id", &uniqueNodes).Error; err != nil {
-		return nil, errors.DatabaseError("get node counts", err)
+		Where("project_id = ? AND name = ?", projectID, name).
+		Count(&count).Error; err != nil {
+		return false, errors.DatabaseError("check VM exists by name", err)
 	}
+	return count > 0, nil
+}
 
-	summary.Nodes.Total = len(uniqueNodes)
-	summary.Nodes.Active = len(uniqueNodes) // Simplified
+// ListClones retrieves the VMs in a status that were cloned from a source VM
+func (r *vmRepository) ListClones(ctx context.Context, sourceID uuid.UUID, status models.VMStatus) ([]*models.VM, error) {
+	var vms []*models.VM
+	if err := r.scoped(ctx).
+		Where("source_vm_id = ? AND status = ?", sourceID, status).
+		Find(&vms).Error; err != nil {
+		return nil, errors.DatabaseError("list clones of VM", err)
+	}
+	return vms, nil
+}
 
-	return summary, nil
+// CountByProject counts the VMs of a project
+func (r *vmRepository) CountByProject(ctx context.Context, projectID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.scoped(ctx).
+		Model(&models.VM{}).
+		Where("project_id = ?", projectID).
+		Count(&count).Error; err != nil {
+		return 0, errors.DatabaseError("count VMs by project", err)
+	}
+	return count, nil
 }
 
-// ExistsByName checks if a VM with the given name exists
-func (r *vmRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
+// CountByImage counts the VMs created from any of the given image names
+func (r *vmRepository) CountByImage(ctx context.Context, imageNames []string) (int64, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).
+	if err := r.scoped(ctx).
 		Model(&models.VM{}).
-		Where("name = ?", name).
+		Where("image_name IN ?", imageNames).
 		Count(&count).Error; err != nil {
-		return false, errors.DatabaseError("check VM exists by name", err)
+		return 0, errors.DatabaseError("count VMs by image", err)
 	}
-	return count > 0, nil
+	return count, nil
 }
 
 // GetByNodeID retrieves all VMs on a specific node
 func (r *vmRepository) GetByNodeID(ctx context.Context, nodeID string) ([]*models.VM, error) {
 	var vms []*models.VM
-	if err := r.db.WithContext(ctx).
+	if err := r.scoped(ctx).
 		Where("node_id = ?", nodeID).
 		Find(&vms).Error; err != nil {
 		return nil, errors.DatabaseError("get VMs by node ID", err)
@@ -312,7 +518,7 @@ func (r *vmRepository) GetByNodeID(ctx context.Context, nodeID string) ([]*model
 // CountByStatus counts VMs by status
 func (r *vmRepository) CountByStatus(ctx context.Context, status models.VMStatus) (int64, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).
+	if err := r.scoped(ctx).
 		Model(&models.VM{}).
 		Where("status = ?", status).
 		Count(&count).Error; err != nil {
@@ -320,3 +526,84 @@ func (r *vmRepository) CountByStatus(ctx context.Context, status models.VMStatus
 	}
 	return count, nil
 }
+
+// CountByNodeAndStatus counts VMs grouped by node and status
+func (r *vmRepository) CountByNodeAndStatus(ctx context.Context) ([]*models.VMCount, error) {
+	var counts []*models.VMCount
+	if err := r.scoped(ctx).
+		Model(&models.VM{}).
+		Select("COALESCE(node_id, '') as node_id, status, COUNT(*) as count").
+		Group("node_id, status").
+		Scan(&counts).Error; err != nil {
+		return nil, errors.DatabaseError("count VMs by node and status", err)
+	}
+	return counts, nil
+}
+
+// GetNodeAllocations sums the resources allocated to VMs per node.
+// Node capacity is shared by all projects, so the sums are never scoped.
+func (r *vmRepository) GetNodeAllocations(ctx context.Context) (map[string]*models.NodeAllocation, error) {
+	var rows []*models.NodeAllocation
+	if err := conn(ctx, r.db).
+		Model(&models.VM{}).
+		Select(`
+			node_id,
+			COALESCE(SUM(cpu_cores), 0) as cpu_cores,
+			COALESCE(SUM(ram_mb), 0) as ram_mb,
+			COALESCE(SUM(disk_gb), 0) as disk_gb,
+			COUNT(*) as vm_count
+		`).
+		Where("node_id IS NOT NULL AND node_id != ''").
+		Group("node_id").
+		Scan(&rows).Error; err != nil {
+		return nil, errors.DatabaseError("get node allocations", err)
+	}
+
+	allocations := make(map[string]*models.NodeAllocation, len(rows))
+	for _, row := range rows {
+		allocations[row.NodeID] = row
+	}
+	return allocations, nil
+}
+
+// applyLabelSelector restricts a VM query to the VMs whose labels match the selector.
+// Equality and set requirements use jsonb containment and existence requirements use
+// the ? operator, so both are served by the GIN index on labels.
+func applyLabelSelector(query *gorm.DB, selector labels.Selector) *gorm.DB {
+	for _, r := range selector {
+		// The ? operator is written literally as gorm would take it for a placeholder
+		hasKey := "labels ? " + quoteLiteral(r.Key)
+
+		switch r.Operator {
+		case labels.Exists:
+			query = query.Where(hasKey)
+		case labels.DoesNotExist:
+			query = query.Where("labels IS NULL OR NOT " + hasKey)
+		case labels.Equals, labels.In:
+			condition, args := labelContainment(r)
+			query = query.Where(condition, args...)
+		case labels.NotEquals, labels.NotIn:
+			condition, args := labelContainment(r)
+			query = query.Where("labels IS NULL OR NOT ("+condition+")", args...)
+		}
+	}
+	return query
+}
+
+// labelContainment returns a condition matching labels that have the key of the
+// requirement set to any of its values
+func labelContainment(r labels.Requirement) (string, []interface{}) {
+	conditions := make([]string, len(r.Values))
+	args := make([]interface{}, len(r.Values))
+	for i, value := range r.Values {
+		data, _ := json.Marshal(map[string]string{r.Key: value})
+		conditions[i] = "labels @> ?::jsonb"
+		args[i] = string(data)
+	}
+	return strings.Join(conditions, " OR "), args
+}
+
+// quoteLiteral quotes a string as an SQL literal
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/tenancy"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// VolumeRepository interface defines volume data access operations
+type VolumeRepository interface {
+	Create(ctx context.Context, volume *models.Volume) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Volume, error)
+	List(ctx context.Context, opts models.VolumeListOptions) ([]*models.Volume, error)
+	ListByVM(ctx context.Context, vmID uuid.UUID) ([]*models.Volume, error)
+	Update(ctx context.Context, volume *models.Volume) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	CountByProject(ctx context.Context, projectID uuid.UUID) (int64, error)
+}
+
+// volumeRepository implements VolumeRepository interface
+type volumeRepository struct {
+	db *gorm.DB
+}
+
+// NewVolumeRepository creates a new volume repository
+func NewVolumeRepository(db *gorm.DB) VolumeRepository {
+	return &volumeRepository{db: db}
+}
+
+// scoped returns a query restricted to the volumes of the caller's projects
+func (r *volumeRepository) scoped(ctx context.Context) *gorm.DB {
+	return scopeToProjects(ctx, conn(ctx, r.db), "project_id")
+}
+
+// Create creates a new volume
+func (r *volumeRepository) Create(ctx context.Context, volume *models.Volume) error {
+	if !tenancy.Allows(ctx, volume.ProjectID) {
+		return errors.NotFoundError("Project", volume.ProjectID.String())
+	}
+	if err := conn(ctx, r.db).Create(volume).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "UNIQUE constraint") {
+			return errors.AlreadyExistsError("Volume", volume.Name)
+		}
+		return errors.DatabaseError("create volume", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a volume by ID
+func (r *volumeRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Volume, error) {
+	var volume models.Volume
+	if err := r.scoped(ctx).First(&volume, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundError("Volume", id.String())
+		}
+		return nil, errors.DatabaseError("get volume by ID", err)
+	}
+	return &volume, nil
+}
+
+// List retrieves the volumes of the caller's projects ordered by name
+func (r *volumeRepository) List(ctx context.Context, opts models.VolumeListOptions) ([]*models.Volume, error) {
+	query := r.scoped(ctx)
+	if opts.Status != "" {
+		query = query.Where("status = ?", opts.Status)
+	}
+	if opts.VMID != "" {
+		query = query.Where("vm_id = ?", opts.VMID)
+	}
+
+	var volumes []*models.Volume
+	if err := query.Order("name ASC").Find(&volumes).Error; err != nil {
+		return nil, errors.DatabaseError("list volumes", err)
+	}
+	return volumes, nil
+}
+
+// ListByVM retrieves the volumes attached to a VM in attachment order
+func (r *volumeRepository) ListByVM(ctx context.Context, vmID uuid.UUID) ([]*models.Volume, error) {
+	var volumes []*models.Volume
+	if err := r.scoped(ctx).
+		Where("vm_id = ?", vmID).
+		Order("attached_at ASC").
+		Find(&volumes).Error; err != nil {
+		return nil, errors.DatabaseError("list volumes of VM", err)
+	}
+	return volumes, nil
+}
+
+// Update updates a volume
+func (r *volumeRepository) Update(ctx context.Context, volume *models.Volume) error {
+	if err := conn(ctx, r.db).Save(volume).Error; err != nil {
+		return errors.DatabaseError("update volume", err)
+	}
+	return nil
+}
+
+// Delete deletes a volume
+func (r *volumeRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.scoped(ctx).Delete(&models.Volume{}, "id = ?", id)
+	if result.Error != nil {
+		return errors.DatabaseError("delete volume", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.NotFoundError("Volume", id.String())
+	}
+	return nil
+}
+
+// CountByProject counts the volumes of a project
+func (r *volumeRepository) CountByProject(ctx context.Context, projectID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.scoped(ctx).
+		Model(&models.Volume{}).
+		Where("project_id = ?", projectID).
+		Count(&count).Error; err != nil {
+		return 0, errors.DatabaseError("count volumes by project", err)
+	}
+	return count, nil
+}
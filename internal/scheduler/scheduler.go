@@ -0,0 +1,148 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+)
+
+// Request describes the resources a VM needs on a node
+type Request struct {
+	CPUCores int
+	RAMMb    int
+	DiskGb   int
+}
+
+// Candidate is a node together with the resources already allocated on it
+type Candidate struct {
+	Node       *models.Node
+	Allocation models.NodeAllocation
+}
+
+// Free returns the unallocated resources of the node
+func (c Candidate) Free() models.NodeCapacity {
+	return models.NodeCapacity{
+		CPUCores: c.Node.Capacity.CPUCores - c.Allocation.CPUCores,
+		RAMMb:    c.Node.Capacity.RAMMb - c.Allocation.RAMMb,
+		DiskGb:   c.Node.Capacity.DiskGb - c.Allocation.DiskGb,
+	}
+}
+
+// Fits checks if the request fits into the free resources of the node
+func (c Candidate) Fits(req Request) bool {
+	free := c.Free()
+	return req.CPUCores <= free.CPUCores && req.RAMMb <= free.RAMMb && req.DiskGb <= free.DiskGb
+}
+
+// utilization returns the average fraction of capacity allocated after placing req
+func (c Candidate) utilization(req Request) float64 {
+	capacity := c.Node.Capacity
+	if capacity.CPUCores == 0 || capacity.RAMMb == 0 || capacity.DiskGb == 0 {
+		return 1
+	}
+
+	cpu := float64(c.Allocation.CPUCores+req.CPUCores) / float64(capacity.CPUCores)
+	ram := float64(c.Allocation.RAMMb+req.RAMMb) / float64(capacity.RAMMb)
+	disk := float64(c.Allocation.DiskGb+req.DiskGb) / float64(capacity.DiskGb)
+	return (cpu + ram + disk) / 3
+}
+
+// Scheduler selects a node for a VM using a placement strategy
+type Scheduler struct {
+	strategy Strategy
+}
+
+// New creates a scheduler using the named strategy
+func New(strategyName string) (*Scheduler, error) {
+	strategy, ok := strategies[strategyName]
+	if !ok {
+		return nil, fmt.Errorf("unknown scheduling strategy: %s", strategyName)
+	}
+	return &Scheduler{strategy: strategy}, nil
+}
+
+// Strategy returns the name of the active strategy
+func (s *Scheduler) Strategy() string {
+	return s.strategy.Name()
+}
+
+// Select filters candidates by free capacity and returns the best scored node.
+// Only schedulable nodes are considered. When no node fits, an INSUFFICIENT_CAPACITY
+// error explains which resources ran out.
+func (s *Scheduler) Select(req Request, candidates []Candidate) (*models.Node, error) {
+	var feasible []Candidate
+	var ready []Candidate
+
+	for _, c := range candidates {
+		if !c.Node.IsSchedulable() {
+			continue
+		}
+		ready = append(ready, c)
+		if c.Fits(req) {
+			feasible = append(feasible, c)
+		}
+	}
+
+	if len(feasible) == 0 {
+		return nil, capacityError(req, ready)
+	}
+
+	// Highest score wins; ties are broken by node name to keep placement deterministic
+	sort.SliceStable(feasible, func(i, j int) bool {
+		si, sj := s.strategy.Score(feasible[i], req), s.strategy.Score(feasible[j], req)
+		if si != sj {
+			return si > sj
+		}
+		return feasible[i].Node.Name < feasible[j].Node.Name
+	})
+
+	return feasible[0].Node, nil
+}
+
+// capacityError builds an error describing why no ready node could take the request
+func capacityError(req Request, ready []Candidate) error {
+	if len(ready) == 0 {
+		return errors.InsufficientCapacityError([]string{"nodes"}, "No ready compute nodes are available")
+	}
+
+	var maxFree models.NodeCapacity
+	for _, c := range ready {
+		free := c.Free()
+		if free.CPUCores > maxFree.CPUCores {
+			maxFree.CPUCores = free.CPUCores
+		}
+		if free.RAMMb > maxFree.RAMMb {
+			maxFree.RAMMb = free.RAMMb
+		}
+		if free.DiskGb > maxFree.DiskGb {
+			maxFree.DiskGb = free.DiskGb
+		}
+	}
+
+	var resources []string
+	var reasons []string
+	if req.CPUCores > maxFree.CPUCores {
+		resources = append(resources, "cpu")
+		reasons = append(reasons, fmt.Sprintf("CPU cores (requested %d, largest free %d)", req.CPUCores, maxFree.CPUCores))
+	}
+	if req.RAMMb > maxFree.RAMMb {
+		resources = append(resources, "ram")
+		reasons = append(reasons, fmt.Sprintf("RAM MB (requested %d, largest free %d)", req.RAMMb, maxFree.RAMMb))
+	}
+	if req.DiskGb > maxFree.DiskGb {
+		resources = append(resources, "disk")
+		reasons = append(reasons, fmt.Sprintf("Disk GB (requested %d, largest free %d)", req.DiskGb, maxFree.DiskGb))
+	}
+
+	if len(resources) == 0 {
+		// Every resource is available somewhere, just not on the same node
+		return errors.InsufficientCapacityError([]string{"cpu", "ram", "disk"},
+			"No single ready node has enough free CPU, RAM and disk at the same time")
+	}
+
+	return errors.InsufficientCapacityError(resources,
+		fmt.Sprintf("No ready node has enough free %s", strings.Join(reasons, ", ")))
+}
@@ -0,0 +1,53 @@
+package scheduler
+
+// Strategy names
+const (
+	StrategyBinPack        = "binpack"
+	StrategySpread         = "spread"
+	StrategyLeastAllocated = "least-allocated"
+)
+
+// Strategy scores a feasible node for a request; higher scores are preferred
+type Strategy interface {
+	Name() string
+	Score(c Candidate, req Request) float64
+}
+
+// strategies holds the registered placement strategies by name
+var strategies = map[string]Strategy{
+	StrategyBinPack:        binPackStrategy{},
+	StrategySpread:         spreadStrategy{},
+	StrategyLeastAllocated: leastAllocatedStrategy{},
+}
+
+// Register adds a custom placement strategy
+func Register(strategy Strategy) {
+	strategies[strategy.Name()] = strategy
+}
+
+// binPackStrategy fills the most utilized nodes first to keep others free
+type binPackStrategy struct{}
+
+func (binPackStrategy) Name() string { return StrategyBinPack }
+
+func (binPackStrategy) Score(c Candidate, req Request) float64 {
+	return c.utilization(req)
+}
+
+// spreadStrategy places VMs on the nodes hosting the fewest VMs
+type spreadStrategy struct{}
+
+func (spreadStrategy) Name() string { return StrategySpread }
+
+func (spreadStrategy) Score(c Candidate, req Request) float64 {
+	return -float64(c.Allocation.VMCount)
+}
+
+// leastAllocatedStrategy places VMs on the nodes with the most free capacity
+type leastAllocatedStrategy struct{}
+
+func (leastAllocatedStrategy) Name() string { return StrategyLeastAllocated }
+
+func (leastAllocatedStrategy) Score(c Candidate, req Request) float64 {
+	return 1 - c.utilization(req)
+}
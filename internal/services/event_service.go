@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/repositories"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// EventService interface defines VM audit trail queries
+type EventService interface {
+	ListEvents(ctx context.Context, opts models.VMEventListOptions) (*models.VMEventListResponse, error)
+}
+
+// eventService implements EventService interface
+type eventService struct {
+	eventRepo repositories.EventRepository
+	logger    *logger.Logger
+}
+
+// NewEventService creates a new event service
+func NewEventService(eventRepo repositories.EventRepository, logger *logger.Logger) EventService {
+	return &eventService{
+		eventRepo: eventRepo,
+		logger:    logger.WithComponent("event-service"),
+	}
+}
+
+// ListEvents lists VM events with pagination and filtering
+func (s *eventService) ListEvents(ctx context.Context, opts models.VMEventListOptions) (*models.VMEventListResponse, error) {
+	events, total, err := s.eventRepo.List(ctx, opts)
+	if err != nil {
+		s.logger.WithOperation("list-events").Errorf("Failed to list VM events: %v", err)
+		return nil, err
+	}
+
+	return &models.VMEventListResponse{
+		Events:     events,
+		Pagination: models.NewPagination(opts.Page, opts.Limit, total),
+	}, nil
+}
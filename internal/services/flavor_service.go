@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/repositories"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// FlavorService interface defines flavor catalog and project flavor operations
+type FlavorService interface {
+	ListFlavors(ctx context.Context) ([]*models.Flavor, error)
+	GetFlavor(ctx context.Context, name string) (*models.Flavor, error)
+	CreateFlavor(ctx context.Context, req *models.FlavorCreateRequest) (*models.Flavor, error)
+	UpdateFlavor(ctx context.Context, name string, req *models.FlavorUpdateRequest) (*models.Flavor, error)
+	DeleteFlavor(ctx context.Context, name string) error
+	GetProjectFlavors(ctx context.Context, project string) (*models.ProjectFlavors, error)
+	UpdateProjectFlavors(ctx context.Context, project string, req *models.ProjectFlavorsUpdateRequest) (*models.ProjectFlavors, error)
+}
+
+// flavorService implements FlavorService interface
+type flavorService struct {
+	flavorRepo  repositories.FlavorRepository
+	projectRepo repositories.ProjectRepository
+	logger      *logger.Logger
+}
+
+// NewFlavorService creates a new flavor service
+func NewFlavorService(flavorRepo repositories.FlavorRepository, projectRepo repositories.ProjectRepository, logger *logger.Logger) FlavorService {
+	return &flavorService{
+		flavorRepo:  flavorRepo,
+		projectRepo: projectRepo,
+		logger:      logger.WithComponent("flavor-service"),
+	}
+}
+
+// ListFlavors lists the flavor catalog, smallest first
+func (s *flavorService) ListFlavors(ctx context.Context) ([]*models.Flavor, error) {
+	return s.flavorRepo.List(ctx)
+}
+
+// GetFlavor retrieves a flavor by name
+func (s *flavorService) GetFlavor(ctx context.Context, name string) (*models.Flavor, error) {
+	return s.flavorRepo.GetByName(ctx, name)
+}
+
+// CreateFlavor adds a flavor to the catalog
+func (s *flavorService) CreateFlavor(ctx context.Context, req *models.FlavorCreateRequest) (*models.Flavor, error) {
+	log := s.logger.WithOperation("create-flavor")
+
+	flavor := req.ToFlavor()
+	if err := s.flavorRepo.Create(ctx, flavor); err != nil {
+		log.Warnf("Failed to create flavor %s: %v", req.Name, err)
+		return nil, err
+	}
+
+	log.Infof("Flavor created: %s (%d vCPUs, %d MB RAM, %d GB disk)", flavor.Name, flavor.CPUCores, flavor.RAMMb, flavor.DiskGb)
+	return flavor, nil
+}
+
+// UpdateFlavor replaces the size of a flavor; VMs sized by it keep their resources
+func (s *flavorService) UpdateFlavor(ctx context.Context, name string, req *models.FlavorUpdateRequest) (*models.Flavor, error) {
+	log := s.logger.WithOperation("update-flavor")
+
+	flavor, err := s.flavorRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	flavor.Description = req.Description
+	flavor.CPUCores = req.CPUCores
+	flavor.RAMMb = req.RAMMb
+	flavor.DiskGb = req.DiskGb
+	flavor.UpdatedBy = req.UpdatedBy
+	if err := s.flavorRepo.Update(ctx, flavor); err != nil {
+		log.Errorf("Failed to update flavor %s: %v", name, err)
+		return nil, err
+	}
+
+	log.Infof("Flavor updated: %s (%d vCPUs, %d MB RAM, %d GB disk)", flavor.Name, flavor.CPUCores, flavor.RAMMb, flavor.DiskGb)
+	return flavor, nil
+}
+
+// DeleteFlavor removes a flavor from the catalog. Flavors that projects are restricted to
+// cannot be deleted, since removing the last one would lift the restriction.
+func (s *flavorService) DeleteFlavor(ctx context.Context, name string) error {
+	log := s.logger.WithOperation("delete-flavor")
+
+	count, err := s.flavorRepo.CountProjectsAllowing(ctx, name)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.ErrResourceLocked.WithDetails(fmt.Sprintf("Flavor %s is allowed in %d projects", name, count))
+	}
+
+	if err := s.flavorRepo.Delete(ctx, name); err != nil {
+		log.Warnf("Failed to delete flavor %s: %v", name, err)
+		return err
+	}
+
+	log.Infof("Flavor deleted: %s", name)
+	return nil
+}
+
+// GetProjectFlavors reports the flavors a project may use
+func (s *flavorService) GetProjectFlavors(ctx context.Context, project string) (*models.ProjectFlavors, error) {
+	p, err := s.projectRepo.GetByName(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	return s.projectFlavors(ctx, p)
+}
+
+// UpdateProjectFlavors replaces the flavors a project is restricted to; an empty list lifts the restriction
+func (s *flavorService) UpdateProjectFlavors(ctx context.Context, project string, req *models.ProjectFlavorsUpdateRequest) (*models.ProjectFlavors, error) {
+	log := s.logger.WithOperation("update-project-flavors")
+
+	p, err := s.projectRepo.GetByName(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+
+	names := uniqueNames(req.Flavors)
+	flavors, err := s.flavorRepo.ListByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	if len(flavors) != len(names) {
+		known := make(map[string]bool, len(flavors))
+		for _, flavor := range flavors {
+			known[flavor.Name] = true
+		}
+		var unknown []string
+		for _, name := range names {
+			if !known[name] {
+				unknown = append(unknown, name)
+			}
+		}
+		return nil, errors.ErrValidationFailed.WithDetails("Unknown flavors: " + strings.Join(unknown, ", "))
+	}
+
+	if err := s.flavorRepo.SetAllowed(ctx, p.ID, names, req.UpdatedBy); err != nil {
+		log.Errorf("Failed to update flavors of project %s: %v", project, err)
+		return nil, err
+	}
+
+	log.Infof("Flavors of project %s updated: %v", project, names)
+	return s.projectFlavors(ctx, p)
+}
+
+// projectFlavors lists the flavors a project is restricted to, or the whole catalog
+func (s *flavorService) projectFlavors(ctx context.Context, project *models.Project) (*models.ProjectFlavors, error) {
+	names, err := s.flavorRepo.AllowedNames(ctx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var flavors []*models.Flavor
+	if len(names) > 0 {
+		flavors, err = s.flavorRepo.ListByNames(ctx, names)
+	} else {
+		flavors, err = s.flavorRepo.List(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ProjectFlavors{
+		Project:    project.Name,
+		ProjectID:  project.ID,
+		Restricted: len(names) > 0,
+		Flavors:    flavors,
+	}, nil
+}
+
+// uniqueNames returns the sorted distinct names
+func uniqueNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	unique := make([]string, 0, len(names))
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
@@ -0,0 +1,481 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/internal/imagestore"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/repositories"
+	"github.com/stackit/enterprise-vm-manager/internal/tenancy"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// ImageService interface defines image catalog operations
+type ImageService interface {
+	ListImages(ctx context.Context, opts models.ImageListOptions) ([]*models.Image, error)
+	GetImage(ctx context.Context, name string) (*models.Image, error)
+	CreateImage(ctx context.Context, req *models.ImageCreateRequest) (*models.Image, error)
+	UpdateImage(ctx context.Context, name string, req *models.ImageUpdateRequest) (*models.Image, error)
+	DeleteImage(ctx context.Context, name string) error
+
+	// Image files
+	GetUpload(ctx context.Context, name string) (*models.ImageUpload, error)
+	UploadImage(ctx context.Context, name string, req *models.ImageUploadRequest) (*models.ImageUpload, error)
+	OpenImage(ctx context.Context, name string) (*models.Image, *os.File, error)
+	CollectGarbage(ctx context.Context) (int, error)
+	StartGarbageCollector(ctx context.Context)
+}
+
+// imageService implements ImageService interface
+type imageService struct {
+	imageRepo    repositories.ImageRepository
+	projectRepo  repositories.ProjectRepository
+	vmRepo       repositories.VMRepository
+	snapshotRepo repositories.SnapshotRepository
+	store        *imagestore.Store
+	cfg          *config.Config
+	logger       *logger.Logger
+}
+
+// NewImageService creates a new image service
+func NewImageService(
+	imageRepo repositories.ImageRepository,
+	projectRepo repositories.ProjectRepository,
+	vmRepo repositories.VMRepository,
+	snapshotRepo repositories.SnapshotRepository,
+	store *imagestore.Store,
+	cfg *config.Config,
+	logger *logger.Logger,
+) ImageService {
+	return &imageService{
+		imageRepo:    imageRepo,
+		projectRepo:  projectRepo,
+		vmRepo:       vmRepo,
+		snapshotRepo: snapshotRepo,
+		store:        store,
+		cfg:          cfg,
+		logger:       logger.WithComponent("image-service"),
+	}
+}
+
+// ListImages lists the images visible to the caller
+func (s *imageService) ListImages(ctx context.Context, opts models.ImageListOptions) ([]*models.Image, error) {
+	return s.imageRepo.List(ctx, opts)
+}
+
+// GetImage retrieves an image visible to the caller by ID or "<name>:<version>" reference
+func (s *imageService) GetImage(ctx context.Context, name string) (*models.Image, error) {
+	if id, err := uuid.Parse(name); err == nil {
+		return s.imageRepo.GetByID(ctx, id)
+	}
+
+	imageName, version := models.ParseImageReference(name)
+	image, err := s.imageRepo.GetByReference(ctx, imageName, version)
+	if err != nil {
+		return nil, err
+	}
+	// References are resolved regardless of visibility, so hide the private images of other projects
+	if image.Visibility == models.ImageVisibilityPrivate && (image.ProjectID == nil || !tenancy.Allows(ctx, *image.ProjectID)) {
+		return nil, errors.NotFoundError("Image", name)
+	}
+	return image, nil
+}
+
+// CreateImage registers an image in the catalog
+func (s *imageService) CreateImage(ctx context.Context, req *models.ImageCreateRequest) (*models.Image, error) {
+	log := s.logger.WithOperation("create-image")
+
+	if req.Checksum != "" {
+		if err := models.ValidateChecksum(req.Checksum); err != nil {
+			return nil, errors.ValidationError("checksum", err.Error())
+		}
+	}
+
+	image := req.ToImage()
+	switch {
+	case image.Visibility == models.ImageVisibilityPrivate && req.Project == "":
+		return nil, errors.ValidationError("project", "Private images must belong to a project")
+	case image.Visibility == models.ImageVisibilityPublic && req.Project != "":
+		return nil, errors.ValidationError("project", "Public images cannot belong to a project")
+	case req.Project != "":
+		project, err := s.projectRepo.GetByName(ctx, req.Project)
+		if err != nil {
+			return nil, err
+		}
+		image.ProjectID = &project.ID
+	}
+
+	if err := s.imageRepo.Create(ctx, image); err != nil {
+		log.Warnf("Failed to create image %s: %v", image.Reference(), err)
+		return nil, err
+	}
+
+	log.Infof("Image registered: %s (%s, %s)", image.Reference(), image.OSFamily, image.Visibility)
+	return image, nil
+}
+
+// UpdateImage replaces the description, requirements, checksum and deprecation state of an image
+func (s *imageService) UpdateImage(ctx context.Context, name string, req *models.ImageUpdateRequest) (*models.Image, error) {
+	log := s.logger.WithOperation("update-image")
+
+	image, err := s.GetImage(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Checksum != "" {
+		if err := models.ValidateChecksum(req.Checksum); err != nil {
+			return nil, errors.ValidationError("checksum", err.Error())
+		}
+	}
+	if image.IsUploaded() && !strings.EqualFold(req.Checksum, image.Checksum) {
+		return nil, errors.ValidationError("checksum", "The checksum of an uploaded image cannot be changed")
+	}
+	if req.Replacement != "" {
+		if !req.Deprecated {
+			return nil, errors.ValidationError("replacement", "Only deprecated images can name a replacement")
+		}
+		if err := s.checkReplacement(ctx, image, req.Replacement); err != nil {
+			return nil, err
+		}
+	}
+
+	image.Description = req.Description
+	image.MinDiskGb = req.MinDiskGb
+	image.MinRAMMb = req.MinRAMMb
+	image.Checksum = req.Checksum
+	image.UpdatedBy = req.UpdatedBy
+	if req.Deprecated {
+		image.Deprecate(req.Replacement)
+	} else {
+		image.Undeprecate()
+	}
+
+	if err := s.imageRepo.Update(ctx, image); err != nil {
+		log.Errorf("Failed to update image %s: %v", image.Reference(), err)
+		return nil, err
+	}
+
+	log.Infof("Image updated: %s (%s)", image.Reference(), image.State)
+	return image, nil
+}
+
+// DeleteImage removes an image and its uploaded file. Images VMs were created from
+// cannot be deleted; they should be deprecated instead.
+func (s *imageService) DeleteImage(ctx context.Context, name string) error {
+	log := s.logger.WithOperation("delete-image")
+
+	image, err := s.GetImage(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	// VMs of every project count, not only those the caller can see
+	count, err := s.vmRepo.CountByImage(tenancy.Unrestricted(ctx), image.References())
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.ErrResourceLocked.
+			WithDetails(fmt.Sprintf("Image %s is used by %d VMs; deprecate it instead", image.Reference(), count))
+	}
+
+	if err := s.removeImage(ctx, image); err != nil {
+		log.Warnf("Failed to delete image %s: %v", image.Reference(), err)
+		return err
+	}
+
+	log.Infof("Image deleted: %s", image.Reference())
+	return nil
+}
+
+// GetUpload reports how much of the file of an image has been uploaded
+func (s *imageService) GetUpload(ctx context.Context, name string) (*models.ImageUpload, error) {
+	image, err := s.GetImage(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if image.IsUploaded() {
+		return &models.ImageUpload{Offset: image.SizeBytes, Total: image.SizeBytes, Complete: true, Image: image}, nil
+	}
+
+	offset, err := s.store.Offset(image.ID)
+	if err != nil {
+		return nil, errors.StorageError("get upload offset", err)
+	}
+	return &models.ImageUpload{Offset: offset}, nil
+}
+
+// UploadImage stores a chunk of the file of an image. Once the last chunk has been
+// received the file is verified against the checksum and becomes available for download.
+func (s *imageService) UploadImage(ctx context.Context, name string, req *models.ImageUploadRequest) (*models.ImageUpload, error) {
+	log := s.logger.WithOperation("upload-image")
+
+	image, err := s.GetImage(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if image.IsUploaded() {
+		return nil, errors.AlreadyExistsError("Image file", image.Reference()).
+			WithDetails(fmt.Sprintf("Image %s has already been uploaded; register a new version instead", image.Reference()))
+	}
+	checksum, err := expectedChecksum(image, req.Checksum)
+	if err != nil {
+		return nil, err
+	}
+	if req.Total > s.store.MaxSize() {
+		return nil, errors.ErrPayloadTooLarge.
+			WithDetails(fmt.Sprintf("Images may have at most %d bytes", s.store.MaxSize()))
+	}
+
+	offset, err := s.store.Write(image.ID, req.Offset, req.Body)
+	if err != nil {
+		return nil, storeError("upload image", image, offset, req.Offset, err)
+	}
+
+	upload := &models.ImageUpload{Offset: offset, Total: req.Total}
+	if req.Total >= 0 && offset < req.Total {
+		return upload, nil
+	}
+	if req.Total >= 0 && offset > req.Total {
+		s.store.Discard(image.ID)
+		return nil, errors.ValidationError("Content-Range", fmt.Sprintf("Upload has more than the declared %d bytes", req.Total))
+	}
+
+	algorithm, _, _ := strings.Cut(checksum, ":")
+	file, err := s.store.Finish(image.ID, algorithm, func(file *imagestore.File) error {
+		if !strings.EqualFold(file.Checksum, checksum) {
+			return errors.ChecksumMismatchError(checksum, file.Checksum)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Warnf("Failed to complete upload of image %s: %v", image.Reference(), err)
+		return nil, storeError("complete image upload", image, offset, req.Offset, err)
+	}
+
+	now := time.Now()
+	image.Checksum = file.Checksum
+	image.DiskFormat = file.Format
+	image.SizeBytes = file.Size
+	image.UploadedAt = &now
+	image.UnreferencedSince = nil
+	image.UpdatedBy = req.UpdatedBy
+	if err := s.imageRepo.Update(ctx, image); err != nil {
+		s.store.Remove(image.ID, file.Format)
+		log.Errorf("Failed to record upload of image %s: %v", image.Reference(), err)
+		return nil, err
+	}
+
+	log.Infof("Image uploaded: %s (%s, %d bytes)", image.Reference(), file.Format, file.Size)
+	upload.Total = file.Size
+	upload.Complete = true
+	upload.Image = image
+	return upload, nil
+}
+
+// OpenImage opens the uploaded file of an image; the caller closes it
+func (s *imageService) OpenImage(ctx context.Context, name string) (*models.Image, *os.File, error) {
+	image, err := s.GetImage(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !image.IsUploaded() {
+		return nil, nil, errors.NotFoundError("Image file", image.Reference()).
+			WithDetails(fmt.Sprintf("Image %s has not been uploaded", image.Reference()))
+	}
+
+	file, err := s.store.Open(image.ID, image.DiskFormat)
+	if err != nil {
+		if stderrors.Is(err, os.ErrNotExist) {
+			return nil, nil, errors.NotFoundError("Image file", image.Reference())
+		}
+		return nil, nil, errors.StorageError("open image", err)
+	}
+	return image, file, nil
+}
+
+// CollectGarbage deletes the uploaded images no VM or snapshot has referred to for the
+// grace period, along with abandoned partial uploads, and returns how many images were deleted
+func (s *imageService) CollectGarbage(ctx context.Context) (int, error) {
+	log := s.logger.WithOperation("collect-images")
+
+	// References from every project count
+	ctx = tenancy.Unrestricted(ctx)
+	now := time.Now()
+	cutoff := now.Add(-s.cfg.Images.GCGracePeriod)
+
+	images, err := s.imageRepo.ListUploaded(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	collected := 0
+	for _, image := range images {
+		referenced, err := s.isReferenced(ctx, image)
+		if err != nil {
+			return collected, err
+		}
+
+		switch {
+		case referenced && image.UnreferencedSince == nil:
+			continue
+		case referenced:
+			image.UnreferencedSince = nil
+		case image.UnreferencedSince == nil:
+			image.UnreferencedSince = &now
+		case image.UnreferencedSince.After(cutoff):
+			continue
+		default:
+			err := s.removeImage(ctx, image)
+			if errors.Is(err, errors.ErrResourceLocked) {
+				// A VM was created from the image since it was checked
+				image.UnreferencedSince = nil
+				break
+			}
+			if err != nil {
+				return collected, err
+			}
+			log.Infof("Collected image %s, unreferenced since %s", image.Reference(), image.UnreferencedSince.Format(time.RFC3339))
+			collected++
+			continue
+		}
+
+		if err := s.imageRepo.Update(ctx, image); err != nil {
+			return collected, err
+		}
+	}
+
+	removed, err := s.store.RemoveStaleUploads(cutoff)
+	if err != nil {
+		return collected, errors.StorageError("remove stale uploads", err)
+	}
+	if removed > 0 {
+		log.Infof("Removed %d abandoned upload(s)", removed)
+	}
+	return collected, nil
+}
+
+// StartGarbageCollector periodically collects unreferenced images until the context is cancelled
+func (s *imageService) StartGarbageCollector(ctx context.Context) {
+	log := s.logger.WithOperation("image-gc")
+	if s.cfg.Images.GCGracePeriod <= 0 {
+		log.Info("Image garbage collection is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(s.cfg.Images.GCInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.CollectGarbage(ctx); err != nil {
+				log.Errorf("Failed to collect images: %v", err)
+			}
+		}
+	}
+}
+
+// isReferenced reports whether a VM or the snapshot of a VM was created from the image
+func (s *imageService) isReferenced(ctx context.Context, image *models.Image) (bool, error) {
+	vms, err := s.vmRepo.CountByImage(ctx, image.References())
+	if err != nil || vms > 0 {
+		return vms > 0, err
+	}
+	snapshots, err := s.snapshotRepo.CountByImage(ctx, image.References())
+	return snapshots > 0, err
+}
+
+// removeImage deletes an image from the catalog and its file from the image store. References
+// are checked again with the image locked, as VMs may have been created from it since the
+// caller checked them; VM admission locks the image as well.
+func (s *imageService) removeImage(ctx context.Context, image *models.Image) error {
+	err := s.imageRepo.WithLocked(ctx, image.ID, func(ctx context.Context, image *models.Image) error {
+		// VMs of every project count, not only those the caller can see
+		referenced, err := s.isReferenced(tenancy.Unrestricted(ctx), image)
+		if err != nil {
+			return err
+		}
+		if referenced {
+			return errors.ErrResourceLocked.
+				WithDetails(fmt.Sprintf("Image %s is in use; deprecate it instead", image.Reference()))
+		}
+		return s.imageRepo.Delete(ctx, image.ID)
+	})
+	if err != nil {
+		return err
+	}
+	if image.IsUploaded() {
+		if err := s.store.Remove(image.ID, image.DiskFormat); err != nil {
+			return errors.StorageError("remove image file", err)
+		}
+	}
+	return nil
+}
+
+// expectedChecksum returns the checksum an upload is verified against: the one sent with
+// the upload, which must agree with the catalog, or else the one in the catalog
+func expectedChecksum(image *models.Image, checksum string) (string, error) {
+	switch {
+	case checksum == "" && image.Checksum == "":
+		return "", errors.ValidationError("checksum", "A checksum is required to verify the upload; register it with the image or send it with the upload")
+	case checksum == "":
+		return image.Checksum, nil
+	}
+
+	if err := models.ValidateChecksum(checksum); err != nil {
+		return "", errors.ValidationError("checksum", err.Error())
+	}
+	if image.Checksum != "" && !strings.EqualFold(checksum, image.Checksum) {
+		return "", errors.ValidationError("checksum", fmt.Sprintf("Checksum differs from %s registered for image %s", image.Checksum, image.Reference()))
+	}
+	return checksum, nil
+}
+
+// storeError maps image store errors to API errors
+func storeError(operation string, image *models.Image, offset, requested int64, err error) error {
+	switch {
+	case stderrors.Is(err, imagestore.ErrOffset):
+		return errors.UploadOffsetError(offset, requested)
+	case stderrors.Is(err, imagestore.ErrBusy):
+		return errors.ErrResourceLocked.WithDetails(fmt.Sprintf("Another upload of image %s is in progress", image.Reference()))
+	case stderrors.Is(err, imagestore.ErrTooLarge):
+		return errors.ErrPayloadTooLarge.WithDetails("Upload exceeds the size limit")
+	case stderrors.Is(err, imagestore.ErrExists):
+		return errors.AlreadyExistsError("Image file", image.Reference())
+	case errors.Is(err, errors.ErrChecksumMismatch):
+		return err
+	}
+	return errors.StorageError(operation, err)
+}
+
+// checkReplacement checks that the replacement of a deprecated image is another active image
+func (s *imageService) checkReplacement(ctx context.Context, image *models.Image, reference string) error {
+	name, version := models.ParseImageReference(reference)
+	if name == image.Name && version == image.Version {
+		return errors.ValidationError("replacement", "An image cannot replace itself")
+	}
+
+	replacement, err := s.imageRepo.GetByReference(ctx, name, version)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return errors.ValidationError("replacement", fmt.Sprintf("Unknown image %s", reference))
+		}
+		return err
+	}
+	if replacement.IsDeprecated() {
+		return errors.ValidationError("replacement", fmt.Sprintf("Image %s is deprecated itself", reference))
+	}
+	return nil
+}
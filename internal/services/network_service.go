@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/repositories"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// NetworkService interface defines virtual network operations
+type NetworkService interface {
+	CreateNetwork(ctx context.Context, req *models.NetworkCreateRequest) (*models.Network, error)
+	GetNetwork(ctx context.Context, id uuid.UUID) (*models.Network, error)
+	ListNetworks(ctx context.Context) ([]*models.Network, error)
+	UpdateNetwork(ctx context.Context, id uuid.UUID, req *models.NetworkUpdateRequest) (*models.Network, error)
+	DeleteNetwork(ctx context.Context, id uuid.UUID) error
+	ListInterfaces(ctx context.Context, id uuid.UUID) ([]*models.NetworkInterface, error)
+}
+
+// networkService implements NetworkService interface
+type networkService struct {
+	networkRepo repositories.NetworkRepository
+	quotaRepo   repositories.QuotaRepository
+	logger      *logger.Logger
+}
+
+// NewNetworkService creates a new network service
+func NewNetworkService(networkRepo repositories.NetworkRepository, quotaRepo repositories.QuotaRepository, logger *logger.Logger) NetworkService {
+	return &networkService{
+		networkRepo: networkRepo,
+		quotaRepo:   quotaRepo,
+		logger:      logger.WithComponent("network-service"),
+	}
+}
+
+// CreateNetwork creates a network in a project
+func (s *networkService) CreateNetwork(ctx context.Context, req *models.NetworkCreateRequest) (*models.Network, error) {
+	log := s.logger.WithOperation("create-network")
+
+	network, err := req.ToNetwork()
+	if err != nil {
+		return nil, errors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.networkRepo.Create(ctx, network); err != nil {
+		log.Warnf("Failed to create network %s: %v", network.Name, err)
+		return nil, err
+	}
+
+	log.Infof("Network created: %s (ID: %s, %s)", network.Name, network.ID, network.CIDR)
+	return network, nil
+}
+
+// GetNetwork retrieves a network by ID
+func (s *networkService) GetNetwork(ctx context.Context, id uuid.UUID) (*models.Network, error) {
+	return s.networkRepo.GetByID(ctx, id)
+}
+
+// ListNetworks lists the networks of the caller's projects
+func (s *networkService) ListNetworks(ctx context.Context) ([]*models.Network, error) {
+	return s.networkRepo.List(ctx)
+}
+
+// UpdateNetwork replaces the description and DNS servers of a network
+func (s *networkService) UpdateNetwork(ctx context.Context, id uuid.UUID, req *models.NetworkUpdateRequest) (*models.Network, error) {
+	network, err := s.networkRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	network.Description = req.Description
+	network.DNSServers = models.AddressList(req.DNSServers)
+	if network.DNSServers == nil {
+		network.DNSServers = models.AddressList{}
+	}
+	network.UpdatedBy = req.UpdatedBy
+	if err := s.networkRepo.Update(ctx, network); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithOperation("update-network").Infof("Network updated: %s (ID: %s)", network.Name, network.ID)
+	return network, nil
+}
+
+// DeleteNetwork deletes a network that no VM is connected to
+func (s *networkService) DeleteNetwork(ctx context.Context, id uuid.UUID) error {
+	network, err := s.networkRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// Addresses are allocated with the project locked, so no VM can connect while the network is deleted
+	err = s.quotaRepo.WithLockedUsage(ctx, network.ProjectID, func(ctx context.Context, _ *models.Quota, _ *models.QuotaUsage) error {
+		interfaces, err := s.networkRepo.ListInterfaces(ctx, network.ID)
+		if err != nil {
+			return err
+		}
+		if len(interfaces) > 0 {
+			return errors.ErrResourceLocked.
+				WithContext("network", network.Name).
+				WithDetails(fmt.Sprintf("Network %s has %d VM interfaces; delete those VMs first", network.Name, len(interfaces)))
+		}
+		return s.networkRepo.Delete(ctx, network.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.WithOperation("delete-network").Infof("Network deleted: %s (ID: %s)", network.Name, network.ID)
+	return nil
+}
+
+// ListInterfaces lists the VM interfaces connected to a network and their addresses
+func (s *networkService) ListInterfaces(ctx context.Context, id uuid.UUID) ([]*models.NetworkInterface, error) {
+	network, err := s.networkRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.networkRepo.ListInterfaces(ctx, network.ID)
+}
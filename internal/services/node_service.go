@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/repositories"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// NodeService interface defines node inventory operations
+type NodeService interface {
+	RegisterNode(ctx context.Context, req *models.NodeRegisterRequest) (*models.Node, bool, error)
+	Heartbeat(ctx context.Context, name string) (*models.Node, error)
+	GetNode(ctx context.Context, name string) (*models.Node, error)
+	ListNodes(ctx context.Context, opts models.NodeListOptions) ([]*models.Node, error)
+	StartHeartbeatMonitor(ctx context.Context)
+}
+
+// nodeService implements NodeService interface
+type nodeService struct {
+	nodeRepo repositories.NodeRepository
+	cfg      *config.Config
+	logger   *logger.Logger
+}
+
+// NewNodeService creates a new node service
+func NewNodeService(nodeRepo repositories.NodeRepository, cfg *config.Config, logger *logger.Logger) NodeService {
+	return &nodeService{
+		nodeRepo: nodeRepo,
+		cfg:      cfg,
+		logger:   logger.WithComponent("node-service"),
+	}
+}
+
+// RegisterNode registers a new node or refreshes the inventory of a known one.
+// The returned flag reports whether the node was newly created.
+func (s *nodeService) RegisterNode(ctx context.Context, req *models.NodeRegisterRequest) (*models.Node, bool, error) {
+	log := s.logger.WithOperation("register-node")
+	now := time.Now()
+
+	node, err := s.nodeRepo.GetByName(ctx, req.Name)
+	if err != nil && !errors.Is(err, errors.ErrNotFound) {
+		return nil, false, err
+	}
+
+	created := node == nil
+	if created {
+		node = &models.Node{State: models.NodeStateReady}
+	}
+
+	if err := req.ApplyToNode(node); err != nil {
+		return nil, false, errors.InternalError("Failed to apply node registration", err)
+	}
+	node.LastHeartbeatAt = &now
+
+	// A node coming back from an outage is ready again; maintenance is kept until lifted
+	if node.State == models.NodeStateUnreachable {
+		node.State = models.NodeStateReady
+	}
+
+	if created {
+		err = s.nodeRepo.Create(ctx, node)
+	} else {
+		err = s.nodeRepo.Update(ctx, node)
+	}
+	if err != nil {
+		log.Errorf("Failed to register node %s: %v", req.Name, err)
+		return nil, false, err
+	}
+
+	log.Infof("Node registered: %s (%d cores, %d MB RAM, %d GB disk)",
+		node.Name, node.Capacity.CPUCores, node.Capacity.RAMMb, node.Capacity.DiskGb)
+	return node, created, nil
+}
+
+// Heartbeat records a heartbeat for a registered node
+func (s *nodeService) Heartbeat(ctx context.Context, name string) (*models.Node, error) {
+	if err := s.nodeRepo.RecordHeartbeat(ctx, name, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.nodeRepo.GetByName(ctx, name)
+}
+
+// GetNode retrieves a node by name
+func (s *nodeService) GetNode(ctx context.Context, name string) (*models.Node, error) {
+	node, err := s.nodeRepo.GetByName(ctx, name)
+	if err != nil {
+		s.logger.WithOperation("get-node").Errorf("Failed to get node %s: %v", name, err)
+		return nil, err
+	}
+	return node, nil
+}
+
+// ListNodes lists registered nodes
+func (s *nodeService) ListNodes(ctx context.Context, opts models.NodeListOptions) ([]*models.Node, error) {
+	nodes, err := s.nodeRepo.List(ctx, opts)
+	if err != nil {
+		s.logger.WithOperation("list-nodes").Errorf("Failed to list nodes: %v", err)
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// StartHeartbeatMonitor periodically marks nodes without recent heartbeats as unreachable
+// until ctx is cancelled
+func (s *nodeService) StartHeartbeatMonitor(ctx context.Context) {
+	log := s.logger.WithOperation("heartbeat-monitor")
+
+	ticker := time.NewTicker(s.cfg.Nodes.MonitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cutoff := time.Now().Add(-s.cfg.Nodes.HeartbeatTimeout)
+			count, err := s.nodeRepo.MarkUnreachable(ctx, cutoff)
+			if err != nil {
+				log.Errorf("Failed to check node heartbeats: %v", err)
+				continue
+			}
+			if count > 0 {
+				log.Warnf("Marked %d node(s) unreachable after missing heartbeats", count)
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/repositories"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// OperationService interface defines operation tracking queries
+type OperationService interface {
+	GetOperation(ctx context.Context, id uuid.UUID) (*models.Operation, error)
+	ListOperations(ctx context.Context, opts models.OperationListOptions) ([]*models.Operation, error)
+	RecoverInterrupted(ctx context.Context) error
+}
+
+// operationService implements OperationService interface
+type operationService struct {
+	opRepo repositories.OperationRepository
+	logger *logger.Logger
+}
+
+// NewOperationService creates a new operation service
+func NewOperationService(opRepo repositories.OperationRepository, logger *logger.Logger) OperationService {
+	return &operationService{
+		opRepo: opRepo,
+		logger: logger.WithComponent("operation-service"),
+	}
+}
+
+// GetOperation retrieves an operation by ID
+func (s *operationService) GetOperation(ctx context.Context, id uuid.UUID) (*models.Operation, error) {
+	op, err := s.opRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.WithOperation("get-operation").Errorf("Failed to get operation %s: %v", id, err)
+		return nil, err
+	}
+	return op, nil
+}
+
+// ListOperations lists operations matching the given filters
+func (s *operationService) ListOperations(ctx context.Context, opts models.OperationListOptions) ([]*models.Operation, error) {
+	ops, err := s.opRepo.List(ctx, opts)
+	if err != nil {
+		s.logger.WithOperation("list-operations").Errorf("Failed to list operations: %v", err)
+		return nil, err
+	}
+	return ops, nil
+}
+
+// RecoverInterrupted fails operations left unfinished by a previous process.
+// Their background workers no longer exist, so clients polling them would wait forever.
+func (s *operationService) RecoverInterrupted(ctx context.Context) error {
+	log := s.logger.WithOperation("recover-operations")
+
+	count, err := s.opRepo.FailUnfinished(ctx, "operation interrupted by server restart")
+	if err != nil {
+		log.Errorf("Failed to recover interrupted operations: %v", err)
+		return err
+	}
+	if count > 0 {
+		log.Warnf("Marked %d interrupted operation(s) as failed", count)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/repositories"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// ProjectService interface defines project and membership operations
+type ProjectService interface {
+	CreateProject(ctx context.Context, req *models.ProjectCreateRequest) (*models.Project, error)
+	GetProject(ctx context.Context, name string) (*models.Project, error)
+	ListProjects(ctx context.Context) ([]*models.Project, error)
+	DeleteProject(ctx context.Context, name string) error
+	EnsureDefaultProject(ctx context.Context) (*models.Project, error)
+	ListMembers(ctx context.Context, name string) ([]*models.ProjectMember, error)
+	AddMember(ctx context.Context, name string, req *models.ProjectMemberCreateRequest) (*models.ProjectMember, error)
+	RemoveMember(ctx context.Context, name, subject string) error
+	ProjectIDsForSubject(ctx context.Context, subject string, includeDefault bool) ([]uuid.UUID, error)
+}
+
+// projectService implements ProjectService interface
+type projectService struct {
+	projectRepo repositories.ProjectRepository
+	vmRepo      repositories.VMRepository
+	volumeRepo  repositories.VolumeRepository
+	networkRepo repositories.NetworkRepository
+	imageRepo   repositories.ImageRepository
+	logger      *logger.Logger
+}
+
+// NewProjectService creates a new project service
+func NewProjectService(
+	projectRepo repositories.ProjectRepository,
+	vmRepo repositories.VMRepository,
+	volumeRepo repositories.VolumeRepository,
+	networkRepo repositories.NetworkRepository,
+	imageRepo repositories.ImageRepository,
+	logger *logger.Logger,
+) ProjectService {
+	return &projectService{
+		projectRepo: projectRepo,
+		vmRepo:      vmRepo,
+		volumeRepo:  volumeRepo,
+		networkRepo: networkRepo,
+		imageRepo:   imageRepo,
+		logger:      logger.WithComponent("project-service"),
+	}
+}
+
+// CreateProject creates a project and makes its creator a member
+func (s *projectService) CreateProject(ctx context.Context, req *models.ProjectCreateRequest) (*models.Project, error) {
+	log := s.logger.WithOperation("create-project")
+
+	project := &models.Project{
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedBy:   req.CreatedBy,
+		UpdatedBy:   req.CreatedBy,
+	}
+	if err := s.projectRepo.Create(ctx, project); err != nil {
+		log.Errorf("Failed to create project %s: %v", req.Name, err)
+		return nil, err
+	}
+
+	// Authentication is disabled when requests are made by the system
+	if req.CreatedBy != "" && req.CreatedBy != "system" {
+		member := &models.ProjectMember{
+			ProjectID: project.ID,
+			Subject:   req.CreatedBy,
+			CreatedBy: req.CreatedBy,
+		}
+		if err := s.projectRepo.AddMember(ctx, member); err != nil {
+			log.Errorf("Failed to add creator %s to project %s: %v", req.CreatedBy, project.Name, err)
+			return nil, err
+		}
+	}
+
+	log.Infof("Project created: %s (ID: %s)", project.Name, project.ID)
+	return project, nil
+}
+
+// GetProject retrieves a project by name
+func (s *projectService) GetProject(ctx context.Context, name string) (*models.Project, error) {
+	return s.projectRepo.GetByName(ctx, name)
+}
+
+// ListProjects lists the projects visible to the caller
+func (s *projectService) ListProjects(ctx context.Context) ([]*models.Project, error) {
+	projects, err := s.projectRepo.List(ctx)
+	if err != nil {
+		s.logger.WithOperation("list-projects").Errorf("Failed to list projects: %v", err)
+		return nil, err
+	}
+	return projects, nil
+}
+
+// DeleteProject deletes an empty project. Deleting the rows of volumes and private images
+// would leave their storage behind, so they must be deleted through their own APIs first.
+func (s *projectService) DeleteProject(ctx context.Context, name string) error {
+	log := s.logger.WithOperation("delete-project")
+
+	project, err := s.projectRepo.GetByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if project.IsDefault() {
+		return errors.ErrResourceLocked.WithDetails("The default project cannot be deleted")
+	}
+
+	counters := []struct {
+		kind  string
+		count func(ctx context.Context, projectID uuid.UUID) (int64, error)
+	}{
+		{"VMs", s.vmRepo.CountByProject},
+		{"volumes", s.volumeRepo.CountByProject},
+		{"networks", s.networkRepo.CountByProject},
+		{"private images", s.imageRepo.CountByProject},
+	}
+	for _, counter := range counters {
+		count, err := counter.count(ctx, project.ID)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.ErrResourceLocked.WithDetails(fmt.Sprintf("Project %s still has %d %s", name, count, counter.kind))
+		}
+	}
+
+	if err := s.projectRepo.Delete(ctx, project.ID); err != nil {
+		log.Errorf("Failed to delete project %s: %v", name, err)
+		return err
+	}
+
+	log.Infof("Project deleted: %s", name)
+	return nil
+}
+
+// EnsureDefaultProject creates the default project if it does not exist yet
+func (s *projectService) EnsureDefaultProject(ctx context.Context) (*models.Project, error) {
+	project, err := s.projectRepo.GetByName(ctx, models.DefaultProjectName)
+	if err == nil {
+		return project, nil
+	}
+	if !errors.Is(err, errors.ErrNotFound) {
+		return nil, err
+	}
+
+	project, err = s.CreateProject(ctx, &models.ProjectCreateRequest{
+		Name:        models.DefaultProjectName,
+		Description: "VMs created outside a project",
+		CreatedBy:   "system",
+	})
+	if errors.Is(err, errors.ErrAlreadyExists) {
+		// Another instance created it concurrently
+		return s.projectRepo.GetByName(ctx, models.DefaultProjectName)
+	}
+	return project, err
+}
+
+// ListMembers lists the members of a project
+func (s *projectService) ListMembers(ctx context.Context, name string) ([]*models.ProjectMember, error) {
+	project, err := s.projectRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.projectRepo.ListMembers(ctx, project.ID)
+}
+
+// AddMember adds a subject to a project
+func (s *projectService) AddMember(ctx context.Context, name string, req *models.ProjectMemberCreateRequest) (*models.ProjectMember, error) {
+	log := s.logger.WithOperation("add-project-member")
+
+	project, err := s.projectRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	member := &models.ProjectMember{
+		ProjectID: project.ID,
+		Subject:   req.Subject,
+		CreatedBy: req.CreatedBy,
+	}
+	if err := s.projectRepo.AddMember(ctx, member); err != nil {
+		log.Errorf("Failed to add %s to project %s: %v", req.Subject, name, err)
+		return nil, err
+	}
+
+	log.Infof("Subject %s added to project %s", member.Subject, name)
+	return member, nil
+}
+
+// RemoveMember removes a subject from a project
+func (s *projectService) RemoveMember(ctx context.Context, name, subject string) error {
+	project, err := s.projectRepo.GetByName(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if err := s.projectRepo.RemoveMember(ctx, project.ID, subject); err != nil {
+		s.logger.WithOperation("remove-project-member").Errorf("Failed to remove %s from project %s: %v", subject, name, err)
+		return err
+	}
+	return nil
+}
+
+// ProjectIDsForSubject returns the projects whose VMs the subject may access
+func (s *projectService) ProjectIDsForSubject(ctx context.Context, subject string, includeDefault bool) ([]uuid.UUID, error) {
+	return s.projectRepo.ProjectIDsForSubject(ctx, subject, includeDefault)
+}
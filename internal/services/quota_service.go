@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/repositories"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// QuotaService interface defines project quota operations
+type QuotaService interface {
+	ListQuotas(ctx context.Context) ([]*models.QuotaStatus, error)
+	GetQuota(ctx context.Context, project string) (*models.QuotaStatus, error)
+	UpdateQuota(ctx context.Context, project string, req *models.QuotaUpdateRequest) (*models.QuotaStatus, error)
+}
+
+// quotaService implements QuotaService interface
+type quotaService struct {
+	quotaRepo   repositories.QuotaRepository
+	projectRepo repositories.ProjectRepository
+	cfg         *config.Config
+	logger      *logger.Logger
+}
+
+// NewQuotaService creates a new quota service
+func NewQuotaService(quotaRepo repositories.QuotaRepository, projectRepo repositories.ProjectRepository, cfg *config.Config, logger *logger.Logger) QuotaService {
+	return &quotaService{
+		quotaRepo:   quotaRepo,
+		projectRepo: projectRepo,
+		cfg:         cfg,
+		logger:      logger.WithComponent("quota-service"),
+	}
+}
+
+// ListQuotas reports the limits and usage of every project visible to the caller
+func (s *quotaService) ListQuotas(ctx context.Context) ([]*models.QuotaStatus, error) {
+	projects, err := s.projectRepo.List(ctx)
+	if err != nil {
+		s.logger.WithOperation("list-quotas").Errorf("Failed to list projects: %v", err)
+		return nil, err
+	}
+
+	statuses := make([]*models.QuotaStatus, 0, len(projects))
+	for _, project := range projects {
+		status, err := s.status(ctx, project)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// GetQuota reports the limits and usage of a project
+func (s *quotaService) GetQuota(ctx context.Context, project string) (*models.QuotaStatus, error) {
+	p, err := s.projectRepo.GetByName(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	return s.status(ctx, p)
+}
+
+// UpdateQuota replaces the limits of a project
+func (s *quotaService) UpdateQuota(ctx context.Context, project string, req *models.QuotaUpdateRequest) (*models.QuotaStatus, error) {
+	log := s.logger.WithOperation("update-quota")
+
+	p, err := s.projectRepo.GetByName(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+
+	quota := &models.Quota{
+		ProjectID: p.ID,
+		Limits:    req.QuotaLimits,
+		UpdatedBy: req.UpdatedBy,
+	}
+	if err := s.quotaRepo.Upsert(ctx, quota); err != nil {
+		log.Errorf("Failed to update quota of project %s: %v", project, err)
+		return nil, err
+	}
+
+	log.Infof("Quota of project %s updated: %+v", project, quota.Limits)
+	return s.status(ctx, p)
+}
+
+// status combines the effective limits of a project with its usage
+func (s *quotaService) status(ctx context.Context, project *models.Project) (*models.QuotaStatus, error) {
+	quota, err := s.quotaRepo.Get(ctx, project.ID)
+	if err != nil && !errors.Is(err, errors.ErrNotFound) {
+		return nil, err
+	}
+
+	usage, err := s.quotaRepo.Usage(ctx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.QuotaStatus{
+		Project:   project.Name,
+		ProjectID: project.ID,
+		Limits:    effectiveLimits(quota, s.cfg),
+		Usage:     *usage,
+		Default:   quota == nil,
+	}, nil
+}
+
+// effectiveLimits returns the limits of a quota, or the configured defaults
+// for projects without one
+func effectiveLimits(quota *models.Quota, cfg *config.Config) models.QuotaLimits {
+	if quota != nil {
+		return quota.Limits
+	}
+	return models.QuotaLimits{MaxVMs: cfg.Limits.MaxVMs}
+}
+
+// checkQuota returns a QUOTA_EXCEEDED error if the request does not fit in the limits
+func checkQuota(limits models.QuotaLimits, usage *models.QuotaUsage, projectID uuid.UUID, req models.QuotaRequest) error {
+	if violation := limits.Exceeded(usage, req); violation != nil {
+		return errors.QuotaExceededError(violation.Dimension, violation.Limit, violation.Used, violation.Requested).
+			WithContext("project_id", projectID.String())
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/repositories"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// RBACService interface defines authorization and role management operations
+type RBACService interface {
+	Authorize(ctx context.Context, subject string, roles []string, permission models.Permission) error
+	ListRoles(ctx context.Context) ([]*models.Role, error)
+	GetRole(ctx context.Context, name string) (*models.Role, error)
+	CreateRole(ctx context.Context, req *models.RoleCreateRequest) (*models.Role, error)
+	UpdateRole(ctx context.Context, name string, req *models.RoleUpdateRequest) (*models.Role, error)
+	DeleteRole(ctx context.Context, name string) error
+	ListBindings(ctx context.Context, opts models.RoleBindingListOptions) ([]*models.RoleBinding, error)
+	CreateBinding(ctx context.Context, req *models.RoleBindingCreateRequest) (*models.RoleBinding, error)
+	DeleteBinding(ctx context.Context, id uuid.UUID) error
+}
+
+// rbacService implements RBACService interface
+type rbacService struct {
+	roleRepo    repositories.RoleRepository
+	bindingRepo repositories.RoleBindingRepository
+	logger      *logger.Logger
+}
+
+// NewRBACService creates a new RBAC service
+func NewRBACService(roleRepo repositories.RoleRepository, bindingRepo repositories.RoleBindingRepository, logger *logger.Logger) RBACService {
+	return &rbacService{
+		roleRepo:    roleRepo,
+		bindingRepo: bindingRepo,
+		logger:      logger.WithComponent("rbac-service"),
+	}
+}
+
+// Authorize checks that the roles of the token, or the roles bound to the subject, grant the permission
+func (s *rbacService) Authorize(ctx context.Context, subject string, roles []string, permission models.Permission) error {
+	bound, err := s.bindingRepo.RolesForSubject(ctx, subject)
+	if err != nil {
+		return err
+	}
+
+	effective := append(append([]string{}, roles...), bound...)
+
+	var custom []string
+	for _, name := range effective {
+		if role := models.BuiltinRole(name); role != nil {
+			if role.Permissions.Grants(permission) {
+				return nil
+			}
+			continue
+		}
+		custom = append(custom, name)
+	}
+
+	customRoles, err := s.roleRepo.ListByNames(ctx, custom)
+	if err != nil {
+		return err
+	}
+	for _, role := range customRoles {
+		if role.Permissions.Grants(permission) {
+			return nil
+		}
+	}
+
+	s.logger.WithOperation("authorize").Debugf("Denied %s to %s with roles %v", permission, subject, effective)
+	return errors.PermissionDeniedError(string(permission))
+}
+
+// ListRoles lists built-in and custom roles
+func (s *rbacService) ListRoles(ctx context.Context) ([]*models.Role, error) {
+	custom, err := s.roleRepo.List(ctx)
+	if err != nil {
+		s.logger.WithOperation("list-roles").Errorf("Failed to list roles: %v", err)
+		return nil, err
+	}
+	return append(models.BuiltinRoles(), custom...), nil
+}
+
+// GetRole retrieves a built-in or custom role by name
+func (s *rbacService) GetRole(ctx context.Context, name string) (*models.Role, error) {
+	if role := models.BuiltinRole(name); role != nil {
+		return role, nil
+	}
+	return s.roleRepo.GetByName(ctx, name)
+}
+
+// CreateRole creates a custom role
+func (s *rbacService) CreateRole(ctx context.Context, req *models.RoleCreateRequest) (*models.Role, error) {
+	log := s.logger.WithOperation("create-role")
+
+	if models.BuiltinRole(req.Name) != nil {
+		return nil, errors.AlreadyExistsError("Role", req.Name)
+	}
+	if err := validatePermissions(req.Permissions); err != nil {
+		return nil, err
+	}
+
+	role := &models.Role{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+		CreatedBy:   req.CreatedBy,
+		UpdatedBy:   req.CreatedBy,
+	}
+	if err := s.roleRepo.Create(ctx, role); err != nil {
+		log.Errorf("Failed to create role %s: %v", req.Name, err)
+		return nil, err
+	}
+
+	log.Infof("Role created: %s with permissions %v", role.Name, role.Permissions)
+	return role, nil
+}
+
+// UpdateRole replaces the description and permissions of a custom role
+func (s *rbacService) UpdateRole(ctx context.Context, name string, req *models.RoleUpdateRequest) (*models.Role, error) {
+	log := s.logger.WithOperation("update-role")
+
+	if models.BuiltinRole(name) != nil {
+		return nil, errors.ErrResourceLocked.WithDetails(fmt.Sprintf("Built-in role %s cannot be modified", name))
+	}
+	if err := validatePermissions(req.Permissions); err != nil {
+		return nil, err
+	}
+
+	role, err := s.roleRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	role.Description = req.Description
+	role.Permissions = req.Permissions
+	role.UpdatedBy = req.UpdatedBy
+	if err := s.roleRepo.Update(ctx, role); err != nil {
+		log.Errorf("Failed to update role %s: %v", name, err)
+		return nil, err
+	}
+
+	log.Infof("Role updated: %s with permissions %v", role.Name, role.Permissions)
+	return role, nil
+}
+
+// DeleteRole deletes a custom role and its bindings
+func (s *rbacService) DeleteRole(ctx context.Context, name string) error {
+	if models.BuiltinRole(name) != nil {
+		return errors.ErrResourceLocked.WithDetails(fmt.Sprintf("Built-in role %s cannot be deleted", name))
+	}
+
+	if err := s.roleRepo.Delete(ctx, name); err != nil {
+		s.logger.WithOperation("delete-role").Errorf("Failed to delete role %s: %v", name, err)
+		return err
+	}
+
+	s.logger.WithOperation("delete-role").Infof("Role deleted: %s", name)
+	return nil
+}
+
+// ListBindings lists role bindings
+func (s *rbacService) ListBindings(ctx context.Context, opts models.RoleBindingListOptions) ([]*models.RoleBinding, error) {
+	bindings, err := s.bindingRepo.List(ctx, opts)
+	if err != nil {
+		s.logger.WithOperation("list-role-bindings").Errorf("Failed to list role bindings: %v", err)
+		return nil, err
+	}
+	return bindings, nil
+}
+
+// CreateBinding grants an existing role to a subject
+func (s *rbacService) CreateBinding(ctx context.Context, req *models.RoleBindingCreateRequest) (*models.RoleBinding, error) {
+	log := s.logger.WithOperation("create-role-binding")
+
+	if _, err := s.GetRole(ctx, req.Role); err != nil {
+		return nil, err
+	}
+
+	binding := &models.RoleBinding{
+		Subject:   req.Subject,
+		Role:      req.Role,
+		CreatedBy: req.CreatedBy,
+	}
+	if err := s.bindingRepo.Create(ctx, binding); err != nil {
+		log.Errorf("Failed to bind role %s to %s: %v", req.Role, req.Subject, err)
+		return nil, err
+	}
+
+	log.Infof("Role %s bound to %s", binding.Role, binding.Subject)
+	return binding, nil
+}
+
+// DeleteBinding revokes a role binding
+func (s *rbacService) DeleteBinding(ctx context.Context, id uuid.UUID) error {
+	if err := s.bindingRepo.Delete(ctx, id); err != nil {
+		s.logger.WithOperation("delete-role-binding").Errorf("Failed to delete role binding %s: %v", id, err)
+		return err
+	}
+	return nil
+}
+
+// validatePermissions checks that all permissions are known
+func validatePermissions(permissions []models.Permission) error {
+	for _, permission := range permissions {
+		if !permission.IsValid() {
+			return errors.ValidationError("permissions", fmt.Sprintf("Unknown permission: %s", permission))
+		}
+	}
+	return nil
+}
@@ -1,88 +1,298 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
-	"math/rand"
+	"strings"
 	"time"
 
+	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
 	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/internal/hypervisor"
+	"github.com/stackit/enterprise-vm-manager/internal/imagestore"
+	"github.com/stackit/enterprise-vm-manager/internal/labels"
+	"github.com/stackit/enterprise-vm-manager/internal/metrics"
 	"github.com/stackit/enterprise-vm-manager/internal/models"
 	"github.com/stackit/enterprise-vm-manager/internal/repositories"
+	"github.com/stackit/enterprise-vm-manager/internal/scheduler"
+	"github.com/stackit/enterprise-vm-manager/internal/tenancy"
+	"github.com/stackit/enterprise-vm-manager/internal/watch"
 	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/jsonpatch"
 	"github.com/stackit/enterprise-vm-manager/pkg/logger"
 )
 
+// statusSwapAttempts is how often a worker retries a status change whose version was
+// advanced by a write that left the status alone
+const statusSwapAttempts = 5
+
 // VMService interface defines VM business operations
 type VMService interface {
-	CreateVM(ctx context.Context, req *models.VMCreateRequest) (*models.VM, error)
+	CreateVM(ctx context.Context, req *models.VMCreateRequest) (*models.VM, *models.Operation, error)
 	GetVM(ctx context.Context, id uuid.UUID) (*models.VM, error)
-	GetVMByName(ctx context.Context, name string) (*models.VM, error)
+	GetVMByName(ctx context.Context, projectID uuid.UUID, name string) (*models.VM, error)
 	UpdateVM(ctx context.Context, id uuid.UUID, req *models.VMUpdateRequest) (*models.VM, error)
-	DeleteVM(ctx context.Context, id uuid.UUID) error
+	PatchVM(ctx context.Context, id uuid.UUID, req *models.VMPatchRequest) (*models.VM, error)
+	DeleteVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) (*models.Operation, error)
 	ListVMs(ctx context.Context, opts models.VMListOptions) (*models.VMListResponse, error)
-	StartVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) error
-	StopVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) error
-	RestartVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) error
-	SuspendVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) error
-	ResumeVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) error
+	StartVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) (*models.Operation, error)
+	StopVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) (*models.Operation, error)
+	RestartVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) (*models.Operation, error)
+	SuspendVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) (*models.Operation, error)
+	ResumeVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) (*models.Operation, error)
 	GetResourceSummary(ctx context.Context) (*models.ResourceSummary, error)
 	UpdateVMStats(ctx context.Context, id uuid.UUID) error
+	WatchVMs(ctx context.Context, opts models.VMListOptions, resourceVersion uint64) (*watch.Subscription, error)
+	WatchVM(ctx context.Context, id uuid.UUID, resourceVersion uint64) (*watch.Subscription, error)
+	CreateSnapshot(ctx context.Context, vmID uuid.UUID, req *models.SnapshotCreateRequest) (*models.Snapshot, *models.Operation, error)
+	GetSnapshot(ctx context.Context, vmID, id uuid.UUID) (*models.Snapshot, error)
+	ListSnapshots(ctx context.Context, vmID uuid.UUID) ([]*models.Snapshot, error)
+	DeleteSnapshot(ctx context.Context, vmID, id uuid.UUID, req *models.VMStateChangeRequest) (*models.Operation, error)
+	RevertSnapshot(ctx context.Context, vmID, id uuid.UUID, req *models.VMStateChangeRequest) (*models.Operation, error)
+	CloneVM(ctx context.Context, sourceID uuid.UUID, req *models.VMCloneRequest) (*models.VM, *models.Operation, error)
 }
 
 // vmService implements VMService interface
 type vmService struct {
-	vmRepo repositories.VMRepository
-	cfg    *config.Config
-	logger *logger.Logger
+	vmRepo       repositories.VMRepository
+	nodeRepo     repositories.NodeRepository
+	opRepo       repositories.OperationRepository
+	eventRepo    repositories.EventRepository
+	quotaRepo    repositories.QuotaRepository
+	snapshotRepo repositories.SnapshotRepository
+	flavorRepo   repositories.FlavorRepository
+	imageRepo    repositories.ImageRepository
+	imageStore   *imagestore.Store
+	volumeRepo   repositories.VolumeRepository
+	networkRepo  repositories.NetworkRepository
+	driver       hypervisor.Driver
+	scheduler    *scheduler.Scheduler
+	hub          *watch.Hub
+	metrics      *metrics.Metrics
+	cfg          *config.Config
+	logger       *logger.Logger
 }
 
 // NewVMService creates a new VM service
-func NewVMService(vmRepo repositories.VMRepository, cfg *config.Config, logger *logger.Logger) VMService {
+func NewVMService(
+	vmRepo repositories.VMRepository,
+	nodeRepo repositories.NodeRepository,
+	opRepo repositories.OperationRepository,
+	eventRepo repositories.EventRepository,
+	quotaRepo repositories.QuotaRepository,
+	snapshotRepo repositories.SnapshotRepository,
+	flavorRepo repositories.FlavorRepository,
+	imageRepo repositories.ImageRepository,
+	imageStore *imagestore.Store,
+	volumeRepo repositories.VolumeRepository,
+	networkRepo repositories.NetworkRepository,
+	driver hypervisor.Driver,
+	sched *scheduler.Scheduler,
+	hub *watch.Hub,
+	metrics *metrics.Metrics,
+	cfg *config.Config,
+	logger *logger.Logger,
+) VMService {
 	return &vmService{
-		vmRepo: vmRepo,
-		cfg:    cfg,
-		logger: logger.WithComponent("vm-service"),
+		vmRepo:       vmRepo,
+		nodeRepo:     nodeRepo,
+		opRepo:       opRepo,
+		eventRepo:    eventRepo,
+		quotaRepo:    quotaRepo,
+		snapshotRepo: snapshotRepo,
+		flavorRepo:   flavorRepo,
+		imageRepo:    imageRepo,
+		imageStore:   imageStore,
+		volumeRepo:   volumeRepo,
+		networkRepo:  networkRepo,
+		driver:       driver,
+		scheduler:    sched,
+		hub:          hub,
+		metrics:      metrics,
+		cfg:          cfg,
+		logger:       logger.WithComponent("vm-service"),
 	}
 }
 
-// CreateVM creates a new virtual machine
-func (s *vmService) CreateVM(ctx context.Context, req *models.VMCreateRequest) (*models.VM, error) {
+// CreateVM creates a new virtual machine and starts its provisioning operation
+func (s *vmService) CreateVM(ctx context.Context, req *models.VMCreateRequest) (*models.VM, *models.Operation, error) {
 	log := s.logger.WithOperation("create-vm")
 
+	if err := req.CheckSizing(); err != nil {
+		return nil, nil, errors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if req.ProjectID == uuid.Nil {
+		return nil, nil, errors.ErrMissingField.WithDetails("VM must belong to a project")
+	}
+
+	// Create VM model, sized by its flavor if it has one
+	vm := req.ToVM()
+	if vm.Flavor != "" {
+		if err := s.expandFlavor(ctx, vm); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := s.checkFlavorAllowed(ctx, vm); err != nil {
+		return nil, nil, err
+	}
+
+	// New VMs must use an active image of the catalog and meet its requirements
+	image, err := s.lookupImage(ctx, vm)
+	if err != nil {
+		log.Warnf("Image validation failed: %v", err)
+		return nil, nil, err
+	}
+	if image.IsDeprecated() {
+		return nil, nil, imageDeprecatedError(image)
+	}
+	if err := checkImageRequirements(image, vm); err != nil {
+		return nil, nil, err
+	}
+
 	// Validate resource limits
-	if err := s.validateResourceLimits(req.CPUCores, req.RAMMb, req.DiskGb); err != nil {
+	if err := s.validateResourceLimits(vm.Spec.CPUCores, vm.Spec.RAMMb, vm.Spec.DiskGb); err != nil {
 		log.Warnf("Resource validation failed: %v", err)
-		return nil, err
+		return nil, nil, err
 	}
+	// Track provisioning so clients can follow it
+	op, err := s.admitVM(ctx, vm, "", models.OperationTypeProvision, req.CreatedBy)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	log.Infof("VM created successfully: %s (ID: %s)", vm.Name, vm.ID)
+
+	audit := auditInfo{actor: req.CreatedBy, requestID: req.RequestID}
+	s.recordEvent(ctx, models.NewVMEvent(models.VMEventCreated, vm, audit.actor, audit.reason, audit.requestID).
+		WithStatusChange("", vm.Status).
+		WithOperation(op))
+	s.hub.Publish(watch.EventCreated, vm)
+
+	// Start async provisioning on the hypervisor; the worker owns its own copy of the operation
+	tracked := *op
+	go s.provisionVM(&tracked, audit)
+
+	return vm, op, nil
+}
+
+// CloneVM creates a new VM from the disk of an existing VM or one of its snapshots
+// and starts the operation copying it
+func (s *vmService) CloneVM(ctx context.Context, sourceID uuid.UUID, req *models.VMCloneRequest) (*models.VM, *models.Operation, error) {
+	log := s.logger.WithOperation("clone-vm")
 
-	// Check if VM name already exists
-	exists, err := s.vmRepo.ExistsByName(ctx, req.Name)
+	if err := req.CheckSizing(); err != nil {
+		return nil, nil, errors.ErrValidationFailed.WithDetails(err.Error())
+	}
+
+	source, err := s.vmRepo.GetByID(ctx, sourceID)
 	if err != nil {
-		log.Errorf("Failed to check VM name existence: %v", err)
-		return nil, err
+		return nil, nil, err
 	}
-	if exists {
-		return nil, errors.AlreadyExistsError("VM", req.Name)
+
+	vm, err := req.ToVM(source)
+	if err != nil {
+		log.Errorf("Failed to build clone of VM %s: %v", source.ID, err)
+		return nil, nil, errors.InternalError("Failed to build clone", err)
+	}
+	if req.Flavor != "" {
+		if err := s.expandFlavor(ctx, vm); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := s.checkFlavorAllowed(ctx, vm); err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkCatalogRequirements(ctx, vm); err != nil {
+		return nil, nil, err
+	}
+	if err := s.validateResourceLimits(vm.Spec.CPUCores, vm.Spec.RAMMb, vm.Spec.DiskGb); err != nil {
+		log.Warnf("Resource validation failed: %v", err)
+		return nil, nil, err
+	}
+	if vm.Spec.DiskGb < source.Spec.DiskGb {
+		return nil, nil, errors.ErrValidationFailed.
+			WithDetails(fmt.Sprintf("Disk of a clone cannot be smaller than the %d GB of its source", source.Spec.DiskGb))
 	}
 
-	// Create VM model
-	vm := req.ToVM()
-	vm.NodeID = s.assignNodeID()
+	// Copying a live disk is only consistent through a snapshot
+	operation, required := "clone", string(models.VMStatusStopped)
+	if req.SnapshotID != nil {
+		operation, required = "clone-snapshot", "stopped, running or suspended"
+	}
 
-	// Create VM in database
-	if err := s.vmRepo.Create(ctx, vm); err != nil {
-		log.Errorf("Failed to create VM: %v", err)
-		return nil, err
+	// Holding the source locked keeps its state and snapshots unchanged until the clone is
+	// admitted. The project quota admitVM locks is locked first, as status changes do.
+	var snapshot *models.Snapshot
+	var op *models.Operation
+	err = s.quotaRepo.WithLockedUsage(ctx, source.ProjectID, func(ctx context.Context, _ *models.Quota, _ *models.QuotaUsage) error {
+		return s.vmRepo.WithLocked(ctx, source.ID, func(ctx context.Context, locked *models.VM) error {
+			source = locked
+			if !source.CanPerformOperation(operation) {
+				return errors.VMStateError(source.ID.String(), string(source.Status), required)
+			}
+			if err := s.checkVMIdle(ctx, source.ID); err != nil {
+				return err
+			}
+
+			var err error
+			if req.SnapshotID != nil {
+				if vm.CloneMode == models.CloneModeLinked {
+					return errors.ErrValidationFailed.WithDetails("Linked clones cannot be created from snapshots")
+				}
+				if snapshot, err = s.snapshotRepo.GetByID(ctx, source.ID, *req.SnapshotID); err != nil {
+					return err
+				}
+				if snapshot.Status != models.SnapshotStatusReady {
+					return errors.ErrSnapshotNotReady.
+						WithContext("snapshot_id", snapshot.ID.String()).
+						WithDetails(fmt.Sprintf("Snapshot %s is %s and cannot be cloned", snapshot.Name, snapshot.Status))
+				}
+			}
+
+			// Linked clones share the source disk, so they must live on its node and freeze it without snapshots
+			node := ""
+			if vm.CloneMode == models.CloneModeLinked {
+				snapshots, err := s.snapshotRepo.ListByVM(ctx, source.ID)
+				if err != nil {
+					return err
+				}
+				if len(snapshots) > 0 {
+					return errors.ErrInvalidVMState.
+						WithContext("vm_id", source.ID.String()).
+						WithDetails(fmt.Sprintf("Linked clones need a source without snapshots, VM %s has %d", source.Name, len(snapshots)))
+				}
+				node = source.NodeID
+			}
+
+			op, err = s.admitVM(ctx, vm, node, models.OperationTypeClone, req.CreatedBy)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
-	log.Infof("VM created successfully: %s (ID: %s)", vm.Name, vm.ID)
+	log.Infof("VM %s cloned from %s (ID: %s, mode: %s)", vm.Name, source.ID, vm.ID, vm.CloneMode)
 
-	// Start async provisioning (simulate)
-	go s.simulateProvisioning(vm.ID)
+	message := fmt.Sprintf("%s clone of %s", vm.CloneMode, source.Name)
+	if snapshot != nil {
+		message += " at snapshot " + snapshot.Name
+	}
+	audit := auditInfo{actor: req.CreatedBy, requestID: req.RequestID}
+	s.recordEvent(ctx, models.NewVMEvent(models.VMEventCreated, vm, audit.actor, audit.reason, audit.requestID).
+		WithStatusChange("", vm.Status).
+		WithOperation(op).
+		WithMessage(message))
+	s.hub.Publish(watch.EventCreated, vm)
 
-	return vm, nil
+	tracked := *op
+	go s.provisionVM(&tracked, audit)
+
+	return vm, op, nil
 }
 
 // GetVM retrieves a VM by ID
@@ -95,9 +305,9 @@ func (s *vmService) GetVM(ctx context.Context, id uuid.UUID) (*models.VM, error)
 	return vm, nil
 }
 
-// GetVMByName retrieves a VM by name
-func (s *vmService) GetVMByName(ctx context.Context, name string) (*models.VM, error) {
-	vm, err := s.vmRepo.GetByName(ctx, name)
+// GetVMByName retrieves a VM by name within a project
+func (s *vmService) GetVMByName(ctx context.Context, projectID uuid.UUID, name string) (*models.VM, error) {
+	vm, err := s.vmRepo.GetByName(ctx, projectID, name)
 	if err != nil {
 		s.logger.WithOperation("get-vm-by-name").Errorf("Failed to get VM %s: %v", name, err)
 		return nil, err
@@ -114,94 +324,287 @@ func (s *vmService) UpdateVM(ctx context.Context, id uuid.UUID, req *models.VMUp
 	if err != nil {
 		return nil, err
 	}
+	if err := checkResourceVersion(vm, req.ResourceVersion); err != nil {
+		return nil, err
+	}
+	if err := req.CheckSizing(); err != nil {
+		return nil, errors.ErrValidationFailed.WithDetails(err.Error())
+	}
 
-	// Check if VM can be updated
-	if !vm.CanPerformOperation("update") {
-		return nil, errors.VMStateError(id.String(), string(vm.Status), "stopped")
+	// Apply updates to a copy so the change can be checked against the current VM
+	updated := *vm
+	if err := req.ApplyToVM(&updated); err != nil {
+		log.Errorf("Failed to apply updates to VM: %v", err)
+		return nil, errors.InternalError("Failed to apply updates", err)
+	}
+
+	return s.saveUpdate(ctx, vm, &updated, req.UpdatedBy, req.RequestID)
+}
+
+// PatchVM applies a JSON Merge Patch or JSON Patch to a VM
+func (s *vmService) PatchVM(ctx context.Context, id uuid.UUID, req *models.VMPatchRequest) (*models.VM, error) {
+	log := s.logger.WithOperation("patch-vm")
+
+	vm, err := s.vmRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResourceVersion(vm, req.ResourceVersion); err != nil {
+		return nil, err
+	}
+
+	doc, err := vm.PatchDocument()
+	if err != nil {
+		return nil, errors.InternalError("Failed to build patch document", err)
+	}
+	original, err := json.Marshal(doc)
+	if err != nil {
+		return nil, errors.InternalError("Failed to build patch document", err)
 	}
 
-	// Validate new resource limits if changed
-	newCPU := req.CPUCores
-	newRAM := req.RAMMb
-	newDisk := req.DiskGb
+	var patched []byte
+	switch req.ContentType {
+	case jsonpatch.MergePatchType:
+		patched, err = jsonpatch.MergePatch(original, req.Patch)
+	case jsonpatch.JSONPatchType:
+		patched, err = jsonpatch.Apply(original, req.Patch)
+	default:
+		return nil, errors.ErrUnsupportedMedia.WithDetails(fmt.Sprintf("Unsupported patch type %q", req.ContentType))
+	}
+	if err != nil {
+		var syntaxErr *jsonpatch.SyntaxError
+		if stderrors.As(err, &syntaxErr) {
+			return nil, errors.ErrInvalidInput.WithDetails(err.Error())
+		}
+		return nil, errors.ErrPatchFailed.WithDetails(err.Error())
+	}
 
-	if newCPU == 0 {
-		newCPU = vm.Spec.CPUCores
+	// Validate the patched document with the rules of a create request
+	var result models.VMCreateRequest
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&result); err != nil {
+		return nil, errors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := binding.Validator.ValidateStruct(&result); err != nil {
+		return nil, errors.ErrValidationFailed.WithDetails(err.Error())
 	}
-	if newRAM == 0 {
-		newRAM = vm.Spec.RAMMb
+	if err := result.CheckSizing(); err != nil {
+		return nil, errors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if result.ImageName != doc.ImageName {
+		return nil, errors.ErrValidationFailed.WithDetails("image_name cannot be changed")
+	}
+	if result.NetworkType != doc.NetworkType {
+		return nil, errors.ErrValidationFailed.WithDetails("network_type cannot be changed")
+	}
+
+	updated := *vm
+	if err := updated.ApplyPatchDocument(&result); err != nil {
+		log.Errorf("Failed to apply patch to VM: %v", err)
+		return nil, errors.InternalError("Failed to apply patch", err)
 	}
-	if newDisk == 0 {
-		newDisk = vm.Spec.DiskGb
+	if req.UpdatedBy != "" {
+		updated.UpdatedBy = req.UpdatedBy
+	}
+
+	return s.saveUpdate(ctx, vm, &updated, req.UpdatedBy, req.RequestID)
+}
+
+// saveUpdate checks the changes of an updated copy of a VM against its state,
+// the resource limits and the project quota and stores them
+func (s *vmService) saveUpdate(ctx context.Context, vm, updated *models.VM, actor, requestID string) (*models.VM, error) {
+	log := s.logger.WithOperation("update-vm")
+
+	// Check if VM can be updated
+	if !vm.CanPerformOperation("update") {
+		return nil, errors.VMStateError(vm.ID.String(), string(vm.Status), "stopped")
+	}
+
+	// Expand a new flavor and check that the project may use the new sizing
+	if updated.Flavor != "" && updated.Flavor != vm.Flavor {
+		if err := s.expandFlavor(ctx, updated); err != nil {
+			return nil, err
+		}
+	}
+	if updated.Flavor != vm.Flavor || updated.Spec != vm.Spec {
+		if err := s.checkFlavorAllowed(ctx, updated); err != nil {
+			return nil, err
+		}
+		if err := s.checkCatalogRequirements(ctx, updated); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := s.validateResourceLimits(newCPU, newRAM, newDisk); err != nil {
+	// Validate new resource limits
+	if err := s.validateResourceLimits(updated.Spec.CPUCores, updated.Spec.RAMMb, updated.Spec.DiskGb); err != nil {
 		return nil, err
 	}
 
-	// Check name uniqueness if changed
-	if req.Name != "" && req.Name != vm.Name {
-		exists, err := s.vmRepo.ExistsByName(ctx, req.Name)
+	// Check name uniqueness within the project if changed
+	if updated.Name != vm.Name {
+		exists, err := s.vmRepo.ExistsByName(ctx, vm.ProjectID, updated.Name)
 		if err != nil {
 			return nil, err
 		}
 		if exists {
-			return nil, errors.AlreadyExistsError("VM", req.Name)
+			return nil, errors.AlreadyExistsError("VM", updated.Name)
 		}
 	}
 
-	// Apply updates
-	if err := req.ApplyToVM(vm); err != nil {
-		log.Errorf("Failed to apply updates to VM: %v", err)
-		return nil, errors.InternalError("Failed to apply updates", err)
-	}
+	err := s.quotaRepo.WithLockedUsage(ctx, vm.ProjectID, func(ctx context.Context, quota *models.Quota, usage *models.QuotaUsage) error {
+		growth := scheduler.Request{
+			CPUCores: updated.Spec.CPUCores - vm.Spec.CPUCores,
+			RAMMb:    updated.Spec.RAMMb - vm.Spec.RAMMb,
+			DiskGb:   updated.Spec.DiskGb - vm.Spec.DiskGb,
+		}
+
+		// Check that the project quota allows a resize
+		request := models.QuotaRequest{CPUCores: growth.CPUCores, RAMMb: growth.RAMMb, DiskGb: growth.DiskGb}
+		if err := checkQuota(effectiveLimits(quota, s.cfg), usage, vm.ProjectID, request); err != nil {
+			log.Warnf("Resize of VM %s exceeds the project quota: %v", vm.ID, err)
+			return err
+		}
 
-	// Update in database
-	if err := s.vmRepo.Update(ctx, vm); err != nil {
-		log.Errorf("Failed to update VM: %v", err)
+		// Check that the current node can absorb a resize
+		if growth.CPUCores > 0 || growth.RAMMb > 0 || growth.DiskGb > 0 {
+			if err := s.checkNodeCapacity(ctx, vm.NodeID, growth); err != nil {
+				log.Warnf("Resize of VM %s does not fit on node %s: %v", vm.ID, vm.NodeID, err)
+				return err
+			}
+		}
+
+		// Update in database
+		if err := s.vmRepo.Update(ctx, updated); err != nil {
+			log.Errorf("Failed to update VM: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	log.Infof("VM updated successfully: %s (ID: %s)", vm.Name, vm.ID)
-	return vm, nil
+	s.recordEvent(ctx, models.NewVMEvent(models.VMEventUpdated, updated, actor, "", requestID).
+		WithMessage(describeChanges(vm, updated)))
+	s.hub.Publish(watch.EventUpdated, updated)
+
+	log.Infof("VM updated successfully: %s (ID: %s)", updated.Name, updated.ID)
+	return updated, nil
 }
 
 // DeleteVM deletes a VM
-func (s *vmService) DeleteVM(ctx context.Context, id uuid.UUID) error {
+func (s *vmService) DeleteVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) (*models.Operation, error) {
 	log := s.logger.WithOperation("delete-vm")
 
 	// Get VM to check status
 	vm, err := s.vmRepo.GetByID(ctx, id)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if err := checkResourceVersion(vm, req.ResourceVersion); err != nil {
+		return nil, err
 	}
 
 	// Check if VM can be deleted
 	if !vm.CanPerformOperation("delete") {
-		return errors.VMStateError(id.String(), string(vm.Status), "stopped")
+		return nil, errors.VMStateError(id.String(), string(vm.Status), "stopped")
+	}
+	if err := s.checkVMIdle(ctx, vm.ID); err != nil {
+		return nil, err
+	}
+
+	// The VM is only claimed if nobody changed it since it was checked above, so of concurrent
+	// requests exactly one destroys its disk, and none can start it while that happens
+	if err := s.vmRepo.UpdateStatus(ctx, vm.ID, vm.Status, vm.ResourceVersion, models.VMStatusDeleting); err != nil {
+		log.Warnf("Failed to claim VM %s for deletion: %v", vm.ID, err)
+		return nil, err
 	}
 
-	// Delete VM
-	if err := s.vmRepo.Delete(ctx, id); err != nil {
+	op, err := s.beginOperation(ctx, models.OperationTypeDelete, vm.ID, req.UpdatedBy)
+	if err != nil {
+		s.restoreStatus(ctx, vm, models.VMStatusDeleting)
+		return nil, err
+	}
+
+	// Release hypervisor resources
+	if err := s.driver.Destroy(ctx, vm); err != nil {
+		log.Errorf("Failed to destroy VM on hypervisor: %v", err)
+		appErr := errors.HypervisorError("destroy", err)
+		s.failOperation(ctx, op, appErr)
+		s.restoreStatus(ctx, vm, models.VMStatusDeleting)
+		return nil, appErr
+	}
+	s.setOperationProgress(ctx, op, 50)
+
+	// Delete VM in the version the claim gave it, together with what it holds
+	err = s.vmRepo.WithLocked(ctx, id, func(ctx context.Context, _ *models.VM) error {
+		// Snapshots were stored in the destroyed disk
+		if err := s.snapshotRepo.DeleteByVM(ctx, id); err != nil {
+			return err
+		}
+
+		// Volumes outlive the VM and become available for other VMs
+		if err := s.releaseVolumes(ctx, vm, req.UpdatedBy); err != nil {
+			return err
+		}
+
+		// Addresses return to the pools of their networks
+		if err := s.networkRepo.ReleaseInterfaces(ctx, id); err != nil {
+			return err
+		}
+		return s.vmRepo.Delete(ctx, id, vm.ResourceVersion+1)
+	})
+	if err != nil {
 		log.Errorf("Failed to delete VM: %v", err)
-		return err
+		s.failOperation(ctx, op, err)
+		s.restoreStatus(ctx, vm, models.VMStatusDeleting)
+		return nil, err
 	}
+	s.completeOperation(ctx, op)
+
+	s.recordEvent(ctx, models.NewVMEvent(models.VMEventDeleted, vm, req.UpdatedBy, req.Reason, req.RequestID).
+		WithOperation(op))
+	s.hub.Publish(watch.EventDeleted, vm)
 
 	log.Infof("VM deleted successfully: %s (ID: %s)", vm.Name, vm.ID)
+	return op, nil
+}
+
+// releaseVolumes detaches the volumes of a VM that is being deleted
+func (s *vmService) releaseVolumes(ctx context.Context, vm *models.VM, actor string) error {
+	volumes, err := s.volumeRepo.ListByVM(ctx, vm.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, volume := range volumes {
+		if err := s.driver.DetachVolume(ctx, vm, volume); err != nil {
+			return errors.HypervisorError("detach volume", err)
+		}
+		volume.Detach()
+		volume.UpdatedBy = actor
+		if err := s.volumeRepo.Update(ctx, volume); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // ListVMs lists VMs with pagination and filtering
 func (s *vmService) ListVMs(ctx context.Context, opts models.VMListOptions) (*models.VMListResponse, error) {
-	vms, total, err := s.vmRepo.List(ctx, opts)
+	// Taken before reading so a watch started from it cannot miss changes made during the list
+	resourceVersion := s.hub.CurrentVersion()
+
+	page, err := s.vmRepo.List(ctx, opts)
 	if err != nil {
 		s.logger.WithOperation("list-vms").Errorf("Failed to list VMs: %v", err)
 		return nil, err
 	}
 
 	// Convert to response format
-	vmResponses := make([]*models.VMResponse, len(vms))
-	for i, vm := range vms {
+	vmResponses := make([]*models.VMResponse, len(page.VMs))
+	for i, vm := range page.VMs {
 		vmResponses[i] = models.NewVMResponse(vm)
 
 		// Update stats if requested
@@ -210,80 +613,74 @@ func (s *vmService) ListVMs(ctx context.Context, opts models.VMListOptions) (*mo
 		}
 	}
 
-	// Calculate pagination
-	totalPages := (total + int64(opts.Limit) - 1) / int64(opts.Limit)
-
-	pagination := models.Pagination{
-		Page:       opts.Page,
-		Limit:      opts.Limit,
-		Total:      total,
-		TotalPages: totalPages,
-		HasNext:    int64(opts.Page) < totalPages,
-		HasPrev:    opts.Page > 1,
+	// Calculate pagination; the cursor tells whether another page follows even without a total
+	pagination := models.Pagination{Page: opts.Page, Limit: opts.Limit}
+	if page.Total != nil {
+		pagination = models.NewPagination(opts.Page, opts.Limit, *page.Total)
 	}
+	pagination.HasNext = page.NextCursor != ""
+	pagination.HasPrev = opts.Page > 1 || opts.Continue != ""
 
 	return &models.VMListResponse{
-		VMs:        vmResponses,
-		Pagination: pagination,
+		VMs:             vmResponses,
+		Pagination:      pagination,
+		NextCursor:      page.NextCursor,
+		ResourceVersion: resourceVersion,
 	}, nil
 }
 
 // StartVM starts a VM
-func (s *vmService) StartVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) error {
-	return s.changeVMState(ctx, id, models.VMStatusStarting, req, "start")
+func (s *vmService) StartVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) (*models.Operation, error) {
+	return s.changeVMState(ctx, id, models.VMStatusStarting, req, models.OperationTypeStart)
 }
 
 // StopVM stops a VM
-func (s *vmService) StopVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) error {
+func (s *vmService) StopVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) (*models.Operation, error) {
 	if req.Force {
-		return s.changeVMState(ctx, id, models.VMStatusStopped, req, "force-stop")
+		return s.changeVMState(ctx, id, models.VMStatusStopping, req, models.OperationTypeForceStop)
 	}
-	return s.changeVMState(ctx, id, models.VMStatusStopping, req, "stop")
+	return s.changeVMState(ctx, id, models.VMStatusStopping, req, models.OperationTypeStop)
 }
 
 // RestartVM restarts a VM
-func (s *vmService) RestartVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) error {
-	log := s.logger.WithOperation("restart-vm")
-
-	vm, err := s.vmRepo.GetByID(ctx, id)
-	if err != nil {
-		return err
-	}
-
-	if !vm.CanPerformOperation("restart") {
-		return errors.VMStateError(id.String(), string(vm.Status), "running")
-	}
-
-	// First stop, then start
-	if err := s.changeVMState(ctx, id, models.VMStatusStopping, req, "restart-stop"); err != nil {
-		return err
-	}
-
-	log.Infof("VM restart initiated: %s (ID: %s)", vm.Name, vm.ID)
-
-	// Start async restart process
-	go s.simulateRestart(id)
-
-	return nil
+func (s *vmService) RestartVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) (*models.Operation, error) {
+	// The VM passes through stopping and starting before it is running again
+	return s.changeVMState(ctx, id, models.VMStatusStopping, req, models.OperationTypeRestart)
 }
 
 // SuspendVM suspends a VM
-func (s *vmService) SuspendVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) error {
-	return s.changeVMState(ctx, id, models.VMStatusSuspended, req, "suspend")
+func (s *vmService) SuspendVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) (*models.Operation, error) {
+	return s.changeVMState(ctx, id, models.VMStatusSuspended, req, models.OperationTypeSuspend)
 }
 
 // ResumeVM resumes a suspended VM
-func (s *vmService) ResumeVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) error {
-	return s.changeVMState(ctx, id, models.VMStatusRunning, req, "resume")
+func (s *vmService) ResumeVM(ctx context.Context, id uuid.UUID, req *models.VMStateChangeRequest) (*models.Operation, error) {
+	return s.changeVMState(ctx, id, models.VMStatusRunning, req, models.OperationTypeResume)
 }
 
 // GetResourceSummary gets resource usage summary
 func (s *vmService) GetResourceSummary(ctx context.Context) (*models.ResourceSummary, error) {
+	log := s.logger.WithOperation("get-resource-summary")
+
 	summary, err := s.vmRepo.GetResourceSummary(ctx)
 	if err != nil {
-		s.logger.WithOperation("get-resource-summary").Errorf("Failed to get resource summary: %v", err)
+		log.Errorf("Failed to get resource summary: %v", err)
+		return nil, err
+	}
+
+	// Node liveness comes from the node inventory
+	total, err := s.nodeRepo.Count(ctx)
+	if err != nil {
+		log.Errorf("Failed to count nodes: %v", err)
+		return nil, err
+	}
+	active, err := s.nodeRepo.CountByState(ctx, models.NodeStateReady)
+	if err != nil {
+		log.Errorf("Failed to count ready nodes: %v", err)
 		return nil, err
 	}
+	summary.Nodes.Total = int(total)
+	summary.Nodes.Active = int(active)
 
 	summary.GeneratedAt = time.Now()
 	return summary, nil
@@ -300,18 +697,234 @@ func (s *vmService) UpdateVMStats(ctx context.Context, id uuid.UUID) error {
 		return nil // Only update stats for running VMs
 	}
 
-	// Simulate realistic statistics
-	stats := models.VMStats{
-		CPUUsagePercent:  10 + rand.Float64()*80,                           // 10-90%
-		RAMUsagePercent:  20 + rand.Float64()*70,                           // 20-90%
-		DiskUsagePercent: 10 + rand.Float64()*50,                           // 10-60%
-		NetworkRxBytes:   vm.Stats.NetworkRxBytes + rand.Int63n(1024*1024), // Add random traffic
-		NetworkTxBytes:   vm.Stats.NetworkTxBytes + rand.Int63n(512*1024),
-		UptimeSeconds:    vm.GetUptime(),
-		LastStatsUpdate:  time.Now(),
+	stats, err := s.driver.Stats(ctx, vm)
+	if err != nil {
+		return errors.HypervisorError("stats", err)
+	}
+
+	if err := s.vmRepo.UpdateStats(ctx, id, *stats); err != nil {
+		return err
 	}
 
-	return s.vmRepo.UpdateStats(ctx, id, stats)
+	vm.Stats = *stats
+	vm.ResourceVersion++
+	s.hub.Publish(watch.EventStats, vm)
+	return nil
+}
+
+// WatchVMs subscribes to changes of all VMs of the caller's projects matching the list filters
+func (s *vmService) WatchVMs(ctx context.Context, opts models.VMListOptions, resourceVersion uint64) (*watch.Subscription, error) {
+	if _, err := labels.Parse(opts.LabelSelector); err != nil {
+		return nil, errors.ErrValidationFailed.WithDetails(err.Error())
+	}
+
+	return s.hub.Subscribe(resourceVersion, func(event *watch.Event) bool {
+		return tenancy.Allows(ctx, event.VM.ProjectID) && opts.Matches(event.VM.VM)
+	})
+}
+
+// WatchVM subscribes to changes of a single VM
+func (s *vmService) WatchVM(ctx context.Context, id uuid.UUID, resourceVersion uint64) (*watch.Subscription, error) {
+	// A resuming client may be waiting for the deletion of the VM, so only fresh watches require it to exist
+	if resourceVersion == 0 {
+		if _, err := s.vmRepo.GetByID(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.hub.Subscribe(resourceVersion, func(event *watch.Event) bool {
+		return event.VM.ID == id && tenancy.Allows(ctx, event.VM.ProjectID)
+	})
+}
+
+// CreateSnapshot snapshots a VM and returns the operation capturing it
+func (s *vmService) CreateSnapshot(ctx context.Context, vmID uuid.UUID, req *models.SnapshotCreateRequest) (*models.Snapshot, *models.Operation, error) {
+	log := s.logger.WithOperation(string(models.OperationTypeSnapshotCreate))
+
+	// Memory snapshots need a guest to capture
+	snapshotType := req.Type
+	if snapshotType == "" {
+		snapshotType = models.SnapshotTypeDisk
+	}
+	operation, required := "snapshot", "stopped, running or suspended"
+	if snapshotType == models.SnapshotTypeMemory {
+		operation, required = "snapshot-memory", "running or suspended"
+	}
+
+	// Holding the VM locked keeps other replicas from starting a snapshot operation or
+	// clone of it until this snapshot is recorded
+	var snapshot *models.Snapshot
+	var op *models.Operation
+	err := s.vmRepo.WithLocked(ctx, vmID, func(ctx context.Context, vm *models.VM) error {
+		if !vm.CanPerformOperation(operation) {
+			return errors.VMStateError(vmID.String(), string(vm.Status), required)
+		}
+		if err := s.checkVMIdle(ctx, vm.ID); err != nil {
+			return err
+		}
+
+		snapshot = &models.Snapshot{
+			VMID:        vm.ID,
+			Name:        req.Name,
+			Description: req.Description,
+			Type:        snapshotType,
+			Status:      models.SnapshotStatusCreating,
+			VMStatus:    vm.Status,
+			CreatedBy:   req.CreatedBy,
+		}
+		if err := s.snapshotRepo.Create(ctx, snapshot); err != nil {
+			log.Warnf("Failed to create snapshot: %v", err)
+			return err
+		}
+
+		var err error
+		op, err = s.beginSnapshotOperation(ctx, models.OperationTypeSnapshotCreate, snapshot, req.CreatedBy)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	log.Infof("Snapshot %s of VM %s initiated (ID: %s, operation: %s)", snapshot.Name, vmID, snapshot.ID, op.ID)
+
+	tracked := *op
+	go s.runLifecycle(&tracked, auditInfo{actor: req.CreatedBy, requestID: req.RequestID})
+
+	return snapshot, op, nil
+}
+
+// GetSnapshot retrieves a snapshot of a VM
+func (s *vmService) GetSnapshot(ctx context.Context, vmID, id uuid.UUID) (*models.Snapshot, error) {
+	if _, err := s.vmRepo.GetByID(ctx, vmID); err != nil {
+		return nil, err
+	}
+	return s.snapshotRepo.GetByID(ctx, vmID, id)
+}
+
+// ListSnapshots lists the snapshots of a VM
+func (s *vmService) ListSnapshots(ctx context.Context, vmID uuid.UUID) ([]*models.Snapshot, error) {
+	if _, err := s.vmRepo.GetByID(ctx, vmID); err != nil {
+		return nil, err
+	}
+	return s.snapshotRepo.ListByVM(ctx, vmID)
+}
+
+// DeleteSnapshot deletes a snapshot of a VM and returns the operation removing it
+func (s *vmService) DeleteSnapshot(ctx context.Context, vmID, id uuid.UUID, req *models.VMStateChangeRequest) (*models.Operation, error) {
+	log := s.logger.WithOperation(string(models.OperationTypeSnapshotDelete))
+
+	var snapshot *models.Snapshot
+	var op *models.Operation
+	err := s.vmRepo.WithLocked(ctx, vmID, func(ctx context.Context, vm *models.VM) error {
+		if !vm.CanPerformOperation("snapshot-delete") {
+			return errors.VMStateError(vmID.String(), string(vm.Status), "stopped, running or suspended")
+		}
+
+		var err error
+		if snapshot, err = s.snapshotRepo.GetByID(ctx, vmID, id); err != nil {
+			return err
+		}
+		if err := s.checkVMIdle(ctx, vm.ID); err != nil {
+			return err
+		}
+
+		snapshot.Status = models.SnapshotStatusDeleting
+		snapshot.Error = ""
+		if err := s.snapshotRepo.Update(ctx, snapshot); err != nil {
+			log.Errorf("Failed to mark snapshot %s as deleting: %v", snapshot.ID, err)
+			return err
+		}
+
+		op, err = s.beginSnapshotOperation(ctx, models.OperationTypeSnapshotDelete, snapshot, req.UpdatedBy)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Deletion of snapshot %s of VM %s initiated (operation: %s)", snapshot.Name, vmID, op.ID)
+
+	tracked := *op
+	go s.runLifecycle(&tracked, auditInfo{actor: req.UpdatedBy, reason: req.Reason, requestID: req.RequestID})
+
+	return op, nil
+}
+
+// RevertSnapshot restores a stopped VM to a snapshot and returns the operation tracking it.
+// Disk snapshots leave the VM stopped; memory snapshots bring it back running.
+func (s *vmService) RevertSnapshot(ctx context.Context, vmID, id uuid.UUID, req *models.VMStateChangeRequest) (*models.Operation, error) {
+	log := s.logger.WithOperation(string(models.OperationTypeSnapshotRevert))
+
+	vm, err := s.vmRepo.GetByID(ctx, vmID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Memory snapshots boot the VM, which locks the project quota; it is locked before the VM
+	// like every other status change does
+	var snapshot *models.Snapshot
+	var op *models.Operation
+	newStatus := models.VMStatusPending
+	err = s.quotaRepo.WithLockedUsage(ctx, vm.ProjectID, func(ctx context.Context, _ *models.Quota, _ *models.QuotaUsage) error {
+		return s.vmRepo.WithLocked(ctx, vmID, func(ctx context.Context, locked *models.VM) error {
+			vm = locked
+			if err := checkResourceVersion(vm, req.ResourceVersion); err != nil {
+				return err
+			}
+			if !vm.CanPerformOperation("snapshot-revert") {
+				return errors.VMStateError(vmID.String(), string(vm.Status), string(models.VMStatusStopped))
+			}
+
+			var err error
+			if snapshot, err = s.snapshotRepo.GetByID(ctx, vmID, id); err != nil {
+				return err
+			}
+			if snapshot.Status != models.SnapshotStatusReady {
+				return errors.ErrSnapshotNotReady.
+					WithContext("snapshot_id", snapshot.ID.String()).
+					WithDetails(fmt.Sprintf("Snapshot %s is %s and cannot be reverted to", snapshot.Name, snapshot.Status))
+			}
+			if err := s.checkVMIdle(ctx, vm.ID); err != nil {
+				return err
+			}
+
+			// Memory snapshots pass through starting and count against the running quota
+			if snapshot.Type == models.SnapshotTypeMemory {
+				newStatus = models.VMStatusStarting
+			}
+			if !vm.IsValidStatusTransition(newStatus) {
+				return errors.VMStateError(vmID.String(), string(vm.Status), string(newStatus))
+			}
+
+			if op, err = s.beginSnapshotOperation(ctx, models.OperationTypeSnapshotRevert, snapshot, req.UpdatedBy); err != nil {
+				return err
+			}
+			if err := s.updateStatus(ctx, vm, newStatus); err != nil {
+				log.Errorf("Failed to update VM status: %v", err)
+				return err
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	audit := auditInfo{actor: req.UpdatedBy, reason: req.Reason, requestID: req.RequestID}
+	s.recordEvent(ctx, models.NewVMEvent(models.VMEventStatusChanged, vm, audit.actor, audit.reason, audit.requestID).
+		WithStatusChange(vm.Status, newStatus).
+		WithOperation(op).
+		WithMessage("reverting to snapshot "+snapshot.Name))
+	vm.Status = newStatus
+	vm.ResourceVersion++
+	s.hub.Publish(watch.EventStatusChanged, vm)
+
+	log.Infof("Revert of VM %s to snapshot %s initiated (operation: %s)", vm.ID, snapshot.Name, op.ID)
+
+	tracked := *op
+	go s.runLifecycle(&tracked, audit)
+
+	return op, nil
 }
 
 // Helper methods
@@ -333,101 +946,787 @@ func (s *vmService) validateResourceLimits(cpu, ram, disk int) error {
 	return nil
 }
 
-// assignNodeID assigns a node ID (simplified implementation)
-func (s *vmService) assignNodeID() string {
-	nodeCount := 10 // In real implementation, this would query available nodes
-	nodeID := rand.Intn(nodeCount) + 1
-	return fmt.Sprintf("node-%02d", nodeID)
+// expandFlavor sets the resources of a VM to those of its flavor
+func (s *vmService) expandFlavor(ctx context.Context, vm *models.VM) error {
+	flavor, err := s.flavorRepo.GetByName(ctx, vm.Flavor)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return errors.ErrValidationFailed.WithDetails(fmt.Sprintf("Unknown flavor %s", vm.Flavor))
+		}
+		return err
+	}
+	flavor.ApplyTo(&vm.Spec)
+	return nil
+}
+
+// checkFlavorAllowed checks that a VM is sized by one of the flavors its project is restricted to, if any
+func (s *vmService) checkFlavorAllowed(ctx context.Context, vm *models.VM) error {
+	allowed, err := s.flavorRepo.AllowedNames(ctx, vm.ProjectID)
+	if err != nil {
+		return err
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	for _, name := range allowed {
+		if vm.Flavor == name {
+			return nil
+		}
+	}
+	if vm.Flavor == "" {
+		return errors.ErrFlavorNotAllowed.WithDetails("Project only allows flavors: " + strings.Join(allowed, ", "))
+	}
+	return errors.ErrFlavorNotAllowed.
+		WithDetails(fmt.Sprintf("Flavor %s is not allowed; project only allows: %s", vm.Flavor, strings.Join(allowed, ", ")))
+}
+
+// lookupImage finds the catalog image of a VM, which must be available to its project
+func (s *vmService) lookupImage(ctx context.Context, vm *models.VM) (*models.Image, error) {
+	name, version := models.ParseImageReference(vm.Spec.ImageName)
+	image, err := s.imageRepo.GetByReference(ctx, name, version)
+	if err != nil && !errors.Is(err, errors.ErrNotFound) {
+		return nil, err
+	}
+	if err != nil || !image.AvailableTo(vm.ProjectID) {
+		return nil, errors.ErrUnknownImage.
+			WithContext("image", vm.Spec.ImageName).
+			WithDetails(fmt.Sprintf("Image %s is not in the image catalog", vm.Spec.ImageName))
+	}
+	return image, nil
+}
+
+// holdImage locks the catalog image of a VM being admitted until the admission commits.
+// Clones may come from VMs whose image predates the catalog, so only new VMs need one.
+func (s *vmService) holdImage(ctx context.Context, vm *models.VM) error {
+	name, version := models.ParseImageReference(vm.Spec.ImageName)
+	_, err := s.imageRepo.LockByReference(ctx, name, version)
+	switch {
+	case err == nil:
+		return nil
+	case !errors.Is(err, errors.ErrNotFound):
+		return err
+	case vm.SourceVMID != nil:
+		return nil
+	}
+	return errors.ErrUnknownImage.
+		WithContext("image", vm.Spec.ImageName).
+		WithDetails(fmt.Sprintf("Image %s is not in the image catalog", vm.Spec.ImageName))
+}
+
+// checkCatalogRequirements checks the resources of an existing VM against the requirements
+// of its image. VMs whose image is not in the catalog, such as those created before it, are
+// not checked.
+func (s *vmService) checkCatalogRequirements(ctx context.Context, vm *models.VM) error {
+	image, err := s.lookupImage(ctx, vm)
+	if err != nil {
+		if errors.Is(err, errors.ErrUnknownImage) {
+			return nil
+		}
+		return err
+	}
+	return checkImageRequirements(image, vm)
+}
+
+// checkImageRequirements checks that a VM has the disk and RAM its image requires
+func checkImageRequirements(image *models.Image, vm *models.VM) error {
+	if vm.Spec.DiskGb < image.MinDiskGb {
+		return errors.ValidationError("disk_gb",
+			fmt.Sprintf("Image %s requires at least %d GB of disk, got %d GB", image.Reference(), image.MinDiskGb, vm.Spec.DiskGb))
+	}
+	if vm.Spec.RAMMb < image.MinRAMMb {
+		return errors.ValidationError("ram_mb",
+			fmt.Sprintf("Image %s requires at least %d MB of RAM, got %d MB", image.Reference(), image.MinRAMMb, vm.Spec.RAMMb))
+	}
+	return nil
+}
+
+// imageDeprecatedError reports a deprecated image and its replacement, if any
+func imageDeprecatedError(image *models.Image) error {
+	details := fmt.Sprintf("Image %s is deprecated", image.Reference())
+	if image.Replacement != "" {
+		details += fmt.Sprintf("; use %s instead", image.Replacement)
+	}
+	return errors.ErrImageDeprecated.WithContext("image", image.Reference()).WithDetails(details)
+}
+
+// admitVM checks the name and project quota of a new VM, places it and stores it together
+// with the operation of the given type that brings it up, so a VM is never stored without one.
+// A non-empty node pins the VM to that node instead of letting the scheduler choose.
+func (s *vmService) admitVM(ctx context.Context, vm *models.VM, node string, opType models.OperationType, actor string) (*models.Operation, error) {
+	log := s.logger.WithOperation("admit-vm")
+
+	// Check if VM name already exists in the project
+	exists, err := s.vmRepo.ExistsByName(ctx, vm.ProjectID, vm.Name)
+	if err != nil {
+		log.Errorf("Failed to check VM name existence: %v", err)
+		return nil, err
+	}
+	if exists {
+		return nil, errors.AlreadyExistsError("VM", vm.Name)
+	}
+
+	var op *models.Operation
+	err = s.quotaRepo.WithLockedUsage(ctx, vm.ProjectID, func(ctx context.Context, quota *models.Quota, usage *models.QuotaUsage) error {
+		// Check the project quota
+		request := models.QuotaRequest{VMs: 1, CPUCores: vm.Spec.CPUCores, RAMMb: vm.Spec.RAMMb, DiskGb: vm.Spec.DiskGb}
+		if err := checkQuota(effectiveLimits(quota, s.cfg), usage, vm.ProjectID, request); err != nil {
+			log.Warnf("Quota check failed: %v", err)
+			return err
+		}
+
+		// Place VM on a node with enough free capacity
+		resources := scheduler.Request{CPUCores: vm.Spec.CPUCores, RAMMb: vm.Spec.RAMMb, DiskGb: vm.Spec.DiskGb}
+		if node != "" {
+			if err := s.checkNodeCapacity(ctx, node, resources); err != nil {
+				log.Warnf("VM placement on node %s failed: %v", node, err)
+				return err
+			}
+			vm.NodeID = node
+		} else {
+			placed, err := s.placeVM(ctx, resources)
+			if err != nil {
+				log.Warnf("VM placement failed: %v", err)
+				return err
+			}
+			vm.NodeID = placed.Name
+		}
+
+		// Keep image garbage collection from deleting the image before the VM refers to it
+		if err := s.holdImage(ctx, vm); err != nil {
+			return err
+		}
+
+		// Create VM in database
+		if err := s.vmRepo.Create(ctx, vm); err != nil {
+			log.Errorf("Failed to create VM: %v", err)
+			return err
+		}
+
+		// Allocating with the project locked keeps concurrent VMs from getting the same address
+		if err := s.allocateInterfaces(ctx, vm); err != nil {
+			return err
+		}
+
+		op = models.NewOperation(opType, vm.ID, actor)
+		if err := s.opRepo.Create(ctx, op); err != nil {
+			log.Errorf("Failed to record %s operation for VM %s: %v", opType, vm.ID, err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// allocateInterfaces replaces the requested interfaces of a new VM with interfaces
+// allocated from the pools of their networks
+func (s *vmService) allocateInterfaces(ctx context.Context, vm *models.VM) error {
+	for i, requested := range vm.Interfaces {
+		network, err := s.networkRepo.GetByName(ctx, vm.ProjectID, requested.Network)
+		if err != nil {
+			if errors.Is(err, errors.ErrNotFound) {
+				return errors.ValidationError("networks", fmt.Sprintf("Unknown network %s", requested.Network))
+			}
+			return err
+		}
+
+		iface, err := s.networkRepo.AllocateInterface(ctx, network, vm.ID, requested.DeviceIndex)
+		if err != nil {
+			s.logger.WithOperation("admit-vm").Warnf("Failed to allocate address on network %s: %v", network.Name, err)
+			return err
+		}
+		vm.Interfaces[i] = iface
+	}
+	return nil
+}
+
+// placeVM selects a ready node with enough free capacity using the configured strategy
+func (s *vmService) placeVM(ctx context.Context, req scheduler.Request) (*models.Node, error) {
+	candidates, err := s.placementCandidates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.scheduler.Select(req, candidates)
+}
+
+// checkNodeCapacity verifies that a node can take additional resources
+func (s *vmService) checkNodeCapacity(ctx context.Context, nodeID string, req scheduler.Request) error {
+	candidates, err := s.placementCandidates(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range candidates {
+		if c.Node.Name == nodeID {
+			_, err := s.scheduler.Select(req, []scheduler.Candidate{c})
+			return err
+		}
+	}
+
+	// VMs on nodes unknown to the inventory are not capacity checked
+	return nil
+}
+
+// placementCandidates loads all nodes together with their current allocations. The nodes stay
+// locked until the transaction carried by ctx ends, so concurrent requests cannot overcommit a node.
+func (s *vmService) placementCandidates(ctx context.Context) ([]scheduler.Candidate, error) {
+	nodes, err := s.nodeRepo.LockAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	allocations, err := s.vmRepo.GetNodeAllocations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]scheduler.Candidate, len(nodes))
+	for i, node := range nodes {
+		candidates[i] = scheduler.Candidate{Node: node}
+		if alloc, ok := allocations[node.Name]; ok {
+			candidates[i].Allocation = *alloc
+		}
+	}
+	return candidates, nil
 }
 
-// changeVMState changes VM state with validation
-func (s *vmService) changeVMState(ctx context.Context, id uuid.UUID, newStatus models.VMStatus, req *models.VMStateChangeRequest, operation string) error {
+// changeVMState changes VM state with validation and returns the operation tracking it
+func (s *vmService) changeVMState(ctx context.Context, id uuid.UUID, newStatus models.VMStatus, req *models.VMStateChangeRequest, opType models.OperationType) (*models.Operation, error) {
+	operation := string(opType)
 	log := s.logger.WithOperation(operation)
 
 	vm, err := s.vmRepo.GetByID(ctx, id)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if err := checkResourceVersion(vm, req.ResourceVersion); err != nil {
+		return nil, err
 	}
 
 	// Check if operation is allowed
 	if !vm.CanPerformOperation(operation) {
-		return errors.VMStateError(id.String(), string(vm.Status), string(newStatus))
+		return nil, errors.VMStateError(id.String(), string(vm.Status), string(newStatus))
 	}
 
 	// Validate state transition
 	if !vm.IsValidStatusTransition(newStatus) {
-		return errors.VMStateError(id.String(), string(vm.Status), string(newStatus))
+		return nil, errors.VMStateError(id.String(), string(vm.Status), string(newStatus))
+	}
+	if err := s.checkVMIdle(ctx, vm.ID); err != nil {
+		return nil, err
 	}
 
-	// Update status
-	if err := s.vmRepo.UpdateStatus(ctx, id, newStatus); err != nil {
-		log.Errorf("Failed to update VM status: %v", err)
-		return err
+	// The status only changes if nobody changed the VM since it was checked above, so of
+	// concurrent requests exactly one goes on to record an operation and apply its change
+	if err := s.updateStatus(ctx, vm, newStatus); err != nil {
+		log.Warnf("Failed to update VM status: %v", err)
+		return nil, err
+	}
+
+	op, err := s.beginOperation(ctx, opType, vm.ID, req.UpdatedBy)
+	if err != nil {
+		s.restoreStatus(ctx, vm, newStatus)
+		return nil, err
+	}
+
+	// Operations without a transitional state are applied on the hypervisor right away
+	async := newStatus == models.VMStatusStarting || newStatus == models.VMStatusStopping
+	if !async {
+		if err := s.applyImmediate(ctx, vm, operation); err != nil {
+			log.Errorf("Hypervisor %s failed: %v", operation, err)
+			appErr := errors.HypervisorError(operation, err)
+			s.failOperation(ctx, op, appErr)
+			s.restoreStatus(ctx, vm, newStatus)
+			return nil, appErr
+		}
+	}
+
+	audit := auditInfo{actor: req.UpdatedBy, reason: req.Reason, requestID: req.RequestID}
+	s.recordEvent(ctx, models.NewVMEvent(models.VMEventStatusChanged, vm, audit.actor, audit.reason, audit.requestID).
+		WithStatusChange(vm.Status, newStatus).
+		WithOperation(op))
+	vm.Status = newStatus
+	vm.ResourceVersion++
+	s.hub.Publish(watch.EventStatusChanged, vm)
+
+	log.Infof("VM %s operation initiated: %s (ID: %s, operation: %s)", operation, vm.Name, vm.ID, op.ID)
+
+	// Complete transitional states asynchronously on the hypervisor
+	if async {
+		tracked := *op
+		go s.runLifecycle(&tracked, audit)
+	} else {
+		s.completeOperation(ctx, op)
 	}
 
-	log.Infof("VM %s operation initiated: %s (ID: %s)", operation, vm.Name, vm.ID)
+	return op, nil
+}
 
-	// Start async state transition simulation
-	if newStatus == models.VMStatusStarting {
-		go s.simulateStartup(id)
-	} else if newStatus == models.VMStatusStopping {
-		go s.simulateShutdown(id)
+// updateStatus persists a status change of a VM as it was read, checking the
+// running-VM quota of the project when a powered off VM is started
+func (s *vmService) updateStatus(ctx context.Context, vm *models.VM, newStatus models.VMStatus) error {
+	if vm.Status.IsPoweredOn() || !newStatus.IsPoweredOn() {
+		return s.vmRepo.UpdateStatus(ctx, vm.ID, vm.Status, vm.ResourceVersion, newStatus)
 	}
 
+	return s.quotaRepo.WithLockedUsage(ctx, vm.ProjectID, func(ctx context.Context, quota *models.Quota, usage *models.QuotaUsage) error {
+		if err := checkQuota(effectiveLimits(quota, s.cfg), usage, vm.ProjectID, models.QuotaRequest{RunningVMs: 1}); err != nil {
+			return err
+		}
+		return s.vmRepo.UpdateStatus(ctx, vm.ID, vm.Status, vm.ResourceVersion, newStatus)
+	})
+}
+
+// restoreStatus gives a VM the status it was read with back after the status change
+// claimed by updateStatus could not be carried out
+func (s *vmService) restoreStatus(ctx context.Context, vm *models.VM, claimed models.VMStatus) {
+	if err := s.vmRepo.UpdateStatus(ctx, vm.ID, claimed, vm.ResourceVersion+1, vm.Status); err != nil {
+		s.logger.Errorf("Failed to restore status %s of VM %s: %v", vm.Status, vm.ID, err)
+	}
+}
+
+// transitionStatus moves a VM from the status a worker left it in to the next one. Writes
+// that keep the status, such as stats updates, only advance the version, so the swap is
+// retried with the current version as long as the status is still the expected one.
+func (s *vmService) transitionStatus(ctx context.Context, vm *models.VM, status models.VMStatus) error {
+	for attempt := 1; ; attempt++ {
+		err := s.vmRepo.UpdateStatus(ctx, vm.ID, vm.Status, vm.ResourceVersion, status)
+		if err == nil || !errors.Is(err, errors.ErrVersionConflict) || attempt == statusSwapAttempts {
+			return err
+		}
+
+		current, err := s.vmRepo.GetByID(ctx, vm.ID)
+		if err != nil {
+			return err
+		}
+		vm.ResourceVersion = current.ResourceVersion
+	}
+}
+
+// checkResourceVersion verifies the version an If-Match request requires, if any
+func checkResourceVersion(vm *models.VM, required int64) error {
+	if required != 0 && vm.ResourceVersion != required {
+		return errors.PreconditionFailedError("VM", vm.ID.String(), required, vm.ResourceVersion)
+	}
 	return nil
 }
 
-// Simulation methods (in real implementation, these would interact with hypervisor)
+// checkVMIdle rejects changes to a VM while one of its snapshots is being created or deleted,
+// or while a clone is still being copied from its disk
+func (s *vmService) checkVMIdle(ctx context.Context, vmID uuid.UUID) error {
+	snapshots, err := s.snapshotRepo.ListByVM(ctx, vmID)
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.Status.IsBusy() {
+			return errors.ErrResourceLocked.
+				WithContext("snapshot_id", snapshot.ID.String()).
+				WithDetails(fmt.Sprintf("Snapshot %s of VM %s is %s", snapshot.Name, vmID, snapshot.Status))
+		}
+	}
 
-func (s *vmService) simulateProvisioning(vmID uuid.UUID) {
-	time.Sleep(2 * time.Second) // Simulate provisioning time
+	clones, err := s.vmRepo.ListClones(ctx, vmID, models.VMStatusPending)
+	if err != nil {
+		return err
+	}
+	if len(clones) > 0 {
+		return errors.ErrResourceLocked.
+			WithContext("clone_vm_id", clones[0].ID.String()).
+			WithDetails(fmt.Sprintf("VM %s is being cloned to %s", vmID, clones[0].Name))
+	}
+	return nil
+}
 
-	ctx := context.Background()
-	if err := s.vmRepo.UpdateStatus(ctx, vmID, models.VMStatusStopped); err != nil {
-		s.logger.Errorf("Failed to update VM status after provisioning: %v", err)
+// applyImmediate performs the driver call of an operation that completes synchronously
+func (s *vmService) applyImmediate(ctx context.Context, vm *models.VM, operation string) error {
+	switch operation {
+	case "suspend":
+		return s.driver.Suspend(ctx, vm)
+	case "resume":
+		return s.driver.Resume(ctx, vm)
+	default:
+		return fmt.Errorf("operation %s is not synchronous", operation)
 	}
 }
 
-func (s *vmService) simulateStartup(vmID uuid.UUID) {
-	time.Sleep(3 * time.Second) // Simulate startup time
+// Operation tracking
 
-	ctx := context.Background()
-	if err := s.vmRepo.UpdateStatus(ctx, vmID, models.VMStatusRunning); err != nil {
-		s.logger.Errorf("Failed to update VM status after startup: %v", err)
+// beginOperation records a new running operation for a VM
+func (s *vmService) beginOperation(ctx context.Context, opType models.OperationType, vmID uuid.UUID, createdBy string) (*models.Operation, error) {
+	return s.recordOperation(ctx, models.NewOperation(opType, vmID, createdBy))
+}
+
+// beginSnapshotOperation records a new running operation for a snapshot of a VM
+func (s *vmService) beginSnapshotOperation(ctx context.Context, opType models.OperationType, snapshot *models.Snapshot, createdBy string) (*models.Operation, error) {
+	return s.recordOperation(ctx, models.NewSnapshotOperation(opType, snapshot, createdBy))
+}
+
+// recordOperation starts an operation and persists it
+func (s *vmService) recordOperation(ctx context.Context, op *models.Operation) (*models.Operation, error) {
+	op.Start()
+
+	if err := s.opRepo.Create(ctx, op); err != nil {
+		s.logger.WithOperation(string(op.Type)).Errorf("Failed to record operation for VM %s: %v", op.VMID, err)
+		return nil, err
+	}
+	return op, nil
+}
+
+// setOperationProgress persists the progress of a running operation
+func (s *vmService) setOperationProgress(ctx context.Context, op *models.Operation, progress int) {
+	op.SetProgress(progress)
+	s.saveOperation(ctx, op)
+}
+
+// completeOperation marks an operation as succeeded
+func (s *vmService) completeOperation(ctx context.Context, op *models.Operation) {
+	if op.IsDone() {
 		return
 	}
+	op.Succeed()
+	s.saveOperation(ctx, op)
+	s.metrics.ObserveOperation(op)
+}
 
-	// Start stats updates for running VM
-	go s.startStatsUpdater(vmID)
+// failOperation marks an operation as failed with the error that caused it
+func (s *vmService) failOperation(ctx context.Context, op *models.Operation, err error) {
+	if op.IsDone() {
+		return
+	}
+	op.Fail(err)
+	s.saveOperation(ctx, op)
+	s.metrics.ObserveOperation(op)
 }
 
-func (s *vmService) simulateShutdown(vmID uuid.UUID) {
-	time.Sleep(2 * time.Second) // Simulate shutdown time
+// saveOperation persists an operation; failures are logged because the VM change already happened
+func (s *vmService) saveOperation(ctx context.Context, op *models.Operation) {
+	if err := s.opRepo.Update(ctx, op); err != nil {
+		s.logger.WithOperation(string(op.Type)).Errorf("Failed to save operation %s: %v", op.ID, err)
+	}
+}
+
+// Lifecycle methods (executed asynchronously against the hypervisor driver)
 
+// provisionVM allocates hypervisor resources for a newly created or cloned VM
+func (s *vmService) provisionVM(op *models.Operation, audit auditInfo) {
 	ctx := context.Background()
-	if err := s.vmRepo.UpdateStatus(ctx, vmID, models.VMStatusStopped); err != nil {
-		s.logger.Errorf("Failed to update VM status after shutdown: %v", err)
+
+	op.Start()
+	s.saveOperation(ctx, op)
+
+	vm, err := s.vmRepo.GetByID(ctx, op.VMID)
+	if err != nil {
+		s.failOperation(ctx, op, err)
+		s.logger.WithOperation("provision").Errorf("Failed to load VM %s for provisioning: %v", op.VMID, err)
+		return
+	}
+
+	phase := "provisioning"
+	if vm.SourceVMID != nil {
+		phase = "cloning"
+		err = s.cloneDisk(ctx, vm)
+	} else {
+		err = s.provisionDisk(ctx, vm)
+	}
+	if err != nil {
+		s.failTransition(ctx, op, vm, audit, err)
+		return
 	}
+	s.setOperationProgress(ctx, op, 90)
+
+	if err := s.finishTransition(ctx, op, vm, audit, models.VMStatusStopped, phase); err != nil {
+		return
+	}
+	s.completeOperation(ctx, op)
 }
 
-func (s *vmService) simulateRestart(vmID uuid.UUID) {
-	time.Sleep(2 * time.Second) // Shutdown time
+// provisionDisk creates the disk of a new VM through the driver from the file of its catalog image
+func (s *vmService) provisionDisk(ctx context.Context, vm *models.VM) error {
+	image, err := s.lookupImage(ctx, vm)
+	if err != nil {
+		return err
+	}
+
+	base := &hypervisor.BaseImage{Reference: image.Reference()}
+	if image.IsUploaded() {
+		base.Path = s.imageStore.Path(image.ID, image.DiskFormat)
+		base.Format = image.DiskFormat
+	}
+	return s.driver.Provision(ctx, vm, base)
+}
 
+// cloneDisk copies the disk of the source of a cloned VM through the driver
+func (s *vmService) cloneDisk(ctx context.Context, vm *models.VM) error {
+	source, err := s.vmRepo.GetByID(ctx, *vm.SourceVMID)
+	if err != nil {
+		return err
+	}
+
+	var snapshot *models.Snapshot
+	if vm.SourceSnapshotID != nil {
+		if snapshot, err = s.snapshotRepo.GetByID(ctx, source.ID, *vm.SourceSnapshotID); err != nil {
+			return err
+		}
+	}
+	return s.driver.Clone(ctx, source, vm, snapshot, vm.CloneMode)
+}
+
+// runLifecycle performs the hypervisor calls of a state change that was accepted by changeVMState
+func (s *vmService) runLifecycle(op *models.Operation, audit auditInfo) {
 	ctx := context.Background()
-	if err := s.vmRepo.UpdateStatus(ctx, vmID, models.VMStatusStarting); err != nil {
-		s.logger.Errorf("Failed to update VM status during restart: %v", err)
+	operation := string(op.Type)
+	log := s.logger.WithOperation(operation)
+
+	vm, err := s.vmRepo.GetByID(ctx, op.VMID)
+	if err != nil {
+		s.failOperation(ctx, op, err)
+		log.Errorf("Failed to load VM %s for %s: %v", op.VMID, operation, err)
 		return
 	}
+	s.setOperationProgress(ctx, op, 10)
 
-	time.Sleep(3 * time.Second) // Startup time
+	switch op.Type {
+	case models.OperationTypeStart:
+		if err := s.driver.Start(ctx, vm); err != nil {
+			s.failTransition(ctx, op, vm, audit, err)
+			return
+		}
+		s.setOperationProgress(ctx, op, 90)
+		if err := s.finishTransition(ctx, op, vm, audit, models.VMStatusRunning, "startup"); err != nil {
+			return
+		}
+		go s.startStatsUpdater(vm.ID)
 
-	if err := s.vmRepo.UpdateStatus(ctx, vmID, models.VMStatusRunning); err != nil {
-		s.logger.Errorf("Failed to update VM status after restart: %v", err)
+	case models.OperationTypeStop, models.OperationTypeForceStop:
+		stop := s.driver.Stop
+		if op.Type == models.OperationTypeForceStop {
+			stop = s.driver.ForceStop
+		}
+		if err := stop(ctx, vm); err != nil {
+			s.failTransition(ctx, op, vm, audit, err)
+			return
+		}
+		s.setOperationProgress(ctx, op, 90)
+		if err := s.finishTransition(ctx, op, vm, audit, models.VMStatusStopped, "shutdown"); err != nil {
+			return
+		}
+
+	case models.OperationTypeRestart:
+		if err := s.driver.Stop(ctx, vm); err != nil {
+			s.failTransition(ctx, op, vm, audit, err)
+			return
+		}
+		if err := s.finishTransition(ctx, op, vm, audit, models.VMStatusStarting, "restart shutdown"); err != nil {
+			return
+		}
+		s.setOperationProgress(ctx, op, 50)
+		if err := s.driver.Start(ctx, vm); err != nil {
+			s.failTransition(ctx, op, vm, audit, err)
+			return
+		}
+		s.setOperationProgress(ctx, op, 90)
+		if err := s.finishTransition(ctx, op, vm, audit, models.VMStatusRunning, "restart"); err != nil {
+			return
+		}
+		go s.startStatsUpdater(vm.ID)
+
+	case models.OperationTypeSnapshotCreate:
+		snapshot, err := s.operationSnapshot(ctx, op)
+		if err != nil {
+			s.failOperation(ctx, op, err)
+			return
+		}
+		if err := s.driver.CreateSnapshot(ctx, vm, snapshot); err != nil {
+			s.failSnapshot(ctx, op, snapshot, err)
+			return
+		}
+		s.setOperationProgress(ctx, op, 90)
+		snapshot.Status = models.SnapshotStatusReady
+		if err := s.snapshotRepo.Update(ctx, snapshot); err != nil {
+			log.Errorf("Failed to mark snapshot %s as ready: %v", snapshot.ID, err)
+			s.failOperation(ctx, op, err)
+			return
+		}
+		s.recordEvent(ctx, models.NewVMEvent(models.VMEventSnapshotCreated, vm, audit.actor, audit.reason, audit.requestID).
+			WithOperation(op).
+			WithMessage(fmt.Sprintf("%s snapshot %s created", snapshot.Type, snapshot.Name)))
+
+	case models.OperationTypeSnapshotDelete:
+		snapshot, err := s.operationSnapshot(ctx, op)
+		if err != nil {
+			s.failOperation(ctx, op, err)
+			return
+		}
+		if err := s.driver.DeleteSnapshot(ctx, vm, snapshot); err != nil {
+			s.failSnapshot(ctx, op, snapshot, err)
+			return
+		}
+		s.setOperationProgress(ctx, op, 90)
+		if err := s.snapshotRepo.Delete(ctx, snapshot.ID); err != nil {
+			log.Errorf("Failed to delete snapshot %s: %v", snapshot.ID, err)
+			s.failOperation(ctx, op, err)
+			return
+		}
+		s.recordEvent(ctx, models.NewVMEvent(models.VMEventSnapshotDeleted, vm, audit.actor, audit.reason, audit.requestID).
+			WithOperation(op).
+			WithMessage(fmt.Sprintf("snapshot %s deleted", snapshot.Name)))
+
+	case models.OperationTypeSnapshotRevert:
+		snapshot, err := s.operationSnapshot(ctx, op)
+		if err != nil {
+			s.failTransition(ctx, op, vm, audit, err)
+			return
+		}
+		if err := s.driver.RevertSnapshot(ctx, vm, snapshot); err != nil {
+			s.failTransition(ctx, op, vm, audit, err)
+			return
+		}
+		s.setOperationProgress(ctx, op, 90)
+		status := models.VMStatusStopped
+		if snapshot.Type == models.SnapshotTypeMemory {
+			status = models.VMStatusRunning
+		}
+		if err := s.finishTransition(ctx, op, vm, audit, status, "snapshot revert"); err != nil {
+			return
+		}
+		s.recordEvent(ctx, models.NewVMEvent(models.VMEventSnapshotReverted, vm, audit.actor, audit.reason, audit.requestID).
+			WithOperation(op).
+			WithMessage(fmt.Sprintf("reverted to %s snapshot %s", snapshot.Type, snapshot.Name)))
+		if status == models.VMStatusRunning {
+			go s.startStatsUpdater(vm.ID)
+		}
+
+	default:
+		err := fmt.Errorf("unknown lifecycle operation %s", operation)
+		s.failOperation(ctx, op, err)
+		log.Errorf("Unknown lifecycle operation %s for VM %s", operation, vm.ID)
 		return
 	}
 
-	// Start stats updates
-	go s.startStatsUpdater(vmID)
+	s.completeOperation(ctx, op)
+}
+
+// finishTransition records the status reached after a successful driver call.
+// A failed status update fails the operation so the outcome is not lost.
+func (s *vmService) finishTransition(ctx context.Context, op *models.Operation, vm *models.VM, audit auditInfo, status models.VMStatus, phase string) error {
+	if err := s.transitionStatus(ctx, vm, status); err != nil {
+		s.logger.Errorf("Failed to update VM status after %s: %v", phase, err)
+		s.failOperation(ctx, op, err)
+		return err
+	}
+
+	s.recordEvent(ctx, models.NewVMEvent(models.VMEventStatusChanged, vm, audit.actor, audit.reason, audit.requestID).
+		WithStatusChange(vm.Status, status).
+		WithOperation(op).
+		WithMessage(phase+" completed"))
+	vm.Status = status
+	vm.ResourceVersion++
+	s.hub.Publish(watch.EventStatusChanged, vm)
+	return nil
+}
+
+// failTransition moves a VM into the error state after a failed driver call
+func (s *vmService) failTransition(ctx context.Context, op *models.Operation, vm *models.VM, audit auditInfo, err error) {
+	operation := string(op.Type)
+	s.logger.WithOperation(operation).Errorf("Hypervisor %s failed for VM %s: %v", operation, op.VMID, err)
+	s.failOperation(ctx, op, errors.HypervisorError(operation, err))
+
+	if updateErr := s.transitionStatus(ctx, vm, models.VMStatusError); updateErr != nil {
+		s.logger.Errorf("Failed to mark VM %s as errored: %v", op.VMID, updateErr)
+		return
+	}
+
+	s.recordEvent(ctx, models.NewVMEvent(models.VMEventStatusChanged, vm, audit.actor, audit.reason, audit.requestID).
+		WithStatusChange(vm.Status, models.VMStatusError).
+		WithOperation(op).
+		WithMessage(fmt.Sprintf("hypervisor %s failed: %v", operation, err)))
+	vm.Status = models.VMStatusError
+	vm.ResourceVersion++
+	s.hub.Publish(watch.EventStatusChanged, vm)
+}
+
+// operationSnapshot loads the snapshot a snapshot operation works on
+func (s *vmService) operationSnapshot(ctx context.Context, op *models.Operation) (*models.Snapshot, error) {
+	if op.SnapshotID == nil {
+		return nil, fmt.Errorf("operation %s has no snapshot", op.ID)
+	}
+	return s.snapshotRepo.GetByID(ctx, op.VMID, *op.SnapshotID)
+}
+
+// failSnapshot marks a snapshot as errored after a failed driver call; the VM itself is unaffected
+func (s *vmService) failSnapshot(ctx context.Context, op *models.Operation, snapshot *models.Snapshot, err error) {
+	operation := string(op.Type)
+	s.logger.WithOperation(operation).Errorf("Hypervisor %s failed for snapshot %s of VM %s: %v", operation, snapshot.ID, op.VMID, err)
+	s.failOperation(ctx, op, errors.HypervisorError(operation, err))
+
+	snapshot.Status = models.SnapshotStatusError
+	snapshot.Error = err.Error()
+	if updateErr := s.snapshotRepo.Update(ctx, snapshot); updateErr != nil {
+		s.logger.Errorf("Failed to mark snapshot %s as errored: %v", snapshot.ID, updateErr)
+	}
+}
+
+// Audit trail
+
+// auditInfo identifies who requested a change, why, and through which API request.
+// It travels with background workers so asynchronous transitions are attributed correctly.
+type auditInfo struct {
+	actor     string
+	reason    string
+	requestID string
+}
+
+// recordEvent appends an event to the VM audit trail; failures are logged because the change already happened
+func (s *vmService) recordEvent(ctx context.Context, event *models.VMEvent) {
+	if err := s.eventRepo.Create(ctx, event); err != nil {
+		s.logger.WithOperation("record-event").Errorf("Failed to record %s event for VM %s: %v", event.Type, event.VMID, err)
+	}
+}
+
+// flavorOrCustom names the flavor of a VM for messages
+func flavorOrCustom(flavor string) string {
+	if flavor == "" {
+		return "custom"
+	}
+	return flavor
+}
+
+// describeChanges summarizes which fields of a VM were modified by an update
+func describeChanges(before, after *models.VM) string {
+	var changed []string
+	if before.Name != after.Name {
+		changed = append(changed, fmt.Sprintf("name: %s -> %s", before.Name, after.Name))
+	}
+	if before.Description != after.Description {
+		changed = append(changed, "description")
+	}
+	if before.Flavor != after.Flavor {
+		changed = append(changed, fmt.Sprintf("flavor: %s -> %s", flavorOrCustom(before.Flavor), flavorOrCustom(after.Flavor)))
+	}
+	if before.Spec.CPUCores != after.Spec.CPUCores {
+		changed = append(changed, fmt.Sprintf("cpu_cores: %d -> %d", before.Spec.CPUCores, after.Spec.CPUCores))
+	}
+	if before.Spec.RAMMb != after.Spec.RAMMb {
+		changed = append(changed, fmt.Sprintf("ram_mb: %d -> %d", before.Spec.RAMMb, after.Spec.RAMMb))
+	}
+	if before.Spec.DiskGb != after.Spec.DiskGb {
+		changed = append(changed, fmt.Sprintf("disk_gb: %d -> %d", before.Spec.DiskGb, after.Spec.DiskGb))
+	}
+	if string(before.Labels) != string(after.Labels) {
+		changed = append(changed, "labels")
+	}
+	if string(before.Annotations) != string(after.Annotations) {
+		changed = append(changed, "annotations")
+	}
+
+	if len(changed) == 0 {
+		return "no changes"
+	}
+	return "changed " + strings.Join(changed, ", ")
 }
 
 func (s *vmService) startStatsUpdater(vmID uuid.UUID) {
@@ -0,0 +1,376 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/internal/hypervisor"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/repositories"
+	"github.com/stackit/enterprise-vm-manager/internal/watch"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+)
+
+// VolumeService interface defines block volume operations
+type VolumeService interface {
+	CreateVolume(ctx context.Context, req *models.VolumeCreateRequest) (*models.Volume, error)
+	GetVolume(ctx context.Context, id uuid.UUID) (*models.Volume, error)
+	ListVolumes(ctx context.Context, opts models.VolumeListOptions) ([]*models.Volume, error)
+	ResizeVolume(ctx context.Context, id uuid.UUID, req *models.VolumeResizeRequest) (*models.Volume, error)
+	DeleteVolume(ctx context.Context, id uuid.UUID) error
+	AttachVolume(ctx context.Context, id uuid.UUID, req *models.VolumeAttachRequest) (*models.Volume, error)
+	DetachVolume(ctx context.Context, id uuid.UUID, req *models.VolumeDetachRequest) (*models.Volume, error)
+}
+
+// volumeService implements VolumeService interface
+type volumeService struct {
+	volumeRepo repositories.VolumeRepository
+	vmRepo     repositories.VMRepository
+	quotaRepo  repositories.QuotaRepository
+	eventRepo  repositories.EventRepository
+	driver     hypervisor.Driver
+	hub        *watch.Hub
+	cfg        *config.Config
+	logger     *logger.Logger
+}
+
+// NewVolumeService creates a new volume service
+func NewVolumeService(
+	volumeRepo repositories.VolumeRepository,
+	vmRepo repositories.VMRepository,
+	quotaRepo repositories.QuotaRepository,
+	eventRepo repositories.EventRepository,
+	driver hypervisor.Driver,
+	hub *watch.Hub,
+	cfg *config.Config,
+	logger *logger.Logger,
+) VolumeService {
+	return &volumeService{
+		volumeRepo: volumeRepo,
+		vmRepo:     vmRepo,
+		quotaRepo:  quotaRepo,
+		eventRepo:  eventRepo,
+		driver:     driver,
+		hub:        hub,
+		cfg:        cfg,
+		logger:     logger.WithComponent("volume-service"),
+	}
+}
+
+// CreateVolume creates a volume after checking the disk quota of its project
+func (s *volumeService) CreateVolume(ctx context.Context, req *models.VolumeCreateRequest) (*models.Volume, error) {
+	log := s.logger.WithOperation("create-volume")
+
+	volume := req.ToVolume()
+	err := s.quotaRepo.WithLockedUsage(ctx, volume.ProjectID, func(ctx context.Context, quota *models.Quota, usage *models.QuotaUsage) error {
+		request := models.QuotaRequest{DiskGb: volume.SizeGb}
+		if err := checkQuota(effectiveLimits(quota, s.cfg), usage, volume.ProjectID, request); err != nil {
+			log.Warnf("Volume %s exceeds the project quota: %v", volume.Name, err)
+			return err
+		}
+
+		if err := s.volumeRepo.Create(ctx, volume); err != nil {
+			return err
+		}
+
+		// Failing to allocate the storage rolls back the volume
+		if err := s.driver.CreateVolume(ctx, volume); err != nil {
+			log.Errorf("Failed to create volume %s on hypervisor: %v", volume.ID, err)
+			return errors.HypervisorError("create volume", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Volume created: %s (ID: %s, %d GB)", volume.Name, volume.ID, volume.SizeGb)
+	return volume, nil
+}
+
+// GetVolume retrieves a volume by ID
+func (s *volumeService) GetVolume(ctx context.Context, id uuid.UUID) (*models.Volume, error) {
+	return s.volumeRepo.GetByID(ctx, id)
+}
+
+// ListVolumes lists the volumes of the caller's projects
+func (s *volumeService) ListVolumes(ctx context.Context, opts models.VolumeListOptions) ([]*models.Volume, error) {
+	return s.volumeRepo.List(ctx, opts)
+}
+
+// ResizeVolume grows a volume. Volumes attached to a running VM are grown live;
+// the guest has to grow its file system itself.
+func (s *volumeService) ResizeVolume(ctx context.Context, id uuid.UUID, req *models.VolumeResizeRequest) (*models.Volume, error) {
+	log := s.logger.WithOperation("resize-volume")
+
+	volume, err := s.volumeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var vm *models.VM
+	err = s.quotaRepo.WithLockedUsage(ctx, volume.ProjectID, func(ctx context.Context, quota *models.Quota, usage *models.QuotaUsage) error {
+		// Read again under the project lock so concurrent changes of the volume are seen
+		if volume, err = s.volumeRepo.GetByID(ctx, id); err != nil {
+			return err
+		}
+		if req.SizeGb < volume.SizeGb {
+			return errors.ValidationError("size_gb", fmt.Sprintf("volumes can only grow, the current size is %d GB", volume.SizeGb))
+		}
+		if req.SizeGb == volume.SizeGb {
+			return nil
+		}
+
+		request := models.QuotaRequest{DiskGb: req.SizeGb - volume.SizeGb}
+		if err := checkQuota(effectiveLimits(quota, s.cfg), usage, volume.ProjectID, request); err != nil {
+			log.Warnf("Resize of volume %s exceeds the project quota: %v", volume.ID, err)
+			return err
+		}
+
+		if volume.IsAttached() {
+			if vm, err = s.vmRepo.GetByID(ctx, *volume.VMID); err != nil {
+				return err
+			}
+			if !vm.CanPerformOperation("volume-resize") {
+				return errors.VMStateError(vm.ID.String(), string(vm.Status), "stopped or running")
+			}
+		}
+
+		volume.SizeGb = req.SizeGb
+		volume.UpdatedBy = req.UpdatedBy
+		if err := s.volumeRepo.Update(ctx, volume); err != nil {
+			return err
+		}
+
+		// The VM shows its volumes, so it changes with them
+		if vm != nil {
+			vm.Volumes = replaceVolume(vm.Volumes, volume)
+			vm.UpdatedBy = req.UpdatedBy
+			if err := s.vmRepo.Update(ctx, vm); err != nil {
+				return err
+			}
+		}
+
+		// Volumes cannot shrink again, so they only grow once the writes above have succeeded.
+		// A failed resize rolls the writes back.
+		if err := s.driver.ResizeVolume(ctx, volume); err != nil {
+			log.Errorf("Failed to resize volume %s on hypervisor: %v", volume.ID, err)
+			return errors.HypervisorError("resize volume", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if vm != nil {
+		s.recordEvent(ctx, models.NewVMEvent(models.VMEventUpdated, vm, req.UpdatedBy, "", req.RequestID).
+			WithMessage(fmt.Sprintf("volume %s: size_gb -> %d", volume.Name, volume.SizeGb)))
+		s.hub.Publish(watch.EventUpdated, vm)
+	}
+
+	log.Infof("Volume resized: %s (ID: %s, %d GB)", volume.Name, volume.ID, volume.SizeGb)
+	return volume, nil
+}
+
+// DeleteVolume deletes a detached volume and its data
+func (s *volumeService) DeleteVolume(ctx context.Context, id uuid.UUID) error {
+	log := s.logger.WithOperation("delete-volume")
+
+	volume, err := s.volumeRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.quotaRepo.WithLockedUsage(ctx, volume.ProjectID, func(ctx context.Context, _ *models.Quota, _ *models.QuotaUsage) error {
+		if volume, err = s.volumeRepo.GetByID(ctx, id); err != nil {
+			return err
+		}
+		if volume.IsAttached() {
+			return errors.ErrResourceLocked.
+				WithContext("vm_id", volume.VMID.String()).
+				WithDetails(fmt.Sprintf("Volume %s is attached to VM %s", volume.Name, volume.VMID))
+		}
+
+		if err := s.volumeRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+
+		// Failing to release the storage keeps the volume
+		if err := s.driver.DeleteVolume(ctx, volume); err != nil {
+			log.Errorf("Failed to delete volume %s on hypervisor: %v", volume.ID, err)
+			return errors.HypervisorError("delete volume", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Volume deleted: %s (ID: %s)", volume.Name, volume.ID)
+	return nil
+}
+
+// AttachVolume attaches an available volume to a stopped or running VM of the same project
+func (s *volumeService) AttachVolume(ctx context.Context, id uuid.UUID, req *models.VolumeAttachRequest) (*models.Volume, error) {
+	log := s.logger.WithOperation("attach-volume")
+
+	volume, err := s.volumeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The project lock serializes attachments, so a volume cannot be attached twice
+	var vm *models.VM
+	err = s.quotaRepo.WithLockedUsage(ctx, volume.ProjectID, func(ctx context.Context, _ *models.Quota, _ *models.QuotaUsage) error {
+		if volume, err = s.volumeRepo.GetByID(ctx, id); err != nil {
+			return err
+		}
+		if volume.IsAttached() {
+			return errors.ErrResourceLocked.
+				WithContext("vm_id", volume.VMID.String()).
+				WithDetails(fmt.Sprintf("Volume %s is already attached to VM %s", volume.Name, volume.VMID))
+		}
+
+		if vm, err = s.vmRepo.GetByID(ctx, req.VMID); err != nil {
+			return err
+		}
+		if vm.ProjectID != volume.ProjectID {
+			return errors.ValidationError("vm_id", "VM must belong to the project of the volume")
+		}
+		if !vm.CanPerformOperation("volume-attach") {
+			return errors.VMStateError(vm.ID.String(), string(vm.Status), "stopped or running")
+		}
+
+		if err := s.driver.AttachVolume(ctx, vm, volume); err != nil {
+			log.Errorf("Failed to attach volume %s to VM %s on hypervisor: %v", volume.ID, vm.ID, err)
+			return errors.HypervisorError("attach volume", err)
+		}
+
+		volume.Attach(vm.ID)
+		volume.UpdatedBy = req.UpdatedBy
+		vm.Volumes = append(vm.Volumes, volume)
+		vm.UpdatedBy = req.UpdatedBy
+		if err := s.saveAttachment(ctx, vm, volume); err != nil {
+			if detachErr := s.driver.DetachVolume(ctx, vm, volume); detachErr != nil {
+				log.Errorf("Failed to roll back attachment of volume %s to VM %s: %v", volume.ID, vm.ID, detachErr)
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordEvent(ctx, models.NewVMEvent(models.VMEventVolumeAttached, vm, req.UpdatedBy, "", req.RequestID).
+		WithMessage(fmt.Sprintf("volume %s (%d GB)", volume.Name, volume.SizeGb)))
+	s.hub.Publish(watch.EventUpdated, vm)
+
+	log.Infof("Volume %s attached to VM %s", volume.ID, vm.ID)
+	return volume, nil
+}
+
+// DetachVolume detaches a volume from its VM, which must be stopped or running
+func (s *volumeService) DetachVolume(ctx context.Context, id uuid.UUID, req *models.VolumeDetachRequest) (*models.Volume, error) {
+	log := s.logger.WithOperation("detach-volume")
+
+	volume, err := s.volumeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var vm *models.VM
+	err = s.quotaRepo.WithLockedUsage(ctx, volume.ProjectID, func(ctx context.Context, _ *models.Quota, _ *models.QuotaUsage) error {
+		if volume, err = s.volumeRepo.GetByID(ctx, id); err != nil {
+			return err
+		}
+		if !volume.IsAttached() {
+			return errors.ErrInvalidInput.WithDetails(fmt.Sprintf("Volume %s is not attached", volume.Name))
+		}
+
+		// Volumes left attached to a VM that no longer exists are released without the hypervisor
+		vm, err = s.vmRepo.GetByID(ctx, *volume.VMID)
+		if err != nil && !errors.Is(err, errors.ErrNotFound) {
+			return err
+		}
+		if vm == nil {
+			volume.Detach()
+			volume.UpdatedBy = req.UpdatedBy
+			return s.volumeRepo.Update(ctx, volume)
+		}
+
+		if !vm.CanPerformOperation("volume-detach") {
+			return errors.VMStateError(vm.ID.String(), string(vm.Status), "stopped or running")
+		}
+		if err := s.driver.DetachVolume(ctx, vm, volume); err != nil {
+			log.Errorf("Failed to detach volume %s from VM %s on hypervisor: %v", volume.ID, vm.ID, err)
+			return errors.HypervisorError("detach volume", err)
+		}
+
+		volume.Detach()
+		volume.UpdatedBy = req.UpdatedBy
+		vm.Volumes = removeVolume(vm.Volumes, volume.ID)
+		vm.UpdatedBy = req.UpdatedBy
+		if err := s.saveAttachment(ctx, vm, volume); err != nil {
+			if attachErr := s.driver.AttachVolume(ctx, vm, volume); attachErr != nil {
+				log.Errorf("Failed to roll back detachment of volume %s from VM %s: %v", volume.ID, vm.ID, attachErr)
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if vm != nil {
+		s.recordEvent(ctx, models.NewVMEvent(models.VMEventVolumeDetached, vm, req.UpdatedBy, "", req.RequestID).
+			WithMessage(fmt.Sprintf("volume %s (%d GB)", volume.Name, volume.SizeGb)))
+		s.hub.Publish(watch.EventUpdated, vm)
+		log.Infof("Volume %s detached from VM %s", volume.ID, vm.ID)
+	}
+	return volume, nil
+}
+
+// saveAttachment stores a changed attachment and bumps the resource version of the VM,
+// so that clients watching the VM see its new volumes
+func (s *volumeService) saveAttachment(ctx context.Context, vm *models.VM, volume *models.Volume) error {
+	if err := s.volumeRepo.Update(ctx, volume); err != nil {
+		return err
+	}
+	return s.vmRepo.Update(ctx, vm)
+}
+
+// recordEvent appends an entry to the audit trail of a VM, logging failures
+func (s *volumeService) recordEvent(ctx context.Context, event *models.VMEvent) {
+	if err := s.eventRepo.Create(ctx, event); err != nil {
+		s.logger.WithOperation("record-event").Errorf("Failed to record %s event for VM %s: %v", event.Type, event.VMID, err)
+	}
+}
+
+// replaceVolume returns the volumes with the one of the same ID replaced by volume
+func replaceVolume(volumes []*models.Volume, volume *models.Volume) []*models.Volume {
+	result := make([]*models.Volume, len(volumes))
+	for i, v := range volumes {
+		if v.ID == volume.ID {
+			v = volume
+		}
+		result[i] = v
+	}
+	return result
+}
+
+// removeVolume returns the volumes without the one with the given ID
+func removeVolume(volumes []*models.Volume, id uuid.UUID) []*models.Volume {
+	result := make([]*models.Volume, 0, len(volumes))
+	for _, v := range volumes {
+		if v.ID != id {
+			result = append(result, v)
+		}
+	}
+	return result
+}
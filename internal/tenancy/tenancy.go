@@ -0,0 +1,49 @@
+// Package tenancy carries the projects a request may access through its context.
+//
+// Repositories read the scope from the context they are given and restrict
+// their queries to it. Contexts without a scope, such as those of background
+// workers and administrators, are unrestricted.
+package tenancy
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// scopeKey is the context key of the project scope
+type scopeKey struct{}
+
+// WithProjects returns a context restricted to the given projects
+func WithProjects(ctx context.Context, projectIDs []uuid.UUID) context.Context {
+	scope := make([]uuid.UUID, len(projectIDs))
+	copy(scope, projectIDs)
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// Projects returns the projects the context is restricted to.
+// The second result is false when the context is unrestricted.
+func Projects(ctx context.Context) ([]uuid.UUID, bool) {
+	scope, ok := ctx.Value(scopeKey{}).([]uuid.UUID)
+	return scope, ok
+}
+
+// Allows reports whether the context may access the project
+func Allows(ctx context.Context, projectID uuid.UUID) bool {
+	scope, ok := Projects(ctx)
+	if !ok {
+		return true
+	}
+	for _, id := range scope {
+		if id == projectID {
+			return true
+		}
+	}
+	return false
+}
+
+// Unrestricted returns a context that may access every project. It is meant
+// for lookups whose results are checked against the caller's scope afterwards.
+func Unrestricted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, scopeKey{}, nil)
+}
@@ -0,0 +1,194 @@
+// Package watch distributes VM change notifications to streaming API clients.
+//
+// Every published event receives a monotonically increasing resource version.
+// The hub keeps a bounded history of recent events so that clients which lose
+// their connection can resume from the last version they have seen.
+package watch
+
+import (
+	"sync"
+	"time"
+
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+)
+
+// EventType represents the kind of change carried by a watch event
+type EventType string
+
+const (
+	EventCreated       EventType = "created"
+	EventUpdated       EventType = "updated"
+	EventStatusChanged EventType = "status_changed"
+	EventStats         EventType = "stats"
+	EventDeleted       EventType = "deleted"
+)
+
+// Event represents a change of a VM delivered to watchers
+type Event struct {
+	ResourceVersion uint64             `json:"resource_version"`
+	Type            EventType          `json:"type"`
+	VM              *models.VMResponse `json:"vm"`
+	Timestamp       time.Time          `json:"timestamp"`
+}
+
+// Filter decides whether an event is delivered to a subscriber
+type Filter func(event *Event) bool
+
+// Hub fans out VM events to subscribers and retains recent history for resumption
+type Hub struct {
+	mu          sync.Mutex
+	version     uint64
+	history     []Event
+	historySize int
+	bufferSize  int
+	subscribers map[*Subscription]struct{}
+}
+
+// NewHub creates a new hub.
+// Versions start at the creation time in microseconds, so versions handed out by a
+// previous process are always older than the history of this one and are reported as expired.
+func NewHub(historySize, bufferSize int) *Hub {
+	return &Hub{
+		version:     uint64(time.Now().UnixMicro()),
+		historySize: historySize,
+		bufferSize:  bufferSize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// CurrentVersion returns the resource version of the latest published event
+func (h *Hub) CurrentVersion() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.version
+}
+
+// Publish records a change of a VM and delivers it to all matching subscribers
+func (h *Hub) Publish(eventType EventType, vm *models.VM) {
+	// Subscribers read the event concurrently, so they get their own copy of the VM
+	snapshot := *vm
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.version++
+	event := Event{
+		ResourceVersion: h.version,
+		Type:            eventType,
+		VM:              models.NewVMResponse(&snapshot),
+		Timestamp:       time.Now(),
+	}
+
+	h.history = append(h.history, event)
+	if len(h.history) > h.historySize {
+		h.history = h.history[len(h.history)-h.historySize:]
+	}
+
+	for sub := range h.subscribers {
+		if sub.filter != nil && !sub.filter(&event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			// A client that cannot keep up is disconnected and has to resume
+			h.remove(sub, true)
+		}
+	}
+}
+
+// Subscribe registers a subscriber that receives all matching events after the given version.
+// A version of zero starts at the current version.
+func (h *Hub) Subscribe(since uint64, filter Filter) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if since == 0 {
+		since = h.version
+	}
+	if !h.canResume(since) {
+		return nil, errors.VersionExpiredError(since)
+	}
+
+	var backlog []Event
+	for i := range h.history {
+		event := h.history[i]
+		if event.ResourceVersion <= since {
+			continue
+		}
+		if filter != nil && !filter(&event) {
+			continue
+		}
+		backlog = append(backlog, event)
+	}
+
+	sub := &Subscription{
+		hub:    h,
+		filter: filter,
+		events: make(chan Event, len(backlog)+h.bufferSize),
+		since:  since,
+	}
+	for _, event := range backlog {
+		sub.events <- event
+	}
+	h.subscribers[sub] = struct{}{}
+
+	return sub, nil
+}
+
+// canResume checks that no event after the given version has been dropped from history
+func (h *Hub) canResume(since uint64) bool {
+	if since > h.version {
+		return false
+	}
+	if len(h.history) == 0 {
+		return since == h.version
+	}
+	return since >= h.history[0].ResourceVersion-1
+}
+
+// remove unregisters a subscriber; must be called with the lock held
+func (h *Hub) remove(sub *Subscription, overflowed bool) {
+	if _, ok := h.subscribers[sub]; !ok {
+		return
+	}
+	delete(h.subscribers, sub)
+	sub.overflowed = overflowed
+	close(sub.events)
+}
+
+// Subscription represents a registered watcher
+type Subscription struct {
+	hub        *Hub
+	filter     Filter
+	events     chan Event
+	since      uint64
+	overflowed bool
+}
+
+// Events returns the channel on which events are delivered.
+// The channel is closed when the subscription ends.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Since returns the resource version the subscription started after
+func (s *Subscription) Since() uint64 {
+	return s.since
+}
+
+// Overflowed reports whether the subscription was ended because the client fell behind.
+// It must only be called after the events channel has been closed.
+func (s *Subscription) Overflowed() bool {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.overflowed
+}
+
+// Close unregisters the subscription
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s, false)
+}
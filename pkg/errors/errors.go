@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
+	"strings"
 )
 
 // AppError represents an application error with additional context
@@ -33,19 +34,29 @@ func (e *AppError) Unwrap() error {
 	return e.Internal
 }
 
-// WithContext adds context to the error
+// WithContext returns a copy of the error with the context added. The error itself is left
+// unchanged, so the shared error types can be used concurrently.
 func (e *AppError) WithContext(key, value string) *AppError {
-	if e.Context == nil {
-		e.Context = make(map[string]string)
-	}
-	e.Context[key] = value
-	return e
+	c := e.clone()
+	c.Context[key] = value
+	return c
 }
 
-// WithDetails adds details to the error
+// WithDetails returns a copy of the error with the details set
 func (e *AppError) WithDetails(details string) *AppError {
-	e.Details = details
-	return e
+	c := e.clone()
+	c.Details = details
+	return c
+}
+
+// clone returns a copy of the error with its own context map
+func (e *AppError) clone() *AppError {
+	c := *e
+	c.Context = make(map[string]string, len(e.Context)+1)
+	for key, value := range e.Context {
+		c.Context[key] = value
+	}
+	return &c
 }
 
 // Error types
@@ -54,6 +65,9 @@ var (
 	ErrInvalidInput     = &AppError{Code: "INVALID_INPUT", Message: "Invalid input provided", HTTPCode: http.StatusBadRequest}
 	ErrValidationFailed = &AppError{Code: "VALIDATION_FAILED", Message: "Validation failed", HTTPCode: http.StatusBadRequest}
 	ErrMissingField     = &AppError{Code: "MISSING_FIELD", Message: "Required field is missing", HTTPCode: http.StatusBadRequest}
+	ErrUnsupportedMedia = &AppError{Code: "UNSUPPORTED_MEDIA_TYPE", Message: "Unsupported media type", HTTPCode: http.StatusUnsupportedMediaType}
+	ErrPatchFailed      = &AppError{Code: "PATCH_FAILED", Message: "Patch could not be applied", HTTPCode: http.StatusUnprocessableEntity}
+	ErrPayloadTooLarge  = &AppError{Code: "PAYLOAD_TOO_LARGE", Message: "Request payload is too large", HTTPCode: http.StatusRequestEntityTooLarge}
 
 	// Authentication errors
 	ErrUnauthorized     = &AppError{Code: "UNAUTHORIZED", Message: "Authentication required", HTTPCode: http.StatusUnauthorized}
@@ -61,16 +75,29 @@ var (
 	ErrInsufficientPerm = &AppError{Code: "INSUFFICIENT_PERMISSIONS", Message: "Insufficient permissions", HTTPCode: http.StatusForbidden}
 
 	// Resource errors
-	ErrNotFound       = &AppError{Code: "NOT_FOUND", Message: "Resource not found", HTTPCode: http.StatusNotFound}
-	ErrAlreadyExists  = &AppError{Code: "ALREADY_EXISTS", Message: "Resource already exists", HTTPCode: http.StatusConflict}
-	ErrResourceLocked = &AppError{Code: "RESOURCE_LOCKED", Message: "Resource is locked", HTTPCode: http.StatusConflict}
+	ErrNotFound           = &AppError{Code: "NOT_FOUND", Message: "Resource not found", HTTPCode: http.StatusNotFound}
+	ErrAlreadyExists      = &AppError{Code: "ALREADY_EXISTS", Message: "Resource already exists", HTTPCode: http.StatusConflict}
+	ErrResourceLocked     = &AppError{Code: "RESOURCE_LOCKED", Message: "Resource is locked", HTTPCode: http.StatusConflict}
+	ErrVersionExpired     = &AppError{Code: "RESOURCE_VERSION_EXPIRED", Message: "Resource version is too old", HTTPCode: http.StatusGone}
+	ErrVersionConflict    = &AppError{Code: "RESOURCE_VERSION_CONFLICT", Message: "Resource was modified concurrently", HTTPCode: http.StatusConflict}
+	ErrPreconditionFailed = &AppError{Code: "PRECONDITION_FAILED", Message: "Resource version does not match", HTTPCode: http.StatusPreconditionFailed}
 
 	// VM specific errors
-	ErrVMNotFound       = &AppError{Code: "VM_NOT_FOUND", Message: "Virtual machine not found", HTTPCode: http.StatusNotFound}
-	ErrVMAlreadyRunning = &AppError{Code: "VM_ALREADY_RUNNING", Message: "Virtual machine is already running", HTTPCode: http.StatusConflict}
-	ErrVMNotRunning     = &AppError{Code: "VM_NOT_RUNNING", Message: "Virtual machine is not running", HTTPCode: http.StatusConflict}
-	ErrInvalidVMState   = &AppError{Code: "INVALID_VM_STATE", Message: "Invalid virtual machine state for this operation", HTTPCode: http.StatusConflict}
-	ErrResourceExceeded = &AppError{Code: "RESOURCE_EXCEEDED", Message: "Resource limits exceeded", HTTPCode: http.StatusConflict}
+	ErrVMNotFound           = &AppError{Code: "VM_NOT_FOUND", Message: "Virtual machine not found", HTTPCode: http.StatusNotFound}
+	ErrVMAlreadyRunning     = &AppError{Code: "VM_ALREADY_RUNNING", Message: "Virtual machine is already running", HTTPCode: http.StatusConflict}
+	ErrVMNotRunning         = &AppError{Code: "VM_NOT_RUNNING", Message: "Virtual machine is not running", HTTPCode: http.StatusConflict}
+	ErrInvalidVMState       = &AppError{Code: "INVALID_VM_STATE", Message: "Invalid virtual machine state for this operation", HTTPCode: http.StatusConflict}
+	ErrResourceExceeded     = &AppError{Code: "RESOURCE_EXCEEDED", Message: "Resource limits exceeded", HTTPCode: http.StatusConflict}
+	ErrInsufficientCapacity = &AppError{Code: "INSUFFICIENT_CAPACITY", Message: "Insufficient capacity to place virtual machine", HTTPCode: http.StatusConflict}
+	ErrQuotaExceeded        = &AppError{Code: "QUOTA_EXCEEDED", Message: "Project quota exceeded", HTTPCode: http.StatusConflict}
+	ErrHypervisor           = &AppError{Code: "HYPERVISOR_ERROR", Message: "Hypervisor operation failed", HTTPCode: http.StatusBadGateway}
+	ErrSnapshotNotReady     = &AppError{Code: "SNAPSHOT_NOT_READY", Message: "Snapshot is not ready", HTTPCode: http.StatusConflict}
+	ErrFlavorNotAllowed     = &AppError{Code: "FLAVOR_NOT_ALLOWED", Message: "Flavor is not allowed in the project", HTTPCode: http.StatusForbidden}
+	ErrUnknownImage         = &AppError{Code: "UNKNOWN_IMAGE", Message: "Image is not in the image catalog", HTTPCode: http.StatusBadRequest}
+	ErrImageDeprecated      = &AppError{Code: "IMAGE_DEPRECATED", Message: "Image is deprecated", HTTPCode: http.StatusBadRequest}
+	ErrUploadOffset         = &AppError{Code: "UPLOAD_OFFSET_MISMATCH", Message: "Upload does not continue at the current offset", HTTPCode: http.StatusConflict}
+	ErrChecksumMismatch     = &AppError{Code: "CHECKSUM_MISMATCH", Message: "Uploaded data does not match the checksum", HTTPCode: http.StatusUnprocessableEntity}
+	ErrAddressesExhausted   = &AppError{Code: "ADDRESSES_EXHAUSTED", Message: "Network has no free IP addresses", HTTPCode: http.StatusConflict}
 
 	// System errors
 	ErrInternalServer     = &AppError{Code: "INTERNAL_SERVER_ERROR", Message: "Internal server error", HTTPCode: http.StatusInternalServerError}
@@ -227,11 +254,93 @@ func ResourceLimitError(resourceType string, requested, limit int) *AppError {
 		WithDetails(fmt.Sprintf("Requested %s (%d) exceeds limit (%d)", resourceType, requested, limit))
 }
 
+// InsufficientCapacityError creates an error for a VM that fits on no node
+func InsufficientCapacityError(resources []string, details string) *AppError {
+	return ErrInsufficientCapacity.
+		WithContext("resources", strings.Join(resources, ",")).
+		WithDetails(details)
+}
+
+// QuotaExceededError creates an error for a request that would exceed a project quota
+func QuotaExceededError(dimension string, limit, used, requested int64) *AppError {
+	return ErrQuotaExceeded.
+		WithContext("dimension", dimension).
+		WithContext("limit", fmt.Sprintf("%d", limit)).
+		WithContext("used", fmt.Sprintf("%d", used)).
+		WithContext("requested", fmt.Sprintf("%d", requested)).
+		WithDetails(fmt.Sprintf("Quota for %s exceeded: %d in use plus %d requested exceeds the limit of %d", dimension, used, requested, limit))
+}
+
+// VersionExpiredError creates an error for a watch that cannot resume from the given resource version
+func VersionExpiredError(resourceVersion uint64) *AppError {
+	return ErrVersionExpired.
+		WithContext("resource_version", fmt.Sprintf("%d", resourceVersion)).
+		WithDetails(fmt.Sprintf("Events after resource version %d are no longer available; list VMs again and watch from the returned version", resourceVersion))
+}
+
+// VersionConflictError creates an error for a write that lost the race against another write
+func VersionConflictError(resourceType, resourceID string, resourceVersion int64) *AppError {
+	return ErrVersionConflict.
+		WithContext("resource_type", resourceType).
+		WithContext("resource_id", resourceID).
+		WithContext("resource_version", fmt.Sprintf("%d", resourceVersion)).
+		WithDetails(fmt.Sprintf("%s %s was modified after version %d was read; read it again and retry", resourceType, resourceID, resourceVersion))
+}
+
+// PreconditionFailedError creates an error for a request whose If-Match version is stale
+func PreconditionFailedError(resourceType, resourceID string, expected, current int64) *AppError {
+	return ErrPreconditionFailed.
+		WithContext("resource_type", resourceType).
+		WithContext("resource_id", resourceID).
+		WithContext("expected_version", fmt.Sprintf("%d", expected)).
+		WithContext("current_version", fmt.Sprintf("%d", current)).
+		WithDetails(fmt.Sprintf("%s %s is at version %d, but the request requires version %d", resourceType, resourceID, current, expected))
+}
+
+// PermissionDeniedError creates an error for a caller lacking a permission
+func PermissionDeniedError(permission string) *AppError {
+	return ErrInsufficientPerm.
+		WithContext("permission", permission).
+		WithDetails(fmt.Sprintf("Permission %s is required for this operation", permission))
+}
+
+// UploadOffsetError creates an error for an upload chunk that does not start where the stored data ends
+func UploadOffsetError(current, requested int64) *AppError {
+	return ErrUploadOffset.
+		WithContext("offset", fmt.Sprintf("%d", current)).
+		WithDetails(fmt.Sprintf("%d bytes have been received, but the chunk starts at byte %d", current, requested))
+}
+
+// ChecksumMismatchError creates an error for uploaded data whose digest differs from the expected one
+func ChecksumMismatchError(expected, actual string) *AppError {
+	return ErrChecksumMismatch.
+		WithContext("expected", expected).
+		WithContext("actual", actual).
+		WithDetails(fmt.Sprintf("Expected %s but the uploaded data has %s; upload it again", expected, actual))
+}
+
+// AddressesExhaustedError creates an error for a network whose DHCP range is fully allocated
+func AddressesExhaustedError(network, dhcpStart, dhcpEnd string) *AppError {
+	return ErrAddressesExhausted.
+		WithContext("network", network).
+		WithDetails(fmt.Sprintf("All addresses from %s to %s of network %s are allocated", dhcpStart, dhcpEnd, network))
+}
+
 // DatabaseError creates a database error
 func DatabaseError(operation string, err error) *AppError {
 	return Wrap(err, "DATABASE_ERROR", fmt.Sprintf("Database error during %s", operation), http.StatusInternalServerError)
 }
 
+// HypervisorError creates a hypervisor driver error
+func HypervisorError(operation string, err error) *AppError {
+	return Wrap(err, "HYPERVISOR_ERROR", fmt.Sprintf("Hypervisor error during %s", operation), http.StatusBadGateway)
+}
+
+// StorageError creates an image store error
+func StorageError(operation string, err error) *AppError {
+	return Wrap(err, "STORAGE_ERROR", fmt.Sprintf("Storage error during %s", operation), http.StatusInternalServerError)
+}
+
 // InternalError creates an internal server error
 func InternalError(message string, err error) *AppError {
 	return Wrap(err, "INTERNAL_SERVER_ERROR", message, http.StatusInternalServerError)
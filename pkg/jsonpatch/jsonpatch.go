@@ -0,0 +1,326 @@
+// Package jsonpatch applies JSON Merge Patches (RFC 7386) and JSON Patches
+// (RFC 6902) to JSON documents.
+package jsonpatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Media types of the supported patch formats
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+// Operation represents a single JSON Patch operation
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// SyntaxError reports a patch that is not valid JSON or not a valid patch document
+type SyntaxError struct {
+	Err error
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid patch: %v", e.Err)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// MergePatch applies a JSON Merge Patch to a document.
+// Members set to null in the patch are removed, objects are merged recursively
+// and all other values replace the value in the document.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var target, changes interface{}
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return nil, &SyntaxError{Err: err}
+	}
+	return json.Marshal(merge(target, changes))
+}
+
+// merge applies a merge patch value to a target value
+func merge(target, patch interface{}) interface{} {
+	changes, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	object, ok := target.(map[string]interface{})
+	if !ok {
+		object = make(map[string]interface{})
+	}
+
+	for key, value := range changes {
+		if value == nil {
+			delete(object, key)
+			continue
+		}
+		object[key] = merge(object[key], value)
+	}
+	return object
+}
+
+// Apply applies a JSON Patch to a document. The operations are applied in
+// order and the document is left unchanged if any of them fails.
+func Apply(doc, patch []byte) ([]byte, error) {
+	var target interface{}
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+
+	var operations []Operation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, &SyntaxError{Err: err}
+	}
+
+	for i, op := range operations {
+		var err error
+		if target, err = apply(target, op); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(target)
+}
+
+// apply applies a single operation and returns the new document
+func apply(doc interface{}, op Operation) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, &SyntaxError{Err: err}
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if len(op.Value) == 0 {
+			return nil, &SyntaxError{Err: fmt.Errorf("%s requires a value", op.Op)}
+		}
+		var value interface{}
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, &SyntaxError{Err: err}
+		}
+
+		switch op.Op {
+		case "add":
+			return add(doc, path, value)
+		case "replace":
+			if _, err := get(doc, path); err != nil {
+				return nil, err
+			}
+			if len(path) == 0 {
+				return value, nil
+			}
+			return update(doc, path, func(parent interface{}, key string) (interface{}, error) {
+				return set(parent, key, value)
+			})
+		default:
+			current, err := get(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, fmt.Errorf("test failed: value at %s differs", op.Path)
+			}
+			return doc, nil
+		}
+
+	case "remove":
+		if len(path) == 0 {
+			return nil, fmt.Errorf("cannot remove the whole document")
+		}
+		return update(doc, path, remove)
+
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, &SyntaxError{Err: err}
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+
+		if op.Op == "copy" {
+			return add(doc, path, deepCopy(value))
+		}
+		if strings.HasPrefix(op.Path+"/", op.From+"/") && op.Path != op.From {
+			return nil, fmt.Errorf("cannot move %s into one of its children", op.From)
+		}
+		if doc, err = update(doc, from, remove); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+
+	default:
+		return nil, &SyntaxError{Err: fmt.Errorf("unknown operation %q", op.Op)}
+	}
+}
+
+// add adds a value at the path, inserting into arrays
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, path, func(parent interface{}, key string) (interface{}, error) {
+		switch container := parent.(type) {
+		case map[string]interface{}:
+			container[key] = value
+			return container, nil
+		case []interface{}:
+			index := len(container)
+			if key != "-" {
+				var err error
+				if index, err = arrayIndex(key, len(container)+1); err != nil {
+					return nil, err
+				}
+			}
+			container = append(container, nil)
+			copy(container[index+1:], container[index:])
+			container[index] = value
+			return container, nil
+		default:
+			return nil, fmt.Errorf("cannot add to a %T", parent)
+		}
+	})
+}
+
+// remove removes the member or element at the key of a container
+func remove(parent interface{}, key string) (interface{}, error) {
+	switch container := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := container[key]; !ok {
+			return nil, fmt.Errorf("member %q does not exist", key)
+		}
+		delete(container, key)
+		return container, nil
+	case []interface{}:
+		index, err := arrayIndex(key, len(container))
+		if err != nil {
+			return nil, err
+		}
+		return append(container[:index], container[index+1:]...), nil
+	default:
+		return nil, fmt.Errorf("cannot remove from a %T", parent)
+	}
+}
+
+// set replaces the member or element at the key of a container
+func set(parent interface{}, key string, value interface{}) (interface{}, error) {
+	switch container := parent.(type) {
+	case map[string]interface{}:
+		container[key] = value
+		return container, nil
+	case []interface{}:
+		index, err := arrayIndex(key, len(container))
+		if err != nil {
+			return nil, err
+		}
+		container[index] = value
+		return container, nil
+	default:
+		return nil, fmt.Errorf("cannot set a member of a %T", parent)
+	}
+}
+
+// update changes the parent container of the path with fn and returns the new document
+func update(doc interface{}, path []string, fn func(parent interface{}, key string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+
+	child, err := get(doc, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	child, err = update(child, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+	return set(doc, path[0], child)
+}
+
+// get returns the value at the path
+func get(doc interface{}, path []string) (interface{}, error) {
+	current := doc
+	for _, key := range path {
+		switch container := current.(type) {
+		case map[string]interface{}:
+			value, ok := container[key]
+			if !ok {
+				return nil, fmt.Errorf("member %q does not exist", key)
+			}
+			current = value
+		case []interface{}:
+			index, err := arrayIndex(key, len(container))
+			if err != nil {
+				return nil, err
+			}
+			current = container[index]
+		default:
+			return nil, fmt.Errorf("cannot look up %q in a %T", key, current)
+		}
+	}
+	return current, nil
+}
+
+// arrayIndex parses an array index that must be below limit
+func arrayIndex(key string, limit int) (int, error) {
+	if key == "" || (len(key) > 1 && key[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", key)
+	}
+	index, err := strconv.Atoi(key)
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("invalid array index %q", key)
+	}
+	if index >= limit {
+		return 0, fmt.Errorf("array index %d out of bounds", index)
+	}
+	return index, nil
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into unescaped reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("json pointer %q must start with /", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// deepCopy copies a decoded JSON value
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = deepCopy(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = deepCopy(item)
+		}
+		return copied
+	default:
+		return v
+	}
+}
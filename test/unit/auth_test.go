@@ -0,0 +1,254 @@
+package tests
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stackit/enterprise-vm-manager/internal/api/handlers"
+	"github.com/stackit/enterprise-vm-manager/internal/api/middleware"
+	"github.com/stackit/enterprise-vm-manager/internal/auth"
+	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAuthConfig() config.AuthConfig {
+	return config.AuthConfig{
+		Enabled:       true,
+		JWTSecret:     "test-secret",
+		JWTExpiration: time.Hour,
+		JWTAlgorithm:  "HS256",
+		Issuer:        "enterprise-vm-manager",
+		APIKeyHeader:  "X-API-Key",
+		APIClients: []config.APIClient{
+			{Name: "alice", Key: "alice-key", Roles: []string{"operator", "viewer"}, Tenant: "team-a"},
+		},
+	}
+}
+
+func TestAuthenticatorIssuesAndParsesHS256Tokens(t *testing.T) {
+	authenticator, err := auth.NewAuthenticator(testAuthConfig())
+	require.NoError(t, err)
+
+	identity, err := authenticator.AuthenticateAPIKey("alice-key")
+	require.NoError(t, err)
+
+	token, expiresAt, err := authenticator.IssueToken(identity)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+
+	parsed, err := authenticator.ParseToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", parsed.Subject)
+	assert.Equal(t, []string{"operator", "viewer"}, parsed.Roles)
+	assert.Equal(t, "team-a", parsed.Tenant)
+}
+
+func TestAuthenticatorRejectsInvalidTokens(t *testing.T) {
+	cfg := testAuthConfig()
+	authenticator, err := auth.NewAuthenticator(cfg)
+	require.NoError(t, err)
+
+	sign := func(secret string, claims auth.Claims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		require.NoError(t, err)
+		return token
+	}
+	valid := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{
+		Subject:   "alice",
+		Issuer:    cfg.Issuer,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}}
+
+	expired := valid
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+
+	noExpiry := valid
+	noExpiry.ExpiresAt = nil
+
+	wrongIssuer := valid
+	wrongIssuer.Issuer = "someone-else"
+
+	noSubject := valid
+	noSubject.Subject = ""
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, valid).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"wrong secret": sign("other-secret", valid),
+		"expired":      sign(cfg.JWTSecret, expired),
+		"no expiry":    sign(cfg.JWTSecret, noExpiry),
+		"wrong issuer": sign(cfg.JWTSecret, wrongIssuer),
+		"no subject":   sign(cfg.JWTSecret, noSubject),
+		"unsigned":     unsigned,
+		"api key":      "alice-key",
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := authenticator.ParseToken(token)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err = authenticator.ParseToken(sign(cfg.JWTSecret, valid))
+	assert.NoError(t, err)
+}
+
+func TestAuthenticatorVerifiesRS256TokensFromJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwks, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "key-1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+	require.NoError(t, err)
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(jwksFile, jwks, 0o600))
+
+	cfg := testAuthConfig()
+	cfg.JWTAlgorithm = "RS256"
+	cfg.JWKSFile = jwksFile
+	authenticator, err := auth.NewAuthenticator(cfg)
+	require.NoError(t, err)
+
+	claims := auth.Claims{
+		Roles:  []string{"admin"},
+		Tenant: "team-b",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "bob",
+			Issuer:    cfg.Issuer,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	identity, err := authenticator.ParseToken(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", identity.Subject)
+	assert.Equal(t, "team-b", identity.Tenant)
+
+	// HS256 tokens must not be accepted when RS256 is configured
+	hs256, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.JWTSecret))
+	require.NoError(t, err)
+	_, err = authenticator.ParseToken(hs256)
+	assert.Error(t, err)
+
+	// Without a signing key the authenticator only verifies tokens
+	_, _, err = authenticator.IssueToken(identity)
+	assert.Error(t, err)
+}
+
+func newAuthTestRouter(t *testing.T) (*gin.Engine, *auth.Authenticator) {
+	log, err := logger.New(logger.Config{Level: "error", Format: "console", Output: "stdout"})
+	require.NoError(t, err)
+
+	cfg := &config.Config{Auth: testAuthConfig()}
+	authenticator, err := auth.NewAuthenticator(cfg.Auth)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(requestid.New())
+	router.POST("/api/v1/auth/token", handlers.NewAuthHandler(authenticator, log).IssueToken)
+
+	api := router.Group("/api/v1", middleware.NewMiddlewareManager(cfg, log, nil, authenticator, nil, nil, nil).AuthenticationMiddleware())
+	api.GET("/whoami", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"user_id": middleware.GetUserID(c),
+			"roles":   middleware.GetUserRoles(c),
+			"tenant":  middleware.GetTenantID(c),
+		})
+	})
+	return router, authenticator
+}
+
+func TestAuthenticationMiddleware(t *testing.T) {
+	router, authenticator := newAuthTestRouter(t)
+
+	token, _, err := authenticator.IssueToken(&auth.Identity{Subject: "carol", Roles: []string{"viewer"}, Tenant: "team-c"})
+	require.NoError(t, err)
+
+	whoami := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/whoami", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := whoami("Authorization", "Bearer "+token)
+	require.Equal(t, http.StatusOK, w.Code)
+	var identity map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &identity))
+	assert.Equal(t, "carol", identity["user_id"])
+	assert.Equal(t, []interface{}{"viewer"}, identity["roles"])
+	assert.Equal(t, "team-c", identity["tenant"])
+
+	w = whoami("X-API-Key", "alice-key")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &identity))
+	assert.Equal(t, "alice", identity["user_id"])
+
+	// API keys are no longer accepted as bearer tokens
+	assert.Equal(t, http.StatusUnauthorized, whoami("Authorization", "Bearer alice-key").Code)
+	assert.Equal(t, http.StatusUnauthorized, whoami("Authorization", "Basic YWxpY2U6c2VjcmV0").Code)
+	assert.Equal(t, http.StatusUnauthorized, whoami("X-API-Key", "unknown").Code)
+	assert.Equal(t, http.StatusUnauthorized, whoami("", "").Code)
+}
+
+func TestIssueTokenEndpoint(t *testing.T) {
+	router, authenticator := newAuthTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/token", nil)
+	req.Header.Set("X-API-Key", "alice-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data struct {
+			AccessToken string `json:"access_token"`
+			TokenType   string `json:"token_type"`
+			ExpiresIn   int64  `json:"expires_in"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Bearer", response.Data.TokenType)
+	assert.InDelta(t, 3600, response.Data.ExpiresIn, 5)
+
+	identity, err := authenticator.ParseToken(response.Data.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", identity.Subject)
+	assert.Equal(t, "team-a", identity.Tenant)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/auth/token", nil)
+	req.Header.Set("X-API-Key", "wrong-key")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,221 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stackit/enterprise-vm-manager/internal/cache"
+	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/repositories"
+	"github.com/stackit/enterprise-vm-manager/internal/tenancy"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// countingVMRepository keeps VMs in memory and counts the reads that reach it
+type countingVMRepository struct {
+	repositories.VMRepository
+	vms   map[uuid.UUID]*models.VM
+	gets  int
+	lists int
+}
+
+func (r *countingVMRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.VM, error) {
+	r.gets++
+	vm, ok := r.vms[id]
+	if !ok || !tenancy.Allows(ctx, vm.ProjectID) {
+		return nil, errors.NotFoundError("VM", id.String())
+	}
+	copied := *vm
+	return &copied, nil
+}
+
+func (r *countingVMRepository) List(ctx context.Context, opts models.VMListOptions) (*models.VMPage, error) {
+	r.lists++
+	var vms []*models.VM
+	for _, vm := range r.vms {
+		if tenancy.Allows(ctx, vm.ProjectID) && opts.Matches(vm) {
+			copied := *vm
+			vms = append(vms, &copied)
+		}
+	}
+	total := int64(len(vms))
+	return &models.VMPage{VMs: vms, Total: &total}, nil
+}
+
+func (r *countingVMRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from models.VMStatus, version int64, to models.VMStatus) error {
+	vm := r.vms[id]
+	if vm.Status != from || vm.ResourceVersion != version {
+		return errors.VersionConflictError("VM", id.String(), version)
+	}
+	vm.Status = to
+	vm.ResourceVersion++
+	return nil
+}
+
+func (r *countingVMRepository) UpdateStats(ctx context.Context, id uuid.UUID, stats models.VMStats) error {
+	r.vms[id].Stats = stats
+	return nil
+}
+
+func newRedisClient(t *testing.T) (*redis.Client, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	cfg := &config.RedisConfig{Host: server.Host(), Timeout: time.Second}
+	_, err := fmt.Sscanf(server.Port(), "%d", &cfg.Port)
+	require.NoError(t, err)
+	client := cache.NewRedisClient(cfg)
+	t.Cleanup(func() { client.Close() })
+	return client, server
+}
+
+func newCachedVMRepository(t *testing.T) (repositories.VMRepository, *countingVMRepository, *miniredis.Miniredis, *models.VM) {
+	client, server := newRedisClient(t)
+
+	vm := &models.VM{
+		ID:        uuid.New(),
+		ProjectID: uuid.New(),
+		Name:      "web-01",
+		Spec:      models.VMSpec{CPUCores: 2, RAMMb: 2048, DiskGb: 50, ImageName: "ubuntu:22.04"},
+		Status:    models.VMStatusStopped,
+	}
+	inner := &countingVMRepository{vms: map[uuid.UUID]*models.VM{vm.ID: vm}}
+	return repositories.NewCachedVMRepository(inner, client, time.Minute), inner, server, vm
+}
+
+func TestCachedVMRepositoryGetByID(t *testing.T) {
+	ctx := context.Background()
+	repo, inner, _, vm := newCachedVMRepository(t)
+
+	for i := 0; i < 3; i++ {
+		cached, err := repo.GetByID(ctx, vm.ID)
+		require.NoError(t, err)
+		assert.Equal(t, vm.Name, cached.Name)
+	}
+	assert.Equal(t, 1, inner.gets)
+
+	// Status changes and stats writes invalidate the VM
+	require.NoError(t, repo.UpdateStatus(ctx, vm.ID, models.VMStatusStopped, vm.ResourceVersion, models.VMStatusRunning))
+	cached, err := repo.GetByID(ctx, vm.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.VMStatusRunning, cached.Status)
+	assert.Equal(t, 2, inner.gets)
+
+	require.NoError(t, repo.UpdateStats(ctx, vm.ID, models.VMStats{CPUUsagePercent: 42}))
+	cached, err = repo.GetByID(ctx, vm.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 42.0, cached.Stats.CPUUsagePercent)
+	assert.Equal(t, 3, inner.gets)
+
+	// Cached VMs stay hidden from callers outside their project
+	_, err = repo.GetByID(tenancy.WithProjects(ctx, []uuid.UUID{uuid.New()}), vm.ID)
+	assert.Equal(t, http.StatusNotFound, errors.GetHTTPCode(err))
+	_, err = repo.GetByID(tenancy.WithProjects(ctx, []uuid.UUID{vm.ProjectID}), vm.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, inner.gets)
+}
+
+func TestCachedVMRepositoryList(t *testing.T) {
+	ctx := context.Background()
+	repo, inner, server, vm := newCachedVMRepository(t)
+	opts := models.VMListOptions{Page: 1, Limit: 20}
+
+	for i := 0; i < 2; i++ {
+		page, err := repo.List(ctx, opts)
+		require.NoError(t, err)
+		require.NotNil(t, page.Total)
+		assert.Equal(t, int64(1), *page.Total)
+		assert.Len(t, page.VMs, 1)
+	}
+	assert.Equal(t, 1, inner.lists)
+
+	// Pages are cached per filter and per project scope
+	page, err := repo.List(ctx, models.VMListOptions{Page: 1, Limit: 20, Status: models.VMStatusRunning})
+	require.NoError(t, err)
+	assert.Empty(t, page.VMs)
+	page, err = repo.List(tenancy.WithProjects(ctx, []uuid.UUID{uuid.New()}), opts)
+	require.NoError(t, err)
+	assert.Empty(t, page.VMs)
+	assert.Equal(t, 3, inner.lists)
+
+	// Any write invalidates every page
+	require.NoError(t, repo.UpdateStatus(ctx, vm.ID, models.VMStatusStopped, vm.ResourceVersion, models.VMStatusRunning))
+	page, err = repo.List(ctx, models.VMListOptions{Page: 1, Limit: 20, Status: models.VMStatusRunning})
+	require.NoError(t, err)
+	assert.Len(t, page.VMs, 1)
+	assert.Equal(t, 4, inner.lists)
+
+	// Reads fall back to the database while Redis is down
+	server.Close()
+	_, err = repo.List(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 5, inner.lists)
+}
+
+func TestCachedVMRepositoryInvalidatesAfterCommit(t *testing.T) {
+	ctx := context.Background()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "vms.db")+"?_journal_mode=WAL"), &gorm.Config{
+		Logger: gormLogger.Default.LogMode(gormLogger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.VM{}, &models.Project{}, &models.Quota{}, &models.Volume{}, &models.NetworkInterface{}))
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	client, _ := newRedisClient(t)
+	repo := repositories.NewCachedVMRepository(repositories.NewVMRepository(db), client, time.Minute)
+	quotaRepo := repositories.NewQuotaRepository(db)
+
+	project := &models.Project{Name: "team-a"}
+	require.NoError(t, db.Create(project).Error)
+	vm := &models.VM{
+		ProjectID: project.ID,
+		Name:      "web-01",
+		Spec:      models.VMSpec{CPUCores: 2, RAMMb: 2048, DiskGb: 50, ImageName: "ubuntu:22.04"},
+		Status:    models.VMStatusStopped,
+	}
+	require.NoError(t, repo.Create(ctx, vm))
+
+	err = quotaRepo.WithLockedUsage(ctx, project.ID, func(txCtx context.Context, _ *models.Quota, _ *models.QuotaUsage) error {
+		vm.Description = "updated"
+		if err := repo.Update(txCtx, vm); err != nil {
+			return err
+		}
+
+		// A reader outside the transaction still sees and caches the committed VM
+		outside, err := repo.GetByID(ctx, vm.ID)
+		require.NoError(t, err)
+		assert.Empty(t, outside.Description)
+		return nil
+	})
+	require.NoError(t, err)
+
+	fetched, err := repo.GetByID(ctx, vm.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "updated", fetched.Description)
+
+	// Rolled back writes leave the cache alone
+	err = quotaRepo.WithLockedUsage(ctx, project.ID, func(txCtx context.Context, _ *models.Quota, _ *models.QuotaUsage) error {
+		vm.Description = "rolled back"
+		if err := repo.Update(txCtx, vm); err != nil {
+			return err
+		}
+		return errors.ErrInternalServer
+	})
+	require.Error(t, err)
+	fetched, err = repo.GetByID(ctx, vm.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "updated", fetched.Description)
+}
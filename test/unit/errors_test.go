@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppErrorWithContextLeavesSharedErrorUnchanged(t *testing.T) {
+	denied := errors.PermissionDeniedError("vm:delete")
+	locked := errors.ErrResourceLocked.WithContext("snapshot_id", "1234").WithDetails("Snapshot in progress")
+
+	assert.Equal(t, "vm:delete", denied.Context["permission"])
+	assert.Equal(t, "1234", locked.Context["snapshot_id"])
+	assert.Empty(t, errors.ErrInsufficientPerm.Context)
+	assert.Empty(t, errors.ErrInsufficientPerm.Details)
+	assert.Empty(t, errors.ErrResourceLocked.Context)
+	assert.Empty(t, errors.ErrResourceLocked.Details)
+	assert.True(t, errors.Is(locked, errors.ErrResourceLocked))
+
+	// Adding context to a derived error does not leak into the error it was derived from
+	withRequest := denied.WithContext("request_id", "req-1")
+	assert.Equal(t, "req-1", withRequest.Context["request_id"])
+	assert.NotContains(t, denied.Context, "request_id")
+}
+
+func TestAppErrorWithContextConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	results := make([]*errors.AppError, 64)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = errors.PermissionDeniedError(fmt.Sprintf("perm:%d", i)).
+				WithContext("request_id", fmt.Sprintf("req-%d", i))
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range results {
+		assert.Equal(t, fmt.Sprintf("perm:%d", i), err.Context["permission"])
+		assert.Equal(t, fmt.Sprintf("req-%d", i), err.Context["request_id"])
+	}
+}
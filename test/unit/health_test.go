@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stackit/enterprise-vm-manager/internal/cache"
+	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/internal/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthCheckerReportsEachCheck(t *testing.T) {
+	checker := health.NewChecker(time.Second)
+	checker.Register("database", func(ctx context.Context) error { return nil })
+	checker.Register("redis", func(ctx context.Context) error { return fmt.Errorf("connection refused") })
+
+	report := checker.Run(context.Background())
+
+	assert.False(t, report.Healthy)
+	assert.Equal(t, health.StatusOK, report.Checks["database"].Status)
+	assert.Equal(t, health.StatusFail, report.Checks["redis"].Status)
+	assert.Equal(t, "connection refused", report.Checks["redis"].Error)
+}
+
+func TestHealthCheckerTimesOutSlowChecks(t *testing.T) {
+	checker := health.NewChecker(20 * time.Millisecond)
+	checker.Register("database", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := checker.Run(context.Background())
+
+	assert.False(t, report.Healthy)
+	assert.GreaterOrEqual(t, report.Checks["database"].LatencyMs, float64(20))
+}
+
+func TestRedisHealthCheck(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	cfg := &config.RedisConfig{Host: server.Host(), Timeout: time.Second}
+	_, err := fmt.Sscanf(server.Port(), "%d", &cfg.Port)
+	require.NoError(t, err)
+
+	client := cache.NewRedisClient(cfg)
+	defer client.Close()
+
+	assert.NoError(t, cache.HealthCheck(context.Background(), client))
+
+	server.Close()
+	assert.Error(t, cache.HealthCheck(context.Background(), client))
+}
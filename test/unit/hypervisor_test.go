@@ -0,0 +1,232 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/internal/hypervisor"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeDriverLifecycle(t *testing.T) {
+	ctx := context.Background()
+	driver := hypervisor.NewFakeDriver(0)
+	vm := &models.VM{ID: uuid.New(), Spec: models.VMSpec{CPUCores: 2, RAMMb: 2048, DiskGb: 20}}
+
+	assert.NoError(t, driver.Provision(ctx, vm, &hypervisor.BaseImage{Reference: "ubuntu:22.04"}))
+	assert.Error(t, driver.Provision(ctx, vm, &hypervisor.BaseImage{Reference: "ubuntu:22.04"}))
+
+	// Stats are only available while running
+	_, err := driver.Stats(ctx, vm)
+	assert.Error(t, err)
+
+	assert.NoError(t, driver.Start(ctx, vm))
+	assert.Error(t, driver.Start(ctx, vm))
+	assert.Error(t, driver.Destroy(ctx, vm))
+
+	stats, err := driver.Stats(ctx, vm)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, stats.CPUUsagePercent, 10.0)
+	assert.Less(t, stats.CPUUsagePercent, 90.0)
+
+	assert.NoError(t, driver.Suspend(ctx, vm))
+	assert.Error(t, driver.Suspend(ctx, vm))
+	assert.NoError(t, driver.Resume(ctx, vm))
+
+	assert.NoError(t, driver.Stop(ctx, vm))
+	assert.NoError(t, driver.Destroy(ctx, vm))
+}
+
+func TestFakeDriverSnapshots(t *testing.T) {
+	ctx := context.Background()
+	driver := hypervisor.NewFakeDriver(0)
+	vm := &models.VM{ID: uuid.New()}
+	disk := &models.Snapshot{ID: uuid.New(), VMID: vm.ID, Type: models.SnapshotTypeDisk}
+	memory := &models.Snapshot{ID: uuid.New(), VMID: vm.ID, Type: models.SnapshotTypeMemory}
+
+	assert.NoError(t, driver.Provision(ctx, vm, &hypervisor.BaseImage{Reference: "ubuntu:22.04"}))
+	assert.NoError(t, driver.CreateSnapshot(ctx, vm, disk))
+	assert.Error(t, driver.CreateSnapshot(ctx, vm, memory))
+
+	assert.NoError(t, driver.Start(ctx, vm))
+	assert.NoError(t, driver.CreateSnapshot(ctx, vm, memory))
+	assert.Error(t, driver.RevertSnapshot(ctx, vm, disk))
+	assert.NoError(t, driver.Stop(ctx, vm))
+
+	// Reverting to a memory snapshot leaves the VM running
+	assert.NoError(t, driver.RevertSnapshot(ctx, vm, memory))
+	_, err := driver.Stats(ctx, vm)
+	assert.NoError(t, err)
+	assert.NoError(t, driver.Stop(ctx, vm))
+
+	assert.NoError(t, driver.RevertSnapshot(ctx, vm, disk))
+	assert.NoError(t, driver.DeleteSnapshot(ctx, vm, disk))
+	assert.NoError(t, driver.DeleteSnapshot(ctx, vm, disk))
+	assert.Error(t, driver.RevertSnapshot(ctx, vm, disk))
+}
+
+func TestFakeDriverClone(t *testing.T) {
+	ctx := context.Background()
+	driver := hypervisor.NewFakeDriver(0)
+	source := &models.VM{ID: uuid.New()}
+	snapshot := &models.Snapshot{ID: uuid.New(), VMID: source.ID, Type: models.SnapshotTypeDisk}
+
+	assert.NoError(t, driver.Provision(ctx, source, &hypervisor.BaseImage{Reference: "ubuntu:22.04"}))
+	assert.Error(t, driver.Clone(ctx, source, &models.VM{ID: uuid.New()}, snapshot, models.CloneModeFull))
+
+	assert.NoError(t, driver.CreateSnapshot(ctx, source, snapshot))
+	clone := &models.VM{ID: uuid.New()}
+	assert.NoError(t, driver.Clone(ctx, source, clone, snapshot, models.CloneModeFull))
+	assert.Error(t, driver.Clone(ctx, source, clone, nil, models.CloneModeLinked))
+
+	// Clones are provisioned stopped
+	assert.NoError(t, driver.Start(ctx, clone))
+}
+
+func TestFakeDriverVolumes(t *testing.T) {
+	ctx := context.Background()
+	driver := hypervisor.NewFakeDriver(0)
+	vm := &models.VM{ID: uuid.New()}
+	other := &models.VM{ID: uuid.New()}
+	volume := &models.Volume{ID: uuid.New(), SizeGb: 10}
+
+	assert.NoError(t, driver.Provision(ctx, vm, &hypervisor.BaseImage{Reference: "ubuntu:22.04"}))
+	assert.NoError(t, driver.CreateVolume(ctx, volume))
+
+	// Volumes grow but never shrink
+	volume.SizeGb = 20
+	assert.NoError(t, driver.ResizeVolume(ctx, volume))
+	volume.SizeGb = 5
+	assert.Error(t, driver.ResizeVolume(ctx, volume))
+	volume.SizeGb = 20
+
+	assert.NoError(t, driver.AttachVolume(ctx, vm, volume))
+	assert.Error(t, driver.AttachVolume(ctx, other, volume))
+	assert.Error(t, driver.DetachVolume(ctx, other, volume))
+	assert.Error(t, driver.DeleteVolume(ctx, volume))
+
+	assert.NoError(t, driver.DetachVolume(ctx, vm, volume))
+	assert.NoError(t, driver.DeleteVolume(ctx, volume))
+}
+
+func TestFakeDriverDeterministicStats(t *testing.T) {
+	ctx := context.Background()
+	vm := &models.VM{ID: uuid.New()}
+
+	first := hypervisor.NewFakeDriver(0)
+	second := hypervisor.NewFakeDriver(0)
+	for _, d := range []*hypervisor.FakeDriver{first, second} {
+		assert.NoError(t, d.Start(ctx, vm))
+	}
+
+	a, err := first.Stats(ctx, vm)
+	assert.NoError(t, err)
+	b, err := second.Stats(ctx, vm)
+	assert.NoError(t, err)
+
+	assert.Equal(t, a.CPUUsagePercent, b.CPUUsagePercent)
+	assert.Equal(t, a.RAMUsagePercent, b.RAMUsagePercent)
+	assert.Equal(t, a.NetworkRxBytes, b.NetworkRxBytes)
+}
+
+func TestFakeDriverFailNext(t *testing.T) {
+	ctx := context.Background()
+	driver := hypervisor.NewFakeDriver(0)
+	vm := &models.VM{ID: uuid.New()}
+
+	injected := errors.New("boom")
+	driver.FailNext("start", injected)
+
+	assert.Equal(t, injected, driver.Start(ctx, vm))
+	assert.NoError(t, driver.Start(ctx, vm))
+}
+
+func TestQEMUDriverProvisionFromBaseImage(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// qemu-img records its arguments instead of creating the disk
+	args := filepath.Join(dir, "args")
+	qemuImg := filepath.Join(dir, "qemu-img")
+	require.NoError(t, os.WriteFile(qemuImg, []byte("#!/bin/sh\necho \"$@\" > "+args+"\n"), 0o755))
+	log, err := logger.New(logger.Config{Level: "error", Format: "console", Output: "stdout"})
+	require.NoError(t, err)
+	driver, err := hypervisor.NewQEMUDriver(&config.HypervisorConfig{
+		QEMUBinary:    qemuImg,
+		QEMUImgBinary: qemuImg,
+		DataDir:       filepath.Join(dir, "disks"),
+		RunDir:        filepath.Join(dir, "run"),
+	}, log)
+	require.NoError(t, err)
+
+	vm := &models.VM{ID: uuid.New(), Spec: models.VMSpec{DiskGb: 20}}
+	assert.Error(t, driver.Provision(ctx, vm, &hypervisor.BaseImage{Reference: "ubuntu:22.04"}))
+	assert.Error(t, driver.Provision(ctx, vm, &hypervisor.BaseImage{Reference: "ubuntu:22.04", Path: filepath.Join(dir, "missing.raw"), Format: models.DiskFormatRaw}))
+
+	// Raw images back the disk as raw
+	base := filepath.Join(dir, "ubuntu-22.04.raw")
+	require.NoError(t, os.WriteFile(base, make([]byte, 512), 0o644))
+	require.NoError(t, driver.Provision(ctx, vm, &hypervisor.BaseImage{Reference: "ubuntu:22.04", Path: base, Format: models.DiskFormatRaw}))
+	recorded, err := os.ReadFile(args)
+	require.NoError(t, err)
+	assert.Contains(t, strings.TrimSpace(string(recorded)), "-F raw -b "+base+" ")
+	assert.True(t, strings.HasSuffix(strings.TrimSpace(string(recorded)), " 20G"))
+}
+
+func TestQEMUDriverRemovesLinkedCloneBases(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// qemu-img creates an empty disk at its second to last argument
+	qemuImg := filepath.Join(dir, "qemu-img")
+	require.NoError(t, os.WriteFile(qemuImg, []byte("#!/bin/sh\nfor arg; do disk=$prev; prev=$arg; done\n: > \"$disk\"\n"), 0o755))
+	log, err := logger.New(logger.Config{Level: "error", Format: "console", Output: "stdout"})
+	require.NoError(t, err)
+	dataDir := filepath.Join(dir, "disks")
+	driver, err := hypervisor.NewQEMUDriver(&config.HypervisorConfig{
+		QEMUBinary:    qemuImg,
+		QEMUImgBinary: qemuImg,
+		DataDir:       dataDir,
+		RunDir:        filepath.Join(dir, "run"),
+	}, log)
+	require.NoError(t, err)
+
+	image := filepath.Join(dir, "ubuntu-22.04.qcow2")
+	require.NoError(t, os.WriteFile(image, make([]byte, 512), 0o644))
+	source := &models.VM{ID: uuid.New(), Spec: models.VMSpec{DiskGb: 20}}
+	require.NoError(t, driver.Provision(ctx, source, &hypervisor.BaseImage{Reference: "ubuntu:22.04", Path: image, Format: models.DiskFormatQCOW2}))
+
+	// Cloning the linked clone freezes its overlay into a second base backed by the first
+	clone := &models.VM{ID: uuid.New(), Spec: models.VMSpec{DiskGb: 20}}
+	require.NoError(t, driver.Clone(ctx, source, clone, nil, models.CloneModeLinked))
+	second := &models.VM{ID: uuid.New(), Spec: models.VMSpec{DiskGb: 20}}
+	require.NoError(t, driver.Clone(ctx, clone, second, nil, models.CloneModeLinked))
+
+	bases := func() []string {
+		files, err := filepath.Glob(filepath.Join(dataDir, "bases", "*.qcow2"))
+		require.NoError(t, err)
+		return files
+	}
+	assert.Len(t, bases(), 2)
+
+	// Bases are kept as long as any disk is backed by them
+	require.NoError(t, driver.Destroy(ctx, source))
+	assert.Len(t, bases(), 2)
+	require.NoError(t, driver.Destroy(ctx, second))
+	assert.Len(t, bases(), 2)
+
+	require.NoError(t, driver.Destroy(ctx, clone))
+	assert.Empty(t, bases())
+	entries, err := os.ReadDir(filepath.Join(dataDir, "bases"))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/imagestore"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImageReference(t *testing.T) {
+	tests := []struct {
+		reference string
+		name      string
+		version   string
+	}{
+		{"ubuntu:22.04", "ubuntu", "22.04"},
+		{"alpine", "alpine", "latest"},
+		{"prom/prometheus:latest", "prom/prometheus", "latest"},
+		{"registry.local:5000/base", "registry.local:5000/base", "latest"},
+		{"registry.local:5000/base:2", "registry.local:5000/base", "2"},
+	}
+	for _, tt := range tests {
+		name, version := models.ParseImageReference(tt.reference)
+		assert.Equal(t, tt.name, name, tt.reference)
+		assert.Equal(t, tt.version, version, tt.reference)
+	}
+}
+
+func TestValidateChecksum(t *testing.T) {
+	assert.NoError(t, models.ValidateChecksum("sha256:"+strings.Repeat("ab", 32)))
+	assert.NoError(t, models.ValidateChecksum("sha512:"+strings.Repeat("0f", 64)))
+
+	assert.Error(t, models.ValidateChecksum(strings.Repeat("ab", 32)))
+	assert.Error(t, models.ValidateChecksum("md5:d41d8cd98f00b204e9800998ecf8427e"))
+	assert.Error(t, models.ValidateChecksum("sha256:abc"))
+	assert.Error(t, models.ValidateChecksum("sha256:"+strings.Repeat("zz", 32)))
+}
+
+func TestImageAvailability(t *testing.T) {
+	owner := uuid.New()
+	public := &models.Image{Visibility: models.ImageVisibilityPublic}
+	private := &models.Image{Visibility: models.ImageVisibilityPrivate, ProjectID: &owner}
+
+	assert.True(t, public.AvailableTo(uuid.New()))
+	assert.True(t, private.AvailableTo(owner))
+	assert.False(t, private.AvailableTo(uuid.New()))
+
+	private.Deprecate("golden:2")
+	assert.True(t, private.IsDeprecated())
+	assert.NotNil(t, private.DeprecatedAt)
+	private.Undeprecate()
+	assert.False(t, private.IsDeprecated())
+	assert.Empty(t, private.Replacement)
+}
+
+func TestImageStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := imagestore.New(dir, 16)
+	require.NoError(t, err)
+	id := uuid.New()
+
+	// An interrupted upload keeps what it received
+	offset, err := store.Write(id, 0, strings.NewReader("0123"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), offset)
+	_, err = store.Write(id, 2, strings.NewReader("23"))
+	assert.ErrorIs(t, err, imagestore.ErrOffset)
+	offset, err = store.Write(id, 4, strings.NewReader("4567"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), offset)
+
+	file, err := store.Finish(id, "sha256", func(*imagestore.File) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, id.String()+".raw"), file.Path)
+	assert.Equal(t, models.DiskFormatRaw, file.Format)
+	assert.Equal(t, int64(8), file.Size)
+	assert.NoError(t, models.ValidateChecksum(file.Checksum))
+
+	// Uploads beyond the size limit are discarded
+	_, err = store.Write(id, 0, strings.NewReader(strings.Repeat("x", 17)))
+	assert.ErrorIs(t, err, imagestore.ErrTooLarge)
+	offset, err = store.Offset(id)
+	require.NoError(t, err)
+	assert.Zero(t, offset)
+
+	require.NoError(t, store.Remove(id, models.DiskFormatRaw))
+	_, err = store.Open(id, models.DiskFormatRaw)
+	assert.Error(t, err)
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stackit/enterprise-vm-manager/pkg/jsonpatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePatch(t *testing.T) {
+	doc := `{"name": "web", "labels": {"tier": "web", "env": "dev"}, "tags": ["a", "b"]}`
+
+	patched, err := jsonpatch.MergePatch([]byte(doc), []byte(`{"labels": {"env": "prod", "tier": null}, "tags": ["c"], "cpu": 2}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": "web", "labels": {"env": "prod"}, "tags": ["c"], "cpu": 2}`, string(patched))
+
+	patched, err = jsonpatch.MergePatch([]byte(doc), []byte(`{"labels": null}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": "web", "tags": ["a", "b"]}`, string(patched))
+
+	_, err = jsonpatch.MergePatch([]byte(doc), []byte(`{"labels":`))
+	var syntaxErr *jsonpatch.SyntaxError
+	assert.ErrorAs(t, err, &syntaxErr)
+}
+
+func TestJSONPatch(t *testing.T) {
+	doc := `{"name": "web", "labels": {"a/b": "1", "c~d": "2"}, "tags": ["x", "y"]}`
+
+	tests := []struct {
+		name     string
+		patch    string
+		expected string
+	}{
+		{"add member", `[{"op": "add", "path": "/cpu", "value": 4}]`,
+			`{"name": "web", "labels": {"a/b": "1", "c~d": "2"}, "tags": ["x", "y"], "cpu": 4}`},
+		{"escaped pointers", `[{"op": "remove", "path": "/labels/a~1b"}, {"op": "replace", "path": "/labels/c~0d", "value": "3"}]`,
+			`{"name": "web", "labels": {"c~d": "3"}, "tags": ["x", "y"]}`},
+		{"insert and append", `[{"op": "add", "path": "/tags/0", "value": "w"}, {"op": "add", "path": "/tags/-", "value": "z"}]`,
+			`{"name": "web", "labels": {"a/b": "1", "c~d": "2"}, "tags": ["w", "x", "y", "z"]}`},
+		{"move and copy", `[{"op": "move", "from": "/name", "path": "/labels/name"}, {"op": "copy", "from": "/tags/1", "path": "/tags/0"}]`,
+			`{"labels": {"a/b": "1", "c~d": "2", "name": "web"}, "tags": ["y", "x", "y"]}`},
+		{"test", `[{"op": "test", "path": "/tags", "value": ["x", "y"]}, {"op": "remove", "path": "/tags/0"}]`,
+			`{"name": "web", "labels": {"a/b": "1", "c~d": "2"}, "tags": ["y"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patched, err := jsonpatch.Apply([]byte(doc), []byte(tt.patch))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(patched))
+		})
+	}
+}
+
+func TestJSONPatchErrors(t *testing.T) {
+	doc := []byte(`{"name": "web", "tags": ["x"]}`)
+
+	failures := map[string]string{
+		"missing member":   `[{"op": "remove", "path": "/labels"}]`,
+		"replace missing":  `[{"op": "replace", "path": "/cpu", "value": 1}]`,
+		"index too large":  `[{"op": "add", "path": "/tags/2", "value": "z"}]`,
+		"leading zero":     `[{"op": "remove", "path": "/tags/00"}]`,
+		"failed test":      `[{"op": "test", "path": "/name", "value": "db"}]`,
+		"move into child":  `[{"op": "move", "from": "/tags", "path": "/tags/0"}]`,
+		"remove document":  `[{"op": "remove", "path": ""}]`,
+		"member of string": `[{"op": "add", "path": "/name/first", "value": "a"}]`,
+	}
+	for name, patch := range failures {
+		t.Run(name, func(t *testing.T) {
+			_, err := jsonpatch.Apply(doc, []byte(patch))
+			assert.Error(t, err)
+		})
+	}
+
+	invalid := map[string]string{
+		"not an array":      `{"op": "add"}`,
+		"unknown operation": `[{"op": "merge", "path": "/name"}]`,
+		"missing value":     `[{"op": "add", "path": "/cpu"}]`,
+		"relative pointer":  `[{"op": "remove", "path": "name"}]`,
+	}
+	for name, patch := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := jsonpatch.Apply(doc, []byte(patch))
+			var syntaxErr *jsonpatch.SyntaxError
+			assert.ErrorAs(t, err, &syntaxErr)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stackit/enterprise-vm-manager/internal/labels"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	selector, err := labels.Parse("env=prod, tier in (web, api),!deprecated,team/owner,stage!=dev,zone notin (a,b),app==shop")
+	require.NoError(t, err)
+	assert.Equal(t, labels.Selector{
+		{Key: "env", Operator: labels.Equals, Values: []string{"prod"}},
+		{Key: "tier", Operator: labels.In, Values: []string{"web", "api"}},
+		{Key: "deprecated", Operator: labels.DoesNotExist},
+		{Key: "team/owner", Operator: labels.Exists},
+		{Key: "stage", Operator: labels.NotEquals, Values: []string{"dev"}},
+		{Key: "zone", Operator: labels.NotIn, Values: []string{"a", "b"}},
+		{Key: "app", Operator: labels.Equals, Values: []string{"shop"}},
+	}, selector)
+
+	empty, err := labels.Parse("")
+	require.NoError(t, err)
+	assert.True(t, empty.Matches(nil))
+
+	for _, invalid := range []string{"env=prod,", "=prod", "tier in (web", "tier in (web,(api))", "!", "env=a b", "a,,b"} {
+		_, err := labels.Parse(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestLabelSelectorMatches(t *testing.T) {
+	selector, err := labels.Parse("env=prod,tier in (web,api),!deprecated,zone!=eu")
+	require.NoError(t, err)
+
+	assert.True(t, selector.Matches(map[string]string{"env": "prod", "tier": "web"}))
+	assert.True(t, selector.Matches(map[string]string{"env": "prod", "tier": "api", "zone": "us"}))
+	assert.False(t, selector.Matches(map[string]string{"env": "dev", "tier": "web"}))
+	assert.False(t, selector.Matches(map[string]string{"env": "prod", "tier": "db"}))
+	assert.False(t, selector.Matches(map[string]string{"env": "prod", "tier": "web", "deprecated": "true"}))
+	assert.False(t, selector.Matches(map[string]string{"env": "prod", "tier": "web", "zone": "eu"}))
+	assert.False(t, selector.Matches(nil))
+}
+
+func TestVMListOptionsMatchLabels(t *testing.T) {
+	vm := &models.VM{Name: "web-1"}
+	require.NoError(t, vm.AddLabel("env", "prod"))
+	require.NoError(t, vm.AddLabel("tier", "web"))
+
+	assert.True(t, models.VMListOptions{LabelSelector: "env=prod,tier in (web,api)"}.Matches(vm))
+	assert.False(t, models.VMListOptions{LabelSelector: "env=prod,!tier"}.Matches(vm))
+	assert.False(t, models.VMListOptions{LabelSelector: "env in (prod"}.Matches(vm))
+}
@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/internal/database"
+	"github.com/stackit/enterprise-vm-manager/internal/metrics"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeVMCounter struct{}
+
+func (fakeVMCounter) CountByNodeAndStatus(ctx context.Context) ([]*models.VMCount, error) {
+	return []*models.VMCount{
+		{NodeID: "node-01", Status: models.VMStatusRunning, Count: 3},
+		{NodeID: "node-02", Status: models.VMStatusStopped, Count: 1},
+	}, nil
+}
+
+type fakeDatabaseStats struct{}
+
+func (fakeDatabaseStats) GetStats() (*database.DatabaseStats, error) {
+	return &database.DatabaseStats{OpenConnections: 5, InUse: 2, Idle: 3, WaitDuration: 2 * time.Second}, nil
+}
+
+func newTestMetrics(t *testing.T) *metrics.Metrics {
+	log, err := logger.New(logger.Config{Level: "error", Format: "console", Output: "stdout"})
+	require.NoError(t, err)
+	return metrics.New(config.MetricsConfig{Namespace: "vm_manager", Subsystem: "api"}, log)
+}
+
+// gatherValues returns the values of a metric family keyed by the joined label values
+func gatherValues(t *testing.T, m *metrics.Metrics, name string) map[string]float64 {
+	families, err := m.Registry().Gather()
+	require.NoError(t, err)
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			key := ""
+			for _, label := range metric.GetLabel() {
+				key += label.GetName() + "=" + label.GetValue() + ","
+			}
+			switch {
+			case metric.GetCounter() != nil:
+				values[key] = metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				values[key] = metric.GetGauge().GetValue()
+			case metric.GetHistogram() != nil:
+				values[key] = float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return values
+}
+
+func TestMetricsRecordRequests(t *testing.T) {
+	m := newTestMetrics(t)
+
+	m.ObserveRequest("GET", "/api/v1/vms/:id", 200, 10*time.Millisecond)
+	m.ObserveRequest("GET", "/api/v1/vms/:id", 200, 20*time.Millisecond)
+	m.ObserveRequest("POST", "/api/v1/vms", 409, 5*time.Millisecond)
+
+	requests := gatherValues(t, m, "vm_manager_api_http_requests_total")
+	assert.Equal(t, float64(2), requests["method=GET,route=/api/v1/vms/:id,status=200,"])
+	assert.Equal(t, float64(1), requests["method=POST,route=/api/v1/vms,status=409,"])
+
+	latency := gatherValues(t, m, "vm_manager_api_http_request_duration_seconds")
+	assert.Equal(t, float64(2), latency["method=GET,route=/api/v1/vms/:id,"])
+}
+
+func TestMetricsRecordOperations(t *testing.T) {
+	m := newTestMetrics(t)
+
+	succeeded := models.NewOperation(models.OperationTypeStart, uuid.New(), "test-user")
+	succeeded.Start()
+	succeeded.Succeed()
+	m.ObserveOperation(succeeded)
+
+	failed := models.NewOperation(models.OperationTypeStart, uuid.New(), "test-user")
+	failed.Fail(assert.AnError)
+	m.ObserveOperation(failed)
+
+	// Operations that are still running are not observed
+	m.ObserveOperation(models.NewOperation(models.OperationTypeStop, uuid.New(), "test-user"))
+
+	durations := gatherValues(t, m, "vm_manager_api_operation_duration_seconds")
+	assert.Equal(t, float64(1), durations["state=succeeded,type=start,"])
+	assert.Equal(t, float64(1), durations["state=failed,type=start,"])
+	assert.Len(t, durations, 2)
+
+	failures := gatherValues(t, m, "vm_manager_api_operation_failures_total")
+	assert.Equal(t, float64(1), failures["type=start,"])
+
+	// A nil collector set ignores observations
+	var disabled *metrics.Metrics
+	disabled.ObserveOperation(failed)
+	disabled.ObserveRequest("GET", "/health", 200, time.Millisecond)
+}
+
+func TestMetricsCollectInventoryAndDatabase(t *testing.T) {
+	m := newTestMetrics(t)
+	m.RegisterVMCounts(fakeVMCounter{})
+	m.RegisterDatabaseStats(fakeDatabaseStats{})
+
+	vms := gatherValues(t, m, "vm_manager_api_vms")
+	assert.Equal(t, float64(3), vms["node=node-01,status=running,"])
+	assert.Equal(t, float64(1), vms["node=node-02,status=stopped,"])
+
+	assert.Equal(t, float64(5), gatherValues(t, m, "vm_manager_api_db_open_connections")[""])
+	assert.Equal(t, float64(2), gatherValues(t, m, "vm_manager_api_db_wait_duration_seconds_total")[""])
+
+	// The handler serves the text exposition format
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `vm_manager_api_vms{node="node-01",status="running"} 3`)
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkCreateRequestDefaults(t *testing.T) {
+	req := &models.NetworkCreateRequest{Name: "frontend", CIDR: "10.0.1.0/24", ProjectID: uuid.New(), CreatedBy: "alice"}
+	network, err := req.ToNetwork()
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.1.1", network.Gateway)
+	assert.Equal(t, "10.0.1.2", network.DHCPStart)
+	assert.Equal(t, "10.0.1.254", network.DHCPEnd)
+	assert.Equal(t, models.AddressList{}, network.DNSServers)
+	assert.Equal(t, req.ProjectID, network.ProjectID)
+
+	req = &models.NetworkCreateRequest{
+		Name:       "backend",
+		CIDR:       "172.16.0.0/16",
+		Gateway:    "172.16.255.254",
+		DNSServers: []string{"1.1.1.1"},
+		DHCPStart:  "172.16.1.0",
+		DHCPEnd:    "172.16.1.255",
+	}
+	network, err = req.ToNetwork()
+	require.NoError(t, err)
+	assert.Equal(t, "172.16.255.254", network.Gateway)
+	assert.Equal(t, "172.16.1.0", network.DHCPStart)
+	assert.Equal(t, models.AddressList{"1.1.1.1"}, network.DNSServers)
+}
+
+func TestNetworkCreateRequestValidation(t *testing.T) {
+	for name, req := range map[string]models.NetworkCreateRequest{
+		"ipv6":              {CIDR: "fd00::/64"},
+		"host bits":         {CIDR: "10.0.1.5/24"},
+		"too small":         {CIDR: "10.0.1.0/30"},
+		"gateway outside":   {CIDR: "10.0.1.0/24", Gateway: "10.0.2.1"},
+		"gateway broadcast": {CIDR: "10.0.1.0/24", Gateway: "10.0.1.255"},
+		"start network":     {CIDR: "10.0.1.0/24", DHCPStart: "10.0.1.0"},
+		"range reversed":    {CIDR: "10.0.1.0/24", DHCPStart: "10.0.1.100", DHCPEnd: "10.0.1.50"},
+		"only gateway":      {CIDR: "10.0.1.0/24", DHCPStart: "10.0.1.1", DHCPEnd: "10.0.1.1"},
+	} {
+		_, err := req.ToNetwork()
+		assert.Error(t, err, name)
+	}
+}
+
+func TestNetworkFreeAddress(t *testing.T) {
+	network := &models.Network{CIDR: "10.0.1.0/29", Gateway: "10.0.1.3", DHCPStart: "10.0.1.2", DHCPEnd: "10.0.1.5"}
+
+	// The gateway is never handed out
+	address, ok := network.FreeAddress(nil)
+	require.True(t, ok)
+	assert.Equal(t, "10.0.1.2", address)
+	address, ok = network.FreeAddress([]string{"10.0.1.2"})
+	require.True(t, ok)
+	assert.Equal(t, "10.0.1.4", address)
+
+	// Released addresses are reused lowest first
+	address, ok = network.FreeAddress([]string{"10.0.1.4", "10.0.1.5"})
+	require.True(t, ok)
+	assert.Equal(t, "10.0.1.2", address)
+
+	_, ok = network.FreeAddress([]string{"10.0.1.2", "10.0.1.4", "10.0.1.5"})
+	assert.False(t, ok)
+
+	iface := network.NewInterface(uuid.New(), 1, "10.0.1.2", "52:54:00:00:00:01")
+	assert.Equal(t, 29, iface.PrefixLength)
+	assert.Equal(t, "10.0.1.3", iface.Gateway)
+}
+
+func TestNewMACAddress(t *testing.T) {
+	pattern := regexp.MustCompile(`^52:54:00(:[0-9a-f]{2}){3}$`)
+	seen := make(map[string]bool)
+	for i := 0; i < 16; i++ {
+		mac, err := models.NewMACAddress()
+		require.NoError(t, err)
+		assert.Regexp(t, pattern, mac)
+		seen[mac] = true
+	}
+	assert.Greater(t, len(seen), 1)
+}
+
+func TestVMIPAddresses(t *testing.T) {
+	vm := &models.VM{ID: uuid.New()}
+	assert.Equal(t, []string{}, vm.IPAddresses())
+	assert.Equal(t, []string{}, models.NewVMResponse(vm).IPAddresses)
+
+	vm.Interfaces = []*models.NetworkInterface{
+		{Network: "frontend", DeviceIndex: 0, IPAddress: "10.0.1.2"},
+		{Network: "backend", DeviceIndex: 1, IPAddress: "10.0.2.7"},
+	}
+	assert.Equal(t, []string{"10.0.1.2", "10.0.2.7"}, vm.IPAddresses())
+	assert.Equal(t, []string{"frontend", "backend"}, vm.NetworkNames())
+}
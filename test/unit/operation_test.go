@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationLifecycle(t *testing.T) {
+	op := models.NewOperation(models.OperationTypeStart, uuid.New(), "test-user")
+	assert.Equal(t, models.OperationStatePending, op.State)
+	assert.False(t, op.IsDone())
+	assert.Nil(t, op.StartedAt)
+
+	op.Start()
+	assert.Equal(t, models.OperationStateRunning, op.State)
+	assert.NotNil(t, op.StartedAt)
+
+	op.SetProgress(150)
+	assert.Equal(t, 100, op.Progress)
+	op.SetProgress(40)
+	assert.Equal(t, 40, op.Progress)
+
+	op.Succeed()
+	assert.True(t, op.IsDone())
+	assert.Equal(t, models.OperationStateSucceeded, op.State)
+	assert.Equal(t, 100, op.Progress)
+	assert.NotNil(t, op.CompletedAt)
+
+	// Final states are not changed afterwards
+	op.Fail(errors.New("late failure"))
+	assert.Equal(t, models.OperationStateSucceeded, op.State)
+	assert.Empty(t, op.Error)
+}
+
+func TestOperationFailure(t *testing.T) {
+	op := models.NewOperation(models.OperationTypeStop, uuid.New(), "test-user")
+	op.Start()
+	op.SetProgress(10)
+
+	op.Fail(errors.New("guest did not shut down"))
+	assert.True(t, op.IsDone())
+	assert.Equal(t, models.OperationStateFailed, op.State)
+	assert.Equal(t, "guest did not shut down", op.Error)
+	assert.Equal(t, 10, op.Progress)
+	assert.NotNil(t, op.CompletedAt)
+}
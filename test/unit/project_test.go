@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/api/middleware"
+	"github.com/stackit/enterprise-vm-manager/internal/auth"
+	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/tenancy"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryProjectResolver resolves projects and memberships kept in memory
+type memoryProjectResolver struct {
+	projects map[string]*models.Project
+	members  map[string][]string
+}
+
+func (r *memoryProjectResolver) GetProject(ctx context.Context, name string) (*models.Project, error) {
+	project, ok := r.projects[name]
+	if !ok {
+		return nil, errors.NotFoundError("Project", name)
+	}
+	return project, nil
+}
+
+func (r *memoryProjectResolver) ProjectIDsForSubject(ctx context.Context, subject string, includeDefault bool) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if includeDefault {
+		ids = append(ids, r.projects[models.DefaultProjectName].ID)
+	}
+	for _, name := range r.members[subject] {
+		ids = append(ids, r.projects[name].ID)
+	}
+	return ids, nil
+}
+
+func TestTenancyScope(t *testing.T) {
+	teamA, teamB := uuid.New(), uuid.New()
+
+	ctx := context.Background()
+	_, restricted := tenancy.Projects(ctx)
+	assert.False(t, restricted)
+	assert.True(t, tenancy.Allows(ctx, teamA))
+
+	ctx = tenancy.WithProjects(ctx, []uuid.UUID{teamA})
+	scope, restricted := tenancy.Projects(ctx)
+	assert.True(t, restricted)
+	assert.Equal(t, []uuid.UUID{teamA}, scope)
+	assert.True(t, tenancy.Allows(ctx, teamA))
+	assert.False(t, tenancy.Allows(ctx, teamB))
+
+	// An empty scope grants access to nothing
+	assert.False(t, tenancy.Allows(tenancy.WithProjects(context.Background(), nil), teamA))
+}
+
+func TestProjectScopeMiddleware(t *testing.T) {
+	log, err := logger.New(logger.Config{Level: "error", Format: "console", Output: "stdout"})
+	require.NoError(t, err)
+
+	resolver := &memoryProjectResolver{
+		projects: map[string]*models.Project{
+			models.DefaultProjectName: {ID: uuid.New(), Name: models.DefaultProjectName},
+			"team-a":                  {ID: uuid.New(), Name: "team-a"},
+			"team-b":                  {ID: uuid.New(), Name: "team-b"},
+		},
+		members: map[string][]string{"alice": {models.DefaultProjectName, "team-a"}, "carol": {"team-a"}},
+	}
+
+	cfg := &config.Config{Auth: testAuthConfig()}
+	authenticator, err := auth.NewAuthenticator(cfg.Auth)
+	require.NoError(t, err)
+	mw := middleware.NewMiddlewareManager(cfg, log, nil, authenticator, newTestRBACService(t), resolver, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(requestid.New())
+	api := router.Group("/api/v1", mw.AuthenticationMiddleware())
+	scope := func(c *gin.Context) {
+		projectIDs, restricted := tenancy.Projects(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{
+			"project_id": middleware.GetProjectID(c),
+			"projects":   projectIDs,
+			"restricted": restricted,
+		})
+	}
+	api.GET("/vms", mw.ProjectScopeMiddleware(), scope)
+	api.GET("/projects/:project/vms", mw.ProjectScopeMiddleware(), scope)
+
+	type result struct {
+		ProjectID  uuid.UUID   `json:"project_id"`
+		Projects   []uuid.UUID `json:"projects"`
+		Restricted bool        `json:"restricted"`
+	}
+	call := func(path, subject string, roles ...string) (int, result) {
+		token, _, err := authenticator.IssueToken(&auth.Identity{Subject: subject, Roles: roles})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var r result
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &r))
+		}
+		return w.Code, r
+	}
+
+	defaultID := resolver.projects[models.DefaultProjectName].ID
+	teamA := resolver.projects["team-a"].ID
+	teamB := resolver.projects["team-b"].ID
+
+	// Outside a project members see the default project and their own projects
+	code, r := call("/api/v1/vms", "alice", models.RoleViewer)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, defaultID, r.ProjectID)
+	assert.True(t, r.Restricted)
+	assert.ElementsMatch(t, []uuid.UUID{defaultID, teamA}, r.Projects)
+
+	// Project routes are scoped to the project of the route
+	code, r = call("/api/v1/projects/team-a/vms", "alice", models.RoleViewer)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, teamA, r.ProjectID)
+	assert.Equal(t, []uuid.UUID{teamA}, r.Projects)
+
+	// Projects of other teams look like missing projects
+	code, _ = call("/api/v1/projects/team-b/vms", "alice", models.RoleViewer)
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = call("/api/v1/projects/team-a/vms", "bob", models.RoleOperator)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// Routes outside a project belong to the default project, which needs membership
+	code, _ = call("/api/v1/vms", "carol", models.RoleViewer)
+	assert.Equal(t, http.StatusNotFound, code)
+	code, r = call("/api/v1/projects/team-a/vms", "carol", models.RoleViewer)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uuid.UUID{teamA}, r.Projects)
+
+	// unless every caller is made a member of it
+	cfg.Auth.OpenDefaultProject = true
+	code, r = call("/api/v1/vms", "carol", models.RoleViewer)
+	require.Equal(t, http.StatusOK, code)
+	assert.ElementsMatch(t, []uuid.UUID{defaultID, teamA}, r.Projects)
+	cfg.Auth.OpenDefaultProject = false
+
+	// Administrators are only restricted by the project of the route
+	code, r = call("/api/v1/vms", "root", models.RoleAdmin)
+	require.Equal(t, http.StatusOK, code)
+	assert.False(t, r.Restricted)
+	code, r = call("/api/v1/projects/team-b/vms", "root", models.RoleAdmin)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []uuid.UUID{teamB}, r.Projects)
+}
@@ -0,0 +1,31 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaLimitsExceeded(t *testing.T) {
+	limits := models.QuotaLimits{MaxVMs: 3, MaxCPUCores: 8}
+	usage := &models.QuotaUsage{VMs: 2, RunningVMs: 5, CPUCores: 6, RAMMb: 8192}
+
+	assert.Nil(t, limits.Exceeded(usage, models.QuotaRequest{VMs: 1, CPUCores: 2, RAMMb: 4096}))
+
+	violation := limits.Exceeded(usage, models.QuotaRequest{VMs: 1, CPUCores: 4})
+	require.NotNil(t, violation)
+	assert.Equal(t, models.QuotaViolation{
+		Dimension: models.QuotaDimensionCPUCores,
+		Limit:     8,
+		Used:      6,
+		Requested: 4,
+	}, *violation)
+
+	// Unlimited dimensions and shrinking requests never exceed a quota
+	assert.Nil(t, limits.Exceeded(usage, models.QuotaRequest{RunningVMs: 1}))
+	over := &models.QuotaUsage{VMs: 5, CPUCores: 12}
+	assert.Nil(t, limits.Exceeded(over, models.QuotaRequest{CPUCores: -2}))
+	assert.Equal(t, models.QuotaDimensionVMs, limits.Exceeded(over, models.QuotaRequest{VMs: 1}).Dimension)
+}
@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/stackit/enterprise-vm-manager/internal/api/middleware"
+	"github.com/stackit/enterprise-vm-manager/internal/auth"
+	"github.com/stackit/enterprise-vm-manager/internal/cache"
+	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/internal/ratelimit"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLimiter(t *testing.T) {
+	ctx := context.Background()
+	limiter := ratelimit.NewMemoryLimiter(time.Minute)
+	limit := ratelimit.Limit{RPS: 1, Burst: 2}
+
+	for i := 0; i < 2; i++ {
+		result, err := limiter.Allow(ctx, "ip:10.0.0.1", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 1-i, result.Remaining)
+	}
+
+	result, err := limiter.Allow(ctx, "ip:10.0.0.1", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 2, result.Limit)
+	assert.InDelta(t, time.Second, result.RetryAfter, float64(100*time.Millisecond))
+
+	// Other clients have their own bucket
+	result, err = limiter.Allow(ctx, "ip:10.0.0.2", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+}
+
+func TestRedisLimiterSharesBuckets(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	now := time.Now()
+	server.SetTime(now)
+
+	cfg := &config.RedisConfig{Host: server.Host(), Timeout: time.Second}
+	_, err := fmt.Sscanf(server.Port(), "%d", &cfg.Port)
+	require.NoError(t, err)
+	client := cache.NewRedisClient(cfg)
+	defer client.Close()
+
+	// Two limiters stand in for two replicas of the API
+	replicaA := ratelimit.NewRedisLimiter(client)
+	replicaB := ratelimit.NewRedisLimiter(client)
+	limit := ratelimit.Limit{RPS: 2, Burst: 2}
+
+	result, err := replicaA.Allow(ctx, "user:alice", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	result, err = replicaB.Allow(ctx, "user:alice", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	result, err = replicaA.Allow(ctx, "user:alice", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+	assert.Equal(t, time.Second, result.ResetAfter)
+
+	server.SetTime(now.Add(500 * time.Millisecond))
+	result, err = replicaB.Allow(ctx, "user:alice", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	// Buckets expire once they could have refilled
+	server.FastForward(3 * time.Second)
+	assert.False(t, server.Exists("ratelimit:user:alice"))
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	log, err := logger.New(logger.Config{Level: "error", Format: "console", Output: "stdout"})
+	require.NoError(t, err)
+
+	cfg := &config.Config{Auth: testAuthConfig()}
+	cfg.Server.RateLimit = config.RateLimitConfig{
+		Enabled: true,
+		RPS:     0.5,
+		Burst:   1,
+		Cleanup: time.Minute,
+		Tiers:   map[string]config.RateLimitTier{"operator": {RPS: 1, Burst: 3}},
+	}
+	authenticator, err := auth.NewAuthenticator(cfg.Auth)
+	require.NoError(t, err)
+	mw := middleware.NewMiddlewareManager(cfg, log, nil, authenticator, nil, nil, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(requestid.New(), mw.RateLimitMiddleware())
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	call := func(ip, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.RemoteAddr = ip + ":12345"
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Anonymous clients are limited per IP
+	w := call("10.0.0.1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Reset"))
+
+	w = call("10.0.0.1", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusOK, call("10.0.0.2", "").Code)
+
+	// API clients get the tier of their roles, independent of their IP
+	for i := 0; i < 3; i++ {
+		w = call(fmt.Sprintf("10.0.1.%d", i), "alice-key")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "3", w.Header().Get("RateLimit-Limit"))
+	}
+	w = call("10.0.1.9", "alice-key")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+}
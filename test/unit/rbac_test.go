@@ -0,0 +1,223 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/api/middleware"
+	"github.com/stackit/enterprise-vm-manager/internal/auth"
+	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/services"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stackit/enterprise-vm-manager/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRoleRepository keeps custom roles in memory
+type memoryRoleRepository struct {
+	roles map[string]*models.Role
+}
+
+func (r *memoryRoleRepository) Create(ctx context.Context, role *models.Role) error {
+	if _, exists := r.roles[role.Name]; exists {
+		return errors.AlreadyExistsError("Role", role.Name)
+	}
+	r.roles[role.Name] = role
+	return nil
+}
+
+func (r *memoryRoleRepository) GetByName(ctx context.Context, name string) (*models.Role, error) {
+	role, ok := r.roles[name]
+	if !ok {
+		return nil, errors.NotFoundError("Role", name)
+	}
+	return role, nil
+}
+
+func (r *memoryRoleRepository) ListByNames(ctx context.Context, names []string) ([]*models.Role, error) {
+	var roles []*models.Role
+	for _, name := range names {
+		if role, ok := r.roles[name]; ok {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+func (r *memoryRoleRepository) List(ctx context.Context) ([]*models.Role, error) {
+	var roles []*models.Role
+	for _, role := range r.roles {
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+func (r *memoryRoleRepository) Update(ctx context.Context, role *models.Role) error {
+	r.roles[role.Name] = role
+	return nil
+}
+
+func (r *memoryRoleRepository) Delete(ctx context.Context, name string) error {
+	if _, ok := r.roles[name]; !ok {
+		return errors.NotFoundError("Role", name)
+	}
+	delete(r.roles, name)
+	return nil
+}
+
+// memoryRoleBindingRepository keeps role bindings in memory
+type memoryRoleBindingRepository struct {
+	bindings []*models.RoleBinding
+}
+
+func (r *memoryRoleBindingRepository) Create(ctx context.Context, binding *models.RoleBinding) error {
+	binding.ID = uuid.New()
+	r.bindings = append(r.bindings, binding)
+	return nil
+}
+
+func (r *memoryRoleBindingRepository) List(ctx context.Context, opts models.RoleBindingListOptions) ([]*models.RoleBinding, error) {
+	return r.bindings, nil
+}
+
+func (r *memoryRoleBindingRepository) RolesForSubject(ctx context.Context, subject string) ([]string, error) {
+	var roles []string
+	for _, binding := range r.bindings {
+		if binding.Subject == subject {
+			roles = append(roles, binding.Role)
+		}
+	}
+	return roles, nil
+}
+
+func (r *memoryRoleBindingRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	for i, binding := range r.bindings {
+		if binding.ID == id {
+			r.bindings = append(r.bindings[:i], r.bindings[i+1:]...)
+			return nil
+		}
+	}
+	return errors.NotFoundError("RoleBinding", id.String())
+}
+
+func newTestRBACService(t *testing.T) services.RBACService {
+	log, err := logger.New(logger.Config{Level: "error", Format: "console", Output: "stdout"})
+	require.NoError(t, err)
+
+	return services.NewRBACService(
+		&memoryRoleRepository{roles: make(map[string]*models.Role)},
+		&memoryRoleBindingRepository{},
+		log,
+	)
+}
+
+func TestBuiltinRoles(t *testing.T) {
+	viewer := models.BuiltinRole(models.RoleViewer)
+	operator := models.BuiltinRole(models.RoleOperator)
+	admin := models.BuiltinRole(models.RoleAdmin)
+
+	assert.True(t, viewer.Permissions.Grants(models.PermissionVMRead))
+	assert.False(t, viewer.Permissions.Grants(models.PermissionVMStart))
+
+	assert.True(t, operator.Permissions.Grants(models.PermissionVMRestart))
+	assert.True(t, operator.Permissions.Grants(models.PermissionStatsRead))
+	assert.False(t, operator.Permissions.Grants(models.PermissionVMDelete))
+	assert.False(t, operator.Permissions.Grants(models.PermissionVMCreate))
+	assert.True(t, operator.Permissions.Grants(models.PermissionSnapshotWrite))
+	assert.False(t, operator.Permissions.Grants(models.PermissionSnapshotRevert))
+	assert.True(t, viewer.Permissions.Grants(models.PermissionSnapshotRead))
+	assert.True(t, viewer.Permissions.Grants(models.PermissionFlavorRead))
+	assert.False(t, operator.Permissions.Grants(models.PermissionFlavorWrite))
+	assert.True(t, viewer.Permissions.Grants(models.PermissionImageRead))
+	assert.False(t, operator.Permissions.Grants(models.PermissionImageWrite))
+
+	for _, permission := range models.AllPermissions {
+		assert.True(t, admin.Permissions.Grants(permission), permission)
+	}
+	assert.Nil(t, models.BuiltinRole("on-call"))
+}
+
+func TestRBACServiceAuthorize(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestRBACService(t)
+
+	// Roles from the token
+	assert.NoError(t, svc.Authorize(ctx, "alice", []string{models.RoleOperator}, models.PermissionVMRestart))
+	err := svc.Authorize(ctx, "alice", []string{models.RoleOperator}, models.PermissionVMDelete)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, errors.GetHTTPCode(err))
+	assert.Error(t, svc.Authorize(ctx, "nobody", nil, models.PermissionVMRead))
+
+	// Custom role granted through a binding
+	_, err = svc.CreateRole(ctx, &models.RoleCreateRequest{
+		Name:        "on-call",
+		Permissions: []models.Permission{models.PermissionVMRead, models.PermissionVMRestart},
+	})
+	require.NoError(t, err)
+	_, err = svc.CreateBinding(ctx, &models.RoleBindingCreateRequest{Subject: "dave", Role: "on-call"})
+	require.NoError(t, err)
+
+	assert.NoError(t, svc.Authorize(ctx, "dave", nil, models.PermissionVMRestart))
+	assert.Error(t, svc.Authorize(ctx, "dave", nil, models.PermissionVMDelete))
+	assert.Error(t, svc.Authorize(ctx, "erin", nil, models.PermissionVMRestart))
+}
+
+func TestRBACServiceValidatesRoles(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestRBACService(t)
+
+	_, err := svc.CreateRole(ctx, &models.RoleCreateRequest{Name: "broken", Permissions: []models.Permission{"vm:explode"}})
+	assert.Equal(t, http.StatusBadRequest, errors.GetHTTPCode(err))
+
+	_, err = svc.CreateRole(ctx, &models.RoleCreateRequest{Name: models.RoleAdmin, Permissions: []models.Permission{models.PermissionVMRead}})
+	assert.Equal(t, http.StatusConflict, errors.GetHTTPCode(err))
+
+	_, err = svc.UpdateRole(ctx, models.RoleOperator, &models.RoleUpdateRequest{Permissions: []models.Permission{models.PermissionAll}})
+	assert.Equal(t, http.StatusConflict, errors.GetHTTPCode(err))
+
+	assert.Equal(t, http.StatusConflict, errors.GetHTTPCode(svc.DeleteRole(ctx, models.RoleViewer)))
+
+	_, err = svc.CreateBinding(ctx, &models.RoleBindingCreateRequest{Subject: "alice", Role: "missing"})
+	assert.Equal(t, http.StatusNotFound, errors.GetHTTPCode(err))
+}
+
+func TestRequirePermissionMiddleware(t *testing.T) {
+	log, err := logger.New(logger.Config{Level: "error", Format: "console", Output: "stdout"})
+	require.NoError(t, err)
+
+	cfg := &config.Config{Auth: testAuthConfig()}
+	authenticator, err := auth.NewAuthenticator(cfg.Auth)
+	require.NoError(t, err)
+	mw := middleware.NewMiddlewareManager(cfg, log, nil, authenticator, newTestRBACService(t), nil, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(requestid.New())
+	vms := router.Group("/api/v1/vms", mw.AuthenticationMiddleware())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	vms.POST("/:id/restart", mw.RequirePermission(models.PermissionVMRestart), ok)
+	vms.DELETE("/:id", mw.RequirePermission(models.PermissionVMDelete), ok)
+
+	call := func(method, path string, roles ...string) int {
+		token, _, err := authenticator.IssueToken(&auth.Identity{Subject: "oncall", Roles: roles})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, call(http.MethodPost, "/api/v1/vms/vm-1/restart", models.RoleOperator))
+	assert.Equal(t, http.StatusForbidden, call(http.MethodDelete, "/api/v1/vms/vm-1", models.RoleOperator))
+	assert.Equal(t, http.StatusForbidden, call(http.MethodPost, "/api/v1/vms/vm-1/restart", models.RoleViewer))
+	assert.Equal(t, http.StatusOK, call(http.MethodDelete, "/api/v1/vms/vm-1", models.RoleAdmin))
+}
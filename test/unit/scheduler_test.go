@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/scheduler"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCandidate(name string, state models.NodeState, allocCPU, allocRAM, allocDisk, vms int) scheduler.Candidate {
+	return scheduler.Candidate{
+		Node: &models.Node{
+			Name:     name,
+			State:    state,
+			Capacity: models.NodeCapacity{CPUCores: 16, RAMMb: 32768, DiskGb: 1000},
+		},
+		Allocation: models.NodeAllocation{
+			NodeID:   name,
+			CPUCores: allocCPU,
+			RAMMb:    allocRAM,
+			DiskGb:   allocDisk,
+			VMCount:  vms,
+		},
+	}
+}
+
+func TestSchedulerStrategies(t *testing.T) {
+	candidates := []scheduler.Candidate{
+		newCandidate("node-01", models.NodeStateReady, 12, 24576, 600, 6),
+		newCandidate("node-02", models.NodeStateReady, 2, 4096, 100, 1),
+		newCandidate("node-03", models.NodeStateReady, 8, 8192, 200, 4),
+	}
+	req := scheduler.Request{CPUCores: 2, RAMMb: 2048, DiskGb: 50}
+
+	tests := []struct {
+		strategy string
+		expected string
+	}{
+		{scheduler.StrategyBinPack, "node-01"},
+		{scheduler.StrategySpread, "node-02"},
+		{scheduler.StrategyLeastAllocated, "node-02"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			s, err := scheduler.New(tt.strategy)
+			require.NoError(t, err)
+
+			node, err := s.Select(req, candidates)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, node.Name)
+		})
+	}
+}
+
+func TestSchedulerSkipsFullAndUnreadyNodes(t *testing.T) {
+	s, err := scheduler.New(scheduler.StrategyBinPack)
+	require.NoError(t, err)
+
+	candidates := []scheduler.Candidate{
+		newCandidate("node-01", models.NodeStateReady, 15, 1024, 10, 3),
+		newCandidate("node-02", models.NodeStateUnreachable, 0, 0, 0, 0),
+		newCandidate("node-03", models.NodeStateReady, 4, 4096, 100, 2),
+	}
+
+	node, err := s.Select(scheduler.Request{CPUCores: 4, RAMMb: 4096, DiskGb: 100}, candidates)
+	require.NoError(t, err)
+	assert.Equal(t, "node-03", node.Name)
+}
+
+func TestSchedulerInsufficientCapacity(t *testing.T) {
+	s, err := scheduler.New(scheduler.StrategySpread)
+	require.NoError(t, err)
+
+	candidates := []scheduler.Candidate{
+		newCandidate("node-01", models.NodeStateReady, 14, 1024, 10, 3),
+		newCandidate("node-02", models.NodeStateReady, 0, 30720, 10, 3),
+	}
+
+	// CPU is free on node-02 and RAM on node-01, but neither node has both
+	_, err = s.Select(scheduler.Request{CPUCores: 8, RAMMb: 8192, DiskGb: 20}, candidates)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.ErrInsufficientCapacity))
+
+	// RAM exceeds the largest free amount on any node
+	_, err = s.Select(scheduler.Request{CPUCores: 1, RAMMb: 40000, DiskGb: 20}, candidates)
+	require.Error(t, err)
+	appErr := errors.ToAppError(err)
+	assert.Equal(t, "INSUFFICIENT_CAPACITY", appErr.Code)
+	assert.Contains(t, appErr.Details, "RAM MB")
+
+	// No ready nodes at all
+	_, err = s.Select(scheduler.Request{CPUCores: 1, RAMMb: 512, DiskGb: 10}, nil)
+	assert.True(t, errors.Is(err, errors.ErrInsufficientCapacity))
+}
+
+func TestSchedulerUnknownStrategy(t *testing.T) {
+	_, err := scheduler.New("random")
+	assert.Error(t, err)
+}
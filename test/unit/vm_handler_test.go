@@ -1,11 +1,18 @@
 package tests
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -15,12 +22,19 @@ import (
 	"github.com/stackit/enterprise-vm-manager/internal/api/middleware"
 	"github.com/stackit/enterprise-vm-manager/internal/api/routes"
 	"github.com/stackit/enterprise-vm-manager/internal/config"
+	"github.com/stackit/enterprise-vm-manager/internal/health"
+	"github.com/stackit/enterprise-vm-manager/internal/hypervisor"
+	"github.com/stackit/enterprise-vm-manager/internal/imagestore"
 	"github.com/stackit/enterprise-vm-manager/internal/models"
 	"github.com/stackit/enterprise-vm-manager/internal/repositories"
+	"github.com/stackit/enterprise-vm-manager/internal/scheduler"
 	"github.com/stackit/enterprise-vm-manager/internal/services"
+	"github.com/stackit/enterprise-vm-manager/internal/watch"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
 	"github.com/stackit/enterprise-vm-manager/pkg/logger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/websocket"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	gormLogger "gorm.io/gorm/logger"
@@ -32,8 +46,12 @@ type VMHandlerTestSuite struct {
 	db        *gorm.DB
 	router    *gin.Engine
 	vmRepo    repositories.VMRepository
+	driver    *hypervisor.FakeDriver
 	vmService services.VMService
 	vmHandler *handlers.VMHandler
+	images    services.ImageService
+	project   *models.Project
+	health    *health.Checker
 	logger    *logger.Logger
 	cfg       *config.Config
 }
@@ -57,6 +75,7 @@ func (suite *VMHandlerTestSuite) SetupSuite() {
 			Host: "localhost",
 			Port: 8080,
 			Mode: "test",
+			CORS: config.CORSConfig{AllowOrigins: []string{"*"}},
 		},
 		Limits: config.LimitsConfig{
 			MaxCPUCores: 32,
@@ -64,6 +83,9 @@ func (suite *VMHandlerTestSuite) SetupSuite() {
 			MaxDiskGB:   5120,
 			MaxVMs:      100,
 		},
+		Images: config.ImagesConfig{
+			GCGracePeriod: time.Hour,
+		},
 	}
 }
 
@@ -77,19 +99,77 @@ func (suite *VMHandlerTestSuite) SetupTest() {
 	suite.db = db
 
 	// Auto migrate
-	err = db.AutoMigrate(&models.VM{})
+	err = db.AutoMigrate(&models.VM{}, &models.Node{}, &models.Operation{}, &models.VMEvent{}, &models.Role{}, &models.RoleBinding{}, &models.Project{}, &models.ProjectMember{}, &models.Quota{}, &models.Snapshot{}, &models.Flavor{}, &models.ProjectFlavor{}, &models.Image{}, &models.Volume{}, &models.Network{}, &models.NetworkInterface{})
 	suite.Require().NoError(err)
 
 	// Initialize components
 	suite.vmRepo = repositories.NewVMRepository(suite.db)
-	suite.vmService = services.NewVMService(suite.vmRepo, suite.cfg, suite.logger)
+	nodeRepo := repositories.NewNodeRepository(suite.db)
+	opRepo := repositories.NewOperationRepository(suite.db)
+	eventRepo := repositories.NewEventRepository(suite.db)
+	quotaRepo := repositories.NewQuotaRepository(suite.db)
+	snapshotRepo := repositories.NewSnapshotRepository(suite.db)
+	flavorRepo := repositories.NewFlavorRepository(suite.db)
+	imageRepo := repositories.NewImageRepository(suite.db)
+	volumeRepo := repositories.NewVolumeRepository(suite.db)
+	networkRepo := repositories.NewNetworkRepository(suite.db)
+
+	// Register a compute node so new VMs can be placed
+	err = nodeRepo.Create(context.Background(), &models.Node{
+		Name:     "node-01",
+		Capacity: models.NodeCapacity{CPUCores: 32, RAMMb: 65536, DiskGb: 5120},
+		State:    models.NodeStateReady,
+	})
+	suite.Require().NoError(err)
+
+	// Register the image test VMs are created from
+	err = imageRepo.Create(context.Background(), &models.Image{
+		Name:       "ubuntu",
+		Version:    "22.04",
+		OSFamily:   models.OSFamilyLinux,
+		MinDiskGb:  10,
+		MinRAMMb:   512,
+		Visibility: models.ImageVisibilityPublic,
+		State:      models.ImageStateActive,
+	})
+	suite.Require().NoError(err)
+	sched, err := scheduler.New(scheduler.StrategyBinPack)
+	suite.Require().NoError(err)
+	hub := watch.NewHub(100, 100)
+	driver := hypervisor.NewFakeDriver(0)
+	suite.driver = driver
+	store, err := imagestore.New(suite.T().TempDir(), 1<<20)
+	suite.Require().NoError(err)
+	suite.vmService = services.NewVMService(suite.vmRepo, nodeRepo, opRepo, eventRepo, quotaRepo, snapshotRepo, flavorRepo, imageRepo, store, volumeRepo, networkRepo, driver, sched, hub, nil, suite.cfg, suite.logger)
 	suite.vmHandler = handlers.NewVMHandler(suite.vmService, suite.logger)
+	nodeHandler := handlers.NewNodeHandler(services.NewNodeService(nodeRepo, suite.cfg, suite.logger), suite.logger)
+	opHandler := handlers.NewOperationHandler(services.NewOperationService(opRepo, suite.logger), suite.logger)
+	eventHandler := handlers.NewEventHandler(services.NewEventService(eventRepo, suite.logger), suite.logger)
+	watchHandler := handlers.NewWatchHandler(suite.vmService, time.Second, suite.logger)
+	snapshotHandler := handlers.NewSnapshotHandler(suite.vmService, suite.logger)
+	rbacService := services.NewRBACService(repositories.NewRoleRepository(suite.db), repositories.NewRoleBindingRepository(suite.db), suite.logger)
+	rbacHandler := handlers.NewRBACHandler(rbacService, suite.logger)
+	projectRepo := repositories.NewProjectRepository(suite.db)
+	projectService := services.NewProjectService(projectRepo, suite.vmRepo, volumeRepo, networkRepo, imageRepo, suite.logger)
+	projectHandler := handlers.NewProjectHandler(projectService, suite.logger)
+	quotaHandler := handlers.NewQuotaHandler(services.NewQuotaService(quotaRepo, projectRepo, suite.cfg, suite.logger), suite.logger)
+	suite.images = services.NewImageService(imageRepo, projectRepo, suite.vmRepo, snapshotRepo, store, suite.cfg, suite.logger)
+	imageHandler := handlers.NewImageHandler(suite.images, suite.logger)
+	volumeHandler := handlers.NewVolumeHandler(services.NewVolumeService(volumeRepo, suite.vmRepo, quotaRepo, eventRepo, driver, hub, suite.cfg, suite.logger), suite.logger)
+	networkHandler := handlers.NewNetworkHandler(services.NewNetworkService(networkRepo, quotaRepo, suite.logger), suite.logger)
+	flavorHandler := handlers.NewFlavorHandler(services.NewFlavorService(flavorRepo, projectRepo, suite.logger), suite.logger)
+	suite.project, err = projectService.EnsureDefaultProject(context.Background())
+	suite.Require().NoError(err)
 
 	// Setup router
 	gin.SetMode(gin.TestMode)
 	suite.router = gin.New()
-	middlewareManager := middleware.NewMiddlewareManager(suite.cfg, suite.logger)
-	router := routes.NewRouter(suite.cfg, suite.logger, suite.vmHandler, middlewareManager)
+	suite.health = health.NewChecker(time.Second)
+	suite.health.Register("database", func(ctx context.Context) error {
+		return suite.db.WithContext(ctx).Exec("SELECT 1").Error
+	})
+	middlewareManager := middleware.NewMiddlewareManager(suite.cfg, suite.logger, nil, nil, rbacService, projectService, nil)
+	router := routes.NewRouter(suite.cfg, suite.logger, suite.vmHandler, nodeHandler, opHandler, eventHandler, watchHandler, snapshotHandler, nil, rbacHandler, projectHandler, quotaHandler, flavorHandler, imageHandler, volumeHandler, networkHandler, middlewareManager, nil, suite.health, health.BuildInfo{Version: "test"})
 	router.SetupRoutes(suite.router)
 }
 
@@ -104,6 +184,7 @@ func (suite *VMHandlerTestSuite) TearDownTest() {
 func (suite *VMHandlerTestSuite) createTestVM() *models.VM {
 	vm := &models.VM{
 		ID:          uuid.New(),
+		ProjectID:   suite.project.ID,
 		Name:        "test-vm",
 		Description: "Test virtual machine",
 		Spec: models.VMSpec{
@@ -126,6 +207,10 @@ func (suite *VMHandlerTestSuite) createTestVM() *models.VM {
 }
 
 func (suite *VMHandlerTestSuite) makeRequest(method, path string, body interface{}) *httptest.ResponseRecorder {
+	return suite.makeRequestWithHeaders(method, path, body, nil)
+}
+
+func (suite *VMHandlerTestSuite) makeRequestWithHeaders(method, path string, body interface{}, headers map[string]string) *httptest.ResponseRecorder {
 	var bodyReader *bytes.Reader
 
 	if body != nil {
@@ -137,6 +222,9 @@ func (suite *VMHandlerTestSuite) makeRequest(method, path string, body interface
 
 	req := httptest.NewRequest(method, path, bodyReader)
 	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
 
 	w := httptest.NewRecorder()
 	suite.router.ServeHTTP(w, req)
@@ -169,6 +257,26 @@ func (suite *VMHandlerTestSuite) TestCreateVM_Success() {
 	assert.NotNil(suite.T(), response["data"])
 }
 
+func (suite *VMHandlerTestSuite) TestCreateVM_IgnoresCreatedByInBody() {
+	request := map[string]interface{}{
+		"name":       "spoofed-vm",
+		"cpu_cores":  2,
+		"ram_mb":     2048,
+		"disk_gb":    20,
+		"image_name": "ubuntu:22.04",
+		"created_by": "someone-else",
+	}
+
+	w := suite.makeRequest("POST", "/api/v1/vms", request)
+
+	assert.Equal(suite.T(), http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	data := response["data"].(map[string]interface{})
+	assert.Equal(suite.T(), "system", data["created_by"])
+}
+
 func (suite *VMHandlerTestSuite) TestCreateVM_ValidationError() {
 	request := models.VMCreateRequest{
 		Name:      "vm", // Too short
@@ -208,6 +316,28 @@ func (suite *VMHandlerTestSuite) TestCreateVM_DuplicateName() {
 	assert.Equal(suite.T(), http.StatusConflict, w.Code)
 }
 
+func (suite *VMHandlerTestSuite) TestCreateVM_NotStoredWithoutOperation() {
+	// Recording the provisioning operation fails, so the VM must not be admitted either
+	suite.Require().NoError(suite.db.Migrator().DropTable(&models.Operation{}))
+
+	w := suite.makeRequest("POST", "/api/v1/vms", models.VMCreateRequest{
+		Name: "orphan", CPUCores: 2, RAMMb: 2048, DiskGb: 50, ImageName: "ubuntu:22.04",
+	})
+	suite.Require().Equal(http.StatusInternalServerError, w.Code, w.Body.String())
+
+	_, err := suite.vmRepo.GetByName(context.Background(), suite.project.ID, "orphan")
+	assert.True(suite.T(), errors.Is(err, errors.ErrNotFound))
+
+	w = suite.makeRequest("GET", "/api/v1/quotas", nil)
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	var quotas struct {
+		Data []models.QuotaStatus `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &quotas))
+	suite.Require().Len(quotas.Data, 1)
+	assert.Equal(suite.T(), int64(0), quotas.Data[0].Usage.VMs)
+}
+
 func (suite *VMHandlerTestSuite) TestGetVM_Success() {
 	vm := suite.createTestVM()
 
@@ -243,6 +373,7 @@ func (suite *VMHandlerTestSuite) TestListVMs_Success() {
 	suite.createTestVM()
 
 	vm2 := &models.VM{
+		ProjectID:   suite.project.ID,
 		Name:        "test-vm-2",
 		Description: "Second test VM",
 		Spec: models.VMSpec{
@@ -295,6 +426,80 @@ func (suite *VMHandlerTestSuite) TestListVMs_WithFilters() {
 	assert.Equal(suite.T(), "stopped", vmData["status"])
 }
 
+func (suite *VMHandlerTestSuite) TestListVMs_InvalidLabelSelector() {
+	suite.createTestVM()
+
+	w := suite.makeRequest("GET", "/api/v1/vms?labelSelector="+url.QueryEscape("tier in (web"), nil)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	w = suite.makeRequest("GET", "/api/v1/vms/watch?labelSelector="+url.QueryEscape("env=prod,"), nil)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestListVMs_CursorPagination() {
+	var expected []string
+	for i := 0; i < 5; i++ {
+		vm := suite.createTestVM()
+		vm.Name = fmt.Sprintf("vm-%d", i)
+		suite.Require().NoError(suite.vmRepo.Update(context.Background(), vm))
+		expected = append(expected, vm.Name)
+	}
+
+	type listResponse struct {
+		Data struct {
+			VMs []struct {
+				Name string `json:"name"`
+			} `json:"vms"`
+			Pagination map[string]interface{} `json:"pagination"`
+			NextCursor string                 `json:"next_cursor"`
+		} `json:"data"`
+	}
+	list := func(query string) listResponse {
+		w := suite.makeRequest("GET", "/api/v1/vms?"+query, nil)
+		suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+		var response listResponse
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	for _, order := range []string{"sort_by=name&sort_order=asc", "sort_by=created_at&sort_order=desc"} {
+		var names []string
+		query := order + "&limit=2&include_total=false"
+		for pages := 0; ; pages++ {
+			suite.Require().Less(pages, 5, "pagination does not terminate")
+			response := list(query)
+			for _, vm := range response.Data.VMs {
+				names = append(names, vm.Name)
+			}
+			assert.NotContains(suite.T(), response.Data.Pagination, "total")
+			assert.Equal(suite.T(), response.Data.NextCursor != "", response.Data.Pagination["has_next"])
+			if response.Data.NextCursor == "" {
+				break
+			}
+			// Cursor pages are not counted, whether or not include_total is set
+			query = order + "&limit=2&continue=" + url.QueryEscape(response.Data.NextCursor)
+		}
+		assert.ElementsMatch(suite.T(), expected, names, order)
+		if order == "sort_by=name&sort_order=asc" {
+			assert.Equal(suite.T(), expected, names)
+		}
+	}
+
+	// Offset pages still report the total and hand out a cursor
+	response := list("sort_by=name&sort_order=asc&limit=2&page=2")
+	assert.Equal(suite.T(), float64(5), response.Data.Pagination["total"])
+	assert.Equal(suite.T(), "vm-2", response.Data.VMs[0].Name)
+	response = list("sort_by=name&sort_order=asc&limit=2&continue=" + url.QueryEscape(response.Data.NextCursor))
+	assert.Equal(suite.T(), "vm-4", response.Data.VMs[0].Name)
+
+	// Tokens only continue the sort order they were issued for
+	first := list("sort_by=name&sort_order=asc&limit=1")
+	w := suite.makeRequest("GET", "/api/v1/vms?sort_by=name&sort_order=desc&continue="+url.QueryEscape(first.Data.NextCursor), nil)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	w = suite.makeRequest("GET", "/api/v1/vms?continue=not-a-token", nil)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+}
+
 func (suite *VMHandlerTestSuite) TestUpdateVM_Success() {
 	vm := suite.createTestVM()
 
@@ -324,7 +529,7 @@ func (suite *VMHandlerTestSuite) TestUpdateVM_RunningVM() {
 	vm := suite.createTestVM()
 
 	// Set VM to running state
-	suite.vmRepo.UpdateStatus(context.Background(), vm.ID, models.VMStatusRunning)
+	suite.vmRepo.UpdateStatus(context.Background(), vm.ID, vm.Status, vm.ResourceVersion, models.VMStatusRunning)
 
 	request := models.VMUpdateRequest{
 		Name:      "updated-vm",
@@ -347,6 +552,10 @@ func (suite *VMHandlerTestSuite) TestDeleteVM_Success() {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), "VM deleted successfully", response["message"])
+	assert.NotEmpty(suite.T(), response["operation_id"])
+	data := response["data"].(map[string]interface{})
+	assert.Equal(suite.T(), response["operation_id"], data["id"])
+	assert.Equal(suite.T(), string(models.OperationStateSucceeded), data["state"])
 
 	// Verify VM is deleted
 	_, err = suite.vmRepo.GetByID(context.Background(), vm.ID)
@@ -357,13 +566,30 @@ func (suite *VMHandlerTestSuite) TestDeleteVM_RunningVM() {
 	vm := suite.createTestVM()
 
 	// Set VM to running state
-	suite.vmRepo.UpdateStatus(context.Background(), vm.ID, models.VMStatusRunning)
+	suite.vmRepo.UpdateStatus(context.Background(), vm.ID, vm.Status, vm.ResourceVersion, models.VMStatusRunning)
 
 	w := suite.makeRequest("DELETE", "/api/v1/vms/"+vm.ID.String(), nil)
 
 	assert.Equal(suite.T(), http.StatusConflict, w.Code)
 }
 
+func (suite *VMHandlerTestSuite) TestDeleteVM_FailedDestroyReleasesClaim() {
+	vm := suite.createTestVM()
+	path := "/api/v1/vms/" + vm.ID.String()
+
+	suite.driver.FailNext("destroy", fmt.Errorf("disk busy"))
+	w := suite.makeRequest("DELETE", path, nil)
+	assert.Equal(suite.T(), http.StatusBadGateway, w.Code)
+
+	// The VM is no longer claimed for deletion, so the delete can be retried
+	current, err := suite.vmRepo.GetByID(context.Background(), vm.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), models.VMStatusStopped, current.Status)
+
+	w = suite.makeRequest("DELETE", path, nil)
+	assert.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+}
+
 func (suite *VMHandlerTestSuite) TestStartVM_Success() {
 	vm := suite.createTestVM()
 
@@ -375,13 +601,293 @@ func (suite *VMHandlerTestSuite) TestStartVM_Success() {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(suite.T(), err)
 	assert.Contains(suite.T(), response["message"], "start operation initiated")
+	assert.NotEmpty(suite.T(), response["operation_id"])
+}
+
+func (suite *VMHandlerTestSuite) TestGetOperation_Success() {
+	vm := suite.createTestVM()
+
+	w := suite.makeRequest("POST", "/api/v1/vms/"+vm.ID.String()+"/start", nil)
+	suite.Require().Equal(http.StatusAccepted, w.Code)
+
+	var started map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &started))
+	operationID, _ := started["operation_id"].(string)
+	suite.Require().NotEmpty(operationID)
+	assert.Equal(suite.T(), "/api/v1/operations/"+operationID, w.Header().Get("Location"))
+
+	// The fake driver has no delay, so the operation completes almost immediately
+	var op map[string]interface{}
+	suite.Require().Eventually(func() bool {
+		w := suite.makeRequest("GET", "/api/v1/operations/"+operationID, nil)
+		if w.Code != http.StatusOK {
+			return false
+		}
+		var response map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			return false
+		}
+		op, _ = response["data"].(map[string]interface{})
+		return op != nil && op["state"] == string(models.OperationStateSucceeded)
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(suite.T(), "start", op["type"])
+	assert.Equal(suite.T(), vm.ID.String(), op["vm_id"])
+	assert.Equal(suite.T(), float64(100), op["progress"])
+}
+
+func (suite *VMHandlerTestSuite) TestGetOperation_NotFound() {
+	w := suite.makeRequest("GET", "/api/v1/operations/"+uuid.New().String(), nil)
+
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestListVMEvents_StatusChanges() {
+	vm := suite.createTestVM()
+
+	w := suite.makeRequest("POST", "/api/v1/vms/"+vm.ID.String()+"/start", map[string]interface{}{
+		"reason": "Maintenance window",
+	})
+	suite.Require().Equal(http.StatusAccepted, w.Code)
+
+	// Accepting the start and finishing it on the hypervisor are recorded separately
+	var events []interface{}
+	suite.Require().Eventually(func() bool {
+		w := suite.makeRequest("GET", "/api/v1/vms/"+vm.ID.String()+"/events", nil)
+		if w.Code != http.StatusOK {
+			return false
+		}
+		var response map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			return false
+		}
+		data, _ := response["data"].(map[string]interface{})
+		events, _ = data["events"].([]interface{})
+		return len(events) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	// Newest first
+	finished := events[0].(map[string]interface{})
+	accepted := events[1].(map[string]interface{})
+
+	assert.Equal(suite.T(), "status_changed", accepted["type"])
+	assert.Equal(suite.T(), "stopped", accepted["old_status"])
+	assert.Equal(suite.T(), "starting", accepted["new_status"])
+	assert.Equal(suite.T(), "Maintenance window", accepted["reason"])
+	assert.Equal(suite.T(), "system", accepted["actor"])
+	assert.NotEmpty(suite.T(), accepted["request_id"])
+	assert.NotEmpty(suite.T(), accepted["operation_id"])
+
+	assert.Equal(suite.T(), "starting", finished["old_status"])
+	assert.Equal(suite.T(), "running", finished["new_status"])
+	assert.Equal(suite.T(), accepted["request_id"], finished["request_id"])
+	assert.Equal(suite.T(), accepted["operation_id"], finished["operation_id"])
+}
+
+func (suite *VMHandlerTestSuite) TestListEvents_KeptAfterDelete() {
+	vm := suite.createTestVM()
+
+	w := suite.makeRequest("DELETE", "/api/v1/vms/"+vm.ID.String(), map[string]interface{}{
+		"reason": "Decommissioned",
+	})
+	suite.Require().Equal(http.StatusOK, w.Code)
+
+	w = suite.makeRequest("GET", "/api/v1/events?type=deleted&vm_id="+vm.ID.String(), nil)
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	data := response["data"].(map[string]interface{})
+	events := data["events"].([]interface{})
+	suite.Require().Len(events, 1)
+
+	event := events[0].(map[string]interface{})
+	assert.Equal(suite.T(), "test-vm", event["vm_name"])
+	assert.Equal(suite.T(), "Decommissioned", event["reason"])
+	assert.Equal(suite.T(), "system", event["actor"])
+}
+
+func (suite *VMHandlerTestSuite) TestListEvents_InvalidFilter() {
+	w := suite.makeRequest("GET", "/api/v1/events?type=rebooted", nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestWatchVM_StreamsStatusChanges() {
+	vm := suite.createTestVM()
+
+	server := httptest.NewServer(suite.router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/vms/" + vm.ID.String() + "/watch")
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), "text/event-stream", resp.Header.Get("Content-Type"))
+
+	w := suite.makeRequest("POST", "/api/v1/vms/"+vm.ID.String()+"/start", nil)
+	suite.Require().Equal(http.StatusAccepted, w.Code)
+
+	// Collect status changes until the VM is running
+	var statuses []string
+	reader := bufio.NewReader(resp.Body)
+	for len(statuses) < 2 {
+		line, err := reader.ReadString('\n')
+		suite.Require().NoError(err)
+
+		data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: ")
+		if !ok {
+			continue
+		}
+		var event watch.Event
+		suite.Require().NoError(json.Unmarshal([]byte(data), &event))
+		assert.Equal(suite.T(), watch.EventStatusChanged, event.Type)
+		assert.NotZero(suite.T(), event.ResourceVersion)
+		statuses = append(statuses, string(event.VM.Status))
+	}
+
+	assert.Equal(suite.T(), []string{"starting", "running"}, statuses)
+}
+
+func (suite *VMHandlerTestSuite) TestWatchVMs_WebSocket() {
+	vm := suite.createTestVM()
+
+	server := httptest.NewServer(suite.router)
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/vms/watch?status=starting"
+	conn, err := websocket.Dial(url, "", server.URL)
+	suite.Require().NoError(err)
+	defer conn.Close()
+
+	w := suite.makeRequest("POST", "/api/v1/vms/"+vm.ID.String()+"/start", nil)
+	suite.Require().Equal(http.StatusAccepted, w.Code)
+
+	var event watch.Event
+	suite.Require().NoError(conn.SetReadDeadline(time.Now().Add(time.Second)))
+	suite.Require().NoError(websocket.JSON.Receive(conn, &event))
+	assert.Equal(suite.T(), vm.ID, event.VM.ID)
+	assert.Equal(suite.T(), models.VMStatusStarting, event.VM.Status)
+}
+
+func (suite *VMHandlerTestSuite) TestWatchVMs_ExpiredResourceVersion() {
+	w := suite.makeRequest("GET", "/api/v1/vms/watch?resource_version=1", nil)
+
+	assert.Equal(suite.T(), http.StatusGone, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestWatchVM_NotFound() {
+	w := suite.makeRequest("GET", "/api/v1/vms/"+uuid.New().String()+"/watch", nil)
+
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestReadiness_Ready() {
+	w := suite.makeRequest("GET", "/ready", nil)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(suite.T(), "ready", response["status"])
+	database := response["checks"].(map[string]interface{})["database"].(map[string]interface{})
+	assert.Equal(suite.T(), "ok", database["status"])
+	assert.Contains(suite.T(), database, "latency_ms")
+}
+
+func (suite *VMHandlerTestSuite) TestReadiness_DatabaseDown() {
+	sqlDB, err := suite.db.DB()
+	suite.Require().NoError(err)
+	sqlDB.Close()
+
+	w := suite.makeRequest("GET", "/ready", nil)
+
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, w.Code)
+
+	var response map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(suite.T(), "not_ready", response["status"])
+	database := response["checks"].(map[string]interface{})["database"].(map[string]interface{})
+	assert.Equal(suite.T(), "fail", database["status"])
+	assert.NotEmpty(suite.T(), database["error"])
+}
+
+func (suite *VMHandlerTestSuite) TestVersion_ReportsBuildInfo() {
+	w := suite.makeRequest("GET", "/version", nil)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(suite.T(), "test", response["version"])
+}
+
+func (suite *VMHandlerTestSuite) TestNodes_RegisterAndRefresh() {
+	req := models.NodeRegisterRequest{
+		Name:     "node-02",
+		Address:  "10.0.0.12",
+		CPUCores: 16,
+		RAMMb:    32768,
+		DiskGb:   1024,
+	}
+
+	w := suite.makeRequest("POST", "/api/v1/nodes", req)
+	assert.Equal(suite.T(), http.StatusCreated, w.Code, w.Body.String())
+
+	// Registering again refreshes the inventory of the known node
+	req.CPUCores = 24
+	w = suite.makeRequest("POST", "/api/v1/nodes", req)
+	assert.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+
+	w = suite.makeRequest("GET", "/api/v1/nodes/node-02", nil)
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+
+	var response struct {
+		Data models.Node `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(suite.T(), 24, response.Data.Capacity.CPUCores)
+	assert.Equal(suite.T(), models.NodeStateReady, response.Data.State)
+	assert.NotNil(suite.T(), response.Data.LastHeartbeatAt)
+}
+
+func (suite *VMHandlerTestSuite) TestNodes_HeartbeatUnknownNode() {
+	w := suite.makeRequest("POST", "/api/v1/nodes/node-99/heartbeat", nil)
+
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestNodes_MissedHeartbeatsMarkUnreachable() {
+	ctx := context.Background()
+	nodeRepo := repositories.NewNodeRepository(suite.db)
+
+	stale := time.Now().Add(-10 * time.Minute)
+	suite.Require().NoError(suite.db.Model(&models.Node{}).Where("name = ?", "node-01").
+		Update("last_heartbeat_at", stale).Error)
+
+	count, err := nodeRepo.MarkUnreachable(ctx, time.Now().Add(-time.Minute))
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), int64(1), count)
+
+	node, err := nodeRepo.GetByName(ctx, "node-01")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), models.NodeStateUnreachable, node.State)
+	assert.False(suite.T(), node.IsSchedulable())
+
+	// A heartbeat brings the node back
+	w := suite.makeRequest("POST", "/api/v1/nodes/node-01/heartbeat", nil)
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+
+	node, err = nodeRepo.GetByName(ctx, "node-01")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), models.NodeStateReady, node.State)
 }
 
 func (suite *VMHandlerTestSuite) TestStartVM_AlreadyRunning() {
 	vm := suite.createTestVM()
 
 	// Set VM to running state
-	suite.vmRepo.UpdateStatus(context.Background(), vm.ID, models.VMStatusRunning)
+	suite.vmRepo.UpdateStatus(context.Background(), vm.ID, vm.Status, vm.ResourceVersion, models.VMStatusRunning)
 
 	w := suite.makeRequest("POST", "/api/v1/vms/"+vm.ID.String()+"/start", nil)
 
@@ -392,7 +898,7 @@ func (suite *VMHandlerTestSuite) TestStopVM_Success() {
 	vm := suite.createTestVM()
 
 	// Set VM to running state
-	suite.vmRepo.UpdateStatus(context.Background(), vm.ID, models.VMStatusRunning)
+	suite.vmRepo.UpdateStatus(context.Background(), vm.ID, vm.Status, vm.ResourceVersion, models.VMStatusRunning)
 
 	w := suite.makeRequest("POST", "/api/v1/vms/"+vm.ID.String()+"/stop", nil)
 
@@ -408,7 +914,7 @@ func (suite *VMHandlerTestSuite) TestRestartVM_Success() {
 	vm := suite.createTestVM()
 
 	// Set VM to running state
-	suite.vmRepo.UpdateStatus(context.Background(), vm.ID, models.VMStatusRunning)
+	suite.vmRepo.UpdateStatus(context.Background(), vm.ID, vm.Status, vm.ResourceVersion, models.VMStatusRunning)
 
 	w := suite.makeRequest("POST", "/api/v1/vms/"+vm.ID.String()+"/restart", nil)
 
@@ -451,6 +957,7 @@ func (suite *VMHandlerTestSuite) TestGetResourceSummary_Success() {
 	suite.createTestVM()
 
 	vm2 := &models.VM{
+		ProjectID:   suite.project.ID,
 		Name:        "running-vm",
 		Description: "Running test VM",
 		Spec: models.VMSpec{
@@ -484,92 +991,1575 @@ func (suite *VMHandlerTestSuite) TestGetResourceSummary_Success() {
 	assert.Equal(suite.T(), float64(1), vms["stopped"])
 
 	cpu := resources["cpu"].(map[string]interface{})
-	assert.Equal(suite.T(), 6, cpu["total"]) // 2 + 4 cores
-	assert.Equal(suite.T(), 4, cpu["used"])  // Only running VM
+	assert.Equal(suite.T(), float64(6), cpu["total"]) // 2 + 4 cores
+	assert.Equal(suite.T(), float64(4), cpu["used"])  // Only running VM
 }
 
-// Run the test suite
-func TestVMHandlerSuite(t *testing.T) {
-	suite.Run(t, new(VMHandlerTestSuite))
-}
+func (suite *VMHandlerTestSuite) TestRBAC_ManageRolesAndBindings() {
+	w := suite.makeRequest("POST", "/api/v1/rbac/roles", models.RoleCreateRequest{
+		Name:        "on-call",
+		Description: "Restart VMs during incidents",
+		Permissions: []models.Permission{models.PermissionVMRead, models.PermissionVMRestart},
+	})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
 
-// Additional unit tests for individual components
+	w = suite.makeRequest("GET", "/api/v1/rbac/roles", nil)
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	var roles struct {
+		Data []models.Role `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &roles))
+	var names []string
+	for _, role := range roles.Data {
+		names = append(names, role.Name)
+	}
+	assert.Equal(suite.T(), []string{"viewer", "operator", "admin", "on-call"}, names)
 
-func TestVMStatusTransitions(t *testing.T) {
-	vm := &models.VM{Status: models.VMStatusStopped}
+	w = suite.makeRequest("POST", "/api/v1/rbac/bindings", models.RoleBindingCreateRequest{Subject: "dave", Role: "on-call"})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	var binding struct {
+		Data models.RoleBinding `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &binding))
 
-	// Valid transitions
-	assert.True(t, vm.IsValidStatusTransition(models.VMStatusStarting))
-	assert.True(t, vm.IsValidStatusTransition(models.VMStatusPending))
+	w = suite.makeRequest("POST", "/api/v1/rbac/bindings", models.RoleBindingCreateRequest{Subject: "dave", Role: "on-call"})
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
 
-	// Invalid transitions
-	assert.False(t, vm.IsValidStatusTransition(models.VMStatusRunning))
-	assert.False(t, vm.IsValidStatusTransition(models.VMStatusStopping))
+	w = suite.makeRequest("GET", "/api/v1/rbac/bindings?subject=dave", nil)
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), binding.Data.ID.String())
 
-	// Test from running state
-	vm.Status = models.VMStatusRunning
-	assert.True(t, vm.IsValidStatusTransition(models.VMStatusStopping))
-	assert.True(t, vm.IsValidStatusTransition(models.VMStatusSuspended))
-	assert.False(t, vm.IsValidStatusTransition(models.VMStatusStarting))
+	w = suite.makeRequest("DELETE", "/api/v1/rbac/bindings/"+binding.Data.ID.String(), nil)
+	assert.Equal(suite.T(), http.StatusNoContent, w.Code)
+
+	w = suite.makeRequest("DELETE", "/api/v1/rbac/roles/on-call", nil)
+	assert.Equal(suite.T(), http.StatusNoContent, w.Code)
+
+	w = suite.makeRequest("GET", "/api/v1/rbac/roles/on-call", nil)
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
 }
 
-func TestVMOperationPermissions(t *testing.T) {
-	vm := &models.VM{Status: models.VMStatusStopped}
+func (suite *VMHandlerTestSuite) TestRBAC_BuiltinRolesAreReadOnly() {
+	w := suite.makeRequest("PUT", "/api/v1/rbac/roles/operator", models.RoleUpdateRequest{
+		Permissions: []models.Permission{models.PermissionAll},
+	})
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
 
-	assert.True(t, vm.CanPerformOperation("start"))
-	assert.True(t, vm.CanPerformOperation("update"))
-	assert.True(t, vm.CanPerformOperation("delete"))
-	assert.False(t, vm.CanPerformOperation("stop"))
-	assert.False(t, vm.CanPerformOperation("restart"))
+	w = suite.makeRequest("DELETE", "/api/v1/rbac/roles/admin", nil)
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
 
-	vm.Status = models.VMStatusRunning
-	assert.False(t, vm.CanPerformOperation("start"))
-	assert.False(t, vm.CanPerformOperation("update"))
-	assert.False(t, vm.CanPerformOperation("delete"))
-	assert.True(t, vm.CanPerformOperation("stop"))
-	assert.True(t, vm.CanPerformOperation("restart"))
+	w = suite.makeRequest("POST", "/api/v1/rbac/roles", models.RoleCreateRequest{
+		Name:        "broken",
+		Permissions: []models.Permission{"vm:explode"},
+	})
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
 }
 
-func TestVMUptime(t *testing.T) {
-	vm := &models.VM{
-		Status:    models.VMStatusStopped,
-		StartedAt: nil,
+func (suite *VMHandlerTestSuite) TestProjects_VMNamesAreUniquePerProject() {
+	w := suite.makeRequest("POST", "/api/v1/projects", models.ProjectCreateRequest{Name: "team-a"})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+
+	req := models.VMCreateRequest{
+		Name:      "web-01",
+		CPUCores:  2,
+		RAMMb:     2048,
+		DiskGb:    50,
+		ImageName: "ubuntu:22.04",
 	}
 
-	assert.Equal(t, int64(0), vm.GetUptime())
+	w = suite.makeRequest("POST", "/api/v1/vms", req)
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
 
-	now := time.Now()
-	vm.Status = models.VMStatusRunning
-	vm.StartedAt = &now
+	w = suite.makeRequest("POST", "/api/v1/projects/team-a/vms", req)
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.VMResponse `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+	assert.NotEqual(suite.T(), suite.project.ID, created.Data.ProjectID)
 
-	time.Sleep(time.Millisecond * 10)
-	uptime := vm.GetUptime()
-	assert.Greater(t, uptime, int64(0))
+	w = suite.makeRequest("POST", "/api/v1/projects/team-a/vms", req)
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+
+	w = suite.makeRequest("POST", "/api/v1/projects/missing/vms", req)
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
 }
 
-func TestVMLabelsAndAnnotations(t *testing.T) {
-	vm := &models.VM{}
+func (suite *VMHandlerTestSuite) TestProjects_RoutesAreScopedToProject() {
+	defaultVM := suite.createTestVM()
 
-	// Test adding labels
-	err := vm.AddLabel("environment", "test")
-	assert.NoError(t, err)
+	w := suite.makeRequest("POST", "/api/v1/projects", models.ProjectCreateRequest{Name: "team-a"})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
 
-	err = vm.AddLabel("tier", "backend")
-	assert.NoError(t, err)
+	w = suite.makeRequest("POST", "/api/v1/projects/team-a/vms", models.VMCreateRequest{
+		Name:      "team-vm",
+		CPUCores:  2,
+		RAMMb:     2048,
+		DiskGb:    50,
+		ImageName: "ubuntu:22.04",
+	})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
 
-	// Test getting labels
-	env, exists := vm.GetLabel("environment")
-	assert.True(t, exists)
-	assert.Equal(t, "test", env)
+	w = suite.makeRequest("GET", "/api/v1/projects/team-a/vms", nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	var list struct {
+		Data models.VMListResponse `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &list))
+	suite.Require().Len(list.Data.VMs, 1)
+	assert.Equal(suite.T(), "team-vm", list.Data.VMs[0].Name)
 
-	tier, exists := vm.GetLabel("tier")
-	assert.True(t, exists)
-	assert.Equal(t, "backend", tier)
+	// VMs of other projects cannot be reached through the project routes
+	w = suite.makeRequest("GET", "/api/v1/projects/team-a/vms/"+defaultVM.ID.String(), nil)
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+	w = suite.makeRequest("GET", "/api/v1/projects/default/vms/"+defaultVM.ID.String(), nil)
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
 
-	_, exists = vm.GetLabel("nonexistent")
-	assert.False(t, exists)
+	// Projects with VMs and the default project cannot be deleted
+	w = suite.makeRequest("DELETE", "/api/v1/projects/team-a", nil)
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+	w = suite.makeRequest("DELETE", "/api/v1/projects/default", nil)
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+}
 
-	// Test adding annotations
-	err = vm.AddAnnotation("created_by", "automated-test")
-	assert.NoError(t, err)
+func (suite *VMHandlerTestSuite) TestProjects_DefaultProjectNeedsMembership() {
+	w := suite.makeRequest("POST", "/api/v1/projects", models.ProjectCreateRequest{Name: "team-a"})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	w = suite.makeRequest("POST", "/api/v1/projects/team-a/members", models.ProjectMemberCreateRequest{Subject: "alice"})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.ProjectMember `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+
+	ctx := context.Background()
+	projects := repositories.NewProjectRepository(suite.db)
+	ids, err := projects.ProjectIDsForSubject(ctx, "alice", false)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), []uuid.UUID{created.Data.ProjectID}, ids)
+
+	ids, err = projects.ProjectIDsForSubject(ctx, "alice", true)
+	suite.Require().NoError(err)
+	assert.ElementsMatch(suite.T(), []uuid.UUID{suite.project.ID, created.Data.ProjectID}, ids)
+}
+
+func (suite *VMHandlerTestSuite) TestProjects_DeleteAfterVMsAreDeleted() {
+	w := suite.makeRequest("POST", "/api/v1/projects", models.ProjectCreateRequest{Name: "team-a"})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.Project `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+
+	vm := suite.createTestVM()
+	suite.Require().NoError(suite.db.Model(vm).Update("project_id", created.Data.ID).Error)
+	w = suite.makeRequest("DELETE", "/api/v1/projects/team-a/vms/"+vm.ID.String(), nil)
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+	// The deleted VM still refers to the project until the project is deleted with it
+	w = suite.makeRequest("DELETE", "/api/v1/projects/team-a", nil)
+	suite.Require().Equal(http.StatusNoContent, w.Code, w.Body.String())
+	var remaining int64
+	suite.Require().NoError(suite.db.Unscoped().Model(&models.VM{}).Where("project_id = ?", created.Data.ID).Count(&remaining).Error)
+	assert.Zero(suite.T(), remaining)
+}
+
+func (suite *VMHandlerTestSuite) TestProjects_DeleteRefusedWhileStorageRemains() {
+	w := suite.makeRequest("POST", "/api/v1/projects", models.ProjectCreateRequest{Name: "team-a"})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data struct {
+			ID uuid.UUID `json:"id"`
+		} `json:"data"`
+	}
+
+	w = suite.makeRequest("POST", "/api/v1/projects/team-a/volumes", map[string]interface{}{"name": "logs", "size_gb": 10})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+	volume := "/api/v1/projects/team-a/volumes/" + created.Data.ID.String()
+	w = suite.makeRequest("POST", "/api/v1/projects/team-a/networks", models.NetworkCreateRequest{Name: "frontend", CIDR: "10.0.1.0/24"})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+	network := "/api/v1/projects/team-a/networks/" + created.Data.ID.String()
+	w = suite.makeRequest("POST", "/api/v1/images", models.ImageCreateRequest{
+		Name: "golden", Version: "1", OSFamily: models.OSFamilyLinux, Visibility: models.ImageVisibilityPrivate, Project: "team-a",
+	})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+
+	// Each kind of resource keeps the project alive until it is deleted through its own API
+	for _, path := range []string{volume, network, "/api/v1/images/golden:1"} {
+		w = suite.makeRequest("DELETE", "/api/v1/projects/team-a", nil)
+		assert.Equal(suite.T(), http.StatusConflict, w.Code, w.Body.String())
+		w = suite.makeRequest("DELETE", path, nil)
+		suite.Require().Less(w.Code, 300, w.Body.String())
+	}
+
+	w = suite.makeRequest("DELETE", "/api/v1/projects/team-a", nil)
+	assert.Equal(suite.T(), http.StatusNoContent, w.Code, w.Body.String())
+}
+
+// Run the test suite
+func (suite *VMHandlerTestSuite) TestQuotas_RejectCreateAndResizeBeyondLimits() {
+	w := suite.makeRequest("PUT", "/api/v1/projects/default/quota", models.QuotaUpdateRequest{
+		QuotaLimits: models.QuotaLimits{MaxVMs: 2, MaxCPUCores: 4},
+	})
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+	vm := suite.createTestVM()
+	create := models.VMCreateRequest{
+		Name:      "web-01",
+		CPUCores:  4,
+		RAMMb:     2048,
+		DiskGb:    50,
+		ImageName: "ubuntu:22.04",
+	}
+
+	// 2 vCPUs in use plus 4 requested exceeds the limit of 4
+	w = suite.makeRequest("POST", "/api/v1/vms", create)
+	suite.Require().Equal(http.StatusConflict, w.Code, w.Body.String())
+	var response struct {
+		Error errors.AppError `json:"error"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(suite.T(), "QUOTA_EXCEEDED", response.Error.Code)
+	assert.Equal(suite.T(), models.QuotaDimensionCPUCores, response.Error.Context["dimension"])
+
+	create.CPUCores = 2
+	w = suite.makeRequest("POST", "/api/v1/vms", create)
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+
+	create.Name = "web-02"
+	w = suite.makeRequest("POST", "/api/v1/vms", create)
+	suite.Require().Equal(http.StatusConflict, w.Code, w.Body.String())
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(suite.T(), models.QuotaDimensionVMs, response.Error.Context["dimension"])
+
+	// Growing a VM counts against the quota, shrinking it does not
+	w = suite.makeRequest("PUT", "/api/v1/vms/"+vm.ID.String(), models.VMUpdateRequest{CPUCores: 3})
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+	w = suite.makeRequest("PUT", "/api/v1/vms/"+vm.ID.String(), models.VMUpdateRequest{CPUCores: 1})
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestQuotas_LimitRunningVMs() {
+	w := suite.makeRequest("PUT", "/api/v1/projects/default/quota", models.QuotaUpdateRequest{
+		QuotaLimits: models.QuotaLimits{MaxRunningVMs: 1},
+	})
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+	first := suite.createTestVM()
+	second := &models.VM{
+		ProjectID: suite.project.ID,
+		Name:      "test-vm-2",
+		Spec:      first.Spec,
+		Status:    models.VMStatusStopped,
+		NodeID:    "node-01",
+	}
+	suite.Require().NoError(suite.vmRepo.Create(context.Background(), second))
+
+	w = suite.makeRequest("POST", "/api/v1/vms/"+first.ID.String()+"/start", nil)
+	suite.Require().Equal(http.StatusAccepted, w.Code, w.Body.String())
+
+	w = suite.makeRequest("POST", "/api/v1/vms/"+second.ID.String()+"/start", nil)
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+
+	stored, err := suite.vmRepo.GetByID(context.Background(), second.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), models.VMStatusStopped, stored.Status)
+}
+
+func (suite *VMHandlerTestSuite) TestQuotas_ReportUsage() {
+	suite.createTestVM()
+
+	w := suite.makeRequest("GET", "/api/v1/quotas", nil)
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	var list struct {
+		Data []models.QuotaStatus `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &list))
+	suite.Require().Len(list.Data, 1)
+
+	status := list.Data[0]
+	assert.Equal(suite.T(), models.DefaultProjectName, status.Project)
+	assert.True(suite.T(), status.Default)
+	assert.Equal(suite.T(), suite.cfg.Limits.MaxVMs, status.Limits.MaxVMs)
+	assert.Equal(suite.T(), models.QuotaUsage{VMs: 1, CPUCores: 2, RAMMb: 2048, DiskGb: 50}, status.Usage)
+
+	w = suite.makeRequest("PUT", "/api/v1/projects/default/quota", map[string]int{"max_vms": -1})
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	w = suite.makeRequest("GET", "/api/v1/projects/missing/quota", nil)
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestConcurrency_IfMatch() {
+	vm := suite.createTestVM()
+	path := "/api/v1/vms/" + vm.ID.String()
+
+	w := suite.makeRequest("GET", path, nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.Equal(suite.T(), `"1"`, etag)
+
+	update := models.VMUpdateRequest{Description: "first"}
+	w = suite.makeRequestWithHeaders("PUT", path, update, map[string]string{"If-Match": etag})
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(suite.T(), `"2"`, w.Header().Get("ETag"))
+
+	// A second writer holding the old ETag loses
+	update.Description = "second"
+	w = suite.makeRequestWithHeaders("PUT", path, update, map[string]string{"If-Match": etag})
+	assert.Equal(suite.T(), http.StatusPreconditionFailed, w.Code)
+	w = suite.makeRequestWithHeaders("POST", path+"/start", nil, map[string]string{"If-Match": etag})
+	assert.Equal(suite.T(), http.StatusPreconditionFailed, w.Code)
+	w = suite.makeRequestWithHeaders("DELETE", path, nil, map[string]string{"If-Match": "not-an-etag"})
+	assert.Equal(suite.T(), http.StatusPreconditionFailed, w.Code)
+
+	stored, err := suite.vmRepo.GetByID(context.Background(), vm.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "first", stored.Description)
+
+	w = suite.makeRequestWithHeaders("POST", path+"/start", nil, map[string]string{"If-Match": `"2"`})
+	assert.Equal(suite.T(), http.StatusAccepted, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestConcurrency_StaleUpdateDoesNotOverwrite() {
+	ctx := context.Background()
+	vm := suite.createTestVM()
+
+	first, err := suite.vmRepo.GetByID(ctx, vm.ID)
+	suite.Require().NoError(err)
+	second, err := suite.vmRepo.GetByID(ctx, vm.ID)
+	suite.Require().NoError(err)
+
+	// Status changes bump the version, so an update read before them is stale
+	suite.Require().NoError(suite.vmRepo.UpdateStatus(ctx, vm.ID, vm.Status, vm.ResourceVersion, models.VMStatusError))
+
+	first.Description = "stale"
+	err = suite.vmRepo.Update(ctx, first)
+	suite.Require().Error(err)
+	assert.Equal(suite.T(), "RESOURCE_VERSION_CONFLICT", errors.GetCode(err))
+	assert.Equal(suite.T(), int64(1), first.ResourceVersion)
+
+	current, err := suite.vmRepo.GetByID(ctx, vm.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), models.VMStatusError, current.Status)
+	assert.Equal(suite.T(), int64(2), current.ResourceVersion)
+
+	current.Description = "fresh"
+	suite.Require().NoError(suite.vmRepo.Update(ctx, current))
+	assert.Equal(suite.T(), int64(3), current.ResourceVersion)
+	assert.Error(suite.T(), suite.vmRepo.Update(ctx, second))
+}
+
+func (suite *VMHandlerTestSuite) TestConcurrency_StatusChangeRequiresReadState() {
+	ctx := context.Background()
+	vm := suite.createTestVM()
+
+	// The status only changes from the status and version the caller read
+	err := suite.vmRepo.UpdateStatus(ctx, vm.ID, models.VMStatusRunning, vm.ResourceVersion, models.VMStatusStopping)
+	suite.Require().Error(err)
+	assert.Equal(suite.T(), "INVALID_VM_STATE", errors.GetCode(err))
+
+	err = suite.vmRepo.UpdateStatus(ctx, vm.ID, vm.Status, vm.ResourceVersion+1, models.VMStatusStarting)
+	suite.Require().Error(err)
+	assert.Equal(suite.T(), "RESOURCE_VERSION_CONFLICT", errors.GetCode(err))
+
+	err = suite.vmRepo.UpdateStatus(ctx, uuid.New(), vm.Status, vm.ResourceVersion, models.VMStatusStarting)
+	assert.Equal(suite.T(), "NOT_FOUND", errors.GetCode(err))
+
+	current, err := suite.vmRepo.GetByID(ctx, vm.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), models.VMStatusStopped, current.Status)
+	assert.Equal(suite.T(), vm.ResourceVersion, current.ResourceVersion)
+
+	suite.Require().NoError(suite.vmRepo.UpdateStatus(ctx, vm.ID, vm.Status, vm.ResourceVersion, models.VMStatusStarting))
+}
+
+func (suite *VMHandlerTestSuite) TestConcurrency_ConcurrentStartsStartOnce() {
+	// Every connection to an in-memory database opens its own database
+	sqlDB, err := suite.db.DB()
+	suite.Require().NoError(err)
+	sqlDB.SetMaxOpenConns(1)
+
+	vm := suite.createTestVM()
+	path := "/api/v1/vms/" + vm.ID.String() + "/start"
+
+	codes := make([]int, 8)
+	var wg sync.WaitGroup
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i] = suite.makeRequest("POST", path, nil).Code
+		}(i)
+	}
+	wg.Wait()
+
+	accepted := 0
+	for _, code := range codes {
+		if code == http.StatusAccepted {
+			accepted++
+			continue
+		}
+		assert.Equal(suite.T(), http.StatusConflict, code)
+	}
+	assert.Equal(suite.T(), 1, accepted)
+
+	var operations int64
+	suite.Require().NoError(suite.db.Model(&models.Operation{}).Where("vm_id = ?", vm.ID).Count(&operations).Error)
+	assert.Equal(suite.T(), int64(1), operations)
+
+	suite.Require().Eventually(func() bool {
+		current, err := suite.vmRepo.GetByID(context.Background(), vm.ID)
+		return err == nil && current.Status == models.VMStatusRunning
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (suite *VMHandlerTestSuite) TestConcurrency_ConcurrentDeletesDeleteOnce() {
+	// Every connection to an in-memory database opens its own database
+	sqlDB, err := suite.db.DB()
+	suite.Require().NoError(err)
+	sqlDB.SetMaxOpenConns(1)
+
+	vm := suite.createTestVM()
+	path := "/api/v1/vms/" + vm.ID.String()
+
+	codes := make([]int, 8)
+	var wg sync.WaitGroup
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i] = suite.makeRequest("DELETE", path, nil).Code
+		}(i)
+	}
+	wg.Wait()
+
+	// Requests that read the VM before it was claimed conflict, later ones no longer find it
+	deleted := 0
+	for _, code := range codes {
+		if code == http.StatusOK {
+			deleted++
+			continue
+		}
+		assert.Contains(suite.T(), []int{http.StatusConflict, http.StatusNotFound}, code)
+	}
+	assert.Equal(suite.T(), 1, deleted)
+
+	var operations int64
+	suite.Require().NoError(suite.db.Model(&models.Operation{}).
+		Where("vm_id = ? AND type = ?", vm.ID, models.OperationTypeDelete).Count(&operations).Error)
+	assert.Equal(suite.T(), int64(1), operations)
+}
+
+func (suite *VMHandlerTestSuite) TestPatchVM_MergePatch() {
+	vm := suite.createTestVM()
+	suite.Require().NoError(vm.AddLabel("tier", "web"))
+	suite.Require().NoError(suite.vmRepo.Update(context.Background(), vm))
+	path := "/api/v1/vms/" + vm.ID.String()
+	mergePatch := map[string]string{"Content-Type": "application/merge-patch+json"}
+
+	patch := json.RawMessage(`{"description": null, "cpu_cores": 4, "labels": {"tier": null, "env": "prod"}}`)
+	w := suite.makeRequestWithHeaders("PATCH", path, patch, mergePatch)
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(suite.T(), `"3"`, w.Header().Get("ETag"))
+
+	stored, err := suite.vmRepo.GetByID(context.Background(), vm.ID)
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), stored.Description)
+	assert.Equal(suite.T(), 4, stored.Spec.CPUCores)
+	_, hasTier := stored.GetLabel("tier")
+	assert.False(suite.T(), hasTier)
+	env, _ := stored.GetLabel("env")
+	assert.Equal(suite.T(), "prod", env)
+
+	// The patched VM must pass the rules of a create request
+	w = suite.makeRequestWithHeaders("PATCH", path, json.RawMessage(`{"ram_mb": 128}`), mergePatch)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	w = suite.makeRequestWithHeaders("PATCH", path, json.RawMessage(`{"name": null}`), mergePatch)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	w = suite.makeRequestWithHeaders("PATCH", path, json.RawMessage(`{"image_name": "debian:12"}`), mergePatch)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	w = suite.makeRequestWithHeaders("PATCH", path, json.RawMessage(`{"status": "running"}`), mergePatch)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	w = suite.makeRequest("PATCH", path, map[string]int{"cpu_cores": 8})
+	assert.Equal(suite.T(), http.StatusUnsupportedMediaType, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestPatchVM_JSONPatch() {
+	vm := suite.createTestVM()
+	suite.Require().NoError(vm.AddLabel("tier", "web"))
+	suite.Require().NoError(vm.AddLabel("team/owner", "alice"))
+	suite.Require().NoError(suite.vmRepo.Update(context.Background(), vm))
+	path := "/api/v1/vms/" + vm.ID.String()
+	jsonPatch := map[string]string{"Content-Type": "application/json-patch+json", "If-Match": `"2"`}
+
+	patch := json.RawMessage(`[
+		{"op": "test", "path": "/labels/tier", "value": "web"},
+		{"op": "remove", "path": "/labels/team~1owner"},
+		{"op": "add", "path": "/annotations", "value": {}},
+		{"op": "add", "path": "/annotations/note", "value": "patched"}
+	]`)
+	w := suite.makeRequestWithHeaders("PATCH", path, patch, jsonPatch)
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+	stored, err := suite.vmRepo.GetByID(context.Background(), vm.ID)
+	suite.Require().NoError(err)
+	tier, _ := stored.GetLabel("tier")
+	assert.Equal(suite.T(), "web", tier)
+	_, hasOwner := stored.GetLabel("team/owner")
+	assert.False(suite.T(), hasOwner)
+	assert.JSONEq(suite.T(), `{"note": "patched"}`, string(stored.Annotations))
+
+	// A failed test operation leaves the VM unchanged
+	jsonPatch["If-Match"] = `"3"`
+	patch = json.RawMessage(`[
+		{"op": "replace", "path": "/description", "value": "changed"},
+		{"op": "test", "path": "/labels/tier", "value": "db"}
+	]`)
+	w = suite.makeRequestWithHeaders("PATCH", path, patch, jsonPatch)
+	assert.Equal(suite.T(), http.StatusUnprocessableEntity, w.Code)
+
+	w = suite.makeRequestWithHeaders("PATCH", path, json.RawMessage(`[{"op": "explode", "path": "/name"}]`), jsonPatch)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	stored, err = suite.vmRepo.GetByID(context.Background(), vm.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "Test virtual machine", stored.Description)
+	assert.Equal(suite.T(), int64(3), stored.ResourceVersion)
+}
+
+func TestVMHandlerSuite(t *testing.T) {
+	suite.Run(t, new(VMHandlerTestSuite))
+}
+
+// Additional unit tests for individual components
+
+func TestVMStatusTransitions(t *testing.T) {
+	vm := &models.VM{Status: models.VMStatusStopped}
+
+	// Valid transitions
+	assert.True(t, vm.IsValidStatusTransition(models.VMStatusStarting))
+	assert.True(t, vm.IsValidStatusTransition(models.VMStatusPending))
+
+	// Invalid transitions
+	assert.False(t, vm.IsValidStatusTransition(models.VMStatusRunning))
+	assert.False(t, vm.IsValidStatusTransition(models.VMStatusStopping))
+
+	// Test from running state
+	vm.Status = models.VMStatusRunning
+	assert.True(t, vm.IsValidStatusTransition(models.VMStatusStopping))
+	assert.True(t, vm.IsValidStatusTransition(models.VMStatusSuspended))
+	assert.False(t, vm.IsValidStatusTransition(models.VMStatusStarting))
+}
+
+func TestVMOperationPermissions(t *testing.T) {
+	vm := &models.VM{Status: models.VMStatusStopped}
+
+	assert.True(t, vm.CanPerformOperation("start"))
+	assert.True(t, vm.CanPerformOperation("update"))
+	assert.True(t, vm.CanPerformOperation("delete"))
+	assert.False(t, vm.CanPerformOperation("stop"))
+	assert.False(t, vm.CanPerformOperation("restart"))
+
+	vm.Status = models.VMStatusRunning
+	assert.False(t, vm.CanPerformOperation("start"))
+	assert.False(t, vm.CanPerformOperation("update"))
+	assert.False(t, vm.CanPerformOperation("delete"))
+	assert.True(t, vm.CanPerformOperation("stop"))
+	assert.True(t, vm.CanPerformOperation("restart"))
+}
+
+func TestVMUptime(t *testing.T) {
+	vm := &models.VM{
+		Status:    models.VMStatusStopped,
+		StartedAt: nil,
+	}
+
+	assert.Equal(t, int64(0), vm.GetUptime())
+
+	// Uptime is reported in whole seconds
+	startedAt := time.Now().Add(-2 * time.Second)
+	vm.Status = models.VMStatusRunning
+	vm.StartedAt = &startedAt
+
+	uptime := vm.GetUptime()
+	assert.Greater(t, uptime, int64(0))
+}
+
+func TestVMLabelsAndAnnotations(t *testing.T) {
+	vm := &models.VM{}
+
+	// Test adding labels
+	err := vm.AddLabel("environment", "test")
+	assert.NoError(t, err)
+
+	err = vm.AddLabel("tier", "backend")
+	assert.NoError(t, err)
+
+	// Test getting labels
+	env, exists := vm.GetLabel("environment")
+	assert.True(t, exists)
+	assert.Equal(t, "test", env)
+
+	tier, exists := vm.GetLabel("tier")
+	assert.True(t, exists)
+	assert.Equal(t, "backend", tier)
+
+	_, exists = vm.GetLabel("nonexistent")
+	assert.False(t, exists)
+
+	// Test adding annotations
+	err = vm.AddAnnotation("created_by", "automated-test")
+	assert.NoError(t, err)
+}
+
+// waitForOperation polls the operation started by a response until it has finished
+func (suite *VMHandlerTestSuite) waitForOperation(w *httptest.ResponseRecorder) *models.Operation {
+	var accepted map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &accepted))
+	operationID, _ := accepted["operation_id"].(string)
+	suite.Require().NotEmpty(operationID, w.Body.String())
+
+	var response struct {
+		Data models.Operation `json:"data"`
+	}
+	suite.Require().Eventually(func() bool {
+		w := suite.makeRequest("GET", "/api/v1/operations/"+operationID, nil)
+		return w.Code == http.StatusOK && json.Unmarshal(w.Body.Bytes(), &response) == nil && response.Data.IsDone()
+	}, time.Second, 10*time.Millisecond)
+	return &response.Data
+}
+
+func (suite *VMHandlerTestSuite) TestSnapshots_DiskSnapshotLifecycle() {
+	vm := suite.createTestVM()
+	path := "/api/v1/vms/" + vm.ID.String() + "/snapshots"
+
+	w := suite.makeRequest("POST", path, map[string]interface{}{"name": "before-upgrade"})
+	suite.Require().Equal(http.StatusAccepted, w.Code, w.Body.String())
+	var created struct {
+		Data models.Snapshot `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(suite.T(), models.SnapshotTypeDisk, created.Data.Type)
+	assert.Equal(suite.T(), models.SnapshotStatusCreating, created.Data.Status)
+	assert.Equal(suite.T(), models.VMStatusStopped, created.Data.VMStatus)
+
+	op := suite.waitForOperation(w)
+	assert.Equal(suite.T(), models.OperationStateSucceeded, op.State, op.Error)
+	assert.Equal(suite.T(), models.OperationTypeSnapshotCreate, op.Type)
+	suite.Require().NotNil(op.SnapshotID)
+	assert.Equal(suite.T(), created.Data.ID, *op.SnapshotID)
+
+	// Names are unique per VM
+	w = suite.makeRequest("POST", path, map[string]interface{}{"name": "before-upgrade"})
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+
+	w = suite.makeRequest("GET", path, nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	var list struct {
+		Data []models.Snapshot `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &list))
+	suite.Require().Len(list.Data, 1)
+	assert.Equal(suite.T(), models.SnapshotStatusReady, list.Data[0].Status)
+
+	// Reverting to a disk snapshot leaves the VM stopped
+	snapshotPath := path + "/" + created.Data.ID.String()
+	w = suite.makeRequest("POST", snapshotPath+"/revert", nil)
+	suite.Require().Equal(http.StatusAccepted, w.Code, w.Body.String())
+	op = suite.waitForOperation(w)
+	assert.Equal(suite.T(), models.OperationStateSucceeded, op.State, op.Error)
+
+	stored, err := suite.vmRepo.GetByID(context.Background(), vm.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), models.VMStatusStopped, stored.Status)
+
+	w = suite.makeRequest("GET", "/api/v1/events?type=snapshot_reverted&vm_id="+vm.ID.String(), nil)
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(suite.T(), w.Body.String(), "before-upgrade")
+
+	w = suite.makeRequest("DELETE", snapshotPath, nil)
+	suite.Require().Equal(http.StatusAccepted, w.Code, w.Body.String())
+	op = suite.waitForOperation(w)
+	assert.Equal(suite.T(), models.OperationStateSucceeded, op.State, op.Error)
+
+	w = suite.makeRequest("GET", snapshotPath, nil)
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestSnapshots_MemorySnapshotRevertStartsVM() {
+	vm := suite.createTestVM()
+	path := "/api/v1/vms/" + vm.ID.String() + "/snapshots"
+	memory := map[string]interface{}{"name": "warm", "type": "memory"}
+
+	// A stopped VM has no memory to capture
+	w := suite.makeRequest("POST", path, memory)
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+
+	w = suite.makeRequest("POST", "/api/v1/vms/"+vm.ID.String()+"/start", nil)
+	suite.Require().Equal(http.StatusAccepted, w.Code)
+	suite.waitForOperation(w)
+
+	w = suite.makeRequest("POST", path, memory)
+	suite.Require().Equal(http.StatusAccepted, w.Code, w.Body.String())
+	var created struct {
+		Data models.Snapshot `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+	op := suite.waitForOperation(w)
+	suite.Require().Equal(models.OperationStateSucceeded, op.State, op.Error)
+
+	// Only stopped VMs can be reverted
+	revertPath := path + "/" + created.Data.ID.String() + "/revert"
+	w = suite.makeRequest("POST", revertPath, nil)
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+
+	w = suite.makeRequest("POST", "/api/v1/vms/"+vm.ID.String()+"/stop", nil)
+	suite.Require().Equal(http.StatusAccepted, w.Code)
+	suite.waitForOperation(w)
+
+	w = suite.makeRequest("POST", revertPath, nil)
+	suite.Require().Equal(http.StatusAccepted, w.Code, w.Body.String())
+	op = suite.waitForOperation(w)
+	assert.Equal(suite.T(), models.OperationStateSucceeded, op.State, op.Error)
+
+	stored, err := suite.vmRepo.GetByID(context.Background(), vm.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), models.VMStatusRunning, stored.Status)
+}
+
+func (suite *VMHandlerTestSuite) TestSnapshots_InvalidRequests() {
+	vm := suite.createTestVM()
+
+	w := suite.makeRequest("POST", "/api/v1/vms/"+vm.ID.String()+"/snapshots", map[string]interface{}{"name": "s1", "type": "full"})
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	w = suite.makeRequest("POST", "/api/v1/vms/"+uuid.New().String()+"/snapshots", map[string]interface{}{"name": "s1"})
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+
+	w = suite.makeRequest("POST", "/api/v1/vms/"+vm.ID.String()+"/snapshots/"+uuid.New().String()+"/revert", nil)
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+
+	w = suite.makeRequest("DELETE", "/api/v1/vms/"+vm.ID.String()+"/snapshots/not-a-uuid", nil)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestClone_FullAndLinked() {
+	source := suite.createTestVM()
+	suite.Require().NoError(source.AddLabel("tier", "web"))
+	suite.Require().NoError(source.AddLabel("env", "prod"))
+	suite.Require().NoError(suite.vmRepo.Update(context.Background(), source))
+	path := "/api/v1/vms/" + source.ID.String() + "/clone"
+
+	// Clones cannot shrink the disk of their source
+	w := suite.makeRequest("POST", path, map[string]interface{}{"name": "web-small", "disk_gb": 20})
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	w = suite.makeRequest("POST", path, map[string]interface{}{
+		"name":      "web-copy",
+		"cpu_cores": 4,
+		"labels":    map[string]string{"env": "staging"},
+	})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.VM `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+	clone := created.Data
+	assert.Equal(suite.T(), models.CloneModeFull, clone.CloneMode)
+	suite.Require().NotNil(clone.SourceVMID)
+	assert.Equal(suite.T(), source.ID, *clone.SourceVMID)
+	assert.Equal(suite.T(), 4, clone.Spec.CPUCores)
+	assert.Equal(suite.T(), source.Spec.RAMMb, clone.Spec.RAMMb)
+	assert.Equal(suite.T(), source.Spec.ImageName, clone.Spec.ImageName)
+	assert.Equal(suite.T(), map[string]string{"tier": "web", "env": "staging"}, clone.GetLabels())
+
+	op := suite.waitForOperation(w)
+	assert.Equal(suite.T(), models.OperationStateSucceeded, op.State, op.Error)
+	assert.Equal(suite.T(), models.OperationTypeClone, op.Type)
+
+	stored, err := suite.vmRepo.GetByID(context.Background(), clone.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), models.VMStatusStopped, stored.Status)
+
+	// Names are unique per project
+	w = suite.makeRequest("POST", path, map[string]interface{}{"name": "web-copy"})
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+
+	// Linked clones stay on the node of their source
+	w = suite.makeRequest("POST", path, map[string]interface{}{"name": "web-linked", "mode": "linked"})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(suite.T(), models.CloneModeLinked, created.Data.CloneMode)
+	assert.Equal(suite.T(), source.NodeID, created.Data.NodeID)
+	op = suite.waitForOperation(w)
+	assert.Equal(suite.T(), models.OperationStateSucceeded, op.State, op.Error)
+
+	// Running VMs can only be cloned from a snapshot
+	w = suite.makeRequest("POST", "/api/v1/vms/"+source.ID.String()+"/start", nil)
+	suite.Require().Equal(http.StatusAccepted, w.Code)
+	suite.waitForOperation(w)
+	w = suite.makeRequest("POST", path, map[string]interface{}{"name": "web-live"})
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestClone_FromSnapshot() {
+	source := suite.createTestVM()
+	path := "/api/v1/vms/" + source.ID.String()
+
+	w := suite.makeRequest("POST", path+"/start", nil)
+	suite.Require().Equal(http.StatusAccepted, w.Code)
+	suite.waitForOperation(w)
+
+	w = suite.makeRequest("POST", path+"/snapshots", map[string]interface{}{"name": "golden"})
+	suite.Require().Equal(http.StatusAccepted, w.Code, w.Body.String())
+	var snapshot struct {
+		Data models.Snapshot `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &snapshot))
+	suite.waitForOperation(w)
+
+	// Linked clones share the live disk and cannot start from a snapshot
+	w = suite.makeRequest("POST", path+"/clone", map[string]interface{}{
+		"name": "from-golden", "mode": "linked", "snapshot_id": snapshot.Data.ID,
+	})
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	w = suite.makeRequest("POST", path+"/clone", map[string]interface{}{"name": "from-golden", "snapshot_id": uuid.New()})
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+
+	w = suite.makeRequest("POST", path+"/clone", map[string]interface{}{"name": "from-golden", "snapshot_id": snapshot.Data.ID})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.VM `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+	suite.Require().NotNil(created.Data.SourceSnapshotID)
+	assert.Equal(suite.T(), snapshot.Data.ID, *created.Data.SourceSnapshotID)
+
+	op := suite.waitForOperation(w)
+	assert.Equal(suite.T(), models.OperationStateSucceeded, op.State, op.Error)
+
+	w = suite.makeRequest("GET", "/api/v1/events?type=created&vm_id="+created.Data.ID.String(), nil)
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(suite.T(), w.Body.String(), "at snapshot golden")
+
+	// Linked clones would freeze the snapshots of their source
+	w = suite.makeRequest("POST", path+"/stop", nil)
+	suite.Require().Equal(http.StatusAccepted, w.Code)
+	suite.waitForOperation(w)
+	w = suite.makeRequest("POST", path+"/clone", map[string]interface{}{"name": "linked", "mode": "linked"})
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestClone_RespectsQuota() {
+	w := suite.makeRequest("PUT", "/api/v1/projects/default/quota", models.QuotaUpdateRequest{
+		QuotaLimits: models.QuotaLimits{MaxVMs: 1},
+	})
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+	source := suite.createTestVM()
+	w = suite.makeRequest("POST", "/api/v1/vms/"+source.ID.String()+"/clone", map[string]interface{}{"name": "web-copy"})
+	suite.Require().Equal(http.StatusConflict, w.Code, w.Body.String())
+	var response struct {
+		Error errors.AppError `json:"error"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(suite.T(), "QUOTA_EXCEEDED", response.Error.Code)
+
+	w = suite.makeRequest("POST", "/api/v1/vms/"+uuid.New().String()+"/clone", map[string]interface{}{"name": "web-copy"})
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestFlavors_CreateVMFromFlavor() {
+	w := suite.makeRequest("POST", "/api/v1/flavors", models.FlavorCreateRequest{
+		Name:     "t1.small",
+		CPUCores: 2,
+		RAMMb:    4096,
+		DiskGb:   40,
+	})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	w = suite.makeRequest("POST", "/api/v1/flavors", models.FlavorCreateRequest{Name: "t1.small", CPUCores: 1, RAMMb: 1024, DiskGb: 10})
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+
+	w = suite.makeRequest("POST", "/api/v1/vms", models.VMCreateRequest{Name: "web-01", Flavor: "t1.small", ImageName: "ubuntu:22.04"})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.VMResponse `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(suite.T(), "t1.small", created.Data.Flavor)
+	assert.Equal(suite.T(), 2, created.Data.Spec.CPUCores)
+	assert.Equal(suite.T(), 4096, created.Data.Spec.RAMMb)
+	assert.Equal(suite.T(), 40, created.Data.Spec.DiskGb)
+
+	// A flavor and custom sizes are mutually exclusive, and one of them is required
+	w = suite.makeRequest("POST", "/api/v1/vms", models.VMCreateRequest{Name: "web-02", Flavor: "t1.small", CPUCores: 4, ImageName: "ubuntu:22.04"})
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	w = suite.makeRequest("POST", "/api/v1/vms", models.VMCreateRequest{Name: "web-02", CPUCores: 4, ImageName: "ubuntu:22.04"})
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	w = suite.makeRequest("POST", "/api/v1/vms", models.VMCreateRequest{Name: "web-02", Flavor: "t9.huge", ImageName: "ubuntu:22.04"})
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	// Resizing a flavor leaves existing VMs alone
+	w = suite.makeRequest("PUT", "/api/v1/flavors/t1.small", models.FlavorUpdateRequest{CPUCores: 4, RAMMb: 8192, DiskGb: 80})
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	stored, err := suite.vmRepo.GetByID(context.Background(), created.Data.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 2, stored.Spec.CPUCores)
+
+	w = suite.makeRequest("GET", "/api/v1/flavors", nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	var list struct {
+		Data []models.Flavor `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &list))
+	suite.Require().Len(list.Data, 1)
+	assert.Equal(suite.T(), 4, list.Data[0].CPUCores)
+}
+
+func (suite *VMHandlerTestSuite) TestFlavors_ProjectRestriction() {
+	for _, flavor := range []models.FlavorCreateRequest{
+		{Name: "t1.small", CPUCores: 1, RAMMb: 2048, DiskGb: 20},
+		{Name: "t1.large", CPUCores: 4, RAMMb: 8192, DiskGb: 80},
+	} {
+		w := suite.makeRequest("POST", "/api/v1/flavors", flavor)
+		suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	}
+	legacy := suite.createTestVM()
+
+	w := suite.makeRequest("PUT", "/api/v1/projects/default/flavors", models.ProjectFlavorsUpdateRequest{Flavors: []string{"t1.missing"}})
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	w = suite.makeRequest("PUT", "/api/v1/projects/default/flavors", models.ProjectFlavorsUpdateRequest{Flavors: []string{"t1.small"}})
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	var allowed struct {
+		Data models.ProjectFlavors `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &allowed))
+	assert.True(suite.T(), allowed.Data.Restricted)
+	suite.Require().Len(allowed.Data.Flavors, 1)
+	assert.Equal(suite.T(), "t1.small", allowed.Data.Flavors[0].Name)
+
+	// Restricted projects reject custom sizes and other flavors
+	w = suite.makeRequest("POST", "/api/v1/vms", models.VMCreateRequest{Name: "web-01", CPUCores: 1, RAMMb: 2048, DiskGb: 20, ImageName: "ubuntu:22.04"})
+	suite.Require().Equal(http.StatusForbidden, w.Code, w.Body.String())
+	var response struct {
+		Error errors.AppError `json:"error"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(suite.T(), "FLAVOR_NOT_ALLOWED", response.Error.Code)
+	w = suite.makeRequest("POST", "/api/v1/vms", models.VMCreateRequest{Name: "web-01", Flavor: "t1.large", ImageName: "ubuntu:22.04"})
+	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+	w = suite.makeRequest("POST", "/api/v1/vms", models.VMCreateRequest{Name: "web-01", Flavor: "t1.small", ImageName: "ubuntu:22.04"})
+	assert.Equal(suite.T(), http.StatusCreated, w.Code, w.Body.String())
+
+	// VMs created before the restriction keep their size until it changes
+	path := "/api/v1/vms/" + legacy.ID.String()
+	w = suite.makeRequest("PUT", path, models.VMUpdateRequest{Description: "renamed"})
+	assert.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+	w = suite.makeRequest("PUT", path, models.VMUpdateRequest{CPUCores: 4})
+	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+	w = suite.makeRequest("PUT", path, models.VMUpdateRequest{Flavor: "t1.small"})
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	stored, err := suite.vmRepo.GetByID(context.Background(), legacy.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "t1.small", stored.Flavor)
+	assert.Equal(suite.T(), 1, stored.Spec.CPUCores)
+
+	// Allowed flavors cannot be deleted until the restriction is lifted
+	w = suite.makeRequest("DELETE", "/api/v1/flavors/t1.small", nil)
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+	w = suite.makeRequest("PUT", "/api/v1/projects/default/flavors", models.ProjectFlavorsUpdateRequest{Flavors: []string{}})
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	w = suite.makeRequest("DELETE", "/api/v1/flavors/t1.small", nil)
+	assert.Equal(suite.T(), http.StatusNoContent, w.Code)
+
+	w = suite.makeRequest("GET", "/api/v1/projects/default/flavors", nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &allowed))
+	assert.False(suite.T(), allowed.Data.Restricted)
+	assert.Len(suite.T(), allowed.Data.Flavors, 1)
+}
+
+func (suite *VMHandlerTestSuite) TestImages_ValidateCreate() {
+	create := models.VMCreateRequest{Name: "web-01", CPUCores: 2, RAMMb: 2048, DiskGb: 20, ImageName: "ubuntu:2204"}
+	var response struct {
+		Error errors.AppError `json:"error"`
+	}
+
+	// Typos in the image name are rejected
+	w := suite.makeRequest("POST", "/api/v1/vms", create)
+	suite.Require().Equal(http.StatusBadRequest, w.Code, w.Body.String())
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(suite.T(), "UNKNOWN_IMAGE", response.Error.Code)
+
+	w = suite.makeRequest("POST", "/api/v1/images", models.ImageCreateRequest{
+		Name:      "ubuntu",
+		Version:   "24.04",
+		OSFamily:  models.OSFamilyLinux,
+		MinDiskGb: 30,
+		MinRAMMb:  1024,
+		Checksum:  "sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+	})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.Image `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(suite.T(), models.ImageVisibilityPublic, created.Data.Visibility)
+
+	// The minimum disk and RAM of the image are enforced
+	create.ImageName = "ubuntu:24.04"
+	w = suite.makeRequest("POST", "/api/v1/vms", create)
+	suite.Require().Equal(http.StatusBadRequest, w.Code, w.Body.String())
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(suite.T(), "disk_gb", response.Error.Context["field"])
+	create.DiskGb = 30
+	w = suite.makeRequest("POST", "/api/v1/vms", create)
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+
+	// Deprecated images cannot be used for new VMs and point to their replacement
+	w = suite.makeRequest("PUT", "/api/v1/images/"+created.Data.ID.String(), models.ImageUpdateRequest{
+		MinDiskGb:   30,
+		MinRAMMb:    1024,
+		Deprecated:  true,
+		Replacement: "ubuntu:22.04",
+	})
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	create.Name = "web-02"
+	w = suite.makeRequest("POST", "/api/v1/vms", create)
+	suite.Require().Equal(http.StatusBadRequest, w.Code, w.Body.String())
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(suite.T(), "IMAGE_DEPRECATED", response.Error.Code)
+	assert.Contains(suite.T(), response.Error.Details, "ubuntu:22.04")
+
+	w = suite.makeRequest("GET", "/api/v1/images", nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	var list struct {
+		Data []models.Image `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Len(suite.T(), list.Data, 1)
+	w = suite.makeRequest("GET", "/api/v1/images?include_deprecated=true", nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Len(suite.T(), list.Data, 2)
+
+	// Images VMs were created from cannot be deleted
+	w = suite.makeRequest("DELETE", "/api/v1/images/"+created.Data.ID.String(), nil)
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestImages_PrivateAndValidation() {
+	w := suite.makeRequest("POST", "/api/v1/projects", models.ProjectCreateRequest{Name: "team-a"})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+
+	image := models.ImageCreateRequest{Name: "golden", Version: "1", OSFamily: models.OSFamilyLinux, Visibility: models.ImageVisibilityPrivate}
+	w = suite.makeRequest("POST", "/api/v1/images", image)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	image.Project = "team-a"
+	image.Checksum = "md5:d41d8cd98f00b204e9800998ecf8427e"
+	w = suite.makeRequest("POST", "/api/v1/images", image)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	image.Checksum = ""
+	w = suite.makeRequest("POST", "/api/v1/images", image)
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	w = suite.makeRequest("POST", "/api/v1/images", image)
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+
+	// Private images are only available to their project
+	create := models.VMCreateRequest{Name: "web-01", CPUCores: 1, RAMMb: 1024, DiskGb: 20, ImageName: "golden:1"}
+	w = suite.makeRequest("POST", "/api/v1/vms", create)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	w = suite.makeRequest("POST", "/api/v1/projects/team-a/vms", create)
+	assert.Equal(suite.T(), http.StatusCreated, w.Code, w.Body.String())
+
+	// Images are addressed by ID or reference
+	w = suite.makeRequest("GET", "/api/v1/images/golden:1", nil)
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	w = suite.makeRequest("GET", "/api/v1/images/golden:2", nil)
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+	w = suite.makeRequest("GET", "/api/v1/images/"+uuid.New().String(), nil)
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestImages_ResumableUploadAndDownload() {
+	data := append([]byte("QFI\xfb"), bytes.Repeat([]byte("image"), 200)...)
+	digest := sha256.Sum256(data)
+	checksum := "sha256:" + hex.EncodeToString(digest[:])
+	w := suite.makeRequest("POST", "/api/v1/images", models.ImageCreateRequest{
+		Name: "alpine", Version: "3.19", OSFamily: models.OSFamilyLinux, Checksum: checksum,
+	})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+
+	w = suite.makeRequest("GET", "/api/v1/images/alpine:3.19/download", nil)
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+
+	// The first chunk is kept while the upload is incomplete
+	w = suite.upload("alpine:3.19", data[:500], map[string]string{"Content-Range": "bytes 0-499/1004"})
+	suite.Require().Equal(http.StatusAccepted, w.Code, w.Body.String())
+	assert.Equal(suite.T(), "500", w.Header().Get("Upload-Offset"))
+
+	// Chunks must continue at the offset received so far
+	w = suite.upload("alpine:3.19", data[600:], map[string]string{"Content-Range": "bytes 600-1003/1004"})
+	suite.Require().Equal(http.StatusConflict, w.Code, w.Body.String())
+	assert.Equal(suite.T(), "500", w.Header().Get("Upload-Offset"))
+	w = suite.makeRequest("GET", "/api/v1/images/alpine:3.19/upload", nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "500", w.Header().Get("Upload-Offset"))
+
+	w = suite.upload("alpine:3.19", data[500:], map[string]string{"Content-Range": "bytes 500-1003/1004"})
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	var uploaded struct {
+		Data models.ImageUpload `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &uploaded))
+	assert.True(suite.T(), uploaded.Data.Complete)
+	suite.Require().NotNil(uploaded.Data.Image)
+	assert.Equal(suite.T(), models.DiskFormatQCOW2, uploaded.Data.Image.DiskFormat)
+	assert.Equal(suite.T(), int64(len(data)), uploaded.Data.Image.SizeBytes)
+
+	// Uploaded images are immutable
+	w = suite.upload("alpine:3.19", data, nil)
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+
+	w = suite.makeRequest("GET", "/api/v1/images/alpine:3.19/download", nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assert.Equal(suite.T(), data, w.Body.Bytes())
+	assert.Equal(suite.T(), checksum, w.Header().Get("X-Image-Checksum"))
+
+	// Downloads resume with range requests
+	w = suite.makeRequestWithHeaders("GET", "/api/v1/images/alpine:3.19/download", nil, map[string]string{"Range": "bytes=1000-"})
+	suite.Require().Equal(http.StatusPartialContent, w.Code)
+	assert.Equal(suite.T(), data[1000:], w.Body.Bytes())
+}
+
+func (suite *VMHandlerTestSuite) TestImages_UploadVerificationAndGarbageCollection() {
+	data := bytes.Repeat([]byte{0}, 4096)
+	digest := sha256.Sum256(data)
+	checksum := "sha256:" + hex.EncodeToString(digest[:])
+	for _, name := range []string{"keep", "drop"} {
+		w := suite.makeRequest("POST", "/api/v1/images", models.ImageCreateRequest{Name: name, Version: "1", OSFamily: models.OSFamilyOther})
+		suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	// Uploads are verified against a checksum, here sent with the upload
+	w := suite.upload("drop:1", data, nil)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	w = suite.upload("drop:1", data, map[string]string{"X-Image-Checksum": "sha256:" + strings.Repeat("0", 64)})
+	suite.Require().Equal(http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	w = suite.makeRequest("GET", "/api/v1/images/drop:1/upload", nil)
+	assert.Equal(suite.T(), "0", w.Header().Get("Upload-Offset"))
+	for _, name := range []string{"keep:1", "drop:1"} {
+		w = suite.upload(name, data, map[string]string{"X-Image-Checksum": checksum})
+		suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = suite.makeRequest("POST", "/api/v1/vms", models.VMCreateRequest{Name: "web-01", CPUCores: 1, RAMMb: 1024, DiskGb: 20, ImageName: "keep:1"})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+
+	// Unreferenced images are marked first and collected once the grace period has passed
+	ctx := context.Background()
+	collected, err := suite.images.CollectGarbage(ctx)
+	suite.Require().NoError(err)
+	assert.Zero(suite.T(), collected)
+	dropped, err := suite.images.GetImage(ctx, "drop:1")
+	suite.Require().NoError(err)
+	suite.Require().NotNil(dropped.UnreferencedSince)
+	kept, err := suite.images.GetImage(ctx, "keep:1")
+	suite.Require().NoError(err)
+	assert.Nil(suite.T(), kept.UnreferencedSince)
+
+	suite.Require().NoError(suite.db.Model(&models.Image{}).Where("name = ?", "drop").
+		Update("unreferenced_since", time.Now().Add(-2*suite.cfg.Images.GCGracePeriod)).Error)
+	collected, err = suite.images.CollectGarbage(ctx)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, collected)
+	w = suite.makeRequest("GET", "/api/v1/images/drop:1", nil)
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+	w = suite.makeRequest("GET", "/api/v1/images/keep:1/download", nil)
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestImages_GarbageCollectionKeepsImageOfNewVM() {
+	w := suite.makeRequest("POST", "/api/v1/images", models.ImageCreateRequest{Name: "late", Version: "1", OSFamily: models.OSFamilyOther})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	data := bytes.Repeat([]byte{0}, 4096)
+	digest := sha256.Sum256(data)
+	w = suite.upload("late:1", data, map[string]string{"X-Image-Checksum": "sha256:" + hex.EncodeToString(digest[:])})
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	suite.Require().NoError(suite.db.Model(&models.Image{}).Where("name = ?", "late").
+		Update("unreferenced_since", time.Now().Add(-2*suite.cfg.Images.GCGracePeriod)).Error)
+
+	// A VM is created from the image right after the collector found it unreferenced
+	vmRepo := &admittingVMRepository{VMRepository: suite.vmRepo, admit: func() {
+		w := suite.makeRequest("POST", "/api/v1/vms", models.VMCreateRequest{Name: "late-01", CPUCores: 1, RAMMb: 1024, DiskGb: 20, ImageName: "late:1"})
+		suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	}}
+	store, err := imagestore.New(suite.T().TempDir(), 1<<20)
+	suite.Require().NoError(err)
+	images := services.NewImageService(repositories.NewImageRepository(suite.db), repositories.NewProjectRepository(suite.db), vmRepo,
+		repositories.NewSnapshotRepository(suite.db), store, suite.cfg, suite.logger)
+
+	ctx := context.Background()
+	collected, err := images.CollectGarbage(ctx)
+	suite.Require().NoError(err)
+	assert.Zero(suite.T(), collected)
+	image, err := images.GetImage(ctx, "late:1")
+	suite.Require().NoError(err)
+	assert.Nil(suite.T(), image.UnreferencedSince)
+	w = suite.makeRequest("GET", "/api/v1/images/late:1/download", nil)
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+}
+
+// admittingVMRepository runs admit after the first count of VMs by image, as a VM
+// request racing with the image garbage collector would
+type admittingVMRepository struct {
+	repositories.VMRepository
+	admit func()
+}
+
+func (r *admittingVMRepository) CountByImage(ctx context.Context, imageNames []string) (int64, error) {
+	count, err := r.VMRepository.CountByImage(ctx, imageNames)
+	if admit := r.admit; admit != nil {
+		r.admit = nil
+		admit()
+	}
+	return count, err
+}
+
+// upload sends raw image data to the upload endpoint of an image
+func (suite *VMHandlerTestSuite) upload(image string, data []byte, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/api/v1/images/"+image+"/upload", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/octet-stream")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w
+}
+
+func (suite *VMHandlerTestSuite) TestVolumes_ResizeGrowsDiskOnlyOnceRecorded() {
+	vm := suite.createTestVM()
+	w := suite.makeRequest("POST", "/api/v1/volumes", map[string]interface{}{"name": "logs", "size_gb": 100})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.Volume `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+	w = suite.makeRequest("POST", "/api/v1/volumes/"+created.Data.ID.String()+"/attach", map[string]interface{}{"vm_id": vm.ID})
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+	// The VM changes concurrently, so recording the resize on it fails
+	ctx := context.Background()
+	driver := hypervisor.NewFakeDriver(0)
+	volume, err := repositories.NewVolumeRepository(suite.db).GetByID(ctx, created.Data.ID)
+	suite.Require().NoError(err)
+	suite.Require().NoError(driver.CreateVolume(ctx, volume))
+	volumes := services.NewVolumeService(repositories.NewVolumeRepository(suite.db), &conflictingVMRepository{VMRepository: suite.vmRepo},
+		repositories.NewQuotaRepository(suite.db), repositories.NewEventRepository(suite.db), driver, watch.NewHub(10, 10), suite.cfg, suite.logger)
+	_, err = volumes.ResizeVolume(ctx, volume.ID, &models.VolumeResizeRequest{SizeGb: 200})
+	suite.Require().Error(err)
+	assert.Equal(suite.T(), "RESOURCE_VERSION_CONFLICT", errors.GetCode(err))
+
+	stored, err := repositories.NewVolumeRepository(suite.db).GetByID(ctx, volume.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 100, stored.SizeGb)
+	// Volumes cannot shrink, so growing the disk to less than requested shows it was left alone
+	volume.SizeGb = 150
+	assert.NoError(suite.T(), driver.ResizeVolume(ctx, volume))
+}
+
+// conflictingVMRepository fails every VM update with a version conflict
+type conflictingVMRepository struct {
+	repositories.VMRepository
+}
+
+func (r *conflictingVMRepository) Update(ctx context.Context, vm *models.VM) error {
+	return errors.VersionConflictError("VM", vm.ID.String(), vm.ResourceVersion)
+}
+
+func (suite *VMHandlerTestSuite) TestVolumes_AttachDetachLifecycle() {
+	vm := suite.createTestVM()
+
+	w := suite.makeRequest("POST", "/api/v1/volumes", map[string]interface{}{"name": "pgdata", "size_gb": 100})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.Volume `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(suite.T(), suite.project.ID, created.Data.ProjectID)
+	assert.Equal(suite.T(), models.VolumeStatusAvailable, created.Data.Status)
+	path := "/api/v1/volumes/" + created.Data.ID.String()
+
+	// Names are unique per project
+	w = suite.makeRequest("POST", "/api/v1/volumes", map[string]interface{}{"name": "pgdata", "size_gb": 10})
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+
+	w = suite.makeRequest("POST", path+"/attach", map[string]interface{}{"vm_id": vm.ID})
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	w = suite.makeRequest("POST", path+"/attach", map[string]interface{}{"vm_id": vm.ID})
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+
+	// Attached volumes are listed in the VM response
+	w = suite.makeRequest("GET", "/api/v1/vms/"+vm.ID.String(), nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	var fetched struct {
+		Data models.VMResponse `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &fetched))
+	suite.Require().Len(fetched.Data.Volumes, 1)
+	assert.Equal(suite.T(), created.Data.ID, fetched.Data.Volumes[0].ID)
+	assert.Equal(suite.T(), int64(2), fetched.Data.ResourceVersion)
+
+	// Attached volumes can grow but not shrink or be deleted
+	w = suite.makeRequest("POST", path+"/resize", map[string]interface{}{"size_gb": 150})
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	w = suite.makeRequest("POST", path+"/resize", map[string]interface{}{"size_gb": 50})
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	w = suite.makeRequest("DELETE", path, nil)
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+
+	w = suite.makeRequest("GET", "/api/v1/volumes?status=in-use&vm_id="+vm.ID.String(), nil)
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	var list struct {
+		Data []models.Volume `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &list))
+	suite.Require().Len(list.Data, 1)
+	assert.Equal(suite.T(), 150, list.Data[0].SizeGb)
+
+	w = suite.makeRequest("GET", "/api/v1/events?type=volume_attached&vm_id="+vm.ID.String(), nil)
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(suite.T(), w.Body.String(), "pgdata")
+
+	w = suite.makeRequest("POST", path+"/detach", nil)
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	w = suite.makeRequest("POST", path+"/detach", nil)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	w = suite.makeRequest("DELETE", path, nil)
+	assert.Equal(suite.T(), http.StatusNoContent, w.Code)
+	w = suite.makeRequest("GET", path, nil)
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestVolumes_CountTowardsQuotaAndOutliveVMs() {
+	w := suite.makeRequest("PUT", "/api/v1/projects/default/quota", models.QuotaUpdateRequest{
+		QuotaLimits: models.QuotaLimits{MaxDiskGb: 200},
+	})
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	vm := suite.createTestVM()
+
+	// 50 GB of the VM disk plus 200 GB requested exceeds the limit of 200
+	w = suite.makeRequest("POST", "/api/v1/volumes", map[string]interface{}{"name": "data", "size_gb": 200})
+	suite.Require().Equal(http.StatusConflict, w.Code, w.Body.String())
+	var response struct {
+		Error errors.AppError `json:"error"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(suite.T(), models.QuotaDimensionDiskGb, response.Error.Context["dimension"])
+
+	w = suite.makeRequest("POST", "/api/v1/volumes", map[string]interface{}{"name": "data", "size_gb": 100})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.Volume `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+	path := "/api/v1/volumes/" + created.Data.ID.String()
+
+	w = suite.makeRequest("POST", path+"/resize", map[string]interface{}{"size_gb": 151})
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+
+	w = suite.makeRequest("GET", "/api/v1/quotas", nil)
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	var quotas struct {
+		Data []models.QuotaStatus `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &quotas))
+	suite.Require().Len(quotas.Data, 1)
+	assert.Equal(suite.T(), int64(150), quotas.Data[0].Usage.DiskGb)
+
+	w = suite.makeRequest("POST", path+"/attach", map[string]interface{}{"vm_id": vm.ID})
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+	// A VM whose volumes cannot be released is not deleted
+	suite.driver.FailNext("volume-detach", fmt.Errorf("device busy"))
+	w = suite.makeRequest("DELETE", "/api/v1/vms/"+vm.ID.String(), nil)
+	suite.Require().Equal(http.StatusBadGateway, w.Code, w.Body.String())
+	current, err := suite.vmRepo.GetByID(context.Background(), vm.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), models.VMStatusStopped, current.Status)
+	suite.Require().Len(current.Volumes, 1)
+
+	// Deleting the VM releases its volumes instead of deleting them
+	w = suite.makeRequest("DELETE", "/api/v1/vms/"+vm.ID.String(), nil)
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+	w = suite.makeRequest("GET", path, nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	var fetched struct {
+		Data models.Volume `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &fetched))
+	assert.Equal(suite.T(), models.VolumeStatusAvailable, fetched.Data.Status)
+	assert.Nil(suite.T(), fetched.Data.VMID)
+
+	w = suite.makeRequest("GET", "/api/v1/stats/summary", nil)
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	var summary struct {
+		Data models.ResourceSummary `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &summary))
+	assert.Equal(suite.T(), 100, summary.Data.Resources.Disk.Total)
+	assert.Equal(suite.T(), 0, summary.Data.Resources.Disk.Used)
+}
+
+func (suite *VMHandlerTestSuite) TestNetworks_AllocateAndReleaseAddresses() {
+	w := suite.makeRequest("POST", "/api/v1/networks", models.NetworkCreateRequest{
+		Name:       "frontend",
+		CIDR:       "10.0.1.0/29",
+		DNSServers: []string{"10.0.0.53"},
+		DHCPEnd:    "10.0.1.4",
+	})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.Network `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(suite.T(), "10.0.1.1", created.Data.Gateway)
+	assert.Equal(suite.T(), "10.0.1.2", created.Data.DHCPStart)
+	path := "/api/v1/networks/" + created.Data.ID.String()
+
+	w = suite.makeRequest("POST", "/api/v1/networks", models.NetworkCreateRequest{Name: "frontend", CIDR: "10.0.2.0/24"})
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+	w = suite.makeRequest("POST", "/api/v1/networks", models.NetworkCreateRequest{Name: "backend", CIDR: "10.0.2.0/24", Gateway: "10.0.3.1"})
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	createVM := func(name string) *httptest.ResponseRecorder {
+		return suite.makeRequest("POST", "/api/v1/vms", models.VMCreateRequest{
+			Name: name, CPUCores: 1, RAMMb: 1024, DiskGb: 10, ImageName: "ubuntu:22.04", Networks: []string{"frontend"},
+		})
+	}
+
+	// Each VM gets the next free address of the range and its own MAC address
+	var vms []models.VMResponse
+	for _, name := range []string{"web-01", "web-02", "web-03"} {
+		w = createVM(name)
+		suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+		suite.waitForOperation(w)
+		var response struct {
+			Data models.VMResponse `json:"data"`
+		}
+		suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		vms = append(vms, response.Data)
+	}
+	assert.Equal(suite.T(), []string{"10.0.1.2"}, vms[0].IPAddresses)
+	assert.Equal(suite.T(), []string{"10.0.1.3"}, vms[1].IPAddresses)
+	assert.Equal(suite.T(), []string{"10.0.1.4"}, vms[2].IPAddresses)
+
+	w = suite.makeRequest("GET", "/api/v1/vms/"+vms[1].ID.String(), nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	var fetched struct {
+		Data models.VMResponse `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &fetched))
+	assert.Equal(suite.T(), []string{"10.0.1.3"}, fetched.Data.IPAddresses)
+	suite.Require().Len(fetched.Data.Interfaces, 1)
+	assert.Equal(suite.T(), "frontend", fetched.Data.Interfaces[0].Network)
+	assert.Equal(suite.T(), 29, fetched.Data.Interfaces[0].PrefixLength)
+
+	w = suite.makeRequest("GET", path+"/interfaces", nil)
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	var interfaces struct {
+		Data []models.NetworkInterface `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &interfaces))
+	suite.Require().Len(interfaces.Data, 3)
+	macs := map[string]bool{}
+	for _, iface := range interfaces.Data {
+		macs[iface.MACAddress] = true
+	}
+	assert.Len(suite.T(), macs, 3)
+
+	// The range is exhausted until a VM is deleted
+	w = createVM("web-04")
+	suite.Require().Equal(http.StatusConflict, w.Code, w.Body.String())
+	var failure struct {
+		Error errors.AppError `json:"error"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &failure))
+	assert.Equal(suite.T(), errors.ErrAddressesExhausted.Code, failure.Error.Code)
+
+	w = suite.makeRequest("DELETE", "/api/v1/vms/"+vms[1].ID.String(), nil)
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	w = createVM("web-04")
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	suite.waitForOperation(w)
+	var reused struct {
+		Data models.VMResponse `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &reused))
+	assert.Equal(suite.T(), []string{"10.0.1.3"}, reused.Data.IPAddresses)
+
+	// Only the description and DNS servers of a network can change
+	w = suite.makeRequest("PUT", path, models.NetworkUpdateRequest{Description: "Web tier", DNSServers: []string{"1.1.1.1", "8.8.8.8"}})
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(suite.T(), models.AddressList{"1.1.1.1", "8.8.8.8"}, created.Data.DNSServers)
+	assert.Equal(suite.T(), "10.0.1.0/29", created.Data.CIDR)
+
+	// Networks cannot be deleted while VMs are connected
+	w = suite.makeRequest("DELETE", path, nil)
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+	for _, id := range []uuid.UUID{vms[0].ID, vms[2].ID, reused.Data.ID} {
+		w = suite.makeRequest("DELETE", "/api/v1/vms/"+id.String(), nil)
+		suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	}
+	w = suite.makeRequest("DELETE", path, nil)
+	assert.Equal(suite.T(), http.StatusNoContent, w.Code)
+	w = suite.makeRequest("GET", path, nil)
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+func (suite *VMHandlerTestSuite) TestNetworks_MultipleInterfacesAndUnknownNetwork() {
+	for _, req := range []models.NetworkCreateRequest{
+		{Name: "frontend", CIDR: "10.0.1.0/24"},
+		{Name: "backend", CIDR: "10.0.2.0/24", DHCPStart: "10.0.2.100"},
+	} {
+		w := suite.makeRequest("POST", "/api/v1/networks", req)
+		suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	w := suite.makeRequest("POST", "/api/v1/vms", models.VMCreateRequest{
+		Name: "app-01", CPUCores: 1, RAMMb: 1024, DiskGb: 10, ImageName: "ubuntu:22.04", Networks: []string{"backend", "frontend"},
+	})
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.VMResponse `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(suite.T(), []string{"10.0.2.100", "10.0.1.2"}, created.Data.IPAddresses)
+
+	// Nothing is created when a network does not exist
+	w = suite.makeRequest("POST", "/api/v1/vms", models.VMCreateRequest{
+		Name: "app-02", CPUCores: 1, RAMMb: 1024, DiskGb: 10, ImageName: "ubuntu:22.04", Networks: []string{"frontend", "storage"},
+	})
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code, w.Body.String())
+	_, err := suite.vmRepo.GetByName(context.Background(), suite.project.ID, "app-02")
+	assert.True(suite.T(), errors.Is(err, errors.ErrNotFound))
+
+	w = suite.makeRequest("GET", "/api/v1/networks", nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	var list struct {
+		Data []models.Network `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &list))
+	suite.Require().Len(list.Data, 2)
+	assert.Equal(suite.T(), "backend", list.Data[0].Name)
 }
@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stackit/enterprise-vm-manager/internal/models"
+	"github.com/stackit/enterprise-vm-manager/internal/watch"
+	"github.com/stackit/enterprise-vm-manager/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWatchedVM(name string, status models.VMStatus) *models.VM {
+	return &models.VM{ID: uuid.New(), Name: name, Status: status, NodeID: "node-01"}
+}
+
+func TestWatchHubDeliversMatchingEvents(t *testing.T) {
+	hub := watch.NewHub(10, 10)
+
+	opts := models.VMListOptions{Status: models.VMStatusRunning}
+	sub, err := hub.Subscribe(0, func(event *watch.Event) bool {
+		return opts.Matches(event.VM.VM)
+	})
+	require.NoError(t, err)
+	defer sub.Close()
+
+	hub.Publish(watch.EventStatusChanged, newWatchedVM("web-01", models.VMStatusStopped))
+	hub.Publish(watch.EventStatusChanged, newWatchedVM("web-02", models.VMStatusRunning))
+
+	event := <-sub.Events()
+	assert.Equal(t, "web-02", event.VM.Name)
+	assert.Equal(t, hub.CurrentVersion(), event.ResourceVersion)
+	assert.Len(t, sub.Events(), 0)
+}
+
+func TestWatchHubResumesFromResourceVersion(t *testing.T) {
+	hub := watch.NewHub(10, 10)
+
+	hub.Publish(watch.EventCreated, newWatchedVM("web-01", models.VMStatusPending))
+	resumeFrom := hub.CurrentVersion()
+	hub.Publish(watch.EventStatusChanged, newWatchedVM("web-01", models.VMStatusStopped))
+	hub.Publish(watch.EventDeleted, newWatchedVM("web-01", models.VMStatusStopped))
+
+	sub, err := hub.Subscribe(resumeFrom, nil)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	first := <-sub.Events()
+	second := <-sub.Events()
+	assert.Equal(t, watch.EventStatusChanged, first.Type)
+	assert.Equal(t, resumeFrom+1, first.ResourceVersion)
+	assert.Equal(t, watch.EventDeleted, second.Type)
+}
+
+func TestWatchHubRejectsExpiredResourceVersion(t *testing.T) {
+	hub := watch.NewHub(2, 10)
+
+	start := hub.CurrentVersion()
+	for i := 0; i < 3; i++ {
+		hub.Publish(watch.EventStats, newWatchedVM("web-01", models.VMStatusRunning))
+	}
+
+	// The first event has been dropped from history
+	_, err := hub.Subscribe(start, nil)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.ErrVersionExpired))
+
+	// Versions from a previous process or the future cannot be resumed either
+	_, err = hub.Subscribe(1, nil)
+	assert.Error(t, err)
+	_, err = hub.Subscribe(hub.CurrentVersion()+1, nil)
+	assert.Error(t, err)
+
+	sub, err := hub.Subscribe(start+1, nil)
+	require.NoError(t, err)
+	sub.Close()
+}
+
+func TestWatchHubDisconnectsSlowSubscriber(t *testing.T) {
+	hub := watch.NewHub(10, 1)
+
+	sub, err := hub.Subscribe(0, nil)
+	require.NoError(t, err)
+
+	hub.Publish(watch.EventStats, newWatchedVM("web-01", models.VMStatusRunning))
+	hub.Publish(watch.EventStats, newWatchedVM("web-01", models.VMStatusRunning))
+
+	<-sub.Events()
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+	assert.True(t, sub.Overflowed())
+
+	// Closing an ended subscription is a no-op
+	sub.Close()
+}